@@ -44,28 +44,100 @@ const (
 	CommandType_CMD_REAUTH_RSP CommandType = 103 // 重新认证响应
 	CommandType_CMD_KICK_OUT   CommandType = 104 // 踢出通知
 	// 消息相关（200-299）
-	CommandType_CMD_SEND_MSG_REQ    CommandType = 200 // 发送消息请求
-	CommandType_CMD_SEND_MSG_RSP    CommandType = 201 // 发送消息响应
-	CommandType_CMD_PUSH_MSG        CommandType = 202 // 推送消息（服务器 → 客户端）
-	CommandType_CMD_MSG_ACK         CommandType = 203 // 消息 ACK
-	CommandType_CMD_BATCH_MSG       CommandType = 204 // 批量消息
-	CommandType_CMD_REVOKE_MSG_REQ  CommandType = 205 // 撤回消息请求
-	CommandType_CMD_REVOKE_MSG_RSP  CommandType = 206 // 撤回消息响应
-	CommandType_CMD_REVOKE_MSG_PUSH CommandType = 207 // 撤回消息推送
+	CommandType_CMD_SEND_MSG_REQ                  CommandType = 200 // 发送消息请求
+	CommandType_CMD_SEND_MSG_RSP                  CommandType = 201 // 发送消息响应
+	CommandType_CMD_PUSH_MSG                      CommandType = 202 // 推送消息（服务器 → 客户端）
+	CommandType_CMD_MSG_ACK                       CommandType = 203 // 消息 ACK
+	CommandType_CMD_BATCH_MSG                     CommandType = 204 // 批量消息
+	CommandType_CMD_REVOKE_MSG_REQ                CommandType = 205 // 撤回消息请求
+	CommandType_CMD_REVOKE_MSG_RSP                CommandType = 206 // 撤回消息响应
+	CommandType_CMD_REVOKE_MSG_PUSH               CommandType = 207 // 撤回消息推送
+	CommandType_CMD_EDIT_MSG_REQ                  CommandType = 208 // 编辑消息请求
+	CommandType_CMD_EDIT_MSG_RSP                  CommandType = 209 // 编辑消息响应
+	CommandType_CMD_EDIT_MSG_PUSH                 CommandType = 210 // 编辑消息推送
+	CommandType_CMD_ADD_REACTION_REQ              CommandType = 211 // 添加表情回应请求
+	CommandType_CMD_ADD_REACTION_RSP              CommandType = 212 // 添加表情回应响应
+	CommandType_CMD_REMOVE_REACTION_REQ           CommandType = 213 // 取消表情回应请求
+	CommandType_CMD_REMOVE_REACTION_RSP           CommandType = 214 // 取消表情回应响应
+	CommandType_CMD_REACTION_PUSH                 CommandType = 215 // 表情回应变化推送
+	CommandType_CMD_FORWARD_MSG_REQ               CommandType = 216 // 转发消息请求
+	CommandType_CMD_FORWARD_MSG_RSP               CommandType = 217 // 转发消息响应
+	CommandType_CMD_DELETE_MSG_REQ                CommandType = 218 // 删除消息请求（仅自己不可见）
+	CommandType_CMD_DELETE_MSG_RSP                CommandType = 219 // 删除消息响应
+	CommandType_CMD_CLEAR_HISTORY_REQ             CommandType = 220 // 清空会话历史请求
+	CommandType_CMD_CLEAR_HISTORY_RSP             CommandType = 221 // 清空会话历史响应
+	CommandType_CMD_DELETE_MSG_PUSH               CommandType = 222 // 删除/清空推送（同步到自己的其他设备）
+	CommandType_CMD_PIN_MSG_REQ                   CommandType = 223 // 置顶消息请求
+	CommandType_CMD_PIN_MSG_RSP                   CommandType = 224 // 置顶消息响应
+	CommandType_CMD_UNPIN_MSG_REQ                 CommandType = 225 // 取消置顶请求
+	CommandType_CMD_UNPIN_MSG_RSP                 CommandType = 226 // 取消置顶响应
+	CommandType_CMD_PIN_PUSH                      CommandType = 227 // 置顶变化推送
+	CommandType_CMD_SCHEDULE_MSG_REQ              CommandType = 228 // 创建定时消息请求
+	CommandType_CMD_SCHEDULE_MSG_RSP              CommandType = 229 // 创建定时消息响应
+	CommandType_CMD_SCHEDULED_LIST_REQ            CommandType = 230 // 获取待发送定时消息列表请求
+	CommandType_CMD_SCHEDULED_LIST_RSP            CommandType = 231 // 获取待发送定时消息列表响应
+	CommandType_CMD_SCHEDULED_EDIT_REQ            CommandType = 232 // 修改定时消息请求
+	CommandType_CMD_SCHEDULED_EDIT_RSP            CommandType = 233 // 修改定时消息响应
+	CommandType_CMD_SCHEDULED_CANCEL_REQ          CommandType = 234 // 取消定时消息请求
+	CommandType_CMD_SCHEDULED_CANCEL_RSP          CommandType = 235 // 取消定时消息响应
+	CommandType_CMD_SET_EPHEMERAL_REQ             CommandType = 236 // 设置会话默认阅后即焚请求
+	CommandType_CMD_SET_EPHEMERAL_RSP             CommandType = 237 // 设置会话默认阅后即焚响应
+	CommandType_CMD_EPHEMERAL_SETTING_PUSH        CommandType = 238 // 会话阅后即焚设置变化推送
+	CommandType_CMD_MSG_EXPIRED_PUSH              CommandType = 239 // 阅后即焚消息过期推送
+	CommandType_CMD_SET_RETENTION_REQ             CommandType = 240 // 设置会话消息保留期限请求
+	CommandType_CMD_SET_RETENTION_RSP             CommandType = 241 // 设置会话消息保留期限响应
+	CommandType_CMD_SET_MODERATION_REQ            CommandType = 242 // 设置群组内容审核严格程度请求
+	CommandType_CMD_SET_MODERATION_RSP            CommandType = 243 // 设置群组内容审核严格程度响应
+	CommandType_CMD_SET_CONVERSATION_SETTINGS_REQ CommandType = 244 // 修改会话个人设置请求（免打扰、置顶、隐藏、自定义字段）
+	CommandType_CMD_SET_CONVERSATION_SETTINGS_RSP CommandType = 245 // 修改会话个人设置响应
+	CommandType_CMD_CONVERSATION_SETTINGS_PUSH    CommandType = 246 // 会话个人设置变化推送（同步到自己的其他设备）
+	CommandType_CMD_SET_DRAFT_REQ                 CommandType = 247 // 保存/清除会话草稿请求
+	CommandType_CMD_SET_DRAFT_RSP                 CommandType = 248 // 保存/清除会话草稿响应
+	CommandType_CMD_DRAFT_PUSH                    CommandType = 249 // 会话草稿变化推送（同步到自己的其他设备）
+	CommandType_CMD_JOIN_CHATROOM_REQ             CommandType = 250 // 加入聊天室请求
+	CommandType_CMD_JOIN_CHATROOM_RSP             CommandType = 251 // 加入聊天室响应（带最近消息）
+	CommandType_CMD_LEAVE_CHATROOM_REQ            CommandType = 252 // 离开聊天室请求
+	CommandType_CMD_LEAVE_CHATROOM_RSP            CommandType = 253 // 离开聊天室响应
+	CommandType_CMD_CHATROOM_MSG_PUSH             CommandType = 254 // 聊天室消息推送（不需要 ACK，不进入会话列表）
+	CommandType_CMD_CHATROOM_MEMBER_COUNT_PUSH    CommandType = 255 // 聊天室在线人数变化推送
+	CommandType_CMD_EDIT_HISTORY_REQ              CommandType = 256 // 查询消息编辑历史请求
+	CommandType_CMD_EDIT_HISTORY_RSP              CommandType = 257 // 查询消息编辑历史响应
 	// 同步相关（300-399）
-	CommandType_CMD_BATCH_SYNC_REQ CommandType = 300 // 批量同步请求（一次性同步所有会话）
-	CommandType_CMD_BATCH_SYNC_RSP CommandType = 301 // 批量同步响应
-	CommandType_CMD_SYNC_FINISHED  CommandType = 302 // 同步完成通知
-	CommandType_CMD_SYNC_RANGE_REQ CommandType = 303 // 范围同步请求（补拉丢失消息）
-	CommandType_CMD_SYNC_RANGE_RSP CommandType = 304 // 范围同步响应
+	CommandType_CMD_BATCH_SYNC_REQ         CommandType = 300 // 批量同步请求（一次性同步所有会话）
+	CommandType_CMD_BATCH_SYNC_RSP         CommandType = 301 // 批量同步响应
+	CommandType_CMD_SYNC_FINISHED          CommandType = 302 // 同步完成通知
+	CommandType_CMD_SYNC_RANGE_REQ         CommandType = 303 // 范围同步请求（补拉丢失消息）
+	CommandType_CMD_SYNC_RANGE_RSP         CommandType = 304 // 范围同步响应
+	CommandType_CMD_THREAD_REPLIES_REQ     CommandType = 305 // 分页拉取话题回复请求
+	CommandType_CMD_THREAD_REPLIES_RSP     CommandType = 306 // 分页拉取话题回复响应
+	CommandType_CMD_PINNED_LIST_REQ        CommandType = 307 // 获取会话置顶消息列表请求
+	CommandType_CMD_PINNED_LIST_RSP        CommandType = 308 // 获取会话置顶消息列表响应
+	CommandType_CMD_GET_EPHEMERAL_REQ      CommandType = 309 // 获取会话阅后即焚设置请求
+	CommandType_CMD_GET_EPHEMERAL_RSP      CommandType = 310 // 获取会话阅后即焚设置响应
+	CommandType_CMD_GET_RETENTION_REQ      CommandType = 311 // 获取会话消息保留期限请求
+	CommandType_CMD_GET_RETENTION_RSP      CommandType = 312 // 获取会话消息保留期限响应
+	CommandType_CMD_HISTORY_REQ            CommandType = 313 // 向前分页拉取历史消息请求
+	CommandType_CMD_HISTORY_RSP            CommandType = 314 // 向前分页拉取历史消息响应
+	CommandType_CMD_SEARCH_MSG_REQ         CommandType = 315 // 搜索消息请求
+	CommandType_CMD_SEARCH_MSG_RSP         CommandType = 316 // 搜索消息响应
+	CommandType_CMD_GET_MODERATION_REQ     CommandType = 317 // 获取群组内容审核严格程度请求
+	CommandType_CMD_GET_MODERATION_RSP     CommandType = 318 // 获取群组内容审核严格程度响应
+	CommandType_CMD_SYNC_CONVERSATIONS_REQ CommandType = 319 // 同步会话列表请求（返回指定版本之后变化的会话）
+	CommandType_CMD_SYNC_CONVERSATIONS_RSP CommandType = 320 // 同步会话列表响应
 	// 在线状态（400-499）
 	CommandType_CMD_ONLINE_STATUS_REQ  CommandType = 400 // 查询在线状态请求
 	CommandType_CMD_ONLINE_STATUS_RSP  CommandType = 401 // 在线状态响应
 	CommandType_CMD_STATUS_CHANGE_PUSH CommandType = 402 // 状态变化推送
+	CommandType_CMD_SET_PRESENCE_REQ   CommandType = 403 // 设置自定义状态和最后在线时间可见范围请求
+	CommandType_CMD_SET_PRESENCE_RSP   CommandType = 404 // 设置自定义状态和最后在线时间可见范围响应
 	// 已读回执（500-599）
-	CommandType_CMD_READ_RECEIPT_REQ  CommandType = 500 // 已读回执请求
-	CommandType_CMD_READ_RECEIPT_RSP  CommandType = 501 // 已读回执响应
-	CommandType_CMD_READ_RECEIPT_PUSH CommandType = 502 // 已读回执推送
+	CommandType_CMD_READ_RECEIPT_REQ        CommandType = 500 // 已读回执请求
+	CommandType_CMD_READ_RECEIPT_RSP        CommandType = 501 // 已读回执响应
+	CommandType_CMD_READ_RECEIPT_PUSH       CommandType = 502 // 已读回执推送
+	CommandType_CMD_READ_MEMBERS_REQ        CommandType = 503 // 查询消息已读/未读成员请求
+	CommandType_CMD_READ_MEMBERS_RSP        CommandType = 504 // 查询消息已读/未读成员响应
+	CommandType_CMD_GROUP_READ_RECEIPT_PUSH CommandType = 505 // 群聊已读人数推送（聚合后推送给消息发送者）
+	CommandType_CMD_UNREAD_COUNT_PUSH       CommandType = 506 // 未读数变化推送（推送给用户自己的设备）
 	// 输入状态（600-699）
 	CommandType_CMD_TYPING_STATUS_REQ  CommandType = 600 // 输入状态请求
 	CommandType_CMD_TYPING_STATUS_PUSH CommandType = 601 // 输入状态推送
@@ -94,54 +166,198 @@ var (
 		205: "CMD_REVOKE_MSG_REQ",
 		206: "CMD_REVOKE_MSG_RSP",
 		207: "CMD_REVOKE_MSG_PUSH",
+		208: "CMD_EDIT_MSG_REQ",
+		209: "CMD_EDIT_MSG_RSP",
+		210: "CMD_EDIT_MSG_PUSH",
+		211: "CMD_ADD_REACTION_REQ",
+		212: "CMD_ADD_REACTION_RSP",
+		213: "CMD_REMOVE_REACTION_REQ",
+		214: "CMD_REMOVE_REACTION_RSP",
+		215: "CMD_REACTION_PUSH",
+		216: "CMD_FORWARD_MSG_REQ",
+		217: "CMD_FORWARD_MSG_RSP",
+		218: "CMD_DELETE_MSG_REQ",
+		219: "CMD_DELETE_MSG_RSP",
+		220: "CMD_CLEAR_HISTORY_REQ",
+		221: "CMD_CLEAR_HISTORY_RSP",
+		222: "CMD_DELETE_MSG_PUSH",
+		223: "CMD_PIN_MSG_REQ",
+		224: "CMD_PIN_MSG_RSP",
+		225: "CMD_UNPIN_MSG_REQ",
+		226: "CMD_UNPIN_MSG_RSP",
+		227: "CMD_PIN_PUSH",
+		228: "CMD_SCHEDULE_MSG_REQ",
+		229: "CMD_SCHEDULE_MSG_RSP",
+		230: "CMD_SCHEDULED_LIST_REQ",
+		231: "CMD_SCHEDULED_LIST_RSP",
+		232: "CMD_SCHEDULED_EDIT_REQ",
+		233: "CMD_SCHEDULED_EDIT_RSP",
+		234: "CMD_SCHEDULED_CANCEL_REQ",
+		235: "CMD_SCHEDULED_CANCEL_RSP",
+		236: "CMD_SET_EPHEMERAL_REQ",
+		237: "CMD_SET_EPHEMERAL_RSP",
+		238: "CMD_EPHEMERAL_SETTING_PUSH",
+		239: "CMD_MSG_EXPIRED_PUSH",
+		240: "CMD_SET_RETENTION_REQ",
+		241: "CMD_SET_RETENTION_RSP",
+		242: "CMD_SET_MODERATION_REQ",
+		243: "CMD_SET_MODERATION_RSP",
+		244: "CMD_SET_CONVERSATION_SETTINGS_REQ",
+		245: "CMD_SET_CONVERSATION_SETTINGS_RSP",
+		246: "CMD_CONVERSATION_SETTINGS_PUSH",
+		247: "CMD_SET_DRAFT_REQ",
+		248: "CMD_SET_DRAFT_RSP",
+		249: "CMD_DRAFT_PUSH",
+		250: "CMD_JOIN_CHATROOM_REQ",
+		251: "CMD_JOIN_CHATROOM_RSP",
+		252: "CMD_LEAVE_CHATROOM_REQ",
+		253: "CMD_LEAVE_CHATROOM_RSP",
+		254: "CMD_CHATROOM_MSG_PUSH",
+		255: "CMD_CHATROOM_MEMBER_COUNT_PUSH",
+		256: "CMD_EDIT_HISTORY_REQ",
+		257: "CMD_EDIT_HISTORY_RSP",
 		300: "CMD_BATCH_SYNC_REQ",
 		301: "CMD_BATCH_SYNC_RSP",
 		302: "CMD_SYNC_FINISHED",
 		303: "CMD_SYNC_RANGE_REQ",
 		304: "CMD_SYNC_RANGE_RSP",
+		305: "CMD_THREAD_REPLIES_REQ",
+		306: "CMD_THREAD_REPLIES_RSP",
+		307: "CMD_PINNED_LIST_REQ",
+		308: "CMD_PINNED_LIST_RSP",
+		309: "CMD_GET_EPHEMERAL_REQ",
+		310: "CMD_GET_EPHEMERAL_RSP",
+		311: "CMD_GET_RETENTION_REQ",
+		312: "CMD_GET_RETENTION_RSP",
+		313: "CMD_HISTORY_REQ",
+		314: "CMD_HISTORY_RSP",
+		315: "CMD_SEARCH_MSG_REQ",
+		316: "CMD_SEARCH_MSG_RSP",
+		317: "CMD_GET_MODERATION_REQ",
+		318: "CMD_GET_MODERATION_RSP",
+		319: "CMD_SYNC_CONVERSATIONS_REQ",
+		320: "CMD_SYNC_CONVERSATIONS_RSP",
 		400: "CMD_ONLINE_STATUS_REQ",
 		401: "CMD_ONLINE_STATUS_RSP",
 		402: "CMD_STATUS_CHANGE_PUSH",
+		403: "CMD_SET_PRESENCE_REQ",
+		404: "CMD_SET_PRESENCE_RSP",
 		500: "CMD_READ_RECEIPT_REQ",
 		501: "CMD_READ_RECEIPT_RSP",
 		502: "CMD_READ_RECEIPT_PUSH",
+		503: "CMD_READ_MEMBERS_REQ",
+		504: "CMD_READ_MEMBERS_RSP",
+		505: "CMD_GROUP_READ_RECEIPT_PUSH",
+		506: "CMD_UNREAD_COUNT_PUSH",
 		600: "CMD_TYPING_STATUS_REQ",
 		601: "CMD_TYPING_STATUS_PUSH",
 	}
 	CommandType_value = map[string]int32{
-		"CMD_UNKNOWN":            0,
-		"CMD_CONNECT_REQ":        1,
-		"CMD_CONNECT_RSP":        2,
-		"CMD_DISCONNECT_REQ":     3,
-		"CMD_DISCONNECT_RSP":     4,
-		"CMD_HEARTBEAT_REQ":      5,
-		"CMD_HEARTBEAT_RSP":      6,
-		"CMD_AUTH_REQ":           100,
-		"CMD_AUTH_RSP":           101,
-		"CMD_REAUTH_REQ":         102,
-		"CMD_REAUTH_RSP":         103,
-		"CMD_KICK_OUT":           104,
-		"CMD_SEND_MSG_REQ":       200,
-		"CMD_SEND_MSG_RSP":       201,
-		"CMD_PUSH_MSG":           202,
-		"CMD_MSG_ACK":            203,
-		"CMD_BATCH_MSG":          204,
-		"CMD_REVOKE_MSG_REQ":     205,
-		"CMD_REVOKE_MSG_RSP":     206,
-		"CMD_REVOKE_MSG_PUSH":    207,
-		"CMD_BATCH_SYNC_REQ":     300,
-		"CMD_BATCH_SYNC_RSP":     301,
-		"CMD_SYNC_FINISHED":      302,
-		"CMD_SYNC_RANGE_REQ":     303,
-		"CMD_SYNC_RANGE_RSP":     304,
-		"CMD_ONLINE_STATUS_REQ":  400,
-		"CMD_ONLINE_STATUS_RSP":  401,
-		"CMD_STATUS_CHANGE_PUSH": 402,
-		"CMD_READ_RECEIPT_REQ":   500,
-		"CMD_READ_RECEIPT_RSP":   501,
-		"CMD_READ_RECEIPT_PUSH":  502,
-		"CMD_TYPING_STATUS_REQ":  600,
-		"CMD_TYPING_STATUS_PUSH": 601,
+		"CMD_UNKNOWN":                       0,
+		"CMD_CONNECT_REQ":                   1,
+		"CMD_CONNECT_RSP":                   2,
+		"CMD_DISCONNECT_REQ":                3,
+		"CMD_DISCONNECT_RSP":                4,
+		"CMD_HEARTBEAT_REQ":                 5,
+		"CMD_HEARTBEAT_RSP":                 6,
+		"CMD_AUTH_REQ":                      100,
+		"CMD_AUTH_RSP":                      101,
+		"CMD_REAUTH_REQ":                    102,
+		"CMD_REAUTH_RSP":                    103,
+		"CMD_KICK_OUT":                      104,
+		"CMD_SEND_MSG_REQ":                  200,
+		"CMD_SEND_MSG_RSP":                  201,
+		"CMD_PUSH_MSG":                      202,
+		"CMD_MSG_ACK":                       203,
+		"CMD_BATCH_MSG":                     204,
+		"CMD_REVOKE_MSG_REQ":                205,
+		"CMD_REVOKE_MSG_RSP":                206,
+		"CMD_REVOKE_MSG_PUSH":               207,
+		"CMD_EDIT_MSG_REQ":                  208,
+		"CMD_EDIT_MSG_RSP":                  209,
+		"CMD_EDIT_MSG_PUSH":                 210,
+		"CMD_ADD_REACTION_REQ":              211,
+		"CMD_ADD_REACTION_RSP":              212,
+		"CMD_REMOVE_REACTION_REQ":           213,
+		"CMD_REMOVE_REACTION_RSP":           214,
+		"CMD_REACTION_PUSH":                 215,
+		"CMD_FORWARD_MSG_REQ":               216,
+		"CMD_FORWARD_MSG_RSP":               217,
+		"CMD_DELETE_MSG_REQ":                218,
+		"CMD_DELETE_MSG_RSP":                219,
+		"CMD_CLEAR_HISTORY_REQ":             220,
+		"CMD_CLEAR_HISTORY_RSP":             221,
+		"CMD_DELETE_MSG_PUSH":               222,
+		"CMD_PIN_MSG_REQ":                   223,
+		"CMD_PIN_MSG_RSP":                   224,
+		"CMD_UNPIN_MSG_REQ":                 225,
+		"CMD_UNPIN_MSG_RSP":                 226,
+		"CMD_PIN_PUSH":                      227,
+		"CMD_SCHEDULE_MSG_REQ":              228,
+		"CMD_SCHEDULE_MSG_RSP":              229,
+		"CMD_SCHEDULED_LIST_REQ":            230,
+		"CMD_SCHEDULED_LIST_RSP":            231,
+		"CMD_SCHEDULED_EDIT_REQ":            232,
+		"CMD_SCHEDULED_EDIT_RSP":            233,
+		"CMD_SCHEDULED_CANCEL_REQ":          234,
+		"CMD_SCHEDULED_CANCEL_RSP":          235,
+		"CMD_SET_EPHEMERAL_REQ":             236,
+		"CMD_SET_EPHEMERAL_RSP":             237,
+		"CMD_EPHEMERAL_SETTING_PUSH":        238,
+		"CMD_MSG_EXPIRED_PUSH":              239,
+		"CMD_SET_RETENTION_REQ":             240,
+		"CMD_SET_RETENTION_RSP":             241,
+		"CMD_SET_MODERATION_REQ":            242,
+		"CMD_SET_MODERATION_RSP":            243,
+		"CMD_SET_CONVERSATION_SETTINGS_REQ": 244,
+		"CMD_SET_CONVERSATION_SETTINGS_RSP": 245,
+		"CMD_CONVERSATION_SETTINGS_PUSH":    246,
+		"CMD_SET_DRAFT_REQ":                 247,
+		"CMD_SET_DRAFT_RSP":                 248,
+		"CMD_DRAFT_PUSH":                    249,
+		"CMD_JOIN_CHATROOM_REQ":             250,
+		"CMD_JOIN_CHATROOM_RSP":             251,
+		"CMD_LEAVE_CHATROOM_REQ":            252,
+		"CMD_LEAVE_CHATROOM_RSP":            253,
+		"CMD_CHATROOM_MSG_PUSH":             254,
+		"CMD_CHATROOM_MEMBER_COUNT_PUSH":    255,
+		"CMD_EDIT_HISTORY_REQ":              256,
+		"CMD_EDIT_HISTORY_RSP":              257,
+		"CMD_BATCH_SYNC_REQ":                300,
+		"CMD_BATCH_SYNC_RSP":                301,
+		"CMD_SYNC_FINISHED":                 302,
+		"CMD_SYNC_RANGE_REQ":                303,
+		"CMD_SYNC_RANGE_RSP":                304,
+		"CMD_THREAD_REPLIES_REQ":            305,
+		"CMD_THREAD_REPLIES_RSP":            306,
+		"CMD_PINNED_LIST_REQ":               307,
+		"CMD_PINNED_LIST_RSP":               308,
+		"CMD_GET_EPHEMERAL_REQ":             309,
+		"CMD_GET_EPHEMERAL_RSP":             310,
+		"CMD_GET_RETENTION_REQ":             311,
+		"CMD_GET_RETENTION_RSP":             312,
+		"CMD_HISTORY_REQ":                   313,
+		"CMD_HISTORY_RSP":                   314,
+		"CMD_SEARCH_MSG_REQ":                315,
+		"CMD_SEARCH_MSG_RSP":                316,
+		"CMD_GET_MODERATION_REQ":            317,
+		"CMD_GET_MODERATION_RSP":            318,
+		"CMD_SYNC_CONVERSATIONS_REQ":        319,
+		"CMD_SYNC_CONVERSATIONS_RSP":        320,
+		"CMD_ONLINE_STATUS_REQ":             400,
+		"CMD_ONLINE_STATUS_RSP":             401,
+		"CMD_STATUS_CHANGE_PUSH":            402,
+		"CMD_SET_PRESENCE_REQ":              403,
+		"CMD_SET_PRESENCE_RSP":              404,
+		"CMD_READ_RECEIPT_REQ":              500,
+		"CMD_READ_RECEIPT_RSP":              501,
+		"CMD_READ_RECEIPT_PUSH":             502,
+		"CMD_READ_MEMBERS_REQ":              503,
+		"CMD_READ_MEMBERS_RSP":              504,
+		"CMD_GROUP_READ_RECEIPT_PUSH":       505,
+		"CMD_UNREAD_COUNT_PUSH":             506,
+		"CMD_TYPING_STATUS_REQ":             600,
+		"CMD_TYPING_STATUS_PUSH":            601,
 	}
 )
 
@@ -186,6 +402,14 @@ const (
 	ErrorCode_ERR_MESSAGE_TOO_LARGE      ErrorCode = 200 // 消息过大
 	ErrorCode_ERR_SEND_TOO_FAST          ErrorCode = 201 // 发送过快
 	ErrorCode_ERR_CONVERSATION_NOT_EXIST ErrorCode = 202 // 会话不存在
+	ErrorCode_ERR_MESSAGE_NOT_EXIST      ErrorCode = 203 // 消息不存在
+	ErrorCode_ERR_EDIT_TIME_EXPIRED      ErrorCode = 204 // 超过可编辑时间
+	ErrorCode_ERR_NOT_GROUP_MEMBER       ErrorCode = 205 // 不是群成员
+	ErrorCode_ERR_SCHEDULE_NOT_PENDING   ErrorCode = 206 // 定时消息已发送或已取消
+	ErrorCode_ERR_CONTENT_REJECTED       ErrorCode = 207 // 内容未通过审核
+	ErrorCode_ERR_NOT_CHATROOM_MEMBER    ErrorCode = 208 // 未加入聊天室
+	ErrorCode_ERR_CHATROOM_FULL          ErrorCode = 209 // 聊天室人数已满
+	ErrorCode_ERR_EDIT_CONFLICT          ErrorCode = 210 // 消息已被同时编辑，需要获取最新内容后重试
 )
 
 // Enum value maps for ErrorCode.
@@ -201,6 +425,14 @@ var (
 		200: "ERR_MESSAGE_TOO_LARGE",
 		201: "ERR_SEND_TOO_FAST",
 		202: "ERR_CONVERSATION_NOT_EXIST",
+		203: "ERR_MESSAGE_NOT_EXIST",
+		204: "ERR_EDIT_TIME_EXPIRED",
+		205: "ERR_NOT_GROUP_MEMBER",
+		206: "ERR_SCHEDULE_NOT_PENDING",
+		207: "ERR_CONTENT_REJECTED",
+		208: "ERR_NOT_CHATROOM_MEMBER",
+		209: "ERR_CHATROOM_FULL",
+		210: "ERR_EDIT_CONFLICT",
 	}
 	ErrorCode_value = map[string]int32{
 		"ERR_SUCCESS":                0,
@@ -213,6 +445,14 @@ var (
 		"ERR_MESSAGE_TOO_LARGE":      200,
 		"ERR_SEND_TOO_FAST":          201,
 		"ERR_CONVERSATION_NOT_EXIST": 202,
+		"ERR_MESSAGE_NOT_EXIST":      203,
+		"ERR_EDIT_TIME_EXPIRED":      204,
+		"ERR_NOT_GROUP_MEMBER":       205,
+		"ERR_SCHEDULE_NOT_PENDING":   206,
+		"ERR_CONTENT_REJECTED":       207,
+		"ERR_NOT_CHATROOM_MEMBER":    208,
+		"ERR_CHATROOM_FULL":          209,
+		"ERR_EDIT_CONFLICT":          210,
 	}
 )
 
@@ -664,32 +904,49 @@ func (x *KickOutNotification) GetMessage() string {
 
 // 通用消息信息（各场景复用）
 type MessageInfo struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	ServerMsgId      string                 `protobuf:"bytes,1,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`                // ✅ 服务器消息 ID（发送时为空，由服务端生成）
-	ClientMsgId      string                 `protobuf:"bytes,2,opt,name=client_msg_id,json=clientMsgId,proto3" json:"client_msg_id,omitempty"`                // 客户端消息 ID
-	ConversationId   string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`         // 会话 ID
-	SenderId         string                 `protobuf:"bytes,4,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`                           // 发送者 ID
-	ReceiverId       string                 `protobuf:"bytes,5,opt,name=receiver_id,json=receiverId,proto3" json:"receiver_id,omitempty"`                     // 接收者 ID（单聊）
-	GroupId          string                 `protobuf:"bytes,6,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`                              // 群组 ID（群聊）
-	MessageType      int32                  `protobuf:"varint,7,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`                 // 消息类型
-	Content          []byte                 `protobuf:"bytes,8,opt,name=content,proto3" json:"content,omitempty"`                                             // 消息内容（JSON 字节）
-	SendTime         int64                  `protobuf:"varint,9,opt,name=send_time,json=sendTime,proto3" json:"send_time,omitempty"`                          // 发送时间
-	ServerTime       int64                  `protobuf:"varint,10,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`                   // 服务器时间
-	Seq              int64                  `protobuf:"varint,11,opt,name=seq,proto3" json:"seq,omitempty"`                                                   // 消息序列号（发送时为0，由服务端生成）
-	Status           int32                  `protobuf:"varint,12,opt,name=status,proto3" json:"status,omitempty"`                                             // 消息状态（同步时使用）
-	IsRead           bool                   `protobuf:"varint,13,opt,name=is_read,json=isRead,proto3" json:"is_read,omitempty"`                               // 是否已读（同步时使用）
-	CreateTime       int64                  `protobuf:"varint,14,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`                   // 创建时间（客户端创建消息的时间）
-	Extra            string                 `protobuf:"bytes,15,opt,name=extra,proto3" json:"extra,omitempty"`                                                // 扩展字段（JSON 字符串）
-	ReadBy           []string               `protobuf:"bytes,16,rep,name=read_by,json=readBy,proto3" json:"read_by,omitempty"`                                // 已读者 ID 列表（群聊）
-	ReadTime         int64                  `protobuf:"varint,17,opt,name=read_time,json=readTime,proto3" json:"read_time,omitempty"`                         // 读取时间（单聊）
-	IsDeleted        bool                   `protobuf:"varint,18,opt,name=is_deleted,json=isDeleted,proto3" json:"is_deleted,omitempty"`                      // 是否已删除
-	IsRevoked        bool                   `protobuf:"varint,19,opt,name=is_revoked,json=isRevoked,proto3" json:"is_revoked,omitempty"`                      // 是否已撤回
-	RevokedBy        string                 `protobuf:"bytes,20,opt,name=revoked_by,json=revokedBy,proto3" json:"revoked_by,omitempty"`                       // 撤回者 ID
-	RevokedTime      int64                  `protobuf:"varint,21,opt,name=revoked_time,json=revokedTime,proto3" json:"revoked_time,omitempty"`                // 撤回时间
-	AttachedInfo     string                 `protobuf:"bytes,22,opt,name=attached_info,json=attachedInfo,proto3" json:"attached_info,omitempty"`              // 附加信息
-	ConversationType int32                  `protobuf:"varint,23,opt,name=conversation_type,json=conversationType,proto3" json:"conversation_type,omitempty"` // 会话类型（1=单聊，2=群聊，3=聊天室，4=系统消息）
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	ServerMsgId         string                 `protobuf:"bytes,1,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`                             // ✅ 服务器消息 ID（发送时为空，由服务端生成）
+	ClientMsgId         string                 `protobuf:"bytes,2,opt,name=client_msg_id,json=clientMsgId,proto3" json:"client_msg_id,omitempty"`                             // 客户端消息 ID
+	ConversationId      string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`                      // 会话 ID
+	SenderId            string                 `protobuf:"bytes,4,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`                                        // 发送者 ID
+	ReceiverId          string                 `protobuf:"bytes,5,opt,name=receiver_id,json=receiverId,proto3" json:"receiver_id,omitempty"`                                  // 接收者 ID（单聊）
+	GroupId             string                 `protobuf:"bytes,6,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`                                           // 群组 ID（群聊）
+	MessageType         int32                  `protobuf:"varint,7,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`                              // 消息类型
+	Content             []byte                 `protobuf:"bytes,8,opt,name=content,proto3" json:"content,omitempty"`                                                          // 消息内容（JSON 字节）
+	SendTime            int64                  `protobuf:"varint,9,opt,name=send_time,json=sendTime,proto3" json:"send_time,omitempty"`                                       // 发送时间
+	ServerTime          int64                  `protobuf:"varint,10,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`                                // 服务器时间
+	Seq                 int64                  `protobuf:"varint,11,opt,name=seq,proto3" json:"seq,omitempty"`                                                                // 消息序列号（发送时为0，由服务端生成）
+	Status              int32                  `protobuf:"varint,12,opt,name=status,proto3" json:"status,omitempty"`                                                          // 消息状态（同步时使用）
+	IsRead              bool                   `protobuf:"varint,13,opt,name=is_read,json=isRead,proto3" json:"is_read,omitempty"`                                            // 是否已读（同步时使用）
+	CreateTime          int64                  `protobuf:"varint,14,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`                                // 创建时间（客户端创建消息的时间）
+	Extra               string                 `protobuf:"bytes,15,opt,name=extra,proto3" json:"extra,omitempty"`                                                             // 扩展字段（JSON 字符串）
+	ReadBy              []string               `protobuf:"bytes,16,rep,name=read_by,json=readBy,proto3" json:"read_by,omitempty"`                                             // 已读者 ID 列表（群聊）
+	ReadTime            int64                  `protobuf:"varint,17,opt,name=read_time,json=readTime,proto3" json:"read_time,omitempty"`                                      // 读取时间（单聊）
+	IsDeleted           bool                   `protobuf:"varint,18,opt,name=is_deleted,json=isDeleted,proto3" json:"is_deleted,omitempty"`                                   // 是否已删除
+	IsRevoked           bool                   `protobuf:"varint,19,opt,name=is_revoked,json=isRevoked,proto3" json:"is_revoked,omitempty"`                                   // 是否已撤回
+	RevokedBy           string                 `protobuf:"bytes,20,opt,name=revoked_by,json=revokedBy,proto3" json:"revoked_by,omitempty"`                                    // 撤回者 ID
+	RevokedTime         int64                  `protobuf:"varint,21,opt,name=revoked_time,json=revokedTime,proto3" json:"revoked_time,omitempty"`                             // 撤回时间
+	AttachedInfo        string                 `protobuf:"bytes,22,opt,name=attached_info,json=attachedInfo,proto3" json:"attached_info,omitempty"`                           // 附加信息
+	ConversationType    int32                  `protobuf:"varint,23,opt,name=conversation_type,json=conversationType,proto3" json:"conversation_type,omitempty"`              // 会话类型（1=单聊，2=群聊，3=聊天室，4=系统消息）
+	EditVersion         int32                  `protobuf:"varint,24,opt,name=edit_version,json=editVersion,proto3" json:"edit_version,omitempty"`                             // 编辑版本号（0 表示未编辑）
+	EditTime            int64                  `protobuf:"varint,25,opt,name=edit_time,json=editTime,proto3" json:"edit_time,omitempty"`                                      // 最后编辑时间
+	Reactions           []*ReactionSummary     `protobuf:"bytes,26,rep,name=reactions,proto3" json:"reactions,omitempty"`                                                     // 表情回应汇总（同步时使用）
+	ReplyToMsgId        string                 `protobuf:"bytes,27,opt,name=reply_to_msg_id,json=replyToMsgId,proto3" json:"reply_to_msg_id,omitempty"`                       // 引用回复的消息 ID（server_msg_id）
+	ReplyToSeq          int64                  `protobuf:"varint,28,opt,name=reply_to_seq,json=replyToSeq,proto3" json:"reply_to_seq,omitempty"`                              // 引用回复的消息 seq
+	QuotedMessage       *QuotedMessage         `protobuf:"bytes,29,opt,name=quoted_message,json=quotedMessage,proto3" json:"quoted_message,omitempty"`                        // 被引用消息的快照（服务端填充）
+	ThreadRootSeq       int64                  `protobuf:"varint,30,opt,name=thread_root_seq,json=threadRootSeq,proto3" json:"thread_root_seq,omitempty"`                     // 所属话题的根消息 seq（0 表示不在话题中）
+	ThreadReplyCount    int32                  `protobuf:"varint,31,opt,name=thread_reply_count,json=threadReplyCount,proto3" json:"thread_reply_count,omitempty"`            // 话题回复数（仅根消息）
+	ThreadLastReplyTime int64                  `protobuf:"varint,32,opt,name=thread_last_reply_time,json=threadLastReplyTime,proto3" json:"thread_last_reply_time,omitempty"` // 话题最后回复时间（仅根消息）
+	MentionUserIds      []string               `protobuf:"bytes,33,rep,name=mention_user_ids,json=mentionUserIds,proto3" json:"mention_user_ids,omitempty"`                   // @的用户 ID 列表（群聊）
+	MentionAll          bool                   `protobuf:"varint,34,opt,name=mention_all,json=mentionAll,proto3" json:"mention_all,omitempty"`                                // 是否 @所有人（群聊，仅群主/管理员）
+	ForwardFromMsgId    string                 `protobuf:"bytes,35,opt,name=forward_from_msg_id,json=forwardFromMsgId,proto3" json:"forward_from_msg_id,omitempty"`           // 转发来源消息的 server_msg_id（逐条转发时）
+	ExpireTtl           int32                  `protobuf:"varint,36,opt,name=expire_ttl,json=expireTtl,proto3" json:"expire_ttl,omitempty"`                                   // 阅后即焚时长（秒，0 表示不过期；发送时为 0 则使用会话默认设置）
+	ExpireMode          int32                  `protobuf:"varint,37,opt,name=expire_mode,json=expireMode,proto3" json:"expire_mode,omitempty"`                                // 计时方式（1: 从发送时开始, 2: 从第一次被读取时开始）
+	ExpireAt            int64                  `protobuf:"varint,38,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`                                      // 过期时间（毫秒，0 表示尚未开始计时，服务端填充）
+	ReadCount           int32                  `protobuf:"varint,39,opt,name=read_count,json=readCount,proto3" json:"read_count,omitempty"`                                   // 已读人数（群聊，仅自己发送的消息，同步时使用）
+	UnreadCount         int32                  `protobuf:"varint,40,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`                             // 未读人数（群聊，仅自己发送的消息，同步时使用）
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
 }
 
 func (x *MessageInfo) Reset() {
@@ -883,6 +1140,279 @@ func (x *MessageInfo) GetConversationType() int32 {
 	return 0
 }
 
+func (x *MessageInfo) GetEditVersion() int32 {
+	if x != nil {
+		return x.EditVersion
+	}
+	return 0
+}
+
+func (x *MessageInfo) GetEditTime() int64 {
+	if x != nil {
+		return x.EditTime
+	}
+	return 0
+}
+
+func (x *MessageInfo) GetReactions() []*ReactionSummary {
+	if x != nil {
+		return x.Reactions
+	}
+	return nil
+}
+
+func (x *MessageInfo) GetReplyToMsgId() string {
+	if x != nil {
+		return x.ReplyToMsgId
+	}
+	return ""
+}
+
+func (x *MessageInfo) GetReplyToSeq() int64 {
+	if x != nil {
+		return x.ReplyToSeq
+	}
+	return 0
+}
+
+func (x *MessageInfo) GetQuotedMessage() *QuotedMessage {
+	if x != nil {
+		return x.QuotedMessage
+	}
+	return nil
+}
+
+func (x *MessageInfo) GetThreadRootSeq() int64 {
+	if x != nil {
+		return x.ThreadRootSeq
+	}
+	return 0
+}
+
+func (x *MessageInfo) GetThreadReplyCount() int32 {
+	if x != nil {
+		return x.ThreadReplyCount
+	}
+	return 0
+}
+
+func (x *MessageInfo) GetThreadLastReplyTime() int64 {
+	if x != nil {
+		return x.ThreadLastReplyTime
+	}
+	return 0
+}
+
+func (x *MessageInfo) GetMentionUserIds() []string {
+	if x != nil {
+		return x.MentionUserIds
+	}
+	return nil
+}
+
+func (x *MessageInfo) GetMentionAll() bool {
+	if x != nil {
+		return x.MentionAll
+	}
+	return false
+}
+
+func (x *MessageInfo) GetForwardFromMsgId() string {
+	if x != nil {
+		return x.ForwardFromMsgId
+	}
+	return ""
+}
+
+func (x *MessageInfo) GetExpireTtl() int32 {
+	if x != nil {
+		return x.ExpireTtl
+	}
+	return 0
+}
+
+func (x *MessageInfo) GetExpireMode() int32 {
+	if x != nil {
+		return x.ExpireMode
+	}
+	return 0
+}
+
+func (x *MessageInfo) GetExpireAt() int64 {
+	if x != nil {
+		return x.ExpireAt
+	}
+	return 0
+}
+
+func (x *MessageInfo) GetReadCount() int32 {
+	if x != nil {
+		return x.ReadCount
+	}
+	return 0
+}
+
+func (x *MessageInfo) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+// 被引用消息快照
+type QuotedMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerMsgId   string                 `protobuf:"bytes,1,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`
+	Seq           int64                  `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	SenderId      string                 `protobuf:"bytes,3,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	MessageType   int32                  `protobuf:"varint,4,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	Content       []byte                 `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	SendTime      int64                  `protobuf:"varint,6,opt,name=send_time,json=sendTime,proto3" json:"send_time,omitempty"`
+	Status        int32                  `protobuf:"varint,7,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuotedMessage) Reset() {
+	*x = QuotedMessage{}
+	mi := &file_im_protocol_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuotedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuotedMessage) ProtoMessage() {}
+
+func (x *QuotedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuotedMessage.ProtoReflect.Descriptor instead.
+func (*QuotedMessage) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *QuotedMessage) GetServerMsgId() string {
+	if x != nil {
+		return x.ServerMsgId
+	}
+	return ""
+}
+
+func (x *QuotedMessage) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *QuotedMessage) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *QuotedMessage) GetMessageType() int32 {
+	if x != nil {
+		return x.MessageType
+	}
+	return 0
+}
+
+func (x *QuotedMessage) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *QuotedMessage) GetSendTime() int64 {
+	if x != nil {
+		return x.SendTime
+	}
+	return 0
+}
+
+func (x *QuotedMessage) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+// 表情回应汇总（按表情聚合）
+type ReactionSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Emoji         string                 `protobuf:"bytes,1,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`                                  // 回应人数
+	ReactedByMe   bool                   `protobuf:"varint,3,opt,name=reacted_by_me,json=reactedByMe,proto3" json:"reacted_by_me,omitempty"` // 当前用户是否回应了该表情
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReactionSummary) Reset() {
+	*x = ReactionSummary{}
+	mi := &file_im_protocol_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactionSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactionSummary) ProtoMessage() {}
+
+func (x *ReactionSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactionSummary.ProtoReflect.Descriptor instead.
+func (*ReactionSummary) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReactionSummary) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *ReactionSummary) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ReactionSummary) GetReactedByMe() bool {
+	if x != nil {
+		return x.ReactedByMe
+	}
+	return false
+}
+
 // 发送消息请求
 type SendMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -893,7 +1423,7 @@ type SendMessageRequest struct {
 
 func (x *SendMessageRequest) Reset() {
 	*x = SendMessageRequest{}
-	mi := &file_im_protocol_proto_msgTypes[8]
+	mi := &file_im_protocol_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -905,7 +1435,7 @@ func (x *SendMessageRequest) String() string {
 func (*SendMessageRequest) ProtoMessage() {}
 
 func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[8]
+	mi := &file_im_protocol_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -918,7 +1448,7 @@ func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
 func (*SendMessageRequest) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{8}
+	return file_im_protocol_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *SendMessageRequest) GetMessage() *MessageInfo {
@@ -933,17 +1463,19 @@ type SendMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
 	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
-	ServerMsgId   string                 `protobuf:"bytes,3,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"` // ✅ 服务器消息 ID
-	ClientMsgId   string                 `protobuf:"bytes,4,opt,name=client_msg_id,json=clientMsgId,proto3" json:"client_msg_id,omitempty"` // 客户端消息 ID（用于匹配本地消息）
-	Seq           int64                  `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`                                     // 消息序列号
-	ServerTime    int64                  `protobuf:"varint,6,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`     // 服务器时间
+	ServerMsgId   string                 `protobuf:"bytes,3,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`      // ✅ 服务器消息 ID
+	ClientMsgId   string                 `protobuf:"bytes,4,opt,name=client_msg_id,json=clientMsgId,proto3" json:"client_msg_id,omitempty"`      // 客户端消息 ID（用于匹配本地消息）
+	Seq           int64                  `protobuf:"varint,5,opt,name=seq,proto3" json:"seq,omitempty"`                                          // 消息序列号
+	ServerTime    int64                  `protobuf:"varint,6,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`          // 服务器时间
+	ContentMasked bool                   `protobuf:"varint,7,opt,name=content_masked,json=contentMasked,proto3" json:"content_masked,omitempty"` // 内容中的敏感词已被替换
+	Content       []byte                 `protobuf:"bytes,8,opt,name=content,proto3" json:"content,omitempty"`                                   // 实际保存的内容（仅 content_masked 为 true 时返回）
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SendMessageResponse) Reset() {
 	*x = SendMessageResponse{}
-	mi := &file_im_protocol_proto_msgTypes[9]
+	mi := &file_im_protocol_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -955,7 +1487,7 @@ func (x *SendMessageResponse) String() string {
 func (*SendMessageResponse) ProtoMessage() {}
 
 func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[9]
+	mi := &file_im_protocol_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -968,7 +1500,7 @@ func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
 func (*SendMessageResponse) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{9}
+	return file_im_protocol_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *SendMessageResponse) GetErrorCode() ErrorCode {
@@ -1013,6 +1545,20 @@ func (x *SendMessageResponse) GetServerTime() int64 {
 	return 0
 }
 
+func (x *SendMessageResponse) GetContentMasked() bool {
+	if x != nil {
+		return x.ContentMasked
+	}
+	return false
+}
+
+func (x *SendMessageResponse) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
 // 推送消息
 type PushMessage struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1023,7 +1569,7 @@ type PushMessage struct {
 
 func (x *PushMessage) Reset() {
 	*x = PushMessage{}
-	mi := &file_im_protocol_proto_msgTypes[10]
+	mi := &file_im_protocol_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1035,7 +1581,7 @@ func (x *PushMessage) String() string {
 func (*PushMessage) ProtoMessage() {}
 
 func (x *PushMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[10]
+	mi := &file_im_protocol_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1048,7 +1594,7 @@ func (x *PushMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PushMessage.ProtoReflect.Descriptor instead.
 func (*PushMessage) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{10}
+	return file_im_protocol_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *PushMessage) GetMessage() *MessageInfo {
@@ -1069,7 +1615,7 @@ type MessageAck struct {
 
 func (x *MessageAck) Reset() {
 	*x = MessageAck{}
-	mi := &file_im_protocol_proto_msgTypes[11]
+	mi := &file_im_protocol_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1081,7 +1627,7 @@ func (x *MessageAck) String() string {
 func (*MessageAck) ProtoMessage() {}
 
 func (x *MessageAck) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[11]
+	mi := &file_im_protocol_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1094,7 +1640,7 @@ func (x *MessageAck) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageAck.ProtoReflect.Descriptor instead.
 func (*MessageAck) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{11}
+	return file_im_protocol_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *MessageAck) GetServerMsgId() string {
@@ -1121,7 +1667,7 @@ type BatchMessages struct {
 
 func (x *BatchMessages) Reset() {
 	*x = BatchMessages{}
-	mi := &file_im_protocol_proto_msgTypes[12]
+	mi := &file_im_protocol_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1133,7 +1679,7 @@ func (x *BatchMessages) String() string {
 func (*BatchMessages) ProtoMessage() {}
 
 func (x *BatchMessages) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[12]
+	mi := &file_im_protocol_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1146,7 +1692,7 @@ func (x *BatchMessages) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BatchMessages.ProtoReflect.Descriptor instead.
 func (*BatchMessages) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{12}
+	return file_im_protocol_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *BatchMessages) GetMessages() []*PushMessage {
@@ -1167,7 +1713,7 @@ type RevokeMessageRequest struct {
 
 func (x *RevokeMessageRequest) Reset() {
 	*x = RevokeMessageRequest{}
-	mi := &file_im_protocol_proto_msgTypes[13]
+	mi := &file_im_protocol_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1179,7 +1725,7 @@ func (x *RevokeMessageRequest) String() string {
 func (*RevokeMessageRequest) ProtoMessage() {}
 
 func (x *RevokeMessageRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[13]
+	mi := &file_im_protocol_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1192,7 +1738,7 @@ func (x *RevokeMessageRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RevokeMessageRequest.ProtoReflect.Descriptor instead.
 func (*RevokeMessageRequest) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{13}
+	return file_im_protocol_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *RevokeMessageRequest) GetServerMsgId() string {
@@ -1220,7 +1766,7 @@ type RevokeMessageResponse struct {
 
 func (x *RevokeMessageResponse) Reset() {
 	*x = RevokeMessageResponse{}
-	mi := &file_im_protocol_proto_msgTypes[14]
+	mi := &file_im_protocol_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1232,7 +1778,7 @@ func (x *RevokeMessageResponse) String() string {
 func (*RevokeMessageResponse) ProtoMessage() {}
 
 func (x *RevokeMessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[14]
+	mi := &file_im_protocol_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1245,7 +1791,7 @@ func (x *RevokeMessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RevokeMessageResponse.ProtoReflect.Descriptor instead.
 func (*RevokeMessageResponse) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{14}
+	return file_im_protocol_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *RevokeMessageResponse) GetErrorCode() ErrorCode {
@@ -1275,7 +1821,7 @@ type RevokeMessagePush struct {
 
 func (x *RevokeMessagePush) Reset() {
 	*x = RevokeMessagePush{}
-	mi := &file_im_protocol_proto_msgTypes[15]
+	mi := &file_im_protocol_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1287,7 +1833,7 @@ func (x *RevokeMessagePush) String() string {
 func (*RevokeMessagePush) ProtoMessage() {}
 
 func (x *RevokeMessagePush) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[15]
+	mi := &file_im_protocol_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1300,7 +1846,7 @@ func (x *RevokeMessagePush) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RevokeMessagePush.ProtoReflect.Descriptor instead.
 func (*RevokeMessagePush) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{15}
+	return file_im_protocol_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *RevokeMessagePush) GetServerMsgId() string {
@@ -1331,30 +1877,31 @@ func (x *RevokeMessagePush) GetRevokedTime() int64 {
 	return 0
 }
 
-// 会话同步状态（客户端本地状态）
-type ConversationSyncState struct {
+// 编辑消息请求
+type EditMessageRequest struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
-	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"` // 会话ID
-	LastSeq        int64                  `protobuf:"varint,2,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"`                     // 本地最大 seq
+	ServerMsgId    string                 `protobuf:"bytes,1,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"` // 服务器消息 ID
+	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Content        []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"` // 新的消息内容
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ConversationSyncState) Reset() {
-	*x = ConversationSyncState{}
-	mi := &file_im_protocol_proto_msgTypes[16]
+func (x *EditMessageRequest) Reset() {
+	*x = EditMessageRequest{}
+	mi := &file_im_protocol_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConversationSyncState) String() string {
+func (x *EditMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConversationSyncState) ProtoMessage() {}
+func (*EditMessageRequest) ProtoMessage() {}
 
-func (x *ConversationSyncState) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[16]
+func (x *EditMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1365,49 +1912,61 @@ func (x *ConversationSyncState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConversationSyncState.ProtoReflect.Descriptor instead.
-func (*ConversationSyncState) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use EditMessageRequest.ProtoReflect.Descriptor instead.
+func (*EditMessageRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *ConversationSyncState) GetConversationId() string {
+func (x *EditMessageRequest) GetServerMsgId() string {
+	if x != nil {
+		return x.ServerMsgId
+	}
+	return ""
+}
+
+func (x *EditMessageRequest) GetConversationId() string {
 	if x != nil {
 		return x.ConversationId
 	}
 	return ""
 }
 
-func (x *ConversationSyncState) GetLastSeq() int64 {
+func (x *EditMessageRequest) GetContent() []byte {
 	if x != nil {
-		return x.LastSeq
+		return x.Content
 	}
-	return 0
+	return nil
 }
 
-// 批量同步请求（一次性同步所有会话）
-type BatchSyncRequest struct {
-	state                   protoimpl.MessageState   `protogen:"open.v1"`
-	ConversationStates      []*ConversationSyncState `protobuf:"bytes,1,rep,name=conversation_states,json=conversationStates,proto3" json:"conversation_states,omitempty"`                     // 客户端的会话同步状态（可为空，表示首次全量同步）
-	MaxCountPerConversation int32                    `protobuf:"varint,2,opt,name=max_count_per_conversation,json=maxCountPerConversation,proto3" json:"max_count_per_conversation,omitempty"` // 每个会话最多拉取的消息数（默认100，最大500）
-	unknownFields           protoimpl.UnknownFields
-	sizeCache               protoimpl.SizeCache
+// 编辑消息响应
+type EditMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ServerMsgId   string                 `protobuf:"bytes,3,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`
+	EditVersion   int32                  `protobuf:"varint,4,opt,name=edit_version,json=editVersion,proto3" json:"edit_version,omitempty"`       // 编辑后的版本号
+	EditTime      int64                  `protobuf:"varint,5,opt,name=edit_time,json=editTime,proto3" json:"edit_time,omitempty"`                // 编辑时间
+	ContentMasked bool                   `protobuf:"varint,6,opt,name=content_masked,json=contentMasked,proto3" json:"content_masked,omitempty"` // 内容中的敏感词已被替换
+	Content       []byte                 `protobuf:"bytes,7,opt,name=content,proto3" json:"content,omitempty"`                                   // 实际保存的内容（仅 content_masked 为 true 时返回）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BatchSyncRequest) Reset() {
-	*x = BatchSyncRequest{}
-	mi := &file_im_protocol_proto_msgTypes[17]
+func (x *EditMessageResponse) Reset() {
+	*x = EditMessageResponse{}
+	mi := &file_im_protocol_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BatchSyncRequest) String() string {
+func (x *EditMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BatchSyncRequest) ProtoMessage() {}
+func (*EditMessageResponse) ProtoMessage() {}
 
-func (x *BatchSyncRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[17]
+func (x *EditMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1418,52 +1977,89 @@ func (x *BatchSyncRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BatchSyncRequest.ProtoReflect.Descriptor instead.
-func (*BatchSyncRequest) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use EditMessageResponse.ProtoReflect.Descriptor instead.
+func (*EditMessageResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *BatchSyncRequest) GetConversationStates() []*ConversationSyncState {
+func (x *EditMessageResponse) GetErrorCode() ErrorCode {
 	if x != nil {
-		return x.ConversationStates
+		return x.ErrorCode
 	}
-	return nil
+	return ErrorCode_ERR_SUCCESS
 }
 
-func (x *BatchSyncRequest) GetMaxCountPerConversation() int32 {
+func (x *EditMessageResponse) GetErrorMsg() string {
 	if x != nil {
-		return x.MaxCountPerConversation
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *EditMessageResponse) GetServerMsgId() string {
+	if x != nil {
+		return x.ServerMsgId
+	}
+	return ""
+}
+
+func (x *EditMessageResponse) GetEditVersion() int32 {
+	if x != nil {
+		return x.EditVersion
 	}
 	return 0
 }
 
-// 会话消息结果
-type ConversationMessages struct {
+func (x *EditMessageResponse) GetEditTime() int64 {
+	if x != nil {
+		return x.EditTime
+	}
+	return 0
+}
+
+func (x *EditMessageResponse) GetContentMasked() bool {
+	if x != nil {
+		return x.ContentMasked
+	}
+	return false
+}
+
+func (x *EditMessageResponse) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+// 编辑消息推送
+type EditMessagePush struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
-	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"` // 会话ID
-	Messages       []*MessageInfo         `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`                                   // 消息列表
-	MaxSeq         int64                  `protobuf:"varint,3,opt,name=max_seq,json=maxSeq,proto3" json:"max_seq,omitempty"`                        // 服务端该会话的最大 seq
-	SyncedSeq      int64                  `protobuf:"varint,4,opt,name=synced_seq,json=syncedSeq,proto3" json:"synced_seq,omitempty"`               // 本次同步到的 seq（可能小于 max_seq，因为有 max_count 限制）
-	HasMore        bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`                     // 是否还有更多消息未同步
+	ServerMsgId    string                 `protobuf:"bytes,1,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`
+	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seq            int64                  `protobuf:"varint,3,opt,name=seq,proto3" json:"seq,omitempty"`
+	Content        []byte                 `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"` // 编辑后的内容
+	EditedBy       string                 `protobuf:"bytes,5,opt,name=edited_by,json=editedBy,proto3" json:"edited_by,omitempty"`
+	EditVersion    int32                  `protobuf:"varint,6,opt,name=edit_version,json=editVersion,proto3" json:"edit_version,omitempty"`
+	EditTime       int64                  `protobuf:"varint,7,opt,name=edit_time,json=editTime,proto3" json:"edit_time,omitempty"`
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *ConversationMessages) Reset() {
-	*x = ConversationMessages{}
-	mi := &file_im_protocol_proto_msgTypes[18]
+func (x *EditMessagePush) Reset() {
+	*x = EditMessagePush{}
+	mi := &file_im_protocol_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ConversationMessages) String() string {
+func (x *EditMessagePush) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ConversationMessages) ProtoMessage() {}
+func (*EditMessagePush) ProtoMessage() {}
 
-func (x *ConversationMessages) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[18]
+func (x *EditMessagePush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1474,73 +2070,84 @@ func (x *ConversationMessages) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ConversationMessages.ProtoReflect.Descriptor instead.
-func (*ConversationMessages) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use EditMessagePush.ProtoReflect.Descriptor instead.
+func (*EditMessagePush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *ConversationMessages) GetConversationId() string {
+func (x *EditMessagePush) GetServerMsgId() string {
+	if x != nil {
+		return x.ServerMsgId
+	}
+	return ""
+}
+
+func (x *EditMessagePush) GetConversationId() string {
 	if x != nil {
 		return x.ConversationId
 	}
 	return ""
 }
 
-func (x *ConversationMessages) GetMessages() []*MessageInfo {
+func (x *EditMessagePush) GetSeq() int64 {
 	if x != nil {
-		return x.Messages
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *EditMessagePush) GetContent() []byte {
+	if x != nil {
+		return x.Content
 	}
 	return nil
 }
 
-func (x *ConversationMessages) GetMaxSeq() int64 {
+func (x *EditMessagePush) GetEditedBy() string {
 	if x != nil {
-		return x.MaxSeq
+		return x.EditedBy
 	}
-	return 0
+	return ""
 }
 
-func (x *ConversationMessages) GetSyncedSeq() int64 {
+func (x *EditMessagePush) GetEditVersion() int32 {
 	if x != nil {
-		return x.SyncedSeq
+		return x.EditVersion
 	}
 	return 0
 }
 
-func (x *ConversationMessages) GetHasMore() bool {
+func (x *EditMessagePush) GetEditTime() int64 {
 	if x != nil {
-		return x.HasMore
+		return x.EditTime
 	}
-	return false
+	return 0
 }
 
-// 批量同步响应
-type BatchSyncResponse struct {
-	state                protoimpl.MessageState  `protogen:"open.v1"`
-	ErrorCode            ErrorCode               `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
-	ErrorMsg             string                  `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
-	ConversationMessages []*ConversationMessages `protobuf:"bytes,3,rep,name=conversation_messages,json=conversationMessages,proto3" json:"conversation_messages,omitempty"` // 所有会话的消息
-	ServerTime           int64                   `protobuf:"varint,4,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`                              // 服务器时间
-	TotalMessageCount    int32                   `protobuf:"varint,5,opt,name=total_message_count,json=totalMessageCount,proto3" json:"total_message_count,omitempty"`       // 本次同步的总消息数
-	unknownFields        protoimpl.UnknownFields
-	sizeCache            protoimpl.SizeCache
+// 查询消息编辑历史请求
+type EditHistoryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seq            int64                  `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"` // 消息在会话内的 seq
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *BatchSyncResponse) Reset() {
-	*x = BatchSyncResponse{}
-	mi := &file_im_protocol_proto_msgTypes[19]
+func (x *EditHistoryRequest) Reset() {
+	*x = EditHistoryRequest{}
+	mi := &file_im_protocol_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BatchSyncResponse) String() string {
+func (x *EditHistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BatchSyncResponse) ProtoMessage() {}
+func (*EditHistoryRequest) ProtoMessage() {}
 
-func (x *BatchSyncResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[19]
+func (x *EditHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1551,73 +2158,52 @@ func (x *BatchSyncResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BatchSyncResponse.ProtoReflect.Descriptor instead.
-func (*BatchSyncResponse) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{19}
-}
-
-func (x *BatchSyncResponse) GetErrorCode() ErrorCode {
-	if x != nil {
-		return x.ErrorCode
-	}
-	return ErrorCode_ERR_SUCCESS
+// Deprecated: Use EditHistoryRequest.ProtoReflect.Descriptor instead.
+func (*EditHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *BatchSyncResponse) GetErrorMsg() string {
+func (x *EditHistoryRequest) GetConversationId() string {
 	if x != nil {
-		return x.ErrorMsg
+		return x.ConversationId
 	}
 	return ""
 }
 
-func (x *BatchSyncResponse) GetConversationMessages() []*ConversationMessages {
-	if x != nil {
-		return x.ConversationMessages
-	}
-	return nil
-}
-
-func (x *BatchSyncResponse) GetServerTime() int64 {
-	if x != nil {
-		return x.ServerTime
-	}
-	return 0
-}
-
-func (x *BatchSyncResponse) GetTotalMessageCount() int32 {
+func (x *EditHistoryRequest) GetSeq() int64 {
 	if x != nil {
-		return x.TotalMessageCount
+		return x.Seq
 	}
 	return 0
 }
 
-// 范围同步请求
-type SyncRangeRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	RequestId      string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`                // 请求唯一标识（由客户端生成，用于响应匹配）
-	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"` // 会话ID（必填）
-	StartSeq       int64                  `protobuf:"varint,3,opt,name=start_seq,json=startSeq,proto3" json:"start_seq,omitempty"`                  // 起始 seq（包含）
-	EndSeq         int64                  `protobuf:"varint,4,opt,name=end_seq,json=endSeq,proto3" json:"end_seq,omitempty"`                        // 结束 seq（包含）
-	Count          int32                  `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`                                        // 单次拉取数量限制（默认100，最大500）
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// 一次编辑记录
+type MessageEditRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EditVersion   int32                  `protobuf:"varint,1,opt,name=edit_version,json=editVersion,proto3" json:"edit_version,omitempty"` // 本次编辑产生的版本号
+	EditorId      string                 `protobuf:"bytes,2,opt,name=editor_id,json=editorId,proto3" json:"editor_id,omitempty"`
+	OldContent    []byte                 `protobuf:"bytes,3,opt,name=old_content,json=oldContent,proto3" json:"old_content,omitempty"` // 编辑前的内容
+	NewContent    []byte                 `protobuf:"bytes,4,opt,name=new_content,json=newContent,proto3" json:"new_content,omitempty"` // 编辑后的内容
+	EditTime      int64                  `protobuf:"varint,5,opt,name=edit_time,json=editTime,proto3" json:"edit_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SyncRangeRequest) Reset() {
-	*x = SyncRangeRequest{}
-	mi := &file_im_protocol_proto_msgTypes[20]
+func (x *MessageEditRecord) Reset() {
+	*x = MessageEditRecord{}
+	mi := &file_im_protocol_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SyncRangeRequest) String() string {
+func (x *MessageEditRecord) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncRangeRequest) ProtoMessage() {}
+func (*MessageEditRecord) ProtoMessage() {}
 
-func (x *SyncRangeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[20]
+func (x *MessageEditRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1628,76 +2214,73 @@ func (x *SyncRangeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncRangeRequest.ProtoReflect.Descriptor instead.
-func (*SyncRangeRequest) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use MessageEditRecord.ProtoReflect.Descriptor instead.
+func (*MessageEditRecord) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *SyncRangeRequest) GetRequestId() string {
+func (x *MessageEditRecord) GetEditVersion() int32 {
 	if x != nil {
-		return x.RequestId
+		return x.EditVersion
 	}
-	return ""
+	return 0
 }
 
-func (x *SyncRangeRequest) GetConversationId() string {
+func (x *MessageEditRecord) GetEditorId() string {
 	if x != nil {
-		return x.ConversationId
+		return x.EditorId
 	}
 	return ""
 }
 
-func (x *SyncRangeRequest) GetStartSeq() int64 {
+func (x *MessageEditRecord) GetOldContent() []byte {
 	if x != nil {
-		return x.StartSeq
+		return x.OldContent
 	}
-	return 0
+	return nil
 }
 
-func (x *SyncRangeRequest) GetEndSeq() int64 {
+func (x *MessageEditRecord) GetNewContent() []byte {
 	if x != nil {
-		return x.EndSeq
+		return x.NewContent
 	}
-	return 0
+	return nil
 }
 
-func (x *SyncRangeRequest) GetCount() int32 {
+func (x *MessageEditRecord) GetEditTime() int64 {
 	if x != nil {
-		return x.Count
+		return x.EditTime
 	}
 	return 0
 }
 
-// 范围同步响应
-type SyncRangeResponse struct {
+// 查询消息编辑历史响应
+type EditHistoryResponse struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
 	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
-	RequestId      string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`                // 对应请求的 request_id
-	ConversationId string                 `protobuf:"bytes,4,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"` // 会话ID
-	Messages       []*MessageInfo         `protobuf:"bytes,5,rep,name=messages,proto3" json:"messages,omitempty"`                                   // 消息列表
-	StartSeq       int64                  `protobuf:"varint,6,opt,name=start_seq,json=startSeq,proto3" json:"start_seq,omitempty"`                  // 实际返回的起始 seq
-	EndSeq         int64                  `protobuf:"varint,7,opt,name=end_seq,json=endSeq,proto3" json:"end_seq,omitempty"`                        // 实际返回的结束 seq
-	HasMore        bool                   `protobuf:"varint,8,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`                     // 是否还有更多消息（如果请求范围过大，需要分批拉取）
+	ConversationId string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seq            int64                  `protobuf:"varint,4,opt,name=seq,proto3" json:"seq,omitempty"`
+	Edits          []*MessageEditRecord   `protobuf:"bytes,5,rep,name=edits,proto3" json:"edits,omitempty"` // 按版本升序
 	unknownFields  protoimpl.UnknownFields
 	sizeCache      protoimpl.SizeCache
 }
 
-func (x *SyncRangeResponse) Reset() {
-	*x = SyncRangeResponse{}
-	mi := &file_im_protocol_proto_msgTypes[21]
+func (x *EditHistoryResponse) Reset() {
+	*x = EditHistoryResponse{}
+	mi := &file_im_protocol_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SyncRangeResponse) String() string {
+func (x *EditHistoryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncRangeResponse) ProtoMessage() {}
+func (*EditHistoryResponse) ProtoMessage() {}
 
-func (x *SyncRangeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[21]
+func (x *EditHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1708,91 +2291,5573 @@ func (x *SyncRangeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncRangeResponse.ProtoReflect.Descriptor instead.
-func (*SyncRangeResponse) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use EditHistoryResponse.ProtoReflect.Descriptor instead.
+func (*EditHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *SyncRangeResponse) GetErrorCode() ErrorCode {
+func (x *EditHistoryResponse) GetErrorCode() ErrorCode {
 	if x != nil {
 		return x.ErrorCode
 	}
 	return ErrorCode_ERR_SUCCESS
 }
 
-func (x *SyncRangeResponse) GetErrorMsg() string {
+func (x *EditHistoryResponse) GetErrorMsg() string {
 	if x != nil {
 		return x.ErrorMsg
 	}
 	return ""
 }
 
-func (x *SyncRangeResponse) GetRequestId() string {
+func (x *EditHistoryResponse) GetConversationId() string {
 	if x != nil {
-		return x.RequestId
+		return x.ConversationId
 	}
 	return ""
 }
 
-func (x *SyncRangeResponse) GetConversationId() string {
+func (x *EditHistoryResponse) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *EditHistoryResponse) GetEdits() []*MessageEditRecord {
+	if x != nil {
+		return x.Edits
+	}
+	return nil
+}
+
+// 表情回应请求（添加和取消共用）
+type ReactionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seq            int64                  `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"` // 消息在会话内的 seq
+	Emoji          string                 `protobuf:"bytes,3,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReactionRequest) Reset() {
+	*x = ReactionRequest{}
+	mi := &file_im_protocol_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactionRequest) ProtoMessage() {}
+
+func (x *ReactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactionRequest.ProtoReflect.Descriptor instead.
+func (*ReactionRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ReactionRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ReactionRequest) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ReactionRequest) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+// 表情回应响应
+type ReactionResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ConversationId string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seq            int64                  `protobuf:"varint,4,opt,name=seq,proto3" json:"seq,omitempty"`
+	Emoji          string                 `protobuf:"bytes,5,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	Count          int32                  `protobuf:"varint,6,opt,name=count,proto3" json:"count,omitempty"` // 操作后该表情的回应人数
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReactionResponse) Reset() {
+	*x = ReactionResponse{}
+	mi := &file_im_protocol_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactionResponse) ProtoMessage() {}
+
+func (x *ReactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactionResponse.ProtoReflect.Descriptor instead.
+func (*ReactionResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ReactionResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *ReactionResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ReactionResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ReactionResponse) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ReactionResponse) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *ReactionResponse) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 表情回应变化推送
+type ReactionPush struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seq            int64                  `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	ServerMsgId    string                 `protobuf:"bytes,3,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`
+	Emoji          string                 `protobuf:"bytes,4,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	UserId         string                 `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // 操作者
+	Action         int32                  `protobuf:"varint,6,opt,name=action,proto3" json:"action,omitempty"`              // 1: 添加, 2: 取消
+	Count          int32                  `protobuf:"varint,7,opt,name=count,proto3" json:"count,omitempty"`                // 操作后该表情的回应人数
+	Time           int64                  `protobuf:"varint,8,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReactionPush) Reset() {
+	*x = ReactionPush{}
+	mi := &file_im_protocol_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactionPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactionPush) ProtoMessage() {}
+
+func (x *ReactionPush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactionPush.ProtoReflect.Descriptor instead.
+func (*ReactionPush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ReactionPush) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ReactionPush) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *ReactionPush) GetServerMsgId() string {
+	if x != nil {
+		return x.ServerMsgId
+	}
+	return ""
+}
+
+func (x *ReactionPush) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *ReactionPush) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReactionPush) GetAction() int32 {
+	if x != nil {
+		return x.Action
+	}
+	return 0
+}
+
+func (x *ReactionPush) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ReactionPush) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+// 转发来源消息
+type ForwardSource struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seq            int64                  `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ForwardSource) Reset() {
+	*x = ForwardSource{}
+	mi := &file_im_protocol_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardSource) ProtoMessage() {}
+
+func (x *ForwardSource) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardSource.ProtoReflect.Descriptor instead.
+func (*ForwardSource) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ForwardSource) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ForwardSource) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+// 转发目标会话
+type ForwardTarget struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ConversationType int32                  `protobuf:"varint,1,opt,name=conversation_type,json=conversationType,proto3" json:"conversation_type,omitempty"` // 1: 单聊, 2: 群聊
+	TargetId         string                 `protobuf:"bytes,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`                          // 单聊为接收者 ID，群聊为群 ID
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ForwardTarget) Reset() {
+	*x = ForwardTarget{}
+	mi := &file_im_protocol_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardTarget) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardTarget) ProtoMessage() {}
+
+func (x *ForwardTarget) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardTarget.ProtoReflect.Descriptor instead.
+func (*ForwardTarget) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ForwardTarget) GetConversationType() int32 {
+	if x != nil {
+		return x.ConversationType
+	}
+	return 0
+}
+
+func (x *ForwardTarget) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+// 转发消息请求
+type ForwardMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Sources       []*ForwardSource       `protobuf:"bytes,2,rep,name=sources,proto3" json:"sources,omitempty"`                            // 最多 100 条
+	Targets       []*ForwardTarget       `protobuf:"bytes,3,rep,name=targets,proto3" json:"targets,omitempty"`                            // 最多 20 个
+	Merged        bool                   `protobuf:"varint,4,opt,name=merged,proto3" json:"merged,omitempty"`                             // true: 合并转发为一条聊天记录消息, false: 逐条转发
+	MergedTitle   string                 `protobuf:"bytes,5,opt,name=merged_title,json=mergedTitle,proto3" json:"merged_title,omitempty"` // 合并转发标题（如"群聊的聊天记录"）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForwardMessageRequest) Reset() {
+	*x = ForwardMessageRequest{}
+	mi := &file_im_protocol_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardMessageRequest) ProtoMessage() {}
+
+func (x *ForwardMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardMessageRequest.ProtoReflect.Descriptor instead.
+func (*ForwardMessageRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ForwardMessageRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ForwardMessageRequest) GetSources() []*ForwardSource {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *ForwardMessageRequest) GetTargets() []*ForwardTarget {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+func (x *ForwardMessageRequest) GetMerged() bool {
+	if x != nil {
+		return x.Merged
+	}
+	return false
+}
+
+func (x *ForwardMessageRequest) GetMergedTitle() string {
+	if x != nil {
+		return x.MergedTitle
+	}
+	return ""
+}
+
+// 单个目标会话的转发结果
+type ForwardResult struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	ErrorCode      ErrorCode              `protobuf:"varint,2,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,3,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ServerMsgIds   []string               `protobuf:"bytes,4,rep,name=server_msg_ids,json=serverMsgIds,proto3" json:"server_msg_ids,omitempty"` // 新生成的消息
+	Seqs           []int64                `protobuf:"varint,5,rep,packed,name=seqs,proto3" json:"seqs,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ForwardResult) Reset() {
+	*x = ForwardResult{}
+	mi := &file_im_protocol_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardResult) ProtoMessage() {}
+
+func (x *ForwardResult) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardResult.ProtoReflect.Descriptor instead.
+func (*ForwardResult) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ForwardResult) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ForwardResult) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *ForwardResult) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ForwardResult) GetServerMsgIds() []string {
+	if x != nil {
+		return x.ServerMsgIds
+	}
+	return nil
+}
+
+func (x *ForwardResult) GetSeqs() []int64 {
+	if x != nil {
+		return x.Seqs
+	}
+	return nil
+}
+
+// 转发消息响应
+type ForwardMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	RequestId     string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Results       []*ForwardResult       `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"` // 与请求中的 targets 一一对应
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForwardMessageResponse) Reset() {
+	*x = ForwardMessageResponse{}
+	mi := &file_im_protocol_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForwardMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForwardMessageResponse) ProtoMessage() {}
+
+func (x *ForwardMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForwardMessageResponse.ProtoReflect.Descriptor instead.
+func (*ForwardMessageResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ForwardMessageResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *ForwardMessageResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ForwardMessageResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ForwardMessageResponse) GetResults() []*ForwardResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// 删除消息请求（仅对自己隐藏）
+type DeleteMessageRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seqs           []int64                `protobuf:"varint,2,rep,packed,name=seqs,proto3" json:"seqs,omitempty"` // 最多 100 条
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeleteMessageRequest) Reset() {
+	*x = DeleteMessageRequest{}
+	mi := &file_im_protocol_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMessageRequest) ProtoMessage() {}
+
+func (x *DeleteMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMessageRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMessageRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteMessageRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *DeleteMessageRequest) GetSeqs() []int64 {
+	if x != nil {
+		return x.Seqs
+	}
+	return nil
+}
+
+// 删除消息响应
+type DeleteMessageResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ConversationId string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seqs           []int64                `protobuf:"varint,4,rep,packed,name=seqs,proto3" json:"seqs,omitempty"` // 实际删除的消息
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeleteMessageResponse) Reset() {
+	*x = DeleteMessageResponse{}
+	mi := &file_im_protocol_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMessageResponse) ProtoMessage() {}
+
+func (x *DeleteMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMessageResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMessageResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DeleteMessageResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *DeleteMessageResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *DeleteMessageResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *DeleteMessageResponse) GetSeqs() []int64 {
+	if x != nil {
+		return x.Seqs
+	}
+	return nil
+}
+
+// 清空会话历史请求
+type ClearHistoryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	ClearSeq       int64                  `protobuf:"varint,2,opt,name=clear_seq,json=clearSeq,proto3" json:"clear_seq,omitempty"` // 清空到该 seq（包含），0 表示清空到当前最新消息
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClearHistoryRequest) Reset() {
+	*x = ClearHistoryRequest{}
+	mi := &file_im_protocol_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearHistoryRequest) ProtoMessage() {}
+
+func (x *ClearHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ClearHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ClearHistoryRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ClearHistoryRequest) GetClearSeq() int64 {
+	if x != nil {
+		return x.ClearSeq
+	}
+	return 0
+}
+
+// 清空会话历史响应
+type ClearHistoryResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ConversationId string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	ClearedSeq     int64                  `protobuf:"varint,4,opt,name=cleared_seq,json=clearedSeq,proto3" json:"cleared_seq,omitempty"` // 生效的清空位置
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ClearHistoryResponse) Reset() {
+	*x = ClearHistoryResponse{}
+	mi := &file_im_protocol_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClearHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClearHistoryResponse) ProtoMessage() {}
+
+func (x *ClearHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClearHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ClearHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ClearHistoryResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *ClearHistoryResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ClearHistoryResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ClearHistoryResponse) GetClearedSeq() int64 {
+	if x != nil {
+		return x.ClearedSeq
+	}
+	return 0
+}
+
+// 删除/清空推送（推送给操作者本人的设备）
+type DeleteMessagePush struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seqs           []int64                `protobuf:"varint,2,rep,packed,name=seqs,proto3" json:"seqs,omitempty"`                        // 删除的消息（清空时为空）
+	ClearedSeq     int64                  `protobuf:"varint,3,opt,name=cleared_seq,json=clearedSeq,proto3" json:"cleared_seq,omitempty"` // 清空位置（删除时为 0）
+	Time           int64                  `protobuf:"varint,4,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DeleteMessagePush) Reset() {
+	*x = DeleteMessagePush{}
+	mi := &file_im_protocol_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMessagePush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMessagePush) ProtoMessage() {}
+
+func (x *DeleteMessagePush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMessagePush.ProtoReflect.Descriptor instead.
+func (*DeleteMessagePush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DeleteMessagePush) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *DeleteMessagePush) GetSeqs() []int64 {
+	if x != nil {
+		return x.Seqs
+	}
+	return nil
+}
+
+func (x *DeleteMessagePush) GetClearedSeq() int64 {
+	if x != nil {
+		return x.ClearedSeq
+	}
+	return 0
+}
+
+func (x *DeleteMessagePush) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+// 置顶消息信息
+type PinnedMessageInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Seq           int64                  `protobuf:"varint,1,opt,name=seq,proto3" json:"seq,omitempty"`
+	ServerMsgId   string                 `protobuf:"bytes,2,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`
+	PinnedBy      string                 `protobuf:"bytes,3,opt,name=pinned_by,json=pinnedBy,proto3" json:"pinned_by,omitempty"`  // 置顶操作者
+	PinnedAt      int64                  `protobuf:"varint,4,opt,name=pinned_at,json=pinnedAt,proto3" json:"pinned_at,omitempty"` // 置顶时间
+	Message       *MessageInfo           `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`                    // 完整消息（仅置顶列表查询时返回）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PinnedMessageInfo) Reset() {
+	*x = PinnedMessageInfo{}
+	mi := &file_im_protocol_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinnedMessageInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinnedMessageInfo) ProtoMessage() {}
+
+func (x *PinnedMessageInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinnedMessageInfo.ProtoReflect.Descriptor instead.
+func (*PinnedMessageInfo) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *PinnedMessageInfo) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *PinnedMessageInfo) GetServerMsgId() string {
+	if x != nil {
+		return x.ServerMsgId
+	}
+	return ""
+}
+
+func (x *PinnedMessageInfo) GetPinnedBy() string {
+	if x != nil {
+		return x.PinnedBy
+	}
+	return ""
+}
+
+func (x *PinnedMessageInfo) GetPinnedAt() int64 {
+	if x != nil {
+		return x.PinnedAt
+	}
+	return 0
+}
+
+func (x *PinnedMessageInfo) GetMessage() *MessageInfo {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+// 置顶/取消置顶请求
+type PinMessageRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seq            int64                  `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PinMessageRequest) Reset() {
+	*x = PinMessageRequest{}
+	mi := &file_im_protocol_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinMessageRequest) ProtoMessage() {}
+
+func (x *PinMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinMessageRequest.ProtoReflect.Descriptor instead.
+func (*PinMessageRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *PinMessageRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *PinMessageRequest) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+// 置顶/取消置顶响应
+type PinMessageResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ConversationId string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seq            int64                  `protobuf:"varint,4,opt,name=seq,proto3" json:"seq,omitempty"`
+	PinnedAt       int64                  `protobuf:"varint,5,opt,name=pinned_at,json=pinnedAt,proto3" json:"pinned_at,omitempty"` // 置顶时间（取消置顶时为 0）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PinMessageResponse) Reset() {
+	*x = PinMessageResponse{}
+	mi := &file_im_protocol_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinMessageResponse) ProtoMessage() {}
+
+func (x *PinMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinMessageResponse.ProtoReflect.Descriptor instead.
+func (*PinMessageResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PinMessageResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *PinMessageResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *PinMessageResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *PinMessageResponse) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *PinMessageResponse) GetPinnedAt() int64 {
+	if x != nil {
+		return x.PinnedAt
+	}
+	return 0
+}
+
+// 置顶变化推送
+type PinMessagePush struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seq            int64                  `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	ServerMsgId    string                 `protobuf:"bytes,3,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`
+	OperatorId     string                 `protobuf:"bytes,4,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	Action         int32                  `protobuf:"varint,5,opt,name=action,proto3" json:"action,omitempty"` // 1: 置顶, 2: 取消置顶
+	Time           int64                  `protobuf:"varint,6,opt,name=time,proto3" json:"time,omitempty"`
+	PinnedMessages []*PinnedMessageInfo   `protobuf:"bytes,7,rep,name=pinned_messages,json=pinnedMessages,proto3" json:"pinned_messages,omitempty"` // 操作后的完整置顶列表
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PinMessagePush) Reset() {
+	*x = PinMessagePush{}
+	mi := &file_im_protocol_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinMessagePush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinMessagePush) ProtoMessage() {}
+
+func (x *PinMessagePush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinMessagePush.ProtoReflect.Descriptor instead.
+func (*PinMessagePush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *PinMessagePush) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *PinMessagePush) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *PinMessagePush) GetServerMsgId() string {
+	if x != nil {
+		return x.ServerMsgId
+	}
+	return ""
+}
+
+func (x *PinMessagePush) GetOperatorId() string {
+	if x != nil {
+		return x.OperatorId
+	}
+	return ""
+}
+
+func (x *PinMessagePush) GetAction() int32 {
+	if x != nil {
+		return x.Action
+	}
+	return 0
+}
+
+func (x *PinMessagePush) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *PinMessagePush) GetPinnedMessages() []*PinnedMessageInfo {
+	if x != nil {
+		return x.PinnedMessages
+	}
+	return nil
+}
+
+// 获取会话置顶消息列表请求
+type PinnedListRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RequestId      string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PinnedListRequest) Reset() {
+	*x = PinnedListRequest{}
+	mi := &file_im_protocol_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinnedListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinnedListRequest) ProtoMessage() {}
+
+func (x *PinnedListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinnedListRequest.ProtoReflect.Descriptor instead.
+func (*PinnedListRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *PinnedListRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *PinnedListRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+// 获取会话置顶消息列表响应
+type PinnedListResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	RequestId      string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ConversationId string                 `protobuf:"bytes,4,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	PinnedMessages []*PinnedMessageInfo   `protobuf:"bytes,5,rep,name=pinned_messages,json=pinnedMessages,proto3" json:"pinned_messages,omitempty"` // 最新置顶的在前，包含完整 MessageInfo
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PinnedListResponse) Reset() {
+	*x = PinnedListResponse{}
+	mi := &file_im_protocol_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PinnedListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PinnedListResponse) ProtoMessage() {}
+
+func (x *PinnedListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PinnedListResponse.ProtoReflect.Descriptor instead.
+func (*PinnedListResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *PinnedListResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *PinnedListResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *PinnedListResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *PinnedListResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *PinnedListResponse) GetPinnedMessages() []*PinnedMessageInfo {
+	if x != nil {
+		return x.PinnedMessages
+	}
+	return nil
+}
+
+// 设置会话默认阅后即焚请求（群聊仅群主/管理员）
+type SetEphemeralRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	ExpireTtl      int32                  `protobuf:"varint,2,opt,name=expire_ttl,json=expireTtl,proto3" json:"expire_ttl,omitempty"`    // 默认时长（秒，0 表示关闭）
+	ExpireMode     int32                  `protobuf:"varint,3,opt,name=expire_mode,json=expireMode,proto3" json:"expire_mode,omitempty"` // 计时方式（1: 从发送时开始, 2: 从第一次被读取时开始）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetEphemeralRequest) Reset() {
+	*x = SetEphemeralRequest{}
+	mi := &file_im_protocol_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEphemeralRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEphemeralRequest) ProtoMessage() {}
+
+func (x *SetEphemeralRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEphemeralRequest.ProtoReflect.Descriptor instead.
+func (*SetEphemeralRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *SetEphemeralRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *SetEphemeralRequest) GetExpireTtl() int32 {
+	if x != nil {
+		return x.ExpireTtl
+	}
+	return 0
+}
+
+func (x *SetEphemeralRequest) GetExpireMode() int32 {
+	if x != nil {
+		return x.ExpireMode
+	}
+	return 0
+}
+
+// 获取会话阅后即焚设置请求
+type GetEphemeralRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetEphemeralRequest) Reset() {
+	*x = GetEphemeralRequest{}
+	mi := &file_im_protocol_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEphemeralRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEphemeralRequest) ProtoMessage() {}
+
+func (x *GetEphemeralRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEphemeralRequest.ProtoReflect.Descriptor instead.
+func (*GetEphemeralRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *GetEphemeralRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+// 会话阅后即焚设置响应（设置和获取共用）
+type EphemeralSettingResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ConversationId string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	ExpireTtl      int32                  `protobuf:"varint,4,opt,name=expire_ttl,json=expireTtl,proto3" json:"expire_ttl,omitempty"`
+	ExpireMode     int32                  `protobuf:"varint,5,opt,name=expire_mode,json=expireMode,proto3" json:"expire_mode,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EphemeralSettingResponse) Reset() {
+	*x = EphemeralSettingResponse{}
+	mi := &file_im_protocol_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EphemeralSettingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EphemeralSettingResponse) ProtoMessage() {}
+
+func (x *EphemeralSettingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EphemeralSettingResponse.ProtoReflect.Descriptor instead.
+func (*EphemeralSettingResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *EphemeralSettingResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *EphemeralSettingResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *EphemeralSettingResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *EphemeralSettingResponse) GetExpireTtl() int32 {
+	if x != nil {
+		return x.ExpireTtl
+	}
+	return 0
+}
+
+func (x *EphemeralSettingResponse) GetExpireMode() int32 {
+	if x != nil {
+		return x.ExpireMode
+	}
+	return 0
+}
+
+// 会话阅后即焚设置变化推送
+type EphemeralSettingPush struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	ExpireTtl      int32                  `protobuf:"varint,2,opt,name=expire_ttl,json=expireTtl,proto3" json:"expire_ttl,omitempty"`
+	ExpireMode     int32                  `protobuf:"varint,3,opt,name=expire_mode,json=expireMode,proto3" json:"expire_mode,omitempty"`
+	OperatorId     string                 `protobuf:"bytes,4,opt,name=operator_id,json=operatorId,proto3" json:"operator_id,omitempty"`
+	Time           int64                  `protobuf:"varint,5,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *EphemeralSettingPush) Reset() {
+	*x = EphemeralSettingPush{}
+	mi := &file_im_protocol_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EphemeralSettingPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EphemeralSettingPush) ProtoMessage() {}
+
+func (x *EphemeralSettingPush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EphemeralSettingPush.ProtoReflect.Descriptor instead.
+func (*EphemeralSettingPush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *EphemeralSettingPush) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *EphemeralSettingPush) GetExpireTtl() int32 {
+	if x != nil {
+		return x.ExpireTtl
+	}
+	return 0
+}
+
+func (x *EphemeralSettingPush) GetExpireMode() int32 {
+	if x != nil {
+		return x.ExpireMode
+	}
+	return 0
+}
+
+func (x *EphemeralSettingPush) GetOperatorId() string {
+	if x != nil {
+		return x.OperatorId
+	}
+	return ""
+}
+
+func (x *EphemeralSettingPush) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+// 阅后即焚消息过期推送（客户端删除本地消息）
+type MessageExpiredPush struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Seqs           []int64                `protobuf:"varint,2,rep,packed,name=seqs,proto3" json:"seqs,omitempty"`
+	ExpireTime     int64                  `protobuf:"varint,3,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *MessageExpiredPush) Reset() {
+	*x = MessageExpiredPush{}
+	mi := &file_im_protocol_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageExpiredPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageExpiredPush) ProtoMessage() {}
+
+func (x *MessageExpiredPush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageExpiredPush.ProtoReflect.Descriptor instead.
+func (*MessageExpiredPush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *MessageExpiredPush) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *MessageExpiredPush) GetSeqs() []int64 {
+	if x != nil {
+		return x.Seqs
+	}
+	return nil
+}
+
+func (x *MessageExpiredPush) GetExpireTime() int64 {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return 0
+}
+
+// 设置会话消息保留期限请求（群聊仅群主）
+type SetRetentionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	RetentionDays  int32                  `protobuf:"varint,2,opt,name=retention_days,json=retentionDays,proto3" json:"retention_days,omitempty"` // 保留天数（0 表示恢复全局默认值）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetRetentionRequest) Reset() {
+	*x = SetRetentionRequest{}
+	mi := &file_im_protocol_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRetentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRetentionRequest) ProtoMessage() {}
+
+func (x *SetRetentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRetentionRequest.ProtoReflect.Descriptor instead.
+func (*SetRetentionRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *SetRetentionRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *SetRetentionRequest) GetRetentionDays() int32 {
+	if x != nil {
+		return x.RetentionDays
+	}
+	return 0
+}
+
+// 获取会话消息保留期限请求
+type GetRetentionRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetRetentionRequest) Reset() {
+	*x = GetRetentionRequest{}
+	mi := &file_im_protocol_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRetentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRetentionRequest) ProtoMessage() {}
+
+func (x *GetRetentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRetentionRequest.ProtoReflect.Descriptor instead.
+func (*GetRetentionRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetRetentionRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+// 会话消息保留期限响应（设置和获取共用）
+type RetentionResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ConversationId string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	RetentionDays  int32                  `protobuf:"varint,4,opt,name=retention_days,json=retentionDays,proto3" json:"retention_days,omitempty"` // 生效的保留天数（0 表示永久保留）
+	IsOverride     bool                   `protobuf:"varint,5,opt,name=is_override,json=isOverride,proto3" json:"is_override,omitempty"`          // 是否是会话单独设置的（否则为全局默认值）
+	MinSeq         int64                  `protobuf:"varint,6,opt,name=min_seq,json=minSeq,proto3" json:"min_seq,omitempty"`                      // seq <= min_seq 的消息已被清理
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RetentionResponse) Reset() {
+	*x = RetentionResponse{}
+	mi := &file_im_protocol_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetentionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionResponse) ProtoMessage() {}
+
+func (x *RetentionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetentionResponse.ProtoReflect.Descriptor instead.
+func (*RetentionResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *RetentionResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *RetentionResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *RetentionResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *RetentionResponse) GetRetentionDays() int32 {
+	if x != nil {
+		return x.RetentionDays
+	}
+	return 0
+}
+
+func (x *RetentionResponse) GetIsOverride() bool {
+	if x != nil {
+		return x.IsOverride
+	}
+	return false
+}
+
+func (x *RetentionResponse) GetMinSeq() int64 {
+	if x != nil {
+		return x.MinSeq
+	}
+	return 0
+}
+
+// 定时消息信息
+type ScheduledMessageInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Message       *MessageInfo           `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`                                   // 待发送的消息（server_msg_id 和 seq 在发送后才有）
+	ScheduledTime int64                  `protobuf:"varint,3,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"` // 计划发送时间（毫秒）
+	Status        int32                  `protobuf:"varint,4,opt,name=status,proto3" json:"status,omitempty"`                                    // 1: 待发送, 2: 发送中, 3: 已发送, 4: 已取消, 5: 发送失败
+	ErrorMsg      string                 `protobuf:"bytes,5,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`                 // 最近一次发送失败的原因
+	CreateTime    int64                  `protobuf:"varint,6,opt,name=create_time,json=createTime,proto3" json:"create_time,omitempty"`
+	UpdateTime    int64                  `protobuf:"varint,7,opt,name=update_time,json=updateTime,proto3" json:"update_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduledMessageInfo) Reset() {
+	*x = ScheduledMessageInfo{}
+	mi := &file_im_protocol_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledMessageInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledMessageInfo) ProtoMessage() {}
+
+func (x *ScheduledMessageInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledMessageInfo.ProtoReflect.Descriptor instead.
+func (*ScheduledMessageInfo) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ScheduledMessageInfo) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *ScheduledMessageInfo) GetMessage() *MessageInfo {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *ScheduledMessageInfo) GetScheduledTime() int64 {
+	if x != nil {
+		return x.ScheduledTime
+	}
+	return 0
+}
+
+func (x *ScheduledMessageInfo) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ScheduledMessageInfo) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ScheduledMessageInfo) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+func (x *ScheduledMessageInfo) GetUpdateTime() int64 {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return 0
+}
+
+// 创建定时消息请求
+type ScheduleMessageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *MessageInfo           `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`                                   // 与发送消息相同（receiver_id / group_id、content、@、引用回复等）
+	ScheduledTime int64                  `protobuf:"varint,2,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"` // 计划发送时间（毫秒，必须在将来，最多提前一年）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScheduleMessageRequest) Reset() {
+	*x = ScheduleMessageRequest{}
+	mi := &file_im_protocol_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleMessageRequest) ProtoMessage() {}
+
+func (x *ScheduleMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleMessageRequest.ProtoReflect.Descriptor instead.
+func (*ScheduleMessageRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ScheduleMessageRequest) GetMessage() *MessageInfo {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *ScheduleMessageRequest) GetScheduledTime() int64 {
+	if x != nil {
+		return x.ScheduledTime
+	}
+	return 0
+}
+
+// 创建/修改定时消息响应
+type ScheduleMessageResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode        ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg         string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ScheduledMessage *ScheduledMessageInfo  `protobuf:"bytes,3,opt,name=scheduled_message,json=scheduledMessage,proto3" json:"scheduled_message,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ScheduleMessageResponse) Reset() {
+	*x = ScheduleMessageResponse{}
+	mi := &file_im_protocol_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduleMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduleMessageResponse) ProtoMessage() {}
+
+func (x *ScheduleMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduleMessageResponse.ProtoReflect.Descriptor instead.
+func (*ScheduleMessageResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ScheduleMessageResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *ScheduleMessageResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ScheduleMessageResponse) GetScheduledMessage() *ScheduledMessageInfo {
+	if x != nil {
+		return x.ScheduledMessage
+	}
+	return nil
+}
+
+// 获取待发送定时消息列表请求
+type ScheduledListRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RequestId      string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"` // 为空表示所有会话
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ScheduledListRequest) Reset() {
+	*x = ScheduledListRequest{}
+	mi := &file_im_protocol_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledListRequest) ProtoMessage() {}
+
+func (x *ScheduledListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledListRequest.ProtoReflect.Descriptor instead.
+func (*ScheduledListRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ScheduledListRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ScheduledListRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+// 获取待发送定时消息列表响应
+type ScheduledListResponse struct {
+	state             protoimpl.MessageState  `protogen:"open.v1"`
+	ErrorCode         ErrorCode               `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg          string                  `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	RequestId         string                  `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ScheduledMessages []*ScheduledMessageInfo `protobuf:"bytes,4,rep,name=scheduled_messages,json=scheduledMessages,proto3" json:"scheduled_messages,omitempty"` // 按计划发送时间排序
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ScheduledListResponse) Reset() {
+	*x = ScheduledListResponse{}
+	mi := &file_im_protocol_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScheduledListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScheduledListResponse) ProtoMessage() {}
+
+func (x *ScheduledListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScheduledListResponse.ProtoReflect.Descriptor instead.
+func (*ScheduledListResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ScheduledListResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *ScheduledListResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ScheduledListResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ScheduledListResponse) GetScheduledMessages() []*ScheduledMessageInfo {
+	if x != nil {
+		return x.ScheduledMessages
+	}
+	return nil
+}
+
+// 修改定时消息请求（仅待发送状态可以修改）
+type EditScheduledRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	Content       []byte                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`                                   // 为空表示不修改
+	ScheduledTime int64                  `protobuf:"varint,3,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"` // 0 表示不修改
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EditScheduledRequest) Reset() {
+	*x = EditScheduledRequest{}
+	mi := &file_im_protocol_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EditScheduledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EditScheduledRequest) ProtoMessage() {}
+
+func (x *EditScheduledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EditScheduledRequest.ProtoReflect.Descriptor instead.
+func (*EditScheduledRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *EditScheduledRequest) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+func (x *EditScheduledRequest) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *EditScheduledRequest) GetScheduledTime() int64 {
+	if x != nil {
+		return x.ScheduledTime
+	}
+	return 0
+}
+
+// 取消定时消息请求（仅待发送状态可以取消）
+type CancelScheduledRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ScheduleId    string                 `protobuf:"bytes,1,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledRequest) Reset() {
+	*x = CancelScheduledRequest{}
+	mi := &file_im_protocol_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledRequest) ProtoMessage() {}
+
+func (x *CancelScheduledRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledRequest.ProtoReflect.Descriptor instead.
+func (*CancelScheduledRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *CancelScheduledRequest) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+// 取消定时消息响应
+type CancelScheduledResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ScheduleId    string                 `protobuf:"bytes,3,opt,name=schedule_id,json=scheduleId,proto3" json:"schedule_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelScheduledResponse) Reset() {
+	*x = CancelScheduledResponse{}
+	mi := &file_im_protocol_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelScheduledResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelScheduledResponse) ProtoMessage() {}
+
+func (x *CancelScheduledResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelScheduledResponse.ProtoReflect.Descriptor instead.
+func (*CancelScheduledResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *CancelScheduledResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *CancelScheduledResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *CancelScheduledResponse) GetScheduleId() string {
+	if x != nil {
+		return x.ScheduleId
+	}
+	return ""
+}
+
+// 会话同步状态（客户端本地状态）
+type ConversationSyncState struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"` // 会话ID
+	LastSeq        int64                  `protobuf:"varint,2,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"`                     // 本地最大 seq
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConversationSyncState) Reset() {
+	*x = ConversationSyncState{}
+	mi := &file_im_protocol_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationSyncState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationSyncState) ProtoMessage() {}
+
+func (x *ConversationSyncState) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationSyncState.ProtoReflect.Descriptor instead.
+func (*ConversationSyncState) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ConversationSyncState) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ConversationSyncState) GetLastSeq() int64 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+// 批量同步请求（一次性同步所有会话）
+type BatchSyncRequest struct {
+	state                   protoimpl.MessageState   `protogen:"open.v1"`
+	ConversationStates      []*ConversationSyncState `protobuf:"bytes,1,rep,name=conversation_states,json=conversationStates,proto3" json:"conversation_states,omitempty"`                     // 客户端的会话同步状态（可为空，表示首次全量同步）
+	MaxCountPerConversation int32                    `protobuf:"varint,2,opt,name=max_count_per_conversation,json=maxCountPerConversation,proto3" json:"max_count_per_conversation,omitempty"` // 每个会话最多拉取的消息数（默认100，最大500）
+	LastSyncTime            int64                    `protobuf:"varint,3,opt,name=last_sync_time,json=lastSyncTime,proto3" json:"last_sync_time,omitempty"`                                    // 上次同步的服务器时间（用于拉取已同步消息的变更，如编辑）
+	unknownFields           protoimpl.UnknownFields
+	sizeCache               protoimpl.SizeCache
+}
+
+func (x *BatchSyncRequest) Reset() {
+	*x = BatchSyncRequest{}
+	mi := &file_im_protocol_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchSyncRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSyncRequest) ProtoMessage() {}
+
+func (x *BatchSyncRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSyncRequest.ProtoReflect.Descriptor instead.
+func (*BatchSyncRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *BatchSyncRequest) GetConversationStates() []*ConversationSyncState {
+	if x != nil {
+		return x.ConversationStates
+	}
+	return nil
+}
+
+func (x *BatchSyncRequest) GetMaxCountPerConversation() int32 {
+	if x != nil {
+		return x.MaxCountPerConversation
+	}
+	return 0
+}
+
+func (x *BatchSyncRequest) GetLastSyncTime() int64 {
+	if x != nil {
+		return x.LastSyncTime
+	}
+	return 0
+}
+
+// 会话消息结果
+type ConversationMessages struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId  string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`      // 会话ID
+	Messages        []*MessageInfo         `protobuf:"bytes,2,rep,name=messages,proto3" json:"messages,omitempty"`                                        // 消息列表
+	MaxSeq          int64                  `protobuf:"varint,3,opt,name=max_seq,json=maxSeq,proto3" json:"max_seq,omitempty"`                             // 服务端该会话的最大 seq
+	SyncedSeq       int64                  `protobuf:"varint,4,opt,name=synced_seq,json=syncedSeq,proto3" json:"synced_seq,omitempty"`                    // 本次同步到的 seq（可能小于 max_seq，因为有 max_count 限制）
+	HasMore         bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`                          // 是否还有更多消息未同步
+	UpdatedMessages []*MessageInfo         `protobuf:"bytes,6,rep,name=updated_messages,json=updatedMessages,proto3" json:"updated_messages,omitempty"`   // last_sync_time 之后发生变更的已同步消息（seq <= last_seq）
+	MentionedSeqs   []int64                `protobuf:"varint,7,rep,packed,name=mentioned_seqs,json=mentionedSeqs,proto3" json:"mentioned_seqs,omitempty"` // 当前用户在该会话中未读的 @ 消息 seq
+	DeletedSeqs     []int64                `protobuf:"varint,8,rep,packed,name=deleted_seqs,json=deletedSeqs,proto3" json:"deleted_seqs,omitempty"`       // last_sync_time 之后当前用户删除的消息 seq
+	ClearedSeq      int64                  `protobuf:"varint,9,opt,name=cleared_seq,json=clearedSeq,proto3" json:"cleared_seq,omitempty"`                 // 当前用户清空历史的位置（seq <= cleared_seq 的消息不可见）
+	PinnedMessages  []*PinnedMessageInfo   `protobuf:"bytes,10,rep,name=pinned_messages,json=pinnedMessages,proto3" json:"pinned_messages,omitempty"`     // 会话的置顶列表（最新置顶的在前）
+	ExpiredSeqs     []int64                `protobuf:"varint,11,rep,packed,name=expired_seqs,json=expiredSeqs,proto3" json:"expired_seqs,omitempty"`      // last_sync_time 之后过期的已同步阅后即焚消息 seq
+	MinSeq          int64                  `protobuf:"varint,12,opt,name=min_seq,json=minSeq,proto3" json:"min_seq,omitempty"`                            // seq <= min_seq 的消息已超过保留期限被清理，不要再请求
+	ReadSeq         int64                  `protobuf:"varint,13,opt,name=read_seq,json=readSeq,proto3" json:"read_seq,omitempty"`                         // 当前用户在该会话中的已读位置
+	UnreadCount     int32                  `protobuf:"varint,14,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`             // 当前用户在该会话中的未读数
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ConversationMessages) Reset() {
+	*x = ConversationMessages{}
+	mi := &file_im_protocol_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationMessages) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationMessages) ProtoMessage() {}
+
+func (x *ConversationMessages) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationMessages.ProtoReflect.Descriptor instead.
+func (*ConversationMessages) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ConversationMessages) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ConversationMessages) GetMessages() []*MessageInfo {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ConversationMessages) GetMaxSeq() int64 {
+	if x != nil {
+		return x.MaxSeq
+	}
+	return 0
+}
+
+func (x *ConversationMessages) GetSyncedSeq() int64 {
+	if x != nil {
+		return x.SyncedSeq
+	}
+	return 0
+}
+
+func (x *ConversationMessages) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *ConversationMessages) GetUpdatedMessages() []*MessageInfo {
+	if x != nil {
+		return x.UpdatedMessages
+	}
+	return nil
+}
+
+func (x *ConversationMessages) GetMentionedSeqs() []int64 {
+	if x != nil {
+		return x.MentionedSeqs
+	}
+	return nil
+}
+
+func (x *ConversationMessages) GetDeletedSeqs() []int64 {
+	if x != nil {
+		return x.DeletedSeqs
+	}
+	return nil
+}
+
+func (x *ConversationMessages) GetClearedSeq() int64 {
+	if x != nil {
+		return x.ClearedSeq
+	}
+	return 0
+}
+
+func (x *ConversationMessages) GetPinnedMessages() []*PinnedMessageInfo {
+	if x != nil {
+		return x.PinnedMessages
+	}
+	return nil
+}
+
+func (x *ConversationMessages) GetExpiredSeqs() []int64 {
+	if x != nil {
+		return x.ExpiredSeqs
+	}
+	return nil
+}
+
+func (x *ConversationMessages) GetMinSeq() int64 {
+	if x != nil {
+		return x.MinSeq
+	}
+	return 0
+}
+
+func (x *ConversationMessages) GetReadSeq() int64 {
+	if x != nil {
+		return x.ReadSeq
+	}
+	return 0
+}
+
+func (x *ConversationMessages) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+// 批量同步响应
+type BatchSyncResponse struct {
+	state                      protoimpl.MessageState  `protogen:"open.v1"`
+	ErrorCode                  ErrorCode               `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg                   string                  `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ConversationMessages       []*ConversationMessages `protobuf:"bytes,3,rep,name=conversation_messages,json=conversationMessages,proto3" json:"conversation_messages,omitempty"`                      // 所有会话的消息
+	ServerTime                 int64                   `protobuf:"varint,4,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`                                                   // 服务器时间
+	TotalMessageCount          int32                   `protobuf:"varint,5,opt,name=total_message_count,json=totalMessageCount,proto3" json:"total_message_count,omitempty"`                            // 本次同步的总消息数
+	MentionedConversationCount int32                   `protobuf:"varint,6,opt,name=mentioned_conversation_count,json=mentionedConversationCount,proto3" json:"mentioned_conversation_count,omitempty"` // 有未读 @ 消息的会话数
+	TotalUnread                int32                   `protobuf:"varint,7,opt,name=total_unread,json=totalUnread,proto3" json:"total_unread,omitempty"`                                                // 所有会话的未读总数（应用角标）
+	unknownFields              protoimpl.UnknownFields
+	sizeCache                  protoimpl.SizeCache
+}
+
+func (x *BatchSyncResponse) Reset() {
+	*x = BatchSyncResponse{}
+	mi := &file_im_protocol_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchSyncResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchSyncResponse) ProtoMessage() {}
+
+func (x *BatchSyncResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchSyncResponse.ProtoReflect.Descriptor instead.
+func (*BatchSyncResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *BatchSyncResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *BatchSyncResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *BatchSyncResponse) GetConversationMessages() []*ConversationMessages {
+	if x != nil {
+		return x.ConversationMessages
+	}
+	return nil
+}
+
+func (x *BatchSyncResponse) GetServerTime() int64 {
+	if x != nil {
+		return x.ServerTime
+	}
+	return 0
+}
+
+func (x *BatchSyncResponse) GetTotalMessageCount() int32 {
+	if x != nil {
+		return x.TotalMessageCount
+	}
+	return 0
+}
+
+func (x *BatchSyncResponse) GetMentionedConversationCount() int32 {
+	if x != nil {
+		return x.MentionedConversationCount
+	}
+	return 0
+}
+
+func (x *BatchSyncResponse) GetTotalUnread() int32 {
+	if x != nil {
+		return x.TotalUnread
+	}
+	return 0
+}
+
+// 会话列表项（当前用户视角）
+type ConversationInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId  string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Type            int32                  `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`                                                 // 1: 单聊, 2: 群聊
+	TargetId        string                 `protobuf:"bytes,3,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`                          // 对方用户ID或群组ID
+	LastServerMsgId string                 `protobuf:"bytes,4,opt,name=last_server_msg_id,json=lastServerMsgId,proto3" json:"last_server_msg_id,omitempty"` // 最后一条消息
+	LastSeq         int64                  `protobuf:"varint,5,opt,name=last_seq,json=lastSeq,proto3" json:"last_seq,omitempty"`
+	LastSenderId    string                 `protobuf:"bytes,6,opt,name=last_sender_id,json=lastSenderId,proto3" json:"last_sender_id,omitempty"`
+	LastMessage     string                 `protobuf:"bytes,7,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"` // 最后一条消息的内容预览
+	LastMessageTime int64                  `protobuf:"varint,8,opt,name=last_message_time,json=lastMessageTime,proto3" json:"last_message_time,omitempty"`
+	UnreadCount     int32                  `protobuf:"varint,9,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	Hidden          bool                   `protobuf:"varint,10,opt,name=hidden,proto3" json:"hidden,omitempty"`   // 会话已被隐藏（客户端不在列表中显示，有新消息时重新出现）
+	Version         int64                  `protobuf:"varint,11,opt,name=version,proto3" json:"version,omitempty"` // 会话最近一次变化的版本号
+	CreatedAt       int64                  `protobuf:"varint,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       int64                  `protobuf:"varint,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	MuteUntil       int64                  `protobuf:"varint,14,opt,name=mute_until,json=muteUntil,proto3" json:"mute_until,omitempty"` // 免打扰（0: 关闭, -1: 永久, >0: 免打扰到该时间，毫秒）
+	PinOrder        int64                  `protobuf:"varint,15,opt,name=pin_order,json=pinOrder,proto3" json:"pin_order,omitempty"`    // 置顶顺序（0: 不置顶，越大越靠前）
+	Extra           string                 `protobuf:"bytes,16,opt,name=extra,proto3" json:"extra,omitempty"`                           // 自定义字段（如聊天背景、备注标签，由客户端定义格式）
+	Draft           *DraftInfo             `protobuf:"bytes,17,opt,name=draft,proto3" json:"draft,omitempty"`                           // 草稿（没有草稿时为空）
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ConversationInfo) Reset() {
+	*x = ConversationInfo{}
+	mi := &file_im_protocol_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationInfo) ProtoMessage() {}
+
+func (x *ConversationInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationInfo.ProtoReflect.Descriptor instead.
+func (*ConversationInfo) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *ConversationInfo) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ConversationInfo) GetType() int32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *ConversationInfo) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *ConversationInfo) GetLastServerMsgId() string {
+	if x != nil {
+		return x.LastServerMsgId
+	}
+	return ""
+}
+
+func (x *ConversationInfo) GetLastSeq() int64 {
+	if x != nil {
+		return x.LastSeq
+	}
+	return 0
+}
+
+func (x *ConversationInfo) GetLastSenderId() string {
+	if x != nil {
+		return x.LastSenderId
+	}
+	return ""
+}
+
+func (x *ConversationInfo) GetLastMessage() string {
+	if x != nil {
+		return x.LastMessage
+	}
+	return ""
+}
+
+func (x *ConversationInfo) GetLastMessageTime() int64 {
+	if x != nil {
+		return x.LastMessageTime
+	}
+	return 0
+}
+
+func (x *ConversationInfo) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *ConversationInfo) GetHidden() bool {
+	if x != nil {
+		return x.Hidden
+	}
+	return false
+}
+
+func (x *ConversationInfo) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *ConversationInfo) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *ConversationInfo) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+func (x *ConversationInfo) GetMuteUntil() int64 {
+	if x != nil {
+		return x.MuteUntil
+	}
+	return 0
+}
+
+func (x *ConversationInfo) GetPinOrder() int64 {
+	if x != nil {
+		return x.PinOrder
+	}
+	return 0
+}
+
+func (x *ConversationInfo) GetExtra() string {
+	if x != nil {
+		return x.Extra
+	}
+	return ""
+}
+
+func (x *ConversationInfo) GetDraft() *DraftInfo {
+	if x != nil {
+		return x.Draft
+	}
+	return nil
+}
+
+// 会话草稿
+type DraftInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	ReplyToMsgId  string                 `protobuf:"bytes,2,opt,name=reply_to_msg_id,json=replyToMsgId,proto3" json:"reply_to_msg_id,omitempty"` // 引用回复的消息 ID（server_msg_id）
+	ClientTime    int64                  `protobuf:"varint,3,opt,name=client_time,json=clientTime,proto3" json:"client_time,omitempty"`          // 客户端保存草稿的时间（毫秒，多端以最新的为准）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DraftInfo) Reset() {
+	*x = DraftInfo{}
+	mi := &file_im_protocol_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DraftInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DraftInfo) ProtoMessage() {}
+
+func (x *DraftInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DraftInfo.ProtoReflect.Descriptor instead.
+func (*DraftInfo) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *DraftInfo) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *DraftInfo) GetReplyToMsgId() string {
+	if x != nil {
+		return x.ReplyToMsgId
+	}
+	return ""
+}
+
+func (x *DraftInfo) GetClientTime() int64 {
+	if x != nil {
+		return x.ClientTime
+	}
+	return 0
+}
+
+// 修改会话个人设置请求
+type SetConversationSettingsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Fields         []string               `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`                         // 要修改的字段：mute_until / pin_order / hidden / extra
+	MuteUntil      int64                  `protobuf:"varint,3,opt,name=mute_until,json=muteUntil,proto3" json:"mute_until,omitempty"` // 0: 关闭, -1: 永久, >0: 免打扰到该时间（毫秒）
+	PinOrder       int64                  `protobuf:"varint,4,opt,name=pin_order,json=pinOrder,proto3" json:"pin_order,omitempty"`    // 0: 取消置顶, >0: 置顶顺序（越大越靠前）
+	Hidden         bool                   `protobuf:"varint,5,opt,name=hidden,proto3" json:"hidden,omitempty"`                        // 隐藏会话（有新消息时自动取消隐藏）
+	Extra          string                 `protobuf:"bytes,6,opt,name=extra,proto3" json:"extra,omitempty"`                           // 自定义字段（最长 1024 字节）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetConversationSettingsRequest) Reset() {
+	*x = SetConversationSettingsRequest{}
+	mi := &file_im_protocol_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetConversationSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetConversationSettingsRequest) ProtoMessage() {}
+
+func (x *SetConversationSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetConversationSettingsRequest.ProtoReflect.Descriptor instead.
+func (*SetConversationSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *SetConversationSettingsRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *SetConversationSettingsRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *SetConversationSettingsRequest) GetMuteUntil() int64 {
+	if x != nil {
+		return x.MuteUntil
+	}
+	return 0
+}
+
+func (x *SetConversationSettingsRequest) GetPinOrder() int64 {
+	if x != nil {
+		return x.PinOrder
+	}
+	return 0
+}
+
+func (x *SetConversationSettingsRequest) GetHidden() bool {
+	if x != nil {
+		return x.Hidden
+	}
+	return false
+}
+
+func (x *SetConversationSettingsRequest) GetExtra() string {
+	if x != nil {
+		return x.Extra
+	}
+	return ""
+}
+
+// 修改会话个人设置响应
+type SetConversationSettingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	Conversation  *ConversationInfo      `protobuf:"bytes,3,opt,name=conversation,proto3" json:"conversation,omitempty"` // 修改后的会话
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetConversationSettingsResponse) Reset() {
+	*x = SetConversationSettingsResponse{}
+	mi := &file_im_protocol_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetConversationSettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetConversationSettingsResponse) ProtoMessage() {}
+
+func (x *SetConversationSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetConversationSettingsResponse.ProtoReflect.Descriptor instead.
+func (*SetConversationSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *SetConversationSettingsResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *SetConversationSettingsResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *SetConversationSettingsResponse) GetConversation() *ConversationInfo {
+	if x != nil {
+		return x.Conversation
+	}
+	return nil
+}
+
+// 会话个人设置变化推送
+type ConversationSettingsPush struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conversation  *ConversationInfo      `protobuf:"bytes,1,opt,name=conversation,proto3" json:"conversation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConversationSettingsPush) Reset() {
+	*x = ConversationSettingsPush{}
+	mi := &file_im_protocol_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConversationSettingsPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConversationSettingsPush) ProtoMessage() {}
+
+func (x *ConversationSettingsPush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConversationSettingsPush.ProtoReflect.Descriptor instead.
+func (*ConversationSettingsPush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ConversationSettingsPush) GetConversation() *ConversationInfo {
+	if x != nil {
+		return x.Conversation
+	}
+	return nil
+}
+
+// 保存/清除会话草稿请求（text 和 reply_to_msg_id 都为空表示清除）
+type SetDraftRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Text           string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`                                         // 草稿内容（最长 4096 字节）
+	ReplyToMsgId   string                 `protobuf:"bytes,3,opt,name=reply_to_msg_id,json=replyToMsgId,proto3" json:"reply_to_msg_id,omitempty"` // 引用回复的消息 ID
+	ClientTime     int64                  `protobuf:"varint,4,opt,name=client_time,json=clientTime,proto3" json:"client_time,omitempty"`          // 客户端修改草稿的时间（毫秒）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetDraftRequest) Reset() {
+	*x = SetDraftRequest{}
+	mi := &file_im_protocol_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDraftRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDraftRequest) ProtoMessage() {}
+
+func (x *SetDraftRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDraftRequest.ProtoReflect.Descriptor instead.
+func (*SetDraftRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *SetDraftRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *SetDraftRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SetDraftRequest) GetReplyToMsgId() string {
+	if x != nil {
+		return x.ReplyToMsgId
+	}
+	return ""
+}
+
+func (x *SetDraftRequest) GetClientTime() int64 {
+	if x != nil {
+		return x.ClientTime
+	}
+	return 0
+}
+
+// 保存/清除会话草稿响应
+type SetDraftResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ConversationId string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Applied        bool                   `protobuf:"varint,4,opt,name=applied,proto3" json:"applied,omitempty"` // 是否生效（其他设备已保存了更新的草稿时不生效）
+	Draft          *DraftInfo             `protobuf:"bytes,5,opt,name=draft,proto3" json:"draft,omitempty"`      // 服务端当前的草稿
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetDraftResponse) Reset() {
+	*x = SetDraftResponse{}
+	mi := &file_im_protocol_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDraftResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDraftResponse) ProtoMessage() {}
+
+func (x *SetDraftResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDraftResponse.ProtoReflect.Descriptor instead.
+func (*SetDraftResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *SetDraftResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *SetDraftResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *SetDraftResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *SetDraftResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *SetDraftResponse) GetDraft() *DraftInfo {
+	if x != nil {
+		return x.Draft
+	}
+	return nil
+}
+
+// 会话草稿变化推送
+type DraftPush struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Draft          *DraftInfo             `protobuf:"bytes,2,opt,name=draft,proto3" json:"draft,omitempty"` // 清除时 text 和 reply_to_msg_id 为空
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DraftPush) Reset() {
+	*x = DraftPush{}
+	mi := &file_im_protocol_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DraftPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DraftPush) ProtoMessage() {}
+
+func (x *DraftPush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DraftPush.ProtoReflect.Descriptor instead.
+func (*DraftPush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *DraftPush) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *DraftPush) GetDraft() *DraftInfo {
+	if x != nil {
+		return x.Draft
+	}
+	return nil
+}
+
+// 加入聊天室请求（聊天室不需要事先创建，第一个人加入时自动出现）
+type JoinChatroomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatroomId    string                 `protobuf:"bytes,1,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	HistoryCount  int32                  `protobuf:"varint,2,opt,name=history_count,json=historyCount,proto3" json:"history_count,omitempty"` // 返回的最近消息条数（0 使用默认值，超过上限按上限返回）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinChatroomRequest) Reset() {
+	*x = JoinChatroomRequest{}
+	mi := &file_im_protocol_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinChatroomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinChatroomRequest) ProtoMessage() {}
+
+func (x *JoinChatroomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinChatroomRequest.ProtoReflect.Descriptor instead.
+func (*JoinChatroomRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *JoinChatroomRequest) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+func (x *JoinChatroomRequest) GetHistoryCount() int32 {
+	if x != nil {
+		return x.HistoryCount
+	}
+	return 0
+}
+
+// 加入聊天室响应
+type JoinChatroomResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ChatroomId     string                 `protobuf:"bytes,3,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	ConversationId string                 `protobuf:"bytes,4,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"` // chatroom_{chatroom_id}，发送消息时使用
+	MemberCount    int32                  `protobuf:"varint,5,opt,name=member_count,json=memberCount,proto3" json:"member_count,omitempty"`         // 当前在线人数
+	History        []*MessageInfo         `protobuf:"bytes,6,rep,name=history,proto3" json:"history,omitempty"`                                     // 最近消息（按 seq 升序）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *JoinChatroomResponse) Reset() {
+	*x = JoinChatroomResponse{}
+	mi := &file_im_protocol_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinChatroomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinChatroomResponse) ProtoMessage() {}
+
+func (x *JoinChatroomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinChatroomResponse.ProtoReflect.Descriptor instead.
+func (*JoinChatroomResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *JoinChatroomResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *JoinChatroomResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *JoinChatroomResponse) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+func (x *JoinChatroomResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *JoinChatroomResponse) GetMemberCount() int32 {
+	if x != nil {
+		return x.MemberCount
+	}
+	return 0
+}
+
+func (x *JoinChatroomResponse) GetHistory() []*MessageInfo {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+// 离开聊天室请求
+type LeaveChatroomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatroomId    string                 `protobuf:"bytes,1,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveChatroomRequest) Reset() {
+	*x = LeaveChatroomRequest{}
+	mi := &file_im_protocol_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveChatroomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveChatroomRequest) ProtoMessage() {}
+
+func (x *LeaveChatroomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveChatroomRequest.ProtoReflect.Descriptor instead.
+func (*LeaveChatroomRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *LeaveChatroomRequest) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+// 离开聊天室响应
+type LeaveChatroomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ChatroomId    string                 `protobuf:"bytes,3,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveChatroomResponse) Reset() {
+	*x = LeaveChatroomResponse{}
+	mi := &file_im_protocol_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveChatroomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveChatroomResponse) ProtoMessage() {}
+
+func (x *LeaveChatroomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveChatroomResponse.ProtoReflect.Descriptor instead.
+func (*LeaveChatroomResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *LeaveChatroomResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *LeaveChatroomResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *LeaveChatroomResponse) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+// 聊天室在线人数推送（人数变化后合并推送）
+type ChatroomMemberCountPush struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatroomId    string                 `protobuf:"bytes,1,opt,name=chatroom_id,json=chatroomId,proto3" json:"chatroom_id,omitempty"`
+	MemberCount   int32                  `protobuf:"varint,2,opt,name=member_count,json=memberCount,proto3" json:"member_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatroomMemberCountPush) Reset() {
+	*x = ChatroomMemberCountPush{}
+	mi := &file_im_protocol_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatroomMemberCountPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatroomMemberCountPush) ProtoMessage() {}
+
+func (x *ChatroomMemberCountPush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatroomMemberCountPush.ProtoReflect.Descriptor instead.
+func (*ChatroomMemberCountPush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *ChatroomMemberCountPush) GetChatroomId() string {
+	if x != nil {
+		return x.ChatroomId
+	}
+	return ""
+}
+
+func (x *ChatroomMemberCountPush) GetMemberCount() int32 {
+	if x != nil {
+		return x.MemberCount
+	}
+	return 0
+}
+
+// 同步会话列表请求
+type SyncConversationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"` // 客户端已同步到的版本号（0 表示全量同步）
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`     // 单次最多返回的会话数（默认 100，最大 500）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncConversationsRequest) Reset() {
+	*x = SyncConversationsRequest{}
+	mi := &file_im_protocol_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncConversationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncConversationsRequest) ProtoMessage() {}
+
+func (x *SyncConversationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncConversationsRequest.ProtoReflect.Descriptor instead.
+func (*SyncConversationsRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *SyncConversationsRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SyncConversationsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// 同步会话列表响应
+type SyncConversationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	Conversations []*ConversationInfo    `protobuf:"bytes,3,rep,name=conversations,proto3" json:"conversations,omitempty"`     // 按版本号升序
+	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`                // 本次同步到的版本号（下次请求时带上）
+	HasMore       bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"` // 是否还有更多（继续用 version 请求）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncConversationsResponse) Reset() {
+	*x = SyncConversationsResponse{}
+	mi := &file_im_protocol_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncConversationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncConversationsResponse) ProtoMessage() {}
+
+func (x *SyncConversationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncConversationsResponse.ProtoReflect.Descriptor instead.
+func (*SyncConversationsResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *SyncConversationsResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *SyncConversationsResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *SyncConversationsResponse) GetConversations() []*ConversationInfo {
+	if x != nil {
+		return x.Conversations
+	}
+	return nil
+}
+
+func (x *SyncConversationsResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SyncConversationsResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+// 范围同步请求
+type SyncRangeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RequestId      string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`                // 请求唯一标识（由客户端生成，用于响应匹配）
+	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"` // 会话ID（必填）
+	StartSeq       int64                  `protobuf:"varint,3,opt,name=start_seq,json=startSeq,proto3" json:"start_seq,omitempty"`                  // 起始 seq（包含）
+	EndSeq         int64                  `protobuf:"varint,4,opt,name=end_seq,json=endSeq,proto3" json:"end_seq,omitempty"`                        // 结束 seq（包含）
+	Count          int32                  `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`                                        // 单次拉取数量限制（默认100，最大500）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SyncRangeRequest) Reset() {
+	*x = SyncRangeRequest{}
+	mi := &file_im_protocol_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncRangeRequest) ProtoMessage() {}
+
+func (x *SyncRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncRangeRequest.ProtoReflect.Descriptor instead.
+func (*SyncRangeRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *SyncRangeRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SyncRangeRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *SyncRangeRequest) GetStartSeq() int64 {
+	if x != nil {
+		return x.StartSeq
+	}
+	return 0
+}
+
+func (x *SyncRangeRequest) GetEndSeq() int64 {
+	if x != nil {
+		return x.EndSeq
+	}
+	return 0
+}
+
+func (x *SyncRangeRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 范围同步响应
+type SyncRangeResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	RequestId      string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`                // 对应请求的 request_id
+	ConversationId string                 `protobuf:"bytes,4,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"` // 会话ID
+	Messages       []*MessageInfo         `protobuf:"bytes,5,rep,name=messages,proto3" json:"messages,omitempty"`                                   // 消息列表
+	StartSeq       int64                  `protobuf:"varint,6,opt,name=start_seq,json=startSeq,proto3" json:"start_seq,omitempty"`                  // 实际返回的起始 seq
+	EndSeq         int64                  `protobuf:"varint,7,opt,name=end_seq,json=endSeq,proto3" json:"end_seq,omitempty"`                        // 实际返回的结束 seq
+	HasMore        bool                   `protobuf:"varint,8,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`                     // 是否还有更多消息（如果请求范围过大，需要分批拉取）
+	MinSeq         int64                  `protobuf:"varint,9,opt,name=min_seq,json=minSeq,proto3" json:"min_seq,omitempty"`                        // seq <= min_seq 的消息已超过保留期限被清理，不要再请求
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SyncRangeResponse) Reset() {
+	*x = SyncRangeResponse{}
+	mi := &file_im_protocol_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncRangeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncRangeResponse) ProtoMessage() {}
+
+func (x *SyncRangeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncRangeResponse.ProtoReflect.Descriptor instead.
+func (*SyncRangeResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *SyncRangeResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *SyncRangeResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *SyncRangeResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SyncRangeResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *SyncRangeResponse) GetMessages() []*MessageInfo {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *SyncRangeResponse) GetStartSeq() int64 {
+	if x != nil {
+		return x.StartSeq
+	}
+	return 0
+}
+
+func (x *SyncRangeResponse) GetEndSeq() int64 {
+	if x != nil {
+		return x.EndSeq
+	}
+	return 0
+}
+
+func (x *SyncRangeResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *SyncRangeResponse) GetMinSeq() int64 {
+	if x != nil {
+		return x.MinSeq
+	}
+	return 0
+}
+
+// 向前分页拉取历史消息请求（加载更早的消息）
+type HistoryRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RequestId      string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	BeforeSeq      int64                  `protobuf:"varint,3,opt,name=before_seq,json=beforeSeq,proto3" json:"before_seq,omitempty"` // 游标：返回 seq 小于该值的消息（0 表示从最新消息开始）
+	Count          int32                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`                          // 单次拉取数量（默认50，最大200）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	mi := &file_im_protocol_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRequest) ProtoMessage() {}
+
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *HistoryRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetBeforeSeq() int64 {
+	if x != nil {
+		return x.BeforeSeq
+	}
+	return 0
+}
+
+func (x *HistoryRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 向前分页拉取历史消息响应
+type HistoryResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	RequestId      string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ConversationId string                 `protobuf:"bytes,4,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	Messages       []*MessageInfo         `protobuf:"bytes,5,rep,name=messages,proto3" json:"messages,omitempty"`                                   // 按 seq 升序（包含当前用户的已读状态）
+	NextBeforeSeq  int64                  `protobuf:"varint,6,opt,name=next_before_seq,json=nextBeforeSeq,proto3" json:"next_before_seq,omitempty"` // 下一页的游标（本页最小的 seq）
+	HasMore        bool                   `protobuf:"varint,7,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`                     // 是否还有更早的消息
+	MinSeq         int64                  `protobuf:"varint,8,opt,name=min_seq,json=minSeq,proto3" json:"min_seq,omitempty"`                        // seq <= min_seq 的消息已超过保留期限被清理
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HistoryResponse) Reset() {
+	*x = HistoryResponse{}
+	mi := &file_im_protocol_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryResponse) ProtoMessage() {}
+
+func (x *HistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryResponse.ProtoReflect.Descriptor instead.
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *HistoryResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *HistoryResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *HistoryResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *HistoryResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *HistoryResponse) GetMessages() []*MessageInfo {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *HistoryResponse) GetNextBeforeSeq() int64 {
+	if x != nil {
+		return x.NextBeforeSeq
+	}
+	return 0
+}
+
+func (x *HistoryResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *HistoryResponse) GetMinSeq() int64 {
+	if x != nil {
+		return x.MinSeq
+	}
+	return 0
+}
+
+// 搜索消息请求
+type SearchMessageRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RequestId      string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Keyword        string                 `protobuf:"bytes,2,opt,name=keyword,proto3" json:"keyword,omitempty"`                                       // 关键词（多个词需要全部命中）
+	ConversationId string                 `protobuf:"bytes,3,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`   // 限定会话（空表示搜索所在的全部会话）
+	SenderId       string                 `protobuf:"bytes,4,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`                     // 限定发送者
+	StartTime      int64                  `protobuf:"varint,5,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`                 // 起始时间（毫秒，0 表示不限）
+	EndTime        int64                  `protobuf:"varint,6,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`                       // 结束时间（毫秒，0 表示不限）
+	MessageTypes   []int32                `protobuf:"varint,7,rep,packed,name=message_types,json=messageTypes,proto3" json:"message_types,omitempty"` // 限定消息类型
+	BeforeTime     int64                  `protobuf:"varint,8,opt,name=before_time,json=beforeTime,proto3" json:"before_time,omitempty"`              // 分页游标（上一页响应的 next_before_time，首页为 0）
+	BeforeMsgId    string                 `protobuf:"bytes,9,opt,name=before_msg_id,json=beforeMsgId,proto3" json:"before_msg_id,omitempty"`          // 分页游标（上一页响应的 next_before_msg_id）
+	Limit          int32                  `protobuf:"varint,10,opt,name=limit,proto3" json:"limit,omitempty"`                                         // 单页数量（默认20，最大50）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SearchMessageRequest) Reset() {
+	*x = SearchMessageRequest{}
+	mi := &file_im_protocol_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMessageRequest) ProtoMessage() {}
+
+func (x *SearchMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMessageRequest.ProtoReflect.Descriptor instead.
+func (*SearchMessageRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *SearchMessageRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SearchMessageRequest) GetKeyword() string {
+	if x != nil {
+		return x.Keyword
+	}
+	return ""
+}
+
+func (x *SearchMessageRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *SearchMessageRequest) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *SearchMessageRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *SearchMessageRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *SearchMessageRequest) GetMessageTypes() []int32 {
+	if x != nil {
+		return x.MessageTypes
+	}
+	return nil
+}
+
+func (x *SearchMessageRequest) GetBeforeTime() int64 {
+	if x != nil {
+		return x.BeforeTime
+	}
+	return 0
+}
+
+func (x *SearchMessageRequest) GetBeforeMsgId() string {
+	if x != nil {
+		return x.BeforeMsgId
+	}
+	return ""
+}
+
+func (x *SearchMessageRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// 搜索消息响应
+type SearchMessageResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode       ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg        string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	RequestId       string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Messages        []*MessageInfo         `protobuf:"bytes,4,rep,name=messages,proto3" json:"messages,omitempty"` // 按发送时间倒序
+	HasMore         bool                   `protobuf:"varint,5,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	NextBeforeTime  int64                  `protobuf:"varint,6,opt,name=next_before_time,json=nextBeforeTime,proto3" json:"next_before_time,omitempty"`
+	NextBeforeMsgId string                 `protobuf:"bytes,7,opt,name=next_before_msg_id,json=nextBeforeMsgId,proto3" json:"next_before_msg_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SearchMessageResponse) Reset() {
+	*x = SearchMessageResponse{}
+	mi := &file_im_protocol_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchMessageResponse) ProtoMessage() {}
+
+func (x *SearchMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchMessageResponse.ProtoReflect.Descriptor instead.
+func (*SearchMessageResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *SearchMessageResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *SearchMessageResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *SearchMessageResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SearchMessageResponse) GetMessages() []*MessageInfo {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *SearchMessageResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *SearchMessageResponse) GetNextBeforeTime() int64 {
+	if x != nil {
+		return x.NextBeforeTime
+	}
+	return 0
+}
+
+func (x *SearchMessageResponse) GetNextBeforeMsgId() string {
+	if x != nil {
+		return x.NextBeforeMsgId
+	}
+	return ""
+}
+
+// 设置群组内容审核严格程度请求（群主和管理员）
+type SetModerationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Strictness    int32                  `protobuf:"varint,2,opt,name=strictness,proto3" json:"strictness,omitempty"` // 1: 宽松，2: 标准，3: 严格（0 表示恢复全局默认值）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetModerationRequest) Reset() {
+	*x = SetModerationRequest{}
+	mi := &file_im_protocol_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetModerationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetModerationRequest) ProtoMessage() {}
+
+func (x *SetModerationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetModerationRequest.ProtoReflect.Descriptor instead.
+func (*SetModerationRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *SetModerationRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *SetModerationRequest) GetStrictness() int32 {
+	if x != nil {
+		return x.Strictness
+	}
+	return 0
+}
+
+// 获取群组内容审核严格程度请求
+type GetModerationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       string                 `protobuf:"bytes,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetModerationRequest) Reset() {
+	*x = GetModerationRequest{}
+	mi := &file_im_protocol_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetModerationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetModerationRequest) ProtoMessage() {}
+
+func (x *GetModerationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetModerationRequest.ProtoReflect.Descriptor instead.
+func (*GetModerationRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *GetModerationRequest) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+// 群组内容审核严格程度响应（设置和获取共用）
+type ModerationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	GroupId       string                 `protobuf:"bytes,3,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Strictness    int32                  `protobuf:"varint,4,opt,name=strictness,proto3" json:"strictness,omitempty"`                   // 生效的严格程度
+	IsOverride    bool                   `protobuf:"varint,5,opt,name=is_override,json=isOverride,proto3" json:"is_override,omitempty"` // 是否是群组单独设置的（否则为全局默认值）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerationResponse) Reset() {
+	*x = ModerationResponse{}
+	mi := &file_im_protocol_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationResponse) ProtoMessage() {}
+
+func (x *ModerationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationResponse.ProtoReflect.Descriptor instead.
+func (*ModerationResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *ModerationResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *ModerationResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ModerationResponse) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *ModerationResponse) GetStrictness() int32 {
+	if x != nil {
+		return x.Strictness
+	}
+	return 0
+}
+
+func (x *ModerationResponse) GetIsOverride() bool {
+	if x != nil {
+		return x.IsOverride
+	}
+	return false
+}
+
+// 分页拉取话题回复请求
+type ThreadRepliesRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RequestId      string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // 请求唯一标识
+	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	RootSeq        int64                  `protobuf:"varint,3,opt,name=root_seq,json=rootSeq,proto3" json:"root_seq,omitempty"`    // 话题根消息 seq
+	AfterSeq       int64                  `protobuf:"varint,4,opt,name=after_seq,json=afterSeq,proto3" json:"after_seq,omitempty"` // 游标：只返回 seq 大于该值的回复（首次传 0）
+	Count          int32                  `protobuf:"varint,5,opt,name=count,proto3" json:"count,omitempty"`                       // 单次拉取数量（默认 50，最大 200）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ThreadRepliesRequest) Reset() {
+	*x = ThreadRepliesRequest{}
+	mi := &file_im_protocol_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThreadRepliesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreadRepliesRequest) ProtoMessage() {}
+
+func (x *ThreadRepliesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreadRepliesRequest.ProtoReflect.Descriptor instead.
+func (*ThreadRepliesRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *ThreadRepliesRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ThreadRepliesRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ThreadRepliesRequest) GetRootSeq() int64 {
+	if x != nil {
+		return x.RootSeq
+	}
+	return 0
+}
+
+func (x *ThreadRepliesRequest) GetAfterSeq() int64 {
+	if x != nil {
+		return x.AfterSeq
+	}
+	return 0
+}
+
+func (x *ThreadRepliesRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// 分页拉取话题回复响应
+type ThreadRepliesResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode      ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg       string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	RequestId      string                 `protobuf:"bytes,3,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ConversationId string                 `protobuf:"bytes,4,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	RootMessage    *MessageInfo           `protobuf:"bytes,5,opt,name=root_message,json=rootMessage,proto3" json:"root_message,omitempty"` // 话题根消息（包含回复数）
+	Replies        []*MessageInfo         `protobuf:"bytes,6,rep,name=replies,proto3" json:"replies,omitempty"`                            // 回复列表（按 seq 升序）
+	HasMore        bool                   `protobuf:"varint,7,opt,name=has_more,json=hasMore,proto3" json:"has_more,omitempty"`
+	NextAfterSeq   int64                  `protobuf:"varint,8,opt,name=next_after_seq,json=nextAfterSeq,proto3" json:"next_after_seq,omitempty"` // 下一页游标
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ThreadRepliesResponse) Reset() {
+	*x = ThreadRepliesResponse{}
+	mi := &file_im_protocol_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThreadRepliesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreadRepliesResponse) ProtoMessage() {}
+
+func (x *ThreadRepliesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreadRepliesResponse.ProtoReflect.Descriptor instead.
+func (*ThreadRepliesResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *ThreadRepliesResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *ThreadRepliesResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ThreadRepliesResponse) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *ThreadRepliesResponse) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ThreadRepliesResponse) GetRootMessage() *MessageInfo {
+	if x != nil {
+		return x.RootMessage
+	}
+	return nil
+}
+
+func (x *ThreadRepliesResponse) GetReplies() []*MessageInfo {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
+}
+
+func (x *ThreadRepliesResponse) GetHasMore() bool {
+	if x != nil {
+		return x.HasMore
+	}
+	return false
+}
+
+func (x *ThreadRepliesResponse) GetNextAfterSeq() int64 {
+	if x != nil {
+		return x.NextAfterSeq
+	}
+	return 0
+}
+
+// 已读回执请求
+type ReadReceiptRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServerMsgIds   []string               `protobuf:"bytes,1,rep,name=server_msg_ids,json=serverMsgIds,proto3" json:"server_msg_ids,omitempty"` // ✅ 服务器消息 ID 列表（读到其中 seq 最大的一条）
+	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	ReadSeq        int64                  `protobuf:"varint,3,opt,name=read_seq,json=readSeq,proto3" json:"read_seq,omitempty"` // 读到的 seq（优先于 server_msg_ids；两者都为空表示读到最新）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReadReceiptRequest) Reset() {
+	*x = ReadReceiptRequest{}
+	mi := &file_im_protocol_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadReceiptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadReceiptRequest) ProtoMessage() {}
+
+func (x *ReadReceiptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadReceiptRequest.ProtoReflect.Descriptor instead.
+func (*ReadReceiptRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *ReadReceiptRequest) GetServerMsgIds() []string {
+	if x != nil {
+		return x.ServerMsgIds
+	}
+	return nil
+}
+
+func (x *ReadReceiptRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ReadReceiptRequest) GetReadSeq() int64 {
+	if x != nil {
+		return x.ReadSeq
+	}
+	return 0
+}
+
+// 已读回执响应
+type ReadReceiptResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ReadSeq       int64                  `protobuf:"varint,3,opt,name=read_seq,json=readSeq,proto3" json:"read_seq,omitempty"` // 当前的已读位置
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadReceiptResponse) Reset() {
+	*x = ReadReceiptResponse{}
+	mi := &file_im_protocol_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadReceiptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadReceiptResponse) ProtoMessage() {}
+
+func (x *ReadReceiptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadReceiptResponse.ProtoReflect.Descriptor instead.
+func (*ReadReceiptResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *ReadReceiptResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *ReadReceiptResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ReadReceiptResponse) GetReadSeq() int64 {
+	if x != nil {
+		return x.ReadSeq
+	}
+	return 0
+}
+
+// 已读回执推送
+type ReadReceiptPush struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ServerMsgIds   []string               `protobuf:"bytes,1,rep,name=server_msg_ids,json=serverMsgIds,proto3" json:"server_msg_ids,omitempty"` // 已废弃，不再填充（以 read_seq 为准）
+	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ReadTime       int64                  `protobuf:"varint,4,opt,name=read_time,json=readTime,proto3" json:"read_time,omitempty"`
+	ReadSeq        int64                  `protobuf:"varint,5,opt,name=read_seq,json=readSeq,proto3" json:"read_seq,omitempty"` // 对方的已读位置（seq <= read_seq 的消息都已读）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReadReceiptPush) Reset() {
+	*x = ReadReceiptPush{}
+	mi := &file_im_protocol_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadReceiptPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadReceiptPush) ProtoMessage() {}
+
+func (x *ReadReceiptPush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadReceiptPush.ProtoReflect.Descriptor instead.
+func (*ReadReceiptPush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *ReadReceiptPush) GetServerMsgIds() []string {
+	if x != nil {
+		return x.ServerMsgIds
+	}
+	return nil
+}
+
+func (x *ReadReceiptPush) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ReadReceiptPush) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReadReceiptPush) GetReadTime() int64 {
+	if x != nil {
+		return x.ReadTime
+	}
+	return 0
+}
+
+func (x *ReadReceiptPush) GetReadSeq() int64 {
+	if x != nil {
+		return x.ReadSeq
+	}
+	return 0
+}
+
+// 消息的已读情况（发送者视角）
+type MessageReadState struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ServerMsgId   string                 `protobuf:"bytes,1,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`
+	Seq           int64                  `protobuf:"varint,2,opt,name=seq,proto3" json:"seq,omitempty"`
+	ReadCount     int32                  `protobuf:"varint,3,opt,name=read_count,json=readCount,proto3" json:"read_count,omitempty"`       // 已读人数
+	UnreadCount   int32                  `protobuf:"varint,4,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"` // 未读人数（消息发送时已在群里的现有成员）
+	ReadBy        []string               `protobuf:"bytes,5,rep,name=read_by,json=readBy,proto3" json:"read_by,omitempty"`                 // 已读成员（群人数超过上限时为空，可通过 CMD_READ_MEMBERS_REQ 查询）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageReadState) Reset() {
+	*x = MessageReadState{}
+	mi := &file_im_protocol_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageReadState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageReadState) ProtoMessage() {}
+
+func (x *MessageReadState) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageReadState.ProtoReflect.Descriptor instead.
+func (*MessageReadState) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *MessageReadState) GetServerMsgId() string {
+	if x != nil {
+		return x.ServerMsgId
+	}
+	return ""
+}
+
+func (x *MessageReadState) GetSeq() int64 {
+	if x != nil {
+		return x.Seq
+	}
+	return 0
+}
+
+func (x *MessageReadState) GetReadCount() int32 {
+	if x != nil {
+		return x.ReadCount
+	}
+	return 0
+}
+
+func (x *MessageReadState) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *MessageReadState) GetReadBy() []string {
+	if x != nil {
+		return x.ReadBy
+	}
+	return nil
+}
+
+// 群聊已读人数推送（同一会话中同一发送者的消息在一个周期内聚合推送）
+type GroupReadReceiptPush struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	States         []*MessageReadState    `protobuf:"bytes,2,rep,name=states,proto3" json:"states,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GroupReadReceiptPush) Reset() {
+	*x = GroupReadReceiptPush{}
+	mi := &file_im_protocol_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GroupReadReceiptPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupReadReceiptPush) ProtoMessage() {}
+
+func (x *GroupReadReceiptPush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupReadReceiptPush.ProtoReflect.Descriptor instead.
+func (*GroupReadReceiptPush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *GroupReadReceiptPush) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *GroupReadReceiptPush) GetStates() []*MessageReadState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+// 查询消息已读/未读成员请求（仅消息发送者）
+type ReadMembersRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	ServerMsgId    string                 `protobuf:"bytes,2,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReadMembersRequest) Reset() {
+	*x = ReadMembersRequest{}
+	mi := &file_im_protocol_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadMembersRequest) ProtoMessage() {}
+
+func (x *ReadMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadMembersRequest.ProtoReflect.Descriptor instead.
+func (*ReadMembersRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *ReadMembersRequest) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *ReadMembersRequest) GetServerMsgId() string {
+	if x != nil {
+		return x.ServerMsgId
+	}
+	return ""
+}
+
+// 已读成员
+type ReadMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ReadTime      int64                  `protobuf:"varint,2,opt,name=read_time,json=readTime,proto3" json:"read_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadMember) Reset() {
+	*x = ReadMember{}
+	mi := &file_im_protocol_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadMember) ProtoMessage() {}
+
+func (x *ReadMember) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadMember.ProtoReflect.Descriptor instead.
+func (*ReadMember) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *ReadMember) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReadMember) GetReadTime() int64 {
+	if x != nil {
+		return x.ReadTime
+	}
+	return 0
+}
+
+// 查询消息已读/未读成员响应
+type ReadMembersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	ServerMsgId   string                 `protobuf:"bytes,3,opt,name=server_msg_id,json=serverMsgId,proto3" json:"server_msg_id,omitempty"`
+	ReadMembers   []*ReadMember          `protobuf:"bytes,4,rep,name=read_members,json=readMembers,proto3" json:"read_members,omitempty"` // 按读取时间排序
+	UnreadUserIds []string               `protobuf:"bytes,5,rep,name=unread_user_ids,json=unreadUserIds,proto3" json:"unread_user_ids,omitempty"`
+	ReadCount     int32                  `protobuf:"varint,6,opt,name=read_count,json=readCount,proto3" json:"read_count,omitempty"`
+	UnreadCount   int32                  `protobuf:"varint,7,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadMembersResponse) Reset() {
+	*x = ReadMembersResponse{}
+	mi := &file_im_protocol_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadMembersResponse) ProtoMessage() {}
+
+func (x *ReadMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadMembersResponse.ProtoReflect.Descriptor instead.
+func (*ReadMembersResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *ReadMembersResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *ReadMembersResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *ReadMembersResponse) GetServerMsgId() string {
+	if x != nil {
+		return x.ServerMsgId
+	}
+	return ""
+}
+
+func (x *ReadMembersResponse) GetReadMembers() []*ReadMember {
+	if x != nil {
+		return x.ReadMembers
+	}
+	return nil
+}
+
+func (x *ReadMembersResponse) GetUnreadUserIds() []string {
+	if x != nil {
+		return x.UnreadUserIds
+	}
+	return nil
+}
+
+func (x *ReadMembersResponse) GetReadCount() int32 {
+	if x != nil {
+		return x.ReadCount
+	}
+	return 0
+}
+
+func (x *ReadMembersResponse) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+// 未读数变化推送（新消息、已读、删除、撤回等导致会话未读数变化时推送）
+type UnreadCountPush struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ConversationId string                 `protobuf:"bytes,1,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
+	UnreadCount    int32                  `protobuf:"varint,2,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"` // 会话的未读数
+	TotalUnread    int32                  `protobuf:"varint,3,opt,name=total_unread,json=totalUnread,proto3" json:"total_unread,omitempty"` // 所有会话的未读总数（应用角标）
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UnreadCountPush) Reset() {
+	*x = UnreadCountPush{}
+	mi := &file_im_protocol_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnreadCountPush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnreadCountPush) ProtoMessage() {}
+
+func (x *UnreadCountPush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnreadCountPush.ProtoReflect.Descriptor instead.
+func (*UnreadCountPush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *UnreadCountPush) GetConversationId() string {
+	if x != nil {
+		return x.ConversationId
+	}
+	return ""
+}
+
+func (x *UnreadCountPush) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *UnreadCountPush) GetTotalUnread() int32 {
+	if x != nil {
+		return x.TotalUnread
+	}
+	return 0
+}
+
+// 查询/订阅在线状态请求
+type OnlineStatusRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserIds        []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`                      // 要查询的用户（最多 200 个）
+	Subscribe      bool                   `protobuf:"varint,2,opt,name=subscribe,proto3" json:"subscribe,omitempty"`                                // 同时订阅这些用户的在线状态变化
+	UnsubscribeIds []string               `protobuf:"bytes,3,rep,name=unsubscribe_ids,json=unsubscribeIds,proto3" json:"unsubscribe_ids,omitempty"` // 取消订阅的用户
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OnlineStatusRequest) Reset() {
+	*x = OnlineStatusRequest{}
+	mi := &file_im_protocol_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnlineStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnlineStatusRequest) ProtoMessage() {}
+
+func (x *OnlineStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnlineStatusRequest.ProtoReflect.Descriptor instead.
+func (*OnlineStatusRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *OnlineStatusRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *OnlineStatusRequest) GetSubscribe() bool {
+	if x != nil {
+		return x.Subscribe
+	}
+	return false
+}
+
+func (x *OnlineStatusRequest) GetUnsubscribeIds() []string {
+	if x != nil {
+		return x.UnsubscribeIds
+	}
+	return nil
+}
+
+// 查询在线状态响应
+type OnlineStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	Presences     []*UserPresence        `protobuf:"bytes,3,rep,name=presences,proto3" json:"presences,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnlineStatusResponse) Reset() {
+	*x = OnlineStatusResponse{}
+	mi := &file_im_protocol_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnlineStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnlineStatusResponse) ProtoMessage() {}
+
+func (x *OnlineStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnlineStatusResponse.ProtoReflect.Descriptor instead.
+func (*OnlineStatusResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *OnlineStatusResponse) GetErrorCode() ErrorCode {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ErrorCode_ERR_SUCCESS
+}
+
+func (x *OnlineStatusResponse) GetErrorMsg() string {
+	if x != nil {
+		return x.ErrorMsg
+	}
+	return ""
+}
+
+func (x *OnlineStatusResponse) GetPresences() []*UserPresence {
+	if x != nil {
+		return x.Presences
+	}
+	return nil
+}
+
+// 用户在线状态
+type UserPresence struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Online        bool                   `protobuf:"varint,2,opt,name=online,proto3" json:"online,omitempty"`
+	Platforms     []*PlatformPresence    `protobuf:"bytes,3,rep,name=platforms,proto3" json:"platforms,omitempty"`                // 在线的平台（离线时为空）
+	LastSeen      int64                  `protobuf:"varint,4,opt,name=last_seen,json=lastSeen,proto3" json:"last_seen,omitempty"` // 最后在线时间（毫秒，在线、未知或对方设置了不可见时为 0）
+	Status        *CustomStatus          `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`                      // 自定义状态（未设置或已过期时为空）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserPresence) Reset() {
+	*x = UserPresence{}
+	mi := &file_im_protocol_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserPresence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserPresence) ProtoMessage() {}
+
+func (x *UserPresence) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserPresence.ProtoReflect.Descriptor instead.
+func (*UserPresence) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *UserPresence) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserPresence) GetOnline() bool {
+	if x != nil {
+		return x.Online
+	}
+	return false
+}
+
+func (x *UserPresence) GetPlatforms() []*PlatformPresence {
+	if x != nil {
+		return x.Platforms
+	}
+	return nil
+}
+
+func (x *UserPresence) GetLastSeen() int64 {
+	if x != nil {
+		return x.LastSeen
+	}
+	return 0
+}
+
+func (x *UserPresence) GetStatus() *CustomStatus {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+// 自定义状态
+type CustomStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          int32                  `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`                         // 1: 忙碌, 2: 离开, 3: 自定义
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`                          // 状态文字
+	Emoji         string                 `protobuf:"bytes,3,opt,name=emoji,proto3" json:"emoji,omitempty"`                        // 状态表情
+	ExpireAt      int64                  `protobuf:"varint,4,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"` // 过期时间（毫秒，0 表示不过期）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomStatus) Reset() {
+	*x = CustomStatus{}
+	mi := &file_im_protocol_proto_msgTypes[101]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomStatus) ProtoMessage() {}
+
+func (x *CustomStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[101]
 	if x != nil {
-		return x.ConversationId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *SyncRangeResponse) GetMessages() []*MessageInfo {
+// Deprecated: Use CustomStatus.ProtoReflect.Descriptor instead.
+func (*CustomStatus) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{101}
+}
+
+func (x *CustomStatus) GetType() int32 {
 	if x != nil {
-		return x.Messages
+		return x.Type
 	}
-	return nil
+	return 0
 }
 
-func (x *SyncRangeResponse) GetStartSeq() int64 {
+func (x *CustomStatus) GetText() string {
 	if x != nil {
-		return x.StartSeq
+		return x.Text
 	}
-	return 0
+	return ""
 }
 
-func (x *SyncRangeResponse) GetEndSeq() int64 {
+func (x *CustomStatus) GetEmoji() string {
 	if x != nil {
-		return x.EndSeq
+		return x.Emoji
 	}
-	return 0
+	return ""
 }
 
-func (x *SyncRangeResponse) GetHasMore() bool {
+func (x *CustomStatus) GetExpireAt() int64 {
 	if x != nil {
-		return x.HasMore
+		return x.ExpireAt
 	}
-	return false
+	return 0
 }
 
-// 已读回执请求
-type ReadReceiptRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ServerMsgIds   []string               `protobuf:"bytes,1,rep,name=server_msg_ids,json=serverMsgIds,proto3" json:"server_msg_ids,omitempty"` // ✅ 服务器消息 ID 列表
-	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+// 设置自定义状态和最后在线时间可见范围请求
+type SetPresenceRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Fields             []string               `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty"`                                                      // 要修改的字段：status / last_seen_visibility
+	Status             *CustomStatus          `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`                                                      // 为空或 type 为 0 表示清除自定义状态
+	LastSeenVisibility int32                  `protobuf:"varint,3,opt,name=last_seen_visibility,json=lastSeenVisibility,proto3" json:"last_seen_visibility,omitempty"` // 0: 所有人, 1: 仅好友, 2: 所有人都不可见
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
-func (x *ReadReceiptRequest) Reset() {
-	*x = ReadReceiptRequest{}
-	mi := &file_im_protocol_proto_msgTypes[22]
+func (x *SetPresenceRequest) Reset() {
+	*x = SetPresenceRequest{}
+	mi := &file_im_protocol_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReadReceiptRequest) String() string {
+func (x *SetPresenceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadReceiptRequest) ProtoMessage() {}
+func (*SetPresenceRequest) ProtoMessage() {}
 
-func (x *ReadReceiptRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[22]
+func (x *SetPresenceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1803,49 +7868,58 @@ func (x *ReadReceiptRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadReceiptRequest.ProtoReflect.Descriptor instead.
-func (*ReadReceiptRequest) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use SetPresenceRequest.ProtoReflect.Descriptor instead.
+func (*SetPresenceRequest) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{102}
 }
 
-func (x *ReadReceiptRequest) GetServerMsgIds() []string {
+func (x *SetPresenceRequest) GetFields() []string {
 	if x != nil {
-		return x.ServerMsgIds
+		return x.Fields
 	}
 	return nil
 }
 
-func (x *ReadReceiptRequest) GetConversationId() string {
+func (x *SetPresenceRequest) GetStatus() *CustomStatus {
 	if x != nil {
-		return x.ConversationId
+		return x.Status
 	}
-	return ""
+	return nil
 }
 
-// 已读回执响应
-type ReadReceiptResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ErrorCode     ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
-	ErrorMsg      string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *SetPresenceRequest) GetLastSeenVisibility() int32 {
+	if x != nil {
+		return x.LastSeenVisibility
+	}
+	return 0
 }
 
-func (x *ReadReceiptResponse) Reset() {
-	*x = ReadReceiptResponse{}
-	mi := &file_im_protocol_proto_msgTypes[23]
+// 设置自定义状态和最后在线时间可见范围响应
+type SetPresenceResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	ErrorCode          ErrorCode              `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3,enum=im.protocol.ErrorCode" json:"error_code,omitempty"`
+	ErrorMsg           string                 `protobuf:"bytes,2,opt,name=error_msg,json=errorMsg,proto3" json:"error_msg,omitempty"`
+	Presence           *UserPresence          `protobuf:"bytes,3,opt,name=presence,proto3" json:"presence,omitempty"` // 自己当前的在线状态
+	LastSeenVisibility int32                  `protobuf:"varint,4,opt,name=last_seen_visibility,json=lastSeenVisibility,proto3" json:"last_seen_visibility,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SetPresenceResponse) Reset() {
+	*x = SetPresenceResponse{}
+	mi := &file_im_protocol_proto_msgTypes[103]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReadReceiptResponse) String() string {
+func (x *SetPresenceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadReceiptResponse) ProtoMessage() {}
+func (*SetPresenceResponse) ProtoMessage() {}
 
-func (x *ReadReceiptResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[23]
+func (x *SetPresenceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[103]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1856,51 +7930,63 @@ func (x *ReadReceiptResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadReceiptResponse.ProtoReflect.Descriptor instead.
-func (*ReadReceiptResponse) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use SetPresenceResponse.ProtoReflect.Descriptor instead.
+func (*SetPresenceResponse) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{103}
 }
 
-func (x *ReadReceiptResponse) GetErrorCode() ErrorCode {
+func (x *SetPresenceResponse) GetErrorCode() ErrorCode {
 	if x != nil {
 		return x.ErrorCode
 	}
 	return ErrorCode_ERR_SUCCESS
 }
 
-func (x *ReadReceiptResponse) GetErrorMsg() string {
+func (x *SetPresenceResponse) GetErrorMsg() string {
 	if x != nil {
 		return x.ErrorMsg
 	}
 	return ""
 }
 
-// 已读回执推送
-type ReadReceiptPush struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ServerMsgIds   []string               `protobuf:"bytes,1,rep,name=server_msg_ids,json=serverMsgIds,proto3" json:"server_msg_ids,omitempty"` // ✅ 服务器消息 ID 列表
-	ConversationId string                 `protobuf:"bytes,2,opt,name=conversation_id,json=conversationId,proto3" json:"conversation_id,omitempty"`
-	UserId         string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ReadTime       int64                  `protobuf:"varint,4,opt,name=read_time,json=readTime,proto3" json:"read_time,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *SetPresenceResponse) GetPresence() *UserPresence {
+	if x != nil {
+		return x.Presence
+	}
+	return nil
 }
 
-func (x *ReadReceiptPush) Reset() {
-	*x = ReadReceiptPush{}
-	mi := &file_im_protocol_proto_msgTypes[24]
+func (x *SetPresenceResponse) GetLastSeenVisibility() int32 {
+	if x != nil {
+		return x.LastSeenVisibility
+	}
+	return 0
+}
+
+// 平台在线状态
+type PlatformPresence struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Platform      string                 `protobuf:"bytes,1,opt,name=platform,proto3" json:"platform,omitempty"`                           // ios / android / web / pc 等（登录时上报）
+	OnlineSince   int64                  `protobuf:"varint,2,opt,name=online_since,json=onlineSince,proto3" json:"online_since,omitempty"` // 上线时间（毫秒）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlatformPresence) Reset() {
+	*x = PlatformPresence{}
+	mi := &file_im_protocol_proto_msgTypes[104]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReadReceiptPush) String() string {
+func (x *PlatformPresence) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReadReceiptPush) ProtoMessage() {}
+func (*PlatformPresence) ProtoMessage() {}
 
-func (x *ReadReceiptPush) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[24]
+func (x *PlatformPresence) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[104]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1911,37 +7997,68 @@ func (x *ReadReceiptPush) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReadReceiptPush.ProtoReflect.Descriptor instead.
-func (*ReadReceiptPush) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use PlatformPresence.ProtoReflect.Descriptor instead.
+func (*PlatformPresence) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{104}
 }
 
-func (x *ReadReceiptPush) GetServerMsgIds() []string {
+func (x *PlatformPresence) GetPlatform() string {
 	if x != nil {
-		return x.ServerMsgIds
+		return x.Platform
 	}
-	return nil
+	return ""
 }
 
-func (x *ReadReceiptPush) GetConversationId() string {
+func (x *PlatformPresence) GetOnlineSince() int64 {
 	if x != nil {
-		return x.ConversationId
+		return x.OnlineSince
 	}
-	return ""
+	return 0
 }
 
-func (x *ReadReceiptPush) GetUserId() string {
+// 在线状态变化推送（推送给订阅者）
+type StatusChangePush struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Presence      *UserPresence          `protobuf:"bytes,1,opt,name=presence,proto3" json:"presence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusChangePush) Reset() {
+	*x = StatusChangePush{}
+	mi := &file_im_protocol_proto_msgTypes[105]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusChangePush) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusChangePush) ProtoMessage() {}
+
+func (x *StatusChangePush) ProtoReflect() protoreflect.Message {
+	mi := &file_im_protocol_proto_msgTypes[105]
 	if x != nil {
-		return x.UserId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ReadReceiptPush) GetReadTime() int64 {
+// Deprecated: Use StatusChangePush.ProtoReflect.Descriptor instead.
+func (*StatusChangePush) Descriptor() ([]byte, []int) {
+	return file_im_protocol_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *StatusChangePush) GetPresence() *UserPresence {
 	if x != nil {
-		return x.ReadTime
+		return x.Presence
 	}
-	return 0
+	return nil
 }
 
 // 输入状态请求
@@ -1955,7 +8072,7 @@ type TypingStatusRequest struct {
 
 func (x *TypingStatusRequest) Reset() {
 	*x = TypingStatusRequest{}
-	mi := &file_im_protocol_proto_msgTypes[25]
+	mi := &file_im_protocol_proto_msgTypes[106]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1967,7 +8084,7 @@ func (x *TypingStatusRequest) String() string {
 func (*TypingStatusRequest) ProtoMessage() {}
 
 func (x *TypingStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[25]
+	mi := &file_im_protocol_proto_msgTypes[106]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1980,7 +8097,7 @@ func (x *TypingStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TypingStatusRequest.ProtoReflect.Descriptor instead.
 func (*TypingStatusRequest) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{25}
+	return file_im_protocol_proto_rawDescGZIP(), []int{106}
 }
 
 func (x *TypingStatusRequest) GetConversationId() string {
@@ -2009,7 +8126,7 @@ type TypingStatusPush struct {
 
 func (x *TypingStatusPush) Reset() {
 	*x = TypingStatusPush{}
-	mi := &file_im_protocol_proto_msgTypes[26]
+	mi := &file_im_protocol_proto_msgTypes[107]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2021,7 +8138,7 @@ func (x *TypingStatusPush) String() string {
 func (*TypingStatusPush) ProtoMessage() {}
 
 func (x *TypingStatusPush) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[26]
+	mi := &file_im_protocol_proto_msgTypes[107]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2034,7 +8151,7 @@ func (x *TypingStatusPush) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TypingStatusPush.ProtoReflect.Descriptor instead.
 func (*TypingStatusPush) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{26}
+	return file_im_protocol_proto_rawDescGZIP(), []int{107}
 }
 
 func (x *TypingStatusPush) GetConversationId() string {
@@ -2076,7 +8193,7 @@ type WebSocketMessage struct {
 
 func (x *WebSocketMessage) Reset() {
 	*x = WebSocketMessage{}
-	mi := &file_im_protocol_proto_msgTypes[27]
+	mi := &file_im_protocol_proto_msgTypes[108]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2088,7 +8205,7 @@ func (x *WebSocketMessage) String() string {
 func (*WebSocketMessage) ProtoMessage() {}
 
 func (x *WebSocketMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_im_protocol_proto_msgTypes[27]
+	mi := &file_im_protocol_proto_msgTypes[108]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2101,7 +8218,7 @@ func (x *WebSocketMessage) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WebSocketMessage.ProtoReflect.Descriptor instead.
 func (*WebSocketMessage) Descriptor() ([]byte, []int) {
-	return file_im_protocol_proto_rawDescGZIP(), []int{27}
+	return file_im_protocol_proto_rawDescGZIP(), []int{108}
 }
 
 func (x *WebSocketMessage) GetCommand() CommandType {
@@ -2177,7 +8294,7 @@ const file_im_protocol_proto_rawDesc = "" +
 	"\amax_seq\x18\x03 \x01(\x03R\x06maxSeq\"G\n" +
 	"\x13KickOutNotification\x12\x16\n" +
 	"\x06reason\x18\x01 \x01(\x05R\x06reason\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\xd4\x05\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x80\v\n" +
 	"\vMessageInfo\x12\"\n" +
 	"\rserver_msg_id\x18\x01 \x01(\tR\vserverMsgId\x12\"\n" +
 	"\rclient_msg_id\x18\x02 \x01(\tR\vclientMsgId\x12'\n" +
@@ -2208,9 +8325,43 @@ const file_im_protocol_proto_rawDesc = "" +
 	"revoked_by\x18\x14 \x01(\tR\trevokedBy\x12!\n" +
 	"\frevoked_time\x18\x15 \x01(\x03R\vrevokedTime\x12#\n" +
 	"\rattached_info\x18\x16 \x01(\tR\fattachedInfo\x12+\n" +
-	"\x11conversation_type\x18\x17 \x01(\x05R\x10conversationType\"H\n" +
+	"\x11conversation_type\x18\x17 \x01(\x05R\x10conversationType\x12!\n" +
+	"\fedit_version\x18\x18 \x01(\x05R\veditVersion\x12\x1b\n" +
+	"\tedit_time\x18\x19 \x01(\x03R\beditTime\x12:\n" +
+	"\treactions\x18\x1a \x03(\v2\x1c.im.protocol.ReactionSummaryR\treactions\x12%\n" +
+	"\x0freply_to_msg_id\x18\x1b \x01(\tR\freplyToMsgId\x12 \n" +
+	"\freply_to_seq\x18\x1c \x01(\x03R\n" +
+	"replyToSeq\x12A\n" +
+	"\x0equoted_message\x18\x1d \x01(\v2\x1a.im.protocol.QuotedMessageR\rquotedMessage\x12&\n" +
+	"\x0fthread_root_seq\x18\x1e \x01(\x03R\rthreadRootSeq\x12,\n" +
+	"\x12thread_reply_count\x18\x1f \x01(\x05R\x10threadReplyCount\x123\n" +
+	"\x16thread_last_reply_time\x18  \x01(\x03R\x13threadLastReplyTime\x12(\n" +
+	"\x10mention_user_ids\x18! \x03(\tR\x0ementionUserIds\x12\x1f\n" +
+	"\vmention_all\x18\" \x01(\bR\n" +
+	"mentionAll\x12-\n" +
+	"\x13forward_from_msg_id\x18# \x01(\tR\x10forwardFromMsgId\x12\x1d\n" +
+	"\n" +
+	"expire_ttl\x18$ \x01(\x05R\texpireTtl\x12\x1f\n" +
+	"\vexpire_mode\x18% \x01(\x05R\n" +
+	"expireMode\x12\x1b\n" +
+	"\texpire_at\x18& \x01(\x03R\bexpireAt\x12\x1d\n" +
+	"\n" +
+	"read_count\x18' \x01(\x05R\treadCount\x12!\n" +
+	"\funread_count\x18( \x01(\x05R\vunreadCount\"\xd4\x01\n" +
+	"\rQuotedMessage\x12\"\n" +
+	"\rserver_msg_id\x18\x01 \x01(\tR\vserverMsgId\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x03R\x03seq\x12\x1b\n" +
+	"\tsender_id\x18\x03 \x01(\tR\bsenderId\x12!\n" +
+	"\fmessage_type\x18\x04 \x01(\x05R\vmessageType\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\fR\acontent\x12\x1b\n" +
+	"\tsend_time\x18\x06 \x01(\x03R\bsendTime\x12\x16\n" +
+	"\x06status\x18\a \x01(\x05R\x06status\"a\n" +
+	"\x0fReactionSummary\x12\x14\n" +
+	"\x05emoji\x18\x01 \x01(\tR\x05emoji\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\"\n" +
+	"\rreacted_by_me\x18\x03 \x01(\bR\vreactedByMe\"H\n" +
 	"\x12SendMessageRequest\x122\n" +
-	"\amessage\x18\x01 \x01(\v2\x18.im.protocol.MessageInfoR\amessage\"\xe4\x01\n" +
+	"\amessage\x18\x01 \x01(\v2\x18.im.protocol.MessageInfoR\amessage\"\xa5\x02\n" +
 	"\x13SendMessageResponse\x125\n" +
 	"\n" +
 	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
@@ -2219,7 +8370,9 @@ const file_im_protocol_proto_rawDesc = "" +
 	"\rclient_msg_id\x18\x04 \x01(\tR\vclientMsgId\x12\x10\n" +
 	"\x03seq\x18\x05 \x01(\x03R\x03seq\x12\x1f\n" +
 	"\vserver_time\x18\x06 \x01(\x03R\n" +
-	"serverTime\"A\n" +
+	"serverTime\x12%\n" +
+	"\x0econtent_masked\x18\a \x01(\bR\rcontentMasked\x12\x18\n" +
+	"\acontent\x18\b \x01(\fR\acontent\"A\n" +
 	"\vPushMessage\x122\n" +
 	"\amessage\x18\x01 \x01(\v2\x18.im.protocol.MessageInfoR\amessage\"B\n" +
 	"\n" +
@@ -2240,20 +8393,270 @@ const file_im_protocol_proto_rawDesc = "" +
 	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\x12\x1d\n" +
 	"\n" +
 	"revoked_by\x18\x03 \x01(\tR\trevokedBy\x12!\n" +
-	"\frevoked_time\x18\x04 \x01(\x03R\vrevokedTime\"[\n" +
+	"\frevoked_time\x18\x04 \x01(\x03R\vrevokedTime\"{\n" +
+	"\x12EditMessageRequest\x12\"\n" +
+	"\rserver_msg_id\x18\x01 \x01(\tR\vserverMsgId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\"\x8e\x02\n" +
+	"\x13EditMessageResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\"\n" +
+	"\rserver_msg_id\x18\x03 \x01(\tR\vserverMsgId\x12!\n" +
+	"\fedit_version\x18\x04 \x01(\x05R\veditVersion\x12\x1b\n" +
+	"\tedit_time\x18\x05 \x01(\x03R\beditTime\x12%\n" +
+	"\x0econtent_masked\x18\x06 \x01(\bR\rcontentMasked\x12\x18\n" +
+	"\acontent\x18\a \x01(\fR\acontent\"\xe7\x01\n" +
+	"\x0fEditMessagePush\x12\"\n" +
+	"\rserver_msg_id\x18\x01 \x01(\tR\vserverMsgId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\x12\x10\n" +
+	"\x03seq\x18\x03 \x01(\x03R\x03seq\x12\x18\n" +
+	"\acontent\x18\x04 \x01(\fR\acontent\x12\x1b\n" +
+	"\tedited_by\x18\x05 \x01(\tR\beditedBy\x12!\n" +
+	"\fedit_version\x18\x06 \x01(\x05R\veditVersion\x12\x1b\n" +
+	"\tedit_time\x18\a \x01(\x03R\beditTime\"O\n" +
+	"\x12EditHistoryRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x03R\x03seq\"\xb2\x01\n" +
+	"\x11MessageEditRecord\x12!\n" +
+	"\fedit_version\x18\x01 \x01(\x05R\veditVersion\x12\x1b\n" +
+	"\teditor_id\x18\x02 \x01(\tR\beditorId\x12\x1f\n" +
+	"\vold_content\x18\x03 \x01(\fR\n" +
+	"oldContent\x12\x1f\n" +
+	"\vnew_content\x18\x04 \x01(\fR\n" +
+	"newContent\x12\x1b\n" +
+	"\tedit_time\x18\x05 \x01(\x03R\beditTime\"\xda\x01\n" +
+	"\x13EditHistoryResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12'\n" +
+	"\x0fconversation_id\x18\x03 \x01(\tR\x0econversationId\x12\x10\n" +
+	"\x03seq\x18\x04 \x01(\x03R\x03seq\x124\n" +
+	"\x05edits\x18\x05 \x03(\v2\x1e.im.protocol.MessageEditRecordR\x05edits\"b\n" +
+	"\x0fReactionRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x03R\x03seq\x12\x14\n" +
+	"\x05emoji\x18\x03 \x01(\tR\x05emoji\"\xcd\x01\n" +
+	"\x10ReactionResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12'\n" +
+	"\x0fconversation_id\x18\x03 \x01(\tR\x0econversationId\x12\x10\n" +
+	"\x03seq\x18\x04 \x01(\x03R\x03seq\x12\x14\n" +
+	"\x05emoji\x18\x05 \x01(\tR\x05emoji\x12\x14\n" +
+	"\x05count\x18\x06 \x01(\x05R\x05count\"\xde\x01\n" +
+	"\fReactionPush\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x03R\x03seq\x12\"\n" +
+	"\rserver_msg_id\x18\x03 \x01(\tR\vserverMsgId\x12\x14\n" +
+	"\x05emoji\x18\x04 \x01(\tR\x05emoji\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06action\x18\x06 \x01(\x05R\x06action\x12\x14\n" +
+	"\x05count\x18\a \x01(\x05R\x05count\x12\x12\n" +
+	"\x04time\x18\b \x01(\x03R\x04time\"J\n" +
+	"\rForwardSource\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x03R\x03seq\"Y\n" +
+	"\rForwardTarget\x12+\n" +
+	"\x11conversation_type\x18\x01 \x01(\x05R\x10conversationType\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\tR\btargetId\"\xdd\x01\n" +
+	"\x15ForwardMessageRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x124\n" +
+	"\asources\x18\x02 \x03(\v2\x1a.im.protocol.ForwardSourceR\asources\x124\n" +
+	"\atargets\x18\x03 \x03(\v2\x1a.im.protocol.ForwardTargetR\atargets\x12\x16\n" +
+	"\x06merged\x18\x04 \x01(\bR\x06merged\x12!\n" +
+	"\fmerged_title\x18\x05 \x01(\tR\vmergedTitle\"\xc6\x01\n" +
+	"\rForwardResult\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x125\n" +
+	"\n" +
+	"error_code\x18\x02 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x03 \x01(\tR\berrorMsg\x12$\n" +
+	"\x0eserver_msg_ids\x18\x04 \x03(\tR\fserverMsgIds\x12\x12\n" +
+	"\x04seqs\x18\x05 \x03(\x03R\x04seqs\"\xc1\x01\n" +
+	"\x16ForwardMessageResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x124\n" +
+	"\aresults\x18\x04 \x03(\v2\x1a.im.protocol.ForwardResultR\aresults\"S\n" +
+	"\x14DeleteMessageRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x12\n" +
+	"\x04seqs\x18\x02 \x03(\x03R\x04seqs\"\xa8\x01\n" +
+	"\x15DeleteMessageResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12'\n" +
+	"\x0fconversation_id\x18\x03 \x01(\tR\x0econversationId\x12\x12\n" +
+	"\x04seqs\x18\x04 \x03(\x03R\x04seqs\"[\n" +
+	"\x13ClearHistoryRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x1b\n" +
+	"\tclear_seq\x18\x02 \x01(\x03R\bclearSeq\"\xb4\x01\n" +
+	"\x14ClearHistoryResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12'\n" +
+	"\x0fconversation_id\x18\x03 \x01(\tR\x0econversationId\x12\x1f\n" +
+	"\vcleared_seq\x18\x04 \x01(\x03R\n" +
+	"clearedSeq\"\x85\x01\n" +
+	"\x11DeleteMessagePush\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x12\n" +
+	"\x04seqs\x18\x02 \x03(\x03R\x04seqs\x12\x1f\n" +
+	"\vcleared_seq\x18\x03 \x01(\x03R\n" +
+	"clearedSeq\x12\x12\n" +
+	"\x04time\x18\x04 \x01(\x03R\x04time\"\xb7\x01\n" +
+	"\x11PinnedMessageInfo\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x03R\x03seq\x12\"\n" +
+	"\rserver_msg_id\x18\x02 \x01(\tR\vserverMsgId\x12\x1b\n" +
+	"\tpinned_by\x18\x03 \x01(\tR\bpinnedBy\x12\x1b\n" +
+	"\tpinned_at\x18\x04 \x01(\x03R\bpinnedAt\x122\n" +
+	"\amessage\x18\x05 \x01(\v2\x18.im.protocol.MessageInfoR\amessage\"N\n" +
+	"\x11PinMessageRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x03R\x03seq\"\xc0\x01\n" +
+	"\x12PinMessageResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12'\n" +
+	"\x0fconversation_id\x18\x03 \x01(\tR\x0econversationId\x12\x10\n" +
+	"\x03seq\x18\x04 \x01(\x03R\x03seq\x12\x1b\n" +
+	"\tpinned_at\x18\x05 \x01(\x03R\bpinnedAt\"\x85\x02\n" +
+	"\x0ePinMessagePush\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x03R\x03seq\x12\"\n" +
+	"\rserver_msg_id\x18\x03 \x01(\tR\vserverMsgId\x12\x1f\n" +
+	"\voperator_id\x18\x04 \x01(\tR\n" +
+	"operatorId\x12\x16\n" +
+	"\x06action\x18\x05 \x01(\x05R\x06action\x12\x12\n" +
+	"\x04time\x18\x06 \x01(\x03R\x04time\x12G\n" +
+	"\x0fpinned_messages\x18\a \x03(\v2\x1e.im.protocol.PinnedMessageInfoR\x0epinnedMessages\"[\n" +
+	"\x11PinnedListRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\"\xf9\x01\n" +
+	"\x12PinnedListResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12'\n" +
+	"\x0fconversation_id\x18\x04 \x01(\tR\x0econversationId\x12G\n" +
+	"\x0fpinned_messages\x18\x05 \x03(\v2\x1e.im.protocol.PinnedMessageInfoR\x0epinnedMessages\"~\n" +
+	"\x13SetEphemeralRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"expire_ttl\x18\x02 \x01(\x05R\texpireTtl\x12\x1f\n" +
+	"\vexpire_mode\x18\x03 \x01(\x05R\n" +
+	"expireMode\">\n" +
+	"\x13GetEphemeralRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\"\xd7\x01\n" +
+	"\x18EphemeralSettingResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12'\n" +
+	"\x0fconversation_id\x18\x03 \x01(\tR\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"expire_ttl\x18\x04 \x01(\x05R\texpireTtl\x12\x1f\n" +
+	"\vexpire_mode\x18\x05 \x01(\x05R\n" +
+	"expireMode\"\xb4\x01\n" +
+	"\x14EphemeralSettingPush\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"expire_ttl\x18\x02 \x01(\x05R\texpireTtl\x12\x1f\n" +
+	"\vexpire_mode\x18\x03 \x01(\x05R\n" +
+	"expireMode\x12\x1f\n" +
+	"\voperator_id\x18\x04 \x01(\tR\n" +
+	"operatorId\x12\x12\n" +
+	"\x04time\x18\x05 \x01(\x03R\x04time\"r\n" +
+	"\x12MessageExpiredPush\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x12\n" +
+	"\x04seqs\x18\x02 \x03(\x03R\x04seqs\x12\x1f\n" +
+	"\vexpire_time\x18\x03 \x01(\x03R\n" +
+	"expireTime\"e\n" +
+	"\x13SetRetentionRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12%\n" +
+	"\x0eretention_days\x18\x02 \x01(\x05R\rretentionDays\">\n" +
+	"\x13GetRetentionRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\"\xf1\x01\n" +
+	"\x11RetentionResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12'\n" +
+	"\x0fconversation_id\x18\x03 \x01(\tR\x0econversationId\x12%\n" +
+	"\x0eretention_days\x18\x04 \x01(\x05R\rretentionDays\x12\x1f\n" +
+	"\vis_override\x18\x05 \x01(\bR\n" +
+	"isOverride\x12\x17\n" +
+	"\amin_seq\x18\x06 \x01(\x03R\x06minSeq\"\x89\x02\n" +
+	"\x14ScheduledMessageInfo\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\x122\n" +
+	"\amessage\x18\x02 \x01(\v2\x18.im.protocol.MessageInfoR\amessage\x12%\n" +
+	"\x0escheduled_time\x18\x03 \x01(\x03R\rscheduledTime\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\x05R\x06status\x12\x1b\n" +
+	"\terror_msg\x18\x05 \x01(\tR\berrorMsg\x12\x1f\n" +
+	"\vcreate_time\x18\x06 \x01(\x03R\n" +
+	"createTime\x12\x1f\n" +
+	"\vupdate_time\x18\a \x01(\x03R\n" +
+	"updateTime\"s\n" +
+	"\x16ScheduleMessageRequest\x122\n" +
+	"\amessage\x18\x01 \x01(\v2\x18.im.protocol.MessageInfoR\amessage\x12%\n" +
+	"\x0escheduled_time\x18\x02 \x01(\x03R\rscheduledTime\"\xbd\x01\n" +
+	"\x17ScheduleMessageResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12N\n" +
+	"\x11scheduled_message\x18\x03 \x01(\v2!.im.protocol.ScheduledMessageInfoR\x10scheduledMessage\"^\n" +
+	"\x14ScheduledListRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\"\xdc\x01\n" +
+	"\x15ScheduledListResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12P\n" +
+	"\x12scheduled_messages\x18\x04 \x03(\v2!.im.protocol.ScheduledMessageInfoR\x11scheduledMessages\"x\n" +
+	"\x14EditScheduledRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\x12%\n" +
+	"\x0escheduled_time\x18\x03 \x01(\x03R\rscheduledTime\"9\n" +
+	"\x16CancelScheduledRequest\x12\x1f\n" +
+	"\vschedule_id\x18\x01 \x01(\tR\n" +
+	"scheduleId\"\x8e\x01\n" +
+	"\x17CancelScheduledResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x1f\n" +
+	"\vschedule_id\x18\x03 \x01(\tR\n" +
+	"scheduleId\"[\n" +
 	"\x15ConversationSyncState\x12'\n" +
 	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x19\n" +
-	"\blast_seq\x18\x02 \x01(\x03R\alastSeq\"\xa4\x01\n" +
+	"\blast_seq\x18\x02 \x01(\x03R\alastSeq\"\xca\x01\n" +
 	"\x10BatchSyncRequest\x12S\n" +
 	"\x13conversation_states\x18\x01 \x03(\v2\".im.protocol.ConversationSyncStateR\x12conversationStates\x12;\n" +
-	"\x1amax_count_per_conversation\x18\x02 \x01(\x05R\x17maxCountPerConversation\"\xc8\x01\n" +
+	"\x1amax_count_per_conversation\x18\x02 \x01(\x05R\x17maxCountPerConversation\x12$\n" +
+	"\x0elast_sync_time\x18\x03 \x01(\x03R\flastSyncTime\"\xbb\x04\n" +
 	"\x14ConversationMessages\x12'\n" +
 	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x124\n" +
 	"\bmessages\x18\x02 \x03(\v2\x18.im.protocol.MessageInfoR\bmessages\x12\x17\n" +
 	"\amax_seq\x18\x03 \x01(\x03R\x06maxSeq\x12\x1d\n" +
 	"\n" +
 	"synced_seq\x18\x04 \x01(\x03R\tsyncedSeq\x12\x19\n" +
-	"\bhas_more\x18\x05 \x01(\bR\ahasMore\"\x90\x02\n" +
+	"\bhas_more\x18\x05 \x01(\bR\ahasMore\x12C\n" +
+	"\x10updated_messages\x18\x06 \x03(\v2\x18.im.protocol.MessageInfoR\x0fupdatedMessages\x12%\n" +
+	"\x0ementioned_seqs\x18\a \x03(\x03R\rmentionedSeqs\x12!\n" +
+	"\fdeleted_seqs\x18\b \x03(\x03R\vdeletedSeqs\x12\x1f\n" +
+	"\vcleared_seq\x18\t \x01(\x03R\n" +
+	"clearedSeq\x12G\n" +
+	"\x0fpinned_messages\x18\n" +
+	" \x03(\v2\x1e.im.protocol.PinnedMessageInfoR\x0epinnedMessages\x12!\n" +
+	"\fexpired_seqs\x18\v \x03(\x03R\vexpiredSeqs\x12\x17\n" +
+	"\amin_seq\x18\f \x01(\x03R\x06minSeq\x12\x19\n" +
+	"\bread_seq\x18\r \x01(\x03R\areadSeq\x12!\n" +
+	"\funread_count\x18\x0e \x01(\x05R\vunreadCount\"\xf5\x02\n" +
 	"\x11BatchSyncResponse\x125\n" +
 	"\n" +
 	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
@@ -2261,14 +8664,110 @@ const file_im_protocol_proto_rawDesc = "" +
 	"\x15conversation_messages\x18\x03 \x03(\v2!.im.protocol.ConversationMessagesR\x14conversationMessages\x12\x1f\n" +
 	"\vserver_time\x18\x04 \x01(\x03R\n" +
 	"serverTime\x12.\n" +
-	"\x13total_message_count\x18\x05 \x01(\x05R\x11totalMessageCount\"\xa6\x01\n" +
+	"\x13total_message_count\x18\x05 \x01(\x05R\x11totalMessageCount\x12@\n" +
+	"\x1cmentioned_conversation_count\x18\x06 \x01(\x05R\x1amentionedConversationCount\x12!\n" +
+	"\ftotal_unread\x18\a \x01(\x05R\vtotalUnread\"\xbc\x04\n" +
+	"\x10ConversationInfo\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\x05R\x04type\x12\x1b\n" +
+	"\ttarget_id\x18\x03 \x01(\tR\btargetId\x12+\n" +
+	"\x12last_server_msg_id\x18\x04 \x01(\tR\x0flastServerMsgId\x12\x19\n" +
+	"\blast_seq\x18\x05 \x01(\x03R\alastSeq\x12$\n" +
+	"\x0elast_sender_id\x18\x06 \x01(\tR\flastSenderId\x12!\n" +
+	"\flast_message\x18\a \x01(\tR\vlastMessage\x12*\n" +
+	"\x11last_message_time\x18\b \x01(\x03R\x0flastMessageTime\x12!\n" +
+	"\funread_count\x18\t \x01(\x05R\vunreadCount\x12\x16\n" +
+	"\x06hidden\x18\n" +
+	" \x01(\bR\x06hidden\x12\x18\n" +
+	"\aversion\x18\v \x01(\x03R\aversion\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\f \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\x03R\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"mute_until\x18\x0e \x01(\x03R\tmuteUntil\x12\x1b\n" +
+	"\tpin_order\x18\x0f \x01(\x03R\bpinOrder\x12\x14\n" +
+	"\x05extra\x18\x10 \x01(\tR\x05extra\x12,\n" +
+	"\x05draft\x18\x11 \x01(\v2\x16.im.protocol.DraftInfoR\x05draft\"g\n" +
+	"\tDraftInfo\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12%\n" +
+	"\x0freply_to_msg_id\x18\x02 \x01(\tR\freplyToMsgId\x12\x1f\n" +
+	"\vclient_time\x18\x03 \x01(\x03R\n" +
+	"clientTime\"\xcb\x01\n" +
+	"\x1eSetConversationSettingsRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x16\n" +
+	"\x06fields\x18\x02 \x03(\tR\x06fields\x12\x1d\n" +
+	"\n" +
+	"mute_until\x18\x03 \x01(\x03R\tmuteUntil\x12\x1b\n" +
+	"\tpin_order\x18\x04 \x01(\x03R\bpinOrder\x12\x16\n" +
+	"\x06hidden\x18\x05 \x01(\bR\x06hidden\x12\x14\n" +
+	"\x05extra\x18\x06 \x01(\tR\x05extra\"\xb8\x01\n" +
+	"\x1fSetConversationSettingsResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12A\n" +
+	"\fconversation\x18\x03 \x01(\v2\x1d.im.protocol.ConversationInfoR\fconversation\"]\n" +
+	"\x18ConversationSettingsPush\x12A\n" +
+	"\fconversation\x18\x01 \x01(\v2\x1d.im.protocol.ConversationInfoR\fconversation\"\x96\x01\n" +
+	"\x0fSetDraftRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12%\n" +
+	"\x0freply_to_msg_id\x18\x03 \x01(\tR\freplyToMsgId\x12\x1f\n" +
+	"\vclient_time\x18\x04 \x01(\x03R\n" +
+	"clientTime\"\xd7\x01\n" +
+	"\x10SetDraftResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12'\n" +
+	"\x0fconversation_id\x18\x03 \x01(\tR\x0econversationId\x12\x18\n" +
+	"\aapplied\x18\x04 \x01(\bR\aapplied\x12,\n" +
+	"\x05draft\x18\x05 \x01(\v2\x16.im.protocol.DraftInfoR\x05draft\"b\n" +
+	"\tDraftPush\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12,\n" +
+	"\x05draft\x18\x02 \x01(\v2\x16.im.protocol.DraftInfoR\x05draft\"[\n" +
+	"\x13JoinChatroomRequest\x12\x1f\n" +
+	"\vchatroom_id\x18\x01 \x01(\tR\n" +
+	"chatroomId\x12#\n" +
+	"\rhistory_count\x18\x02 \x01(\x05R\fhistoryCount\"\x8b\x02\n" +
+	"\x14JoinChatroomResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x1f\n" +
+	"\vchatroom_id\x18\x03 \x01(\tR\n" +
+	"chatroomId\x12'\n" +
+	"\x0fconversation_id\x18\x04 \x01(\tR\x0econversationId\x12!\n" +
+	"\fmember_count\x18\x05 \x01(\x05R\vmemberCount\x122\n" +
+	"\ahistory\x18\x06 \x03(\v2\x18.im.protocol.MessageInfoR\ahistory\"7\n" +
+	"\x14LeaveChatroomRequest\x12\x1f\n" +
+	"\vchatroom_id\x18\x01 \x01(\tR\n" +
+	"chatroomId\"\x8c\x01\n" +
+	"\x15LeaveChatroomResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x1f\n" +
+	"\vchatroom_id\x18\x03 \x01(\tR\n" +
+	"chatroomId\"]\n" +
+	"\x17ChatroomMemberCountPush\x12\x1f\n" +
+	"\vchatroom_id\x18\x01 \x01(\tR\n" +
+	"chatroomId\x12!\n" +
+	"\fmember_count\x18\x02 \x01(\x05R\vmemberCount\"J\n" +
+	"\x18SyncConversationsRequest\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\xe9\x01\n" +
+	"\x19SyncConversationsResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12C\n" +
+	"\rconversations\x18\x03 \x03(\v2\x1d.im.protocol.ConversationInfoR\rconversations\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\x12\x19\n" +
+	"\bhas_more\x18\x05 \x01(\bR\ahasMore\"\xa6\x01\n" +
 	"\x10SyncRangeRequest\x12\x1d\n" +
 	"\n" +
 	"request_id\x18\x01 \x01(\tR\trequestId\x12'\n" +
 	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\x12\x1b\n" +
 	"\tstart_seq\x18\x03 \x01(\x03R\bstartSeq\x12\x17\n" +
 	"\aend_seq\x18\x04 \x01(\x03R\x06endSeq\x12\x14\n" +
-	"\x05count\x18\x05 \x01(\x05R\x05count\"\xb6\x02\n" +
+	"\x05count\x18\x05 \x01(\x05R\x05count\"\xcf\x02\n" +
 	"\x11SyncRangeResponse\x125\n" +
 	"\n" +
 	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
@@ -2279,19 +8778,167 @@ const file_im_protocol_proto_rawDesc = "" +
 	"\bmessages\x18\x05 \x03(\v2\x18.im.protocol.MessageInfoR\bmessages\x12\x1b\n" +
 	"\tstart_seq\x18\x06 \x01(\x03R\bstartSeq\x12\x17\n" +
 	"\aend_seq\x18\a \x01(\x03R\x06endSeq\x12\x19\n" +
-	"\bhas_more\x18\b \x01(\bR\ahasMore\"c\n" +
+	"\bhas_more\x18\b \x01(\bR\ahasMore\x12\x17\n" +
+	"\amin_seq\x18\t \x01(\x03R\x06minSeq\"\x8d\x01\n" +
+	"\x0eHistoryRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\x12\x1d\n" +
+	"\n" +
+	"before_seq\x18\x03 \x01(\x03R\tbeforeSeq\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\"\xbf\x02\n" +
+	"\x0fHistoryResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12'\n" +
+	"\x0fconversation_id\x18\x04 \x01(\tR\x0econversationId\x124\n" +
+	"\bmessages\x18\x05 \x03(\v2\x18.im.protocol.MessageInfoR\bmessages\x12&\n" +
+	"\x0fnext_before_seq\x18\x06 \x01(\x03R\rnextBeforeSeq\x12\x19\n" +
+	"\bhas_more\x18\a \x01(\bR\ahasMore\x12\x17\n" +
+	"\amin_seq\x18\b \x01(\x03R\x06minSeq\"\xcf\x02\n" +
+	"\x14SearchMessageRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x18\n" +
+	"\akeyword\x18\x02 \x01(\tR\akeyword\x12'\n" +
+	"\x0fconversation_id\x18\x03 \x01(\tR\x0econversationId\x12\x1b\n" +
+	"\tsender_id\x18\x04 \x01(\tR\bsenderId\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x05 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x06 \x01(\x03R\aendTime\x12#\n" +
+	"\rmessage_types\x18\a \x03(\x05R\fmessageTypes\x12\x1f\n" +
+	"\vbefore_time\x18\b \x01(\x03R\n" +
+	"beforeTime\x12\"\n" +
+	"\rbefore_msg_id\x18\t \x01(\tR\vbeforeMsgId\x12\x14\n" +
+	"\x05limit\x18\n" +
+	" \x01(\x05R\x05limit\"\xb2\x02\n" +
+	"\x15SearchMessageResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x124\n" +
+	"\bmessages\x18\x04 \x03(\v2\x18.im.protocol.MessageInfoR\bmessages\x12\x19\n" +
+	"\bhas_more\x18\x05 \x01(\bR\ahasMore\x12(\n" +
+	"\x10next_before_time\x18\x06 \x01(\x03R\x0enextBeforeTime\x12+\n" +
+	"\x12next_before_msg_id\x18\a \x01(\tR\x0fnextBeforeMsgId\"Q\n" +
+	"\x14SetModerationRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\x12\x1e\n" +
+	"\n" +
+	"strictness\x18\x02 \x01(\x05R\n" +
+	"strictness\"1\n" +
+	"\x14GetModerationRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\tR\agroupId\"\xc4\x01\n" +
+	"\x12ModerationResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x19\n" +
+	"\bgroup_id\x18\x03 \x01(\tR\agroupId\x12\x1e\n" +
+	"\n" +
+	"strictness\x18\x04 \x01(\x05R\n" +
+	"strictness\x12\x1f\n" +
+	"\vis_override\x18\x05 \x01(\bR\n" +
+	"isOverride\"\xac\x01\n" +
+	"\x14ThreadRepliesRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12'\n" +
+	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\x12\x19\n" +
+	"\broot_seq\x18\x03 \x01(\x03R\arootSeq\x12\x1b\n" +
+	"\tafter_seq\x18\x04 \x01(\x03R\bafterSeq\x12\x14\n" +
+	"\x05count\x18\x05 \x01(\x05R\x05count\"\xe5\x02\n" +
+	"\x15ThreadRepliesResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x03 \x01(\tR\trequestId\x12'\n" +
+	"\x0fconversation_id\x18\x04 \x01(\tR\x0econversationId\x12;\n" +
+	"\froot_message\x18\x05 \x01(\v2\x18.im.protocol.MessageInfoR\vrootMessage\x122\n" +
+	"\areplies\x18\x06 \x03(\v2\x18.im.protocol.MessageInfoR\areplies\x12\x19\n" +
+	"\bhas_more\x18\a \x01(\bR\ahasMore\x12$\n" +
+	"\x0enext_after_seq\x18\b \x01(\x03R\fnextAfterSeq\"~\n" +
 	"\x12ReadReceiptRequest\x12$\n" +
 	"\x0eserver_msg_ids\x18\x01 \x03(\tR\fserverMsgIds\x12'\n" +
-	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\"i\n" +
+	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\x12\x19\n" +
+	"\bread_seq\x18\x03 \x01(\x03R\areadSeq\"\x84\x01\n" +
 	"\x13ReadReceiptResponse\x125\n" +
 	"\n" +
 	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
-	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\"\x96\x01\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\x19\n" +
+	"\bread_seq\x18\x03 \x01(\x03R\areadSeq\"\xb1\x01\n" +
 	"\x0fReadReceiptPush\x12$\n" +
 	"\x0eserver_msg_ids\x18\x01 \x03(\tR\fserverMsgIds\x12'\n" +
 	"\x0fconversation_id\x18\x02 \x01(\tR\x0econversationId\x12\x17\n" +
 	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1b\n" +
-	"\tread_time\x18\x04 \x01(\x03R\breadTime\"V\n" +
+	"\tread_time\x18\x04 \x01(\x03R\breadTime\x12\x19\n" +
+	"\bread_seq\x18\x05 \x01(\x03R\areadSeq\"\xa3\x01\n" +
+	"\x10MessageReadState\x12\"\n" +
+	"\rserver_msg_id\x18\x01 \x01(\tR\vserverMsgId\x12\x10\n" +
+	"\x03seq\x18\x02 \x01(\x03R\x03seq\x12\x1d\n" +
+	"\n" +
+	"read_count\x18\x03 \x01(\x05R\treadCount\x12!\n" +
+	"\funread_count\x18\x04 \x01(\x05R\vunreadCount\x12\x17\n" +
+	"\aread_by\x18\x05 \x03(\tR\x06readBy\"v\n" +
+	"\x14GroupReadReceiptPush\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x125\n" +
+	"\x06states\x18\x02 \x03(\v2\x1d.im.protocol.MessageReadStateR\x06states\"a\n" +
+	"\x12ReadMembersRequest\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\"\n" +
+	"\rserver_msg_id\x18\x02 \x01(\tR\vserverMsgId\"B\n" +
+	"\n" +
+	"ReadMember\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tread_time\x18\x02 \x01(\x03R\breadTime\"\xb3\x02\n" +
+	"\x13ReadMembersResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x12\"\n" +
+	"\rserver_msg_id\x18\x03 \x01(\tR\vserverMsgId\x12:\n" +
+	"\fread_members\x18\x04 \x03(\v2\x17.im.protocol.ReadMemberR\vreadMembers\x12&\n" +
+	"\x0funread_user_ids\x18\x05 \x03(\tR\runreadUserIds\x12\x1d\n" +
+	"\n" +
+	"read_count\x18\x06 \x01(\x05R\treadCount\x12!\n" +
+	"\funread_count\x18\a \x01(\x05R\vunreadCount\"\x80\x01\n" +
+	"\x0fUnreadCountPush\x12'\n" +
+	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12!\n" +
+	"\funread_count\x18\x02 \x01(\x05R\vunreadCount\x12!\n" +
+	"\ftotal_unread\x18\x03 \x01(\x05R\vtotalUnread\"w\n" +
+	"\x13OnlineStatusRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\x12\x1c\n" +
+	"\tsubscribe\x18\x02 \x01(\bR\tsubscribe\x12'\n" +
+	"\x0funsubscribe_ids\x18\x03 \x03(\tR\x0eunsubscribeIds\"\xa3\x01\n" +
+	"\x14OnlineStatusResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x127\n" +
+	"\tpresences\x18\x03 \x03(\v2\x19.im.protocol.UserPresenceR\tpresences\"\xcc\x01\n" +
+	"\fUserPresence\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06online\x18\x02 \x01(\bR\x06online\x12;\n" +
+	"\tplatforms\x18\x03 \x03(\v2\x1d.im.protocol.PlatformPresenceR\tplatforms\x12\x1b\n" +
+	"\tlast_seen\x18\x04 \x01(\x03R\blastSeen\x121\n" +
+	"\x06status\x18\x05 \x01(\v2\x19.im.protocol.CustomStatusR\x06status\"i\n" +
+	"\fCustomStatus\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\x05R\x04type\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x14\n" +
+	"\x05emoji\x18\x03 \x01(\tR\x05emoji\x12\x1b\n" +
+	"\texpire_at\x18\x04 \x01(\x03R\bexpireAt\"\x91\x01\n" +
+	"\x12SetPresenceRequest\x12\x16\n" +
+	"\x06fields\x18\x01 \x03(\tR\x06fields\x121\n" +
+	"\x06status\x18\x02 \x01(\v2\x19.im.protocol.CustomStatusR\x06status\x120\n" +
+	"\x14last_seen_visibility\x18\x03 \x01(\x05R\x12lastSeenVisibility\"\xd2\x01\n" +
+	"\x13SetPresenceResponse\x125\n" +
+	"\n" +
+	"error_code\x18\x01 \x01(\x0e2\x16.im.protocol.ErrorCodeR\terrorCode\x12\x1b\n" +
+	"\terror_msg\x18\x02 \x01(\tR\berrorMsg\x125\n" +
+	"\bpresence\x18\x03 \x01(\v2\x19.im.protocol.UserPresenceR\bpresence\x120\n" +
+	"\x14last_seen_visibility\x18\x04 \x01(\x05R\x12lastSeenVisibility\"Q\n" +
+	"\x10PlatformPresence\x12\x1a\n" +
+	"\bplatform\x18\x01 \x01(\tR\bplatform\x12!\n" +
+	"\fonline_since\x18\x02 \x01(\x03R\vonlineSince\"I\n" +
+	"\x10StatusChangePush\x125\n" +
+	"\bpresence\x18\x01 \x01(\v2\x19.im.protocol.UserPresenceR\bpresence\"V\n" +
 	"\x13TypingStatusRequest\x12'\n" +
 	"\x0fconversation_id\x18\x01 \x01(\tR\x0econversationId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\x05R\x06status\"l\n" +
@@ -2303,7 +8950,7 @@ const file_im_protocol_proto_rawDesc = "" +
 	"\acommand\x18\x01 \x01(\x0e2\x18.im.protocol.CommandTypeR\acommand\x12\x1a\n" +
 	"\bsequence\x18\x02 \x01(\rR\bsequence\x12\x12\n" +
 	"\x04body\x18\x03 \x01(\fR\x04body\x12\x1c\n" +
-	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp*\x93\x06\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp*\xdd\x15\n" +
 	"\vCommandType\x12\x0f\n" +
 	"\vCMD_UNKNOWN\x10\x00\x12\x13\n" +
 	"\x0fCMD_CONNECT_REQ\x10\x01\x12\x13\n" +
@@ -2324,20 +8971,92 @@ const file_im_protocol_proto_rawDesc = "" +
 	"\rCMD_BATCH_MSG\x10\xcc\x01\x12\x17\n" +
 	"\x12CMD_REVOKE_MSG_REQ\x10\xcd\x01\x12\x17\n" +
 	"\x12CMD_REVOKE_MSG_RSP\x10\xce\x01\x12\x18\n" +
-	"\x13CMD_REVOKE_MSG_PUSH\x10\xcf\x01\x12\x17\n" +
+	"\x13CMD_REVOKE_MSG_PUSH\x10\xcf\x01\x12\x15\n" +
+	"\x10CMD_EDIT_MSG_REQ\x10\xd0\x01\x12\x15\n" +
+	"\x10CMD_EDIT_MSG_RSP\x10\xd1\x01\x12\x16\n" +
+	"\x11CMD_EDIT_MSG_PUSH\x10\xd2\x01\x12\x19\n" +
+	"\x14CMD_ADD_REACTION_REQ\x10\xd3\x01\x12\x19\n" +
+	"\x14CMD_ADD_REACTION_RSP\x10\xd4\x01\x12\x1c\n" +
+	"\x17CMD_REMOVE_REACTION_REQ\x10\xd5\x01\x12\x1c\n" +
+	"\x17CMD_REMOVE_REACTION_RSP\x10\xd6\x01\x12\x16\n" +
+	"\x11CMD_REACTION_PUSH\x10\xd7\x01\x12\x18\n" +
+	"\x13CMD_FORWARD_MSG_REQ\x10\xd8\x01\x12\x18\n" +
+	"\x13CMD_FORWARD_MSG_RSP\x10\xd9\x01\x12\x17\n" +
+	"\x12CMD_DELETE_MSG_REQ\x10\xda\x01\x12\x17\n" +
+	"\x12CMD_DELETE_MSG_RSP\x10\xdb\x01\x12\x1a\n" +
+	"\x15CMD_CLEAR_HISTORY_REQ\x10\xdc\x01\x12\x1a\n" +
+	"\x15CMD_CLEAR_HISTORY_RSP\x10\xdd\x01\x12\x18\n" +
+	"\x13CMD_DELETE_MSG_PUSH\x10\xde\x01\x12\x14\n" +
+	"\x0fCMD_PIN_MSG_REQ\x10\xdf\x01\x12\x14\n" +
+	"\x0fCMD_PIN_MSG_RSP\x10\xe0\x01\x12\x16\n" +
+	"\x11CMD_UNPIN_MSG_REQ\x10\xe1\x01\x12\x16\n" +
+	"\x11CMD_UNPIN_MSG_RSP\x10\xe2\x01\x12\x11\n" +
+	"\fCMD_PIN_PUSH\x10\xe3\x01\x12\x19\n" +
+	"\x14CMD_SCHEDULE_MSG_REQ\x10\xe4\x01\x12\x19\n" +
+	"\x14CMD_SCHEDULE_MSG_RSP\x10\xe5\x01\x12\x1b\n" +
+	"\x16CMD_SCHEDULED_LIST_REQ\x10\xe6\x01\x12\x1b\n" +
+	"\x16CMD_SCHEDULED_LIST_RSP\x10\xe7\x01\x12\x1b\n" +
+	"\x16CMD_SCHEDULED_EDIT_REQ\x10\xe8\x01\x12\x1b\n" +
+	"\x16CMD_SCHEDULED_EDIT_RSP\x10\xe9\x01\x12\x1d\n" +
+	"\x18CMD_SCHEDULED_CANCEL_REQ\x10\xea\x01\x12\x1d\n" +
+	"\x18CMD_SCHEDULED_CANCEL_RSP\x10\xeb\x01\x12\x1a\n" +
+	"\x15CMD_SET_EPHEMERAL_REQ\x10\xec\x01\x12\x1a\n" +
+	"\x15CMD_SET_EPHEMERAL_RSP\x10\xed\x01\x12\x1f\n" +
+	"\x1aCMD_EPHEMERAL_SETTING_PUSH\x10\xee\x01\x12\x19\n" +
+	"\x14CMD_MSG_EXPIRED_PUSH\x10\xef\x01\x12\x1a\n" +
+	"\x15CMD_SET_RETENTION_REQ\x10\xf0\x01\x12\x1a\n" +
+	"\x15CMD_SET_RETENTION_RSP\x10\xf1\x01\x12\x1b\n" +
+	"\x16CMD_SET_MODERATION_REQ\x10\xf2\x01\x12\x1b\n" +
+	"\x16CMD_SET_MODERATION_RSP\x10\xf3\x01\x12&\n" +
+	"!CMD_SET_CONVERSATION_SETTINGS_REQ\x10\xf4\x01\x12&\n" +
+	"!CMD_SET_CONVERSATION_SETTINGS_RSP\x10\xf5\x01\x12#\n" +
+	"\x1eCMD_CONVERSATION_SETTINGS_PUSH\x10\xf6\x01\x12\x16\n" +
+	"\x11CMD_SET_DRAFT_REQ\x10\xf7\x01\x12\x16\n" +
+	"\x11CMD_SET_DRAFT_RSP\x10\xf8\x01\x12\x13\n" +
+	"\x0eCMD_DRAFT_PUSH\x10\xf9\x01\x12\x1a\n" +
+	"\x15CMD_JOIN_CHATROOM_REQ\x10\xfa\x01\x12\x1a\n" +
+	"\x15CMD_JOIN_CHATROOM_RSP\x10\xfb\x01\x12\x1b\n" +
+	"\x16CMD_LEAVE_CHATROOM_REQ\x10\xfc\x01\x12\x1b\n" +
+	"\x16CMD_LEAVE_CHATROOM_RSP\x10\xfd\x01\x12\x1a\n" +
+	"\x15CMD_CHATROOM_MSG_PUSH\x10\xfe\x01\x12#\n" +
+	"\x1eCMD_CHATROOM_MEMBER_COUNT_PUSH\x10\xff\x01\x12\x19\n" +
+	"\x14CMD_EDIT_HISTORY_REQ\x10\x80\x02\x12\x19\n" +
+	"\x14CMD_EDIT_HISTORY_RSP\x10\x81\x02\x12\x17\n" +
 	"\x12CMD_BATCH_SYNC_REQ\x10\xac\x02\x12\x17\n" +
 	"\x12CMD_BATCH_SYNC_RSP\x10\xad\x02\x12\x16\n" +
 	"\x11CMD_SYNC_FINISHED\x10\xae\x02\x12\x17\n" +
 	"\x12CMD_SYNC_RANGE_REQ\x10\xaf\x02\x12\x17\n" +
-	"\x12CMD_SYNC_RANGE_RSP\x10\xb0\x02\x12\x1a\n" +
+	"\x12CMD_SYNC_RANGE_RSP\x10\xb0\x02\x12\x1b\n" +
+	"\x16CMD_THREAD_REPLIES_REQ\x10\xb1\x02\x12\x1b\n" +
+	"\x16CMD_THREAD_REPLIES_RSP\x10\xb2\x02\x12\x18\n" +
+	"\x13CMD_PINNED_LIST_REQ\x10\xb3\x02\x12\x18\n" +
+	"\x13CMD_PINNED_LIST_RSP\x10\xb4\x02\x12\x1a\n" +
+	"\x15CMD_GET_EPHEMERAL_REQ\x10\xb5\x02\x12\x1a\n" +
+	"\x15CMD_GET_EPHEMERAL_RSP\x10\xb6\x02\x12\x1a\n" +
+	"\x15CMD_GET_RETENTION_REQ\x10\xb7\x02\x12\x1a\n" +
+	"\x15CMD_GET_RETENTION_RSP\x10\xb8\x02\x12\x14\n" +
+	"\x0fCMD_HISTORY_REQ\x10\xb9\x02\x12\x14\n" +
+	"\x0fCMD_HISTORY_RSP\x10\xba\x02\x12\x17\n" +
+	"\x12CMD_SEARCH_MSG_REQ\x10\xbb\x02\x12\x17\n" +
+	"\x12CMD_SEARCH_MSG_RSP\x10\xbc\x02\x12\x1b\n" +
+	"\x16CMD_GET_MODERATION_REQ\x10\xbd\x02\x12\x1b\n" +
+	"\x16CMD_GET_MODERATION_RSP\x10\xbe\x02\x12\x1f\n" +
+	"\x1aCMD_SYNC_CONVERSATIONS_REQ\x10\xbf\x02\x12\x1f\n" +
+	"\x1aCMD_SYNC_CONVERSATIONS_RSP\x10\xc0\x02\x12\x1a\n" +
 	"\x15CMD_ONLINE_STATUS_REQ\x10\x90\x03\x12\x1a\n" +
 	"\x15CMD_ONLINE_STATUS_RSP\x10\x91\x03\x12\x1b\n" +
 	"\x16CMD_STATUS_CHANGE_PUSH\x10\x92\x03\x12\x19\n" +
+	"\x14CMD_SET_PRESENCE_REQ\x10\x93\x03\x12\x19\n" +
+	"\x14CMD_SET_PRESENCE_RSP\x10\x94\x03\x12\x19\n" +
 	"\x14CMD_READ_RECEIPT_REQ\x10\xf4\x03\x12\x19\n" +
 	"\x14CMD_READ_RECEIPT_RSP\x10\xf5\x03\x12\x1a\n" +
-	"\x15CMD_READ_RECEIPT_PUSH\x10\xf6\x03\x12\x1a\n" +
+	"\x15CMD_READ_RECEIPT_PUSH\x10\xf6\x03\x12\x19\n" +
+	"\x14CMD_READ_MEMBERS_REQ\x10\xf7\x03\x12\x19\n" +
+	"\x14CMD_READ_MEMBERS_RSP\x10\xf8\x03\x12 \n" +
+	"\x1bCMD_GROUP_READ_RECEIPT_PUSH\x10\xf9\x03\x12\x1a\n" +
+	"\x15CMD_UNREAD_COUNT_PUSH\x10\xfa\x03\x12\x1a\n" +
 	"\x15CMD_TYPING_STATUS_REQ\x10\xd8\x04\x12\x1b\n" +
-	"\x16CMD_TYPING_STATUS_PUSH\x10\xd9\x04*\xf8\x01\n" +
+	"\x16CMD_TYPING_STATUS_PUSH\x10\xd9\x04*\xd3\x03\n" +
 	"\tErrorCode\x12\x0f\n" +
 	"\vERR_SUCCESS\x10\x00\x12\x0f\n" +
 	"\vERR_UNKNOWN\x10\x01\x12\x15\n" +
@@ -2348,7 +9067,15 @@ const file_im_protocol_proto_rawDesc = "" +
 	"\x12ERR_USER_NOT_EXIST\x10g\x12\x1a\n" +
 	"\x15ERR_MESSAGE_TOO_LARGE\x10\xc8\x01\x12\x16\n" +
 	"\x11ERR_SEND_TOO_FAST\x10\xc9\x01\x12\x1f\n" +
-	"\x1aERR_CONVERSATION_NOT_EXIST\x10\xca\x01B.Z,github.com/arwen/im-server/internal/protocolb\x06proto3"
+	"\x1aERR_CONVERSATION_NOT_EXIST\x10\xca\x01\x12\x1a\n" +
+	"\x15ERR_MESSAGE_NOT_EXIST\x10\xcb\x01\x12\x1a\n" +
+	"\x15ERR_EDIT_TIME_EXPIRED\x10\xcc\x01\x12\x19\n" +
+	"\x14ERR_NOT_GROUP_MEMBER\x10\xcd\x01\x12\x1d\n" +
+	"\x18ERR_SCHEDULE_NOT_PENDING\x10\xce\x01\x12\x19\n" +
+	"\x14ERR_CONTENT_REJECTED\x10\xcf\x01\x12\x1c\n" +
+	"\x17ERR_NOT_CHATROOM_MEMBER\x10\xd0\x01\x12\x16\n" +
+	"\x11ERR_CHATROOM_FULL\x10\xd1\x01\x12\x16\n" +
+	"\x11ERR_EDIT_CONFLICT\x10\xd2\x01B.Z,github.com/arwen/im-server/internal/protocolb\x06proto3"
 
 var (
 	file_im_protocol_proto_rawDescOnce sync.Once
@@ -2363,62 +9090,203 @@ func file_im_protocol_proto_rawDescGZIP() []byte {
 }
 
 var file_im_protocol_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_im_protocol_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_im_protocol_proto_msgTypes = make([]protoimpl.MessageInfo, 110)
 var file_im_protocol_proto_goTypes = []any{
-	(CommandType)(0),              // 0: im.protocol.CommandType
-	(ErrorCode)(0),                // 1: im.protocol.ErrorCode
-	(*ConnectRequest)(nil),        // 2: im.protocol.ConnectRequest
-	(*ConnectResponse)(nil),       // 3: im.protocol.ConnectResponse
-	(*HeartbeatRequest)(nil),      // 4: im.protocol.HeartbeatRequest
-	(*HeartbeatResponse)(nil),     // 5: im.protocol.HeartbeatResponse
-	(*AuthRequest)(nil),           // 6: im.protocol.AuthRequest
-	(*AuthResponse)(nil),          // 7: im.protocol.AuthResponse
-	(*KickOutNotification)(nil),   // 8: im.protocol.KickOutNotification
-	(*MessageInfo)(nil),           // 9: im.protocol.MessageInfo
-	(*SendMessageRequest)(nil),    // 10: im.protocol.SendMessageRequest
-	(*SendMessageResponse)(nil),   // 11: im.protocol.SendMessageResponse
-	(*PushMessage)(nil),           // 12: im.protocol.PushMessage
-	(*MessageAck)(nil),            // 13: im.protocol.MessageAck
-	(*BatchMessages)(nil),         // 14: im.protocol.BatchMessages
-	(*RevokeMessageRequest)(nil),  // 15: im.protocol.RevokeMessageRequest
-	(*RevokeMessageResponse)(nil), // 16: im.protocol.RevokeMessageResponse
-	(*RevokeMessagePush)(nil),     // 17: im.protocol.RevokeMessagePush
-	(*ConversationSyncState)(nil), // 18: im.protocol.ConversationSyncState
-	(*BatchSyncRequest)(nil),      // 19: im.protocol.BatchSyncRequest
-	(*ConversationMessages)(nil),  // 20: im.protocol.ConversationMessages
-	(*BatchSyncResponse)(nil),     // 21: im.protocol.BatchSyncResponse
-	(*SyncRangeRequest)(nil),      // 22: im.protocol.SyncRangeRequest
-	(*SyncRangeResponse)(nil),     // 23: im.protocol.SyncRangeResponse
-	(*ReadReceiptRequest)(nil),    // 24: im.protocol.ReadReceiptRequest
-	(*ReadReceiptResponse)(nil),   // 25: im.protocol.ReadReceiptResponse
-	(*ReadReceiptPush)(nil),       // 26: im.protocol.ReadReceiptPush
-	(*TypingStatusRequest)(nil),   // 27: im.protocol.TypingStatusRequest
-	(*TypingStatusPush)(nil),      // 28: im.protocol.TypingStatusPush
-	(*WebSocketMessage)(nil),      // 29: im.protocol.WebSocketMessage
-	nil,                           // 30: im.protocol.ConnectRequest.ExtraEntry
+	(CommandType)(0),                        // 0: im.protocol.CommandType
+	(ErrorCode)(0),                          // 1: im.protocol.ErrorCode
+	(*ConnectRequest)(nil),                  // 2: im.protocol.ConnectRequest
+	(*ConnectResponse)(nil),                 // 3: im.protocol.ConnectResponse
+	(*HeartbeatRequest)(nil),                // 4: im.protocol.HeartbeatRequest
+	(*HeartbeatResponse)(nil),               // 5: im.protocol.HeartbeatResponse
+	(*AuthRequest)(nil),                     // 6: im.protocol.AuthRequest
+	(*AuthResponse)(nil),                    // 7: im.protocol.AuthResponse
+	(*KickOutNotification)(nil),             // 8: im.protocol.KickOutNotification
+	(*MessageInfo)(nil),                     // 9: im.protocol.MessageInfo
+	(*QuotedMessage)(nil),                   // 10: im.protocol.QuotedMessage
+	(*ReactionSummary)(nil),                 // 11: im.protocol.ReactionSummary
+	(*SendMessageRequest)(nil),              // 12: im.protocol.SendMessageRequest
+	(*SendMessageResponse)(nil),             // 13: im.protocol.SendMessageResponse
+	(*PushMessage)(nil),                     // 14: im.protocol.PushMessage
+	(*MessageAck)(nil),                      // 15: im.protocol.MessageAck
+	(*BatchMessages)(nil),                   // 16: im.protocol.BatchMessages
+	(*RevokeMessageRequest)(nil),            // 17: im.protocol.RevokeMessageRequest
+	(*RevokeMessageResponse)(nil),           // 18: im.protocol.RevokeMessageResponse
+	(*RevokeMessagePush)(nil),               // 19: im.protocol.RevokeMessagePush
+	(*EditMessageRequest)(nil),              // 20: im.protocol.EditMessageRequest
+	(*EditMessageResponse)(nil),             // 21: im.protocol.EditMessageResponse
+	(*EditMessagePush)(nil),                 // 22: im.protocol.EditMessagePush
+	(*EditHistoryRequest)(nil),              // 23: im.protocol.EditHistoryRequest
+	(*MessageEditRecord)(nil),               // 24: im.protocol.MessageEditRecord
+	(*EditHistoryResponse)(nil),             // 25: im.protocol.EditHistoryResponse
+	(*ReactionRequest)(nil),                 // 26: im.protocol.ReactionRequest
+	(*ReactionResponse)(nil),                // 27: im.protocol.ReactionResponse
+	(*ReactionPush)(nil),                    // 28: im.protocol.ReactionPush
+	(*ForwardSource)(nil),                   // 29: im.protocol.ForwardSource
+	(*ForwardTarget)(nil),                   // 30: im.protocol.ForwardTarget
+	(*ForwardMessageRequest)(nil),           // 31: im.protocol.ForwardMessageRequest
+	(*ForwardResult)(nil),                   // 32: im.protocol.ForwardResult
+	(*ForwardMessageResponse)(nil),          // 33: im.protocol.ForwardMessageResponse
+	(*DeleteMessageRequest)(nil),            // 34: im.protocol.DeleteMessageRequest
+	(*DeleteMessageResponse)(nil),           // 35: im.protocol.DeleteMessageResponse
+	(*ClearHistoryRequest)(nil),             // 36: im.protocol.ClearHistoryRequest
+	(*ClearHistoryResponse)(nil),            // 37: im.protocol.ClearHistoryResponse
+	(*DeleteMessagePush)(nil),               // 38: im.protocol.DeleteMessagePush
+	(*PinnedMessageInfo)(nil),               // 39: im.protocol.PinnedMessageInfo
+	(*PinMessageRequest)(nil),               // 40: im.protocol.PinMessageRequest
+	(*PinMessageResponse)(nil),              // 41: im.protocol.PinMessageResponse
+	(*PinMessagePush)(nil),                  // 42: im.protocol.PinMessagePush
+	(*PinnedListRequest)(nil),               // 43: im.protocol.PinnedListRequest
+	(*PinnedListResponse)(nil),              // 44: im.protocol.PinnedListResponse
+	(*SetEphemeralRequest)(nil),             // 45: im.protocol.SetEphemeralRequest
+	(*GetEphemeralRequest)(nil),             // 46: im.protocol.GetEphemeralRequest
+	(*EphemeralSettingResponse)(nil),        // 47: im.protocol.EphemeralSettingResponse
+	(*EphemeralSettingPush)(nil),            // 48: im.protocol.EphemeralSettingPush
+	(*MessageExpiredPush)(nil),              // 49: im.protocol.MessageExpiredPush
+	(*SetRetentionRequest)(nil),             // 50: im.protocol.SetRetentionRequest
+	(*GetRetentionRequest)(nil),             // 51: im.protocol.GetRetentionRequest
+	(*RetentionResponse)(nil),               // 52: im.protocol.RetentionResponse
+	(*ScheduledMessageInfo)(nil),            // 53: im.protocol.ScheduledMessageInfo
+	(*ScheduleMessageRequest)(nil),          // 54: im.protocol.ScheduleMessageRequest
+	(*ScheduleMessageResponse)(nil),         // 55: im.protocol.ScheduleMessageResponse
+	(*ScheduledListRequest)(nil),            // 56: im.protocol.ScheduledListRequest
+	(*ScheduledListResponse)(nil),           // 57: im.protocol.ScheduledListResponse
+	(*EditScheduledRequest)(nil),            // 58: im.protocol.EditScheduledRequest
+	(*CancelScheduledRequest)(nil),          // 59: im.protocol.CancelScheduledRequest
+	(*CancelScheduledResponse)(nil),         // 60: im.protocol.CancelScheduledResponse
+	(*ConversationSyncState)(nil),           // 61: im.protocol.ConversationSyncState
+	(*BatchSyncRequest)(nil),                // 62: im.protocol.BatchSyncRequest
+	(*ConversationMessages)(nil),            // 63: im.protocol.ConversationMessages
+	(*BatchSyncResponse)(nil),               // 64: im.protocol.BatchSyncResponse
+	(*ConversationInfo)(nil),                // 65: im.protocol.ConversationInfo
+	(*DraftInfo)(nil),                       // 66: im.protocol.DraftInfo
+	(*SetConversationSettingsRequest)(nil),  // 67: im.protocol.SetConversationSettingsRequest
+	(*SetConversationSettingsResponse)(nil), // 68: im.protocol.SetConversationSettingsResponse
+	(*ConversationSettingsPush)(nil),        // 69: im.protocol.ConversationSettingsPush
+	(*SetDraftRequest)(nil),                 // 70: im.protocol.SetDraftRequest
+	(*SetDraftResponse)(nil),                // 71: im.protocol.SetDraftResponse
+	(*DraftPush)(nil),                       // 72: im.protocol.DraftPush
+	(*JoinChatroomRequest)(nil),             // 73: im.protocol.JoinChatroomRequest
+	(*JoinChatroomResponse)(nil),            // 74: im.protocol.JoinChatroomResponse
+	(*LeaveChatroomRequest)(nil),            // 75: im.protocol.LeaveChatroomRequest
+	(*LeaveChatroomResponse)(nil),           // 76: im.protocol.LeaveChatroomResponse
+	(*ChatroomMemberCountPush)(nil),         // 77: im.protocol.ChatroomMemberCountPush
+	(*SyncConversationsRequest)(nil),        // 78: im.protocol.SyncConversationsRequest
+	(*SyncConversationsResponse)(nil),       // 79: im.protocol.SyncConversationsResponse
+	(*SyncRangeRequest)(nil),                // 80: im.protocol.SyncRangeRequest
+	(*SyncRangeResponse)(nil),               // 81: im.protocol.SyncRangeResponse
+	(*HistoryRequest)(nil),                  // 82: im.protocol.HistoryRequest
+	(*HistoryResponse)(nil),                 // 83: im.protocol.HistoryResponse
+	(*SearchMessageRequest)(nil),            // 84: im.protocol.SearchMessageRequest
+	(*SearchMessageResponse)(nil),           // 85: im.protocol.SearchMessageResponse
+	(*SetModerationRequest)(nil),            // 86: im.protocol.SetModerationRequest
+	(*GetModerationRequest)(nil),            // 87: im.protocol.GetModerationRequest
+	(*ModerationResponse)(nil),              // 88: im.protocol.ModerationResponse
+	(*ThreadRepliesRequest)(nil),            // 89: im.protocol.ThreadRepliesRequest
+	(*ThreadRepliesResponse)(nil),           // 90: im.protocol.ThreadRepliesResponse
+	(*ReadReceiptRequest)(nil),              // 91: im.protocol.ReadReceiptRequest
+	(*ReadReceiptResponse)(nil),             // 92: im.protocol.ReadReceiptResponse
+	(*ReadReceiptPush)(nil),                 // 93: im.protocol.ReadReceiptPush
+	(*MessageReadState)(nil),                // 94: im.protocol.MessageReadState
+	(*GroupReadReceiptPush)(nil),            // 95: im.protocol.GroupReadReceiptPush
+	(*ReadMembersRequest)(nil),              // 96: im.protocol.ReadMembersRequest
+	(*ReadMember)(nil),                      // 97: im.protocol.ReadMember
+	(*ReadMembersResponse)(nil),             // 98: im.protocol.ReadMembersResponse
+	(*UnreadCountPush)(nil),                 // 99: im.protocol.UnreadCountPush
+	(*OnlineStatusRequest)(nil),             // 100: im.protocol.OnlineStatusRequest
+	(*OnlineStatusResponse)(nil),            // 101: im.protocol.OnlineStatusResponse
+	(*UserPresence)(nil),                    // 102: im.protocol.UserPresence
+	(*CustomStatus)(nil),                    // 103: im.protocol.CustomStatus
+	(*SetPresenceRequest)(nil),              // 104: im.protocol.SetPresenceRequest
+	(*SetPresenceResponse)(nil),             // 105: im.protocol.SetPresenceResponse
+	(*PlatformPresence)(nil),                // 106: im.protocol.PlatformPresence
+	(*StatusChangePush)(nil),                // 107: im.protocol.StatusChangePush
+	(*TypingStatusRequest)(nil),             // 108: im.protocol.TypingStatusRequest
+	(*TypingStatusPush)(nil),                // 109: im.protocol.TypingStatusPush
+	(*WebSocketMessage)(nil),                // 110: im.protocol.WebSocketMessage
+	nil,                                     // 111: im.protocol.ConnectRequest.ExtraEntry
 }
 var file_im_protocol_proto_depIdxs = []int32{
-	30, // 0: im.protocol.ConnectRequest.extra:type_name -> im.protocol.ConnectRequest.ExtraEntry
-	1,  // 1: im.protocol.ConnectResponse.error_code:type_name -> im.protocol.ErrorCode
-	1,  // 2: im.protocol.AuthResponse.error_code:type_name -> im.protocol.ErrorCode
-	9,  // 3: im.protocol.SendMessageRequest.message:type_name -> im.protocol.MessageInfo
-	1,  // 4: im.protocol.SendMessageResponse.error_code:type_name -> im.protocol.ErrorCode
-	9,  // 5: im.protocol.PushMessage.message:type_name -> im.protocol.MessageInfo
-	12, // 6: im.protocol.BatchMessages.messages:type_name -> im.protocol.PushMessage
-	1,  // 7: im.protocol.RevokeMessageResponse.error_code:type_name -> im.protocol.ErrorCode
-	18, // 8: im.protocol.BatchSyncRequest.conversation_states:type_name -> im.protocol.ConversationSyncState
-	9,  // 9: im.protocol.ConversationMessages.messages:type_name -> im.protocol.MessageInfo
-	1,  // 10: im.protocol.BatchSyncResponse.error_code:type_name -> im.protocol.ErrorCode
-	20, // 11: im.protocol.BatchSyncResponse.conversation_messages:type_name -> im.protocol.ConversationMessages
-	1,  // 12: im.protocol.SyncRangeResponse.error_code:type_name -> im.protocol.ErrorCode
-	9,  // 13: im.protocol.SyncRangeResponse.messages:type_name -> im.protocol.MessageInfo
-	1,  // 14: im.protocol.ReadReceiptResponse.error_code:type_name -> im.protocol.ErrorCode
-	0,  // 15: im.protocol.WebSocketMessage.command:type_name -> im.protocol.CommandType
-	16, // [16:16] is the sub-list for method output_type
-	16, // [16:16] is the sub-list for method input_type
-	16, // [16:16] is the sub-list for extension type_name
-	16, // [16:16] is the sub-list for extension extendee
-	0,  // [0:16] is the sub-list for field type_name
+	111, // 0: im.protocol.ConnectRequest.extra:type_name -> im.protocol.ConnectRequest.ExtraEntry
+	1,   // 1: im.protocol.ConnectResponse.error_code:type_name -> im.protocol.ErrorCode
+	1,   // 2: im.protocol.AuthResponse.error_code:type_name -> im.protocol.ErrorCode
+	11,  // 3: im.protocol.MessageInfo.reactions:type_name -> im.protocol.ReactionSummary
+	10,  // 4: im.protocol.MessageInfo.quoted_message:type_name -> im.protocol.QuotedMessage
+	9,   // 5: im.protocol.SendMessageRequest.message:type_name -> im.protocol.MessageInfo
+	1,   // 6: im.protocol.SendMessageResponse.error_code:type_name -> im.protocol.ErrorCode
+	9,   // 7: im.protocol.PushMessage.message:type_name -> im.protocol.MessageInfo
+	14,  // 8: im.protocol.BatchMessages.messages:type_name -> im.protocol.PushMessage
+	1,   // 9: im.protocol.RevokeMessageResponse.error_code:type_name -> im.protocol.ErrorCode
+	1,   // 10: im.protocol.EditMessageResponse.error_code:type_name -> im.protocol.ErrorCode
+	1,   // 11: im.protocol.EditHistoryResponse.error_code:type_name -> im.protocol.ErrorCode
+	24,  // 12: im.protocol.EditHistoryResponse.edits:type_name -> im.protocol.MessageEditRecord
+	1,   // 13: im.protocol.ReactionResponse.error_code:type_name -> im.protocol.ErrorCode
+	29,  // 14: im.protocol.ForwardMessageRequest.sources:type_name -> im.protocol.ForwardSource
+	30,  // 15: im.protocol.ForwardMessageRequest.targets:type_name -> im.protocol.ForwardTarget
+	1,   // 16: im.protocol.ForwardResult.error_code:type_name -> im.protocol.ErrorCode
+	1,   // 17: im.protocol.ForwardMessageResponse.error_code:type_name -> im.protocol.ErrorCode
+	32,  // 18: im.protocol.ForwardMessageResponse.results:type_name -> im.protocol.ForwardResult
+	1,   // 19: im.protocol.DeleteMessageResponse.error_code:type_name -> im.protocol.ErrorCode
+	1,   // 20: im.protocol.ClearHistoryResponse.error_code:type_name -> im.protocol.ErrorCode
+	9,   // 21: im.protocol.PinnedMessageInfo.message:type_name -> im.protocol.MessageInfo
+	1,   // 22: im.protocol.PinMessageResponse.error_code:type_name -> im.protocol.ErrorCode
+	39,  // 23: im.protocol.PinMessagePush.pinned_messages:type_name -> im.protocol.PinnedMessageInfo
+	1,   // 24: im.protocol.PinnedListResponse.error_code:type_name -> im.protocol.ErrorCode
+	39,  // 25: im.protocol.PinnedListResponse.pinned_messages:type_name -> im.protocol.PinnedMessageInfo
+	1,   // 26: im.protocol.EphemeralSettingResponse.error_code:type_name -> im.protocol.ErrorCode
+	1,   // 27: im.protocol.RetentionResponse.error_code:type_name -> im.protocol.ErrorCode
+	9,   // 28: im.protocol.ScheduledMessageInfo.message:type_name -> im.protocol.MessageInfo
+	9,   // 29: im.protocol.ScheduleMessageRequest.message:type_name -> im.protocol.MessageInfo
+	1,   // 30: im.protocol.ScheduleMessageResponse.error_code:type_name -> im.protocol.ErrorCode
+	53,  // 31: im.protocol.ScheduleMessageResponse.scheduled_message:type_name -> im.protocol.ScheduledMessageInfo
+	1,   // 32: im.protocol.ScheduledListResponse.error_code:type_name -> im.protocol.ErrorCode
+	53,  // 33: im.protocol.ScheduledListResponse.scheduled_messages:type_name -> im.protocol.ScheduledMessageInfo
+	1,   // 34: im.protocol.CancelScheduledResponse.error_code:type_name -> im.protocol.ErrorCode
+	61,  // 35: im.protocol.BatchSyncRequest.conversation_states:type_name -> im.protocol.ConversationSyncState
+	9,   // 36: im.protocol.ConversationMessages.messages:type_name -> im.protocol.MessageInfo
+	9,   // 37: im.protocol.ConversationMessages.updated_messages:type_name -> im.protocol.MessageInfo
+	39,  // 38: im.protocol.ConversationMessages.pinned_messages:type_name -> im.protocol.PinnedMessageInfo
+	1,   // 39: im.protocol.BatchSyncResponse.error_code:type_name -> im.protocol.ErrorCode
+	63,  // 40: im.protocol.BatchSyncResponse.conversation_messages:type_name -> im.protocol.ConversationMessages
+	66,  // 41: im.protocol.ConversationInfo.draft:type_name -> im.protocol.DraftInfo
+	1,   // 42: im.protocol.SetConversationSettingsResponse.error_code:type_name -> im.protocol.ErrorCode
+	65,  // 43: im.protocol.SetConversationSettingsResponse.conversation:type_name -> im.protocol.ConversationInfo
+	65,  // 44: im.protocol.ConversationSettingsPush.conversation:type_name -> im.protocol.ConversationInfo
+	1,   // 45: im.protocol.SetDraftResponse.error_code:type_name -> im.protocol.ErrorCode
+	66,  // 46: im.protocol.SetDraftResponse.draft:type_name -> im.protocol.DraftInfo
+	66,  // 47: im.protocol.DraftPush.draft:type_name -> im.protocol.DraftInfo
+	1,   // 48: im.protocol.JoinChatroomResponse.error_code:type_name -> im.protocol.ErrorCode
+	9,   // 49: im.protocol.JoinChatroomResponse.history:type_name -> im.protocol.MessageInfo
+	1,   // 50: im.protocol.LeaveChatroomResponse.error_code:type_name -> im.protocol.ErrorCode
+	1,   // 51: im.protocol.SyncConversationsResponse.error_code:type_name -> im.protocol.ErrorCode
+	65,  // 52: im.protocol.SyncConversationsResponse.conversations:type_name -> im.protocol.ConversationInfo
+	1,   // 53: im.protocol.SyncRangeResponse.error_code:type_name -> im.protocol.ErrorCode
+	9,   // 54: im.protocol.SyncRangeResponse.messages:type_name -> im.protocol.MessageInfo
+	1,   // 55: im.protocol.HistoryResponse.error_code:type_name -> im.protocol.ErrorCode
+	9,   // 56: im.protocol.HistoryResponse.messages:type_name -> im.protocol.MessageInfo
+	1,   // 57: im.protocol.SearchMessageResponse.error_code:type_name -> im.protocol.ErrorCode
+	9,   // 58: im.protocol.SearchMessageResponse.messages:type_name -> im.protocol.MessageInfo
+	1,   // 59: im.protocol.ModerationResponse.error_code:type_name -> im.protocol.ErrorCode
+	1,   // 60: im.protocol.ThreadRepliesResponse.error_code:type_name -> im.protocol.ErrorCode
+	9,   // 61: im.protocol.ThreadRepliesResponse.root_message:type_name -> im.protocol.MessageInfo
+	9,   // 62: im.protocol.ThreadRepliesResponse.replies:type_name -> im.protocol.MessageInfo
+	1,   // 63: im.protocol.ReadReceiptResponse.error_code:type_name -> im.protocol.ErrorCode
+	94,  // 64: im.protocol.GroupReadReceiptPush.states:type_name -> im.protocol.MessageReadState
+	1,   // 65: im.protocol.ReadMembersResponse.error_code:type_name -> im.protocol.ErrorCode
+	97,  // 66: im.protocol.ReadMembersResponse.read_members:type_name -> im.protocol.ReadMember
+	1,   // 67: im.protocol.OnlineStatusResponse.error_code:type_name -> im.protocol.ErrorCode
+	102, // 68: im.protocol.OnlineStatusResponse.presences:type_name -> im.protocol.UserPresence
+	106, // 69: im.protocol.UserPresence.platforms:type_name -> im.protocol.PlatformPresence
+	103, // 70: im.protocol.UserPresence.status:type_name -> im.protocol.CustomStatus
+	103, // 71: im.protocol.SetPresenceRequest.status:type_name -> im.protocol.CustomStatus
+	1,   // 72: im.protocol.SetPresenceResponse.error_code:type_name -> im.protocol.ErrorCode
+	102, // 73: im.protocol.SetPresenceResponse.presence:type_name -> im.protocol.UserPresence
+	102, // 74: im.protocol.StatusChangePush.presence:type_name -> im.protocol.UserPresence
+	0,   // 75: im.protocol.WebSocketMessage.command:type_name -> im.protocol.CommandType
+	76,  // [76:76] is the sub-list for method output_type
+	76,  // [76:76] is the sub-list for method input_type
+	76,  // [76:76] is the sub-list for extension type_name
+	76,  // [76:76] is the sub-list for extension extendee
+	0,   // [0:76] is the sub-list for field type_name
 }
 
 func init() { file_im_protocol_proto_init() }
@@ -2432,7 +9300,7 @@ func file_im_protocol_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_im_protocol_proto_rawDesc), len(file_im_protocol_proto_rawDesc)),
 			NumEnums:      2,
-			NumMessages:   29,
+			NumMessages:   110,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
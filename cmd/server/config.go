@@ -1,6 +1,9 @@
 package main
 
 import (
+	"time"
+
+	"github.com/arwen/im-server/internal/service"
 	"github.com/spf13/viper"
 )
 
@@ -14,6 +17,10 @@ type Config struct {
 	Message    MessageConfig    `mapstructure:"message"`
 	Connection ConnectionConfig `mapstructure:"connection"`
 	RateLimit  RateLimitConfig  `mapstructure:"rate_limit"`
+	Search     SearchConfig     `mapstructure:"search"`
+	Media      MediaConfig      `mapstructure:"media"`
+	Moderation ModerationConfig `mapstructure:"moderation"`
+	Chatroom   ChatroomConfig   `mapstructure:"chatroom"`
 }
 
 type ServerConfig struct {
@@ -57,9 +64,54 @@ type AuthConfig struct {
 }
 
 type MessageConfig struct {
-	BatchSize   int `mapstructure:"batch_size"`
-	MaxLength   int `mapstructure:"max_length"`
-	OfflineDays int `mapstructure:"offline_days"`
+	BatchSize              int                       `mapstructure:"batch_size"`
+	MaxLength              int                       `mapstructure:"max_length"`
+	OfflineDays            int                       `mapstructure:"offline_days"`
+	EditTimeLimit          int                       `mapstructure:"edit_time_limit"`
+	ScheduleInterval       int                       `mapstructure:"schedule_interval"`
+	EphemeralSweepInterval int                       `mapstructure:"ephemeral_sweep_interval"`
+	RetentionInterval      int                       `mapstructure:"retention_interval"`
+	RetentionArchive       bool                      `mapstructure:"retention_archive"`
+	ReadReceiptInterval    int                       `mapstructure:"read_receipt_interval"`
+	ReadByMaxMembers       int                       `mapstructure:"read_by_max_members"`
+	UnreadBackupInterval   int                       `mapstructure:"unread_backup_interval"`
+	CustomTypes            []CustomContentTypeConfig `mapstructure:"custom_types"`
+}
+
+// CustomContentTypeConfig 应用自定义消息类型
+type CustomContentTypeConfig struct {
+	Type         int                  `mapstructure:"type"`
+	Name         string               `mapstructure:"name"`
+	Fields       []ContentFieldConfig `mapstructure:"fields"`
+	Preview      string               `mapstructure:"preview"`
+	PreviewField string               `mapstructure:"preview_field"`
+}
+
+// ContentFieldConfig 自定义消息类型的字段定义
+type ContentFieldConfig struct {
+	Name      string `mapstructure:"name"`
+	Kind      string `mapstructure:"kind"`
+	Required  bool   `mapstructure:"required"`
+	MaxLength int    `mapstructure:"max_length"`
+}
+
+// toContentType 转换为消息内容类型定义
+func (c CustomContentTypeConfig) toContentType() *service.ContentType {
+	ct := &service.ContentType{
+		Type:         c.Type,
+		Name:         c.Name,
+		PreviewLabel: c.Preview,
+		PreviewField: c.PreviewField,
+	}
+	for _, f := range c.Fields {
+		ct.Fields = append(ct.Fields, service.ContentField{
+			Name:      f.Name,
+			Kind:      f.Kind,
+			Required:  f.Required,
+			MaxLength: f.MaxLength,
+		})
+	}
+	return ct
 }
 
 type ConnectionConfig struct {
@@ -68,6 +120,7 @@ type ConnectionConfig struct {
 	ReadTimeout       int `mapstructure:"read_timeout"`
 	WriteTimeout      int `mapstructure:"write_timeout"`
 	MaxMessageSize    int `mapstructure:"max_message_size"`
+	PresenceDebounce  int `mapstructure:"presence_debounce"`
 }
 
 type RateLimitConfig struct {
@@ -76,6 +129,89 @@ type RateLimitConfig struct {
 	Burst              int  `mapstructure:"burst"`
 }
 
+type SearchConfig struct {
+	Engine string `mapstructure:"engine"`
+}
+
+type MediaConfig struct {
+	Storage          string   `mapstructure:"storage"`   // 存储后端，目前支持 local
+	LocalDir         string   `mapstructure:"local_dir"` // 本地存储目录
+	MaxFileSize      int64    `mapstructure:"max_file_size"`
+	ChunkSize        int64    `mapstructure:"chunk_size"`
+	AllowedMimeTypes []string `mapstructure:"allowed_mime_types"`
+	URLSecret        string   `mapstructure:"url_secret"` // 为空时使用 auth.jwt_secret
+	URLExpire        int      `mapstructure:"url_expire"` // 秒
+	UploadExpire     int      `mapstructure:"upload_expire"`
+	BaseURL          string   `mapstructure:"base_url"`
+	ThumbnailSize    int      `mapstructure:"thumbnail_size"`
+	CleanupInterval  int      `mapstructure:"cleanup_interval"`
+}
+
+// toServiceConfig 转换为媒体服务配置
+func (c MediaConfig) toServiceConfig(jwtSecret string) service.MediaConfig {
+	secret := c.URLSecret
+	if secret == "" {
+		secret = jwtSecret
+	}
+	return service.MediaConfig{
+		MaxFileSize:      c.MaxFileSize,
+		ChunkSize:        c.ChunkSize,
+		AllowedMimeTypes: c.AllowedMimeTypes,
+		URLSecret:        secret,
+		URLExpire:        time.Duration(c.URLExpire) * time.Second,
+		UploadExpire:     time.Duration(c.UploadExpire) * time.Second,
+		ThumbnailSize:    c.ThumbnailSize,
+	}
+}
+
+type ModerationConfig struct {
+	WordFile          string `mapstructure:"word_file"`       // 敏感词表文件（为空表示不做敏感词检查）
+	ReloadInterval    int    `mapstructure:"reload_interval"` // 词表热加载检查间隔（秒）
+	DefaultStrictness int    `mapstructure:"default_strictness"`
+	LowAction         string `mapstructure:"low_action"`
+	MediumAction      string `mapstructure:"medium_action"`
+	HighAction        string `mapstructure:"high_action"`
+	ClassifierTimeout int    `mapstructure:"classifier_timeout"` // 毫秒
+}
+
+// toServiceConfig 转换为内容审核服务配置
+func (c ModerationConfig) toServiceConfig() (service.ModerationConfig, error) {
+	config := service.ModerationConfig{
+		WordFile:          c.WordFile,
+		DefaultStrictness: c.DefaultStrictness,
+		ClassifierTimeout: time.Duration(c.ClassifierTimeout) * time.Millisecond,
+	}
+	var err error
+	if config.LowAction, err = service.ParseModerationAction(c.LowAction); err != nil {
+		return config, err
+	}
+	if config.MediumAction, err = service.ParseModerationAction(c.MediumAction); err != nil {
+		return config, err
+	}
+	if config.HighAction, err = service.ParseModerationAction(c.HighAction); err != nil {
+		return config, err
+	}
+	return config, nil
+}
+
+type ChatroomConfig struct {
+	MaxMembers    int `mapstructure:"max_members"`    // 单个聊天室最大在线人数
+	MaxJoined     int `mapstructure:"max_joined"`     // 每个用户最多同时加入的聊天室数
+	HistorySize   int `mapstructure:"history_size"`   // 保留的最近消息条数
+	HistoryTTL    int `mapstructure:"history_ttl"`    // 最近消息保留时长（秒）
+	CountInterval int `mapstructure:"count_interval"` // 在线人数推送间隔（秒）
+}
+
+// toServiceConfig 转换为聊天室服务配置
+func (c ChatroomConfig) toServiceConfig() service.ChatroomConfig {
+	return service.ChatroomConfig{
+		MaxMembers:  c.MaxMembers,
+		MaxJoined:   c.MaxJoined,
+		HistorySize: c.HistorySize,
+		HistoryTTL:  time.Duration(c.HistoryTTL) * time.Second,
+	}
+}
+
 // LoadConfig 加载配置
 func LoadConfig(configPath string) (*Config, error) {
 	viper.SetConfigFile(configPath)
@@ -86,6 +222,35 @@ func LoadConfig(configPath string) (*Config, error) {
 	viper.SetDefault("server.http_port", 8080)
 	viper.SetDefault("server.ws_port", 8081)
 	viper.SetDefault("server.tcp_port", 8082)
+	viper.SetDefault("message.edit_time_limit", 86400)
+	viper.SetDefault("message.schedule_interval", 1)
+	viper.SetDefault("message.ephemeral_sweep_interval", 1)
+	viper.SetDefault("message.retention_interval", 3600)
+	viper.SetDefault("message.read_receipt_interval", 1)
+	viper.SetDefault("message.read_by_max_members", 100)
+	viper.SetDefault("message.unread_backup_interval", 10)
+	viper.SetDefault("connection.heartbeat_timeout", 90)
+	viper.SetDefault("connection.presence_debounce", 5)
+	viper.SetDefault("search.engine", "auto")
+	viper.SetDefault("media.storage", "local")
+	viper.SetDefault("media.local_dir", "data/media")
+	viper.SetDefault("media.max_file_size", 100*1024*1024)
+	viper.SetDefault("media.chunk_size", 4*1024*1024)
+	viper.SetDefault("media.url_expire", 86400)
+	viper.SetDefault("media.upload_expire", 86400)
+	viper.SetDefault("media.thumbnail_size", 256)
+	viper.SetDefault("media.cleanup_interval", 3600)
+	viper.SetDefault("moderation.reload_interval", 10)
+	viper.SetDefault("moderation.default_strictness", 2)
+	viper.SetDefault("moderation.low_action", "flag")
+	viper.SetDefault("moderation.medium_action", "mask")
+	viper.SetDefault("moderation.high_action", "reject")
+	viper.SetDefault("moderation.classifier_timeout", 2000)
+	viper.SetDefault("chatroom.max_members", 10000)
+	viper.SetDefault("chatroom.max_joined", 10)
+	viper.SetDefault("chatroom.history_size", 50)
+	viper.SetDefault("chatroom.history_ttl", 600)
+	viper.SetDefault("chatroom.count_interval", 2)
 
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, err
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/arwen/im-server/internal/handler"
 	"github.com/arwen/im-server/internal/repository"
@@ -71,9 +72,61 @@ func main() {
 
 	// 创建服务
 	userService := service.NewUserService(config.Auth.JWTSecret)
-	messageService := service.NewMessageService()
+	contentService := service.NewContentService(config.Message.MaxLength)
+	for _, ct := range config.Message.CustomTypes {
+		if err := contentService.RegisterType(ct.toContentType()); err != nil {
+			logger.Fatal("Failed to register custom message type", zap.Error(err))
+		}
+	}
+	messageService := service.NewMessageService(time.Duration(config.Message.EditTimeLimit)*time.Second, contentService)
 	conversationService := service.NewConversationService()
+	if err := conversationService.InitVersion(); err != nil {
+		logger.Fatal("Failed to init conversation version", zap.Error(err))
+	}
 	groupService := service.NewGroupService(repository.GetDB())
+	reactionService := service.NewReactionService()
+	mentionService := service.NewMentionService(groupService)
+	readReceiptService := service.NewReadReceiptService(groupService, config.Message.ReadByMaxMembers)
+	unreadService := service.NewUnreadService(readReceiptService)
+	offlinePushService := service.NewOfflinePushService(nil, contentService, unreadService)
+	forwardService := service.NewForwardService(messageService, groupService, userService)
+	friendService := service.NewFriendService()
+	typingService := service.NewTypingService()
+	presenceService := service.NewPresenceService(
+		friendService,
+		time.Duration(config.Connection.HeartbeatTimeout)*time.Second,
+		time.Duration(config.Connection.PresenceDebounce)*time.Second,
+	)
+	chatroomService := service.NewChatroomService(config.Chatroom.toServiceConfig())
+	pinService := service.NewPinService(groupService)
+	scheduledService := service.NewScheduledMessageService(groupService, mentionService, contentService)
+	ephemeralService := service.NewEphemeralService(groupService)
+	searchIndex, rebuildSearchIndex, err := newSearchIndex(config.Search.Engine)
+	if err != nil {
+		logger.Fatal("Failed to init search index", zap.Error(err))
+	}
+	searchService := service.NewSearchService(searchIndex, messageService, groupService)
+	if rebuildSearchIndex {
+		go func() {
+			if err := searchService.Rebuild(); err != nil {
+				logger.Error("Failed to rebuild search index", zap.Error(err))
+			}
+		}()
+	}
+	retentionService := service.NewRetentionService(groupService, searchService, config.Message.OfflineDays, config.Message.RetentionArchive)
+	mediaStorage, err := newMediaStorage(config.Media)
+	if err != nil {
+		logger.Fatal("Failed to init media storage", zap.Error(err))
+	}
+	mediaService := service.NewMediaService(mediaStorage, config.Media.toServiceConfig(config.Auth.JWTSecret))
+	moderationConfig, err := config.Moderation.toServiceConfig()
+	if err != nil {
+		logger.Fatal("Invalid moderation config", zap.Error(err))
+	}
+	moderationService := service.NewModerationService(contentService, groupService, nil, moderationConfig)
+	if err := moderationService.Reload(); err != nil {
+		logger.Fatal("Failed to load moderation word list", zap.Error(err))
+	}
 
 	// 创建连接管理器
 	connManager := transport.NewConnectionManager()
@@ -85,8 +138,58 @@ func main() {
 		messageService,
 		conversationService,
 		groupService,
+		reactionService,
+		mentionService,
+		offlinePushService,
+		forwardService,
+		friendService,
+		pinService,
+		scheduledService,
+		ephemeralService,
+		retentionService,
+		searchService,
+		contentService,
+		moderationService,
+		readReceiptService,
+		unreadService,
+		typingService,
+		presenceService,
+		chatroomService,
 	)
 
+	// 用户上线/下线时更新在线状态
+	connManager.SetPresenceListener(messageHandler.HandlePresenceChange)
+
+	// 启动定时消息调度器
+	scheduledService.Start(time.Duration(config.Message.ScheduleInterval)*time.Second, messageHandler.ReleaseScheduledMessage)
+
+	// 启动阅后即焚消息清理器
+	ephemeralService.Start(time.Duration(config.Message.EphemeralSweepInterval)*time.Second, messageHandler.NotifyExpiredMessages)
+
+	// 启动过期消息清理任务（保留期限为 message.offline_days，会话可单独设置）
+	retentionService.Start(time.Duration(config.Message.RetentionInterval) * time.Second)
+
+	// 启动过期上传任务清理
+	mediaService.Start(time.Duration(config.Media.CleanupInterval) * time.Second)
+
+	// 启动群聊已读回执聚合推送
+	readReceiptService.Start(time.Duration(config.Message.ReadReceiptInterval)*time.Second, messageHandler.NotifyGroupReadReceipts)
+
+	// 启动未读数备份任务
+	unreadService.Start(time.Duration(config.Message.UnreadBackupInterval) * time.Second)
+
+	// 启动输入状态超时检查
+	typingService.Start(time.Second, messageHandler.NotifyTypingExpired)
+
+	// 启动在线状态变化推送
+	presenceService.Start(time.Second, messageHandler.NotifyPresenceChange)
+
+	// 启动聊天室在线人数推送
+	chatroomService.Start(time.Duration(config.Chatroom.CountInterval)*time.Second, messageHandler.IsChatroomConnAlive, messageHandler.NotifyChatroomMemberCount)
+
+	// 启动敏感词表热加载
+	moderationService.Start(time.Duration(config.Moderation.ReloadInterval) * time.Second)
+
 	// 创建TCP服务器（默认传输协议）
 	tcpServer := transport.NewTCPServer(connManager, messageHandler)
 
@@ -114,11 +217,15 @@ func main() {
 	// 启动HTTP API服务器
 	httpHandler := handler.NewHTTPHandler(userService, messageService, conversationService)
 	groupHandler := handler.NewGroupHandler(groupService, userService)
+	scheduleHandler := handler.NewScheduleHandler(scheduledService, config.Auth.JWTSecret)
+	mediaHandler := handler.NewMediaHandler(mediaService, config.Auth.JWTSecret, config.Media.BaseURL)
 	httpAddr := fmt.Sprintf(":%d", config.Server.HTTPPort)
 	go func() {
 		mux := http.NewServeMux()
 		httpHandler.RegisterRoutes(mux)
 		groupHandler.RegisterRoutes(mux)
+		scheduleHandler.RegisterRoutes(mux)
+		mediaHandler.RegisterRoutes(mux)
 		
 		logger.Info("HTTP API server starting", zap.String("addr", httpAddr))
 		if err := http.ListenAndServe(httpAddr, mux); err != nil {
@@ -139,7 +246,47 @@ func main() {
 	logger.Info("Shutting down server...")
 
 	// TODO: 优雅关闭
+	scheduledService.Stop()
+	ephemeralService.Stop()
+	retentionService.Stop()
+	mediaService.Stop()
+	moderationService.Stop()
+	readReceiptService.Stop()
+	unreadService.Stop()
+	typingService.Stop()
+	presenceService.Stop()
+	chatroomService.Stop()
 
 	logger.Info("Server stopped")
 }
 
+// newSearchIndex 按配置创建消息搜索索引（auto：使用 PostgreSQL 时选择 tsvector 索引，否则选择内存索引）
+// 内存索引需要在启动时从消息表重建（rebuild 为 true）
+func newSearchIndex(engine string) (index service.SearchIndex, rebuild bool, err error) {
+	if engine == "" || engine == "auto" {
+		engine = "memory"
+		if repository.DB.Dialector.Name() == "postgres" {
+			engine = "postgres"
+		}
+	}
+
+	switch engine {
+	case "memory":
+		return service.NewMemorySearchIndex(), true, nil
+	case "postgres":
+		index, err = service.NewPostgresSearchIndex(repository.DB)
+		return index, false, err
+	default:
+		return nil, false, fmt.Errorf("unsupported search engine: %s", engine)
+	}
+}
+
+// newMediaStorage 按配置创建媒体文件存储
+func newMediaStorage(config MediaConfig) (service.MediaStorage, error) {
+	switch config.Storage {
+	case "", "local":
+		return service.NewLocalMediaStorage(config.LocalDir)
+	default:
+		return nil, fmt.Errorf("unsupported media storage: %s", config.Storage)
+	}
+}
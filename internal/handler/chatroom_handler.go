@@ -0,0 +1,289 @@
+package handler
+
+import (
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// handleJoinChatroom 处理加入聊天室（成员关系绑定到当前连接），返回在线人数和最近消息
+func (h *MessageHandler) handleJoinChatroom(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.JoinChatroomRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.JoinChatroomResponse{
+		ChatroomId: req.ChatroomId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_JOIN_CHATROOM_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 已被新连接替换（踢下线）的连接不能加入，否则成员关系会绑定到一个不再接收推送的连接
+	if !h.IsChatroomConnAlive(userID, conn.GetID()) {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Connection has been replaced"
+		return h.sendResponse(conn, protocol.CMD_JOIN_CHATROOM_RSP, wsMsg.Sequence, resp)
+	}
+
+	count, err := h.chatroomService.Join(req.ChatroomId, userID, conn.GetID())
+	if err != nil {
+		resp.ErrorMsg = err.Error()
+		switch err {
+		case service.ErrChatroomFull:
+			resp.ErrorCode = protocol.ERR_CHATROOM_FULL
+		case service.ErrInvalidChatroom, service.ErrTooManyChatrooms:
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		default:
+			logger.Error("Failed to join chatroom", zap.Error(err), zap.String("user_id", userID))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to join chatroom"
+		}
+		return h.sendResponse(conn, protocol.CMD_JOIN_CHATROOM_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 回放最近消息（失败时只返回空列表，不影响加入）
+	history, err := h.chatroomService.GetHistory(req.ChatroomId, int(req.HistoryCount))
+	if err != nil {
+		logger.Error("Failed to get chatroom history", zap.Error(err), zap.String("chatroom_id", req.ChatroomId))
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.ConversationId = service.ChatroomConversationID(req.ChatroomId)
+	resp.MemberCount = int32(count)
+	resp.History = make([]*protocol.MessageInfo, 0, len(history))
+	for _, msg := range history {
+		resp.History = append(resp.History, toChatroomMessageInfo(msg))
+	}
+
+	logger.Debug("User joined chatroom",
+		zap.String("chatroom_id", req.ChatroomId),
+		zap.String("user_id", userID),
+		zap.Int("member_count", count))
+
+	return h.sendResponse(conn, protocol.CMD_JOIN_CHATROOM_RSP, wsMsg.Sequence, resp)
+}
+
+// handleLeaveChatroom 处理离开聊天室（未加入时也返回成功）
+func (h *MessageHandler) handleLeaveChatroom(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.LeaveChatroomRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.LeaveChatroomResponse{
+		ChatroomId: req.ChatroomId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_LEAVE_CHATROOM_RSP, wsMsg.Sequence, resp)
+	}
+
+	if h.chatroomService.Leave(req.ChatroomId, userID, conn.GetID()) {
+		logger.Debug("User left chatroom",
+			zap.String("chatroom_id", req.ChatroomId),
+			zap.String("user_id", userID))
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	return h.sendResponse(conn, protocol.CMD_LEAVE_CHATROOM_RSP, wsMsg.Sequence, resp)
+}
+
+// handleSendChatroomMessage 处理发送聊天室消息：校验内容和审核后写入最近消息，再推送给其他成员
+// 聊天室消息不写入消息表，不更新会话列表和未读数，接收者不需要 ACK
+func (h *MessageHandler) handleSendChatroomMessage(conn transport.Connection, sequence uint32, chatroomID string, msgInfo *protocol.MessageInfo) error {
+	userID := conn.GetUserID()
+	resp := &protocol.SendMessageResponse{
+		ClientMsgId: msgInfo.ClientMsgId,
+	}
+
+	if !h.chatroomService.IsMember(chatroomID, userID, conn.GetID()) {
+		resp.ErrorCode = protocol.ERR_NOT_CHATROOM_MEMBER
+		resp.ErrorMsg = service.ErrNotChatroomMember.Error()
+		return h.sendResponse(conn, protocol.CMD_SEND_MSG_RSP, sequence, resp)
+	}
+
+	if err := h.contentService.Validate(int(msgInfo.MessageType), string(msgInfo.Content)); err != nil {
+		resp.ErrorCode, _ = contentErrorCode(err)
+		resp.ErrorMsg = err.Error()
+		return h.sendResponse(conn, protocol.CMD_SEND_MSG_RSP, sequence, resp)
+	}
+
+	// 内容审核（复用单聊/群聊的规则，聊天室使用默认严格程度）
+	now := utils.GetCurrentMillis()
+	msg := &model.Message{
+		ClientMsgID:    msgInfo.ClientMsgId,
+		ConversationID: service.ChatroomConversationID(chatroomID),
+		SenderID:       userID,
+		MessageType:    int(msgInfo.MessageType),
+		Content:        string(msgInfo.Content),
+		SendTime:       msgInfo.SendTime,
+		ServerTime:     now,
+	}
+	moderation, ok := h.moderateMessage(msg)
+	if !ok {
+		resp.ErrorCode = protocol.ERR_CONTENT_REJECTED
+		resp.ErrorMsg = service.ErrContentRejected.Error()
+		return h.sendResponse(conn, protocol.CMD_SEND_MSG_RSP, sequence, resp)
+	}
+
+	chatroomMsg := &service.ChatroomMessage{
+		ClientMsgID: msg.ClientMsgID,
+		ChatroomID:  chatroomID,
+		SenderID:    userID,
+		MessageType: msg.MessageType,
+		Content:     msg.Content,
+		SendTime:    msg.SendTime,
+		ServerTime:  now,
+	}
+	if err := h.chatroomService.SaveMessage(chatroomMsg); err != nil {
+		logger.Error("Failed to save chatroom message", zap.Error(err), zap.String("chatroom_id", chatroomID))
+		resp.ErrorCode = protocol.ERR_UNKNOWN
+		resp.ErrorMsg = "Failed to save message"
+		return h.sendResponse(conn, protocol.CMD_SEND_MSG_RSP, sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.ServerMsgId = chatroomMsg.ServerMsgID
+	resp.Seq = chatroomMsg.Seq
+	resp.ServerTime = now
+	if msg.Content != moderation.Original {
+		resp.ContentMasked = true
+		resp.Content = []byte(msg.Content)
+	}
+	if err := h.sendResponse(conn, protocol.CMD_SEND_MSG_RSP, sequence, resp); err != nil {
+		return err
+	}
+
+	body, err := protocol.Marshal(&protocol.PushMessage{Message: toChatroomMessageInfo(chatroomMsg)})
+	if err != nil {
+		logger.Error("Failed to marshal chatroom message push", zap.Error(err))
+		return nil
+	}
+	h.broadcastChatroom(chatroomID, userID, protocol.CMD_CHATROOM_MSG_PUSH, body)
+	return nil
+}
+
+// NotifyChatroomMemberCount 推送在线人数给聊天室成员（由聊天室服务的推送任务调用）
+func (h *MessageHandler) NotifyChatroomMemberCount(chatroomID string, count int, members []service.ChatroomMember) {
+	push := &protocol.ChatroomMemberCountPush{
+		ChatroomId:  chatroomID,
+		MemberCount: int32(count),
+	}
+	body, err := protocol.Marshal(push)
+	if err != nil {
+		logger.Error("Failed to marshal chatroom member count push", zap.Error(err))
+		return
+	}
+	h.sendToChatroomMembers(chatroomID, members, "", protocol.CMD_CHATROOM_MEMBER_COUNT_PUSH, body)
+}
+
+// IsChatroomConnAlive 连接是否仍是用户当前的连接
+func (h *MessageHandler) IsChatroomConnAlive(userID, connID string) bool {
+	conn, ok := h.connManager.GetUserConnection(userID)
+	return ok && conn.GetID() == connID
+}
+
+// leaveStaleChatrooms 用户上线/下线时离开不是通过当前连接加入的聊天室
+func (h *MessageHandler) leaveStaleChatrooms(userID string) {
+	connID := ""
+	if conn, ok := h.connManager.GetUserConnection(userID); ok {
+		connID = conn.GetID()
+	}
+	if left := h.chatroomService.LeaveStale(userID, connID); len(left) > 0 {
+		logger.Debug("User left chatrooms with connection",
+			zap.String("user_id", userID),
+			zap.Strings("chatroom_ids", left))
+	}
+}
+
+// broadcastChatroom 推送给聊天室所有成员（除了 excludeUserID）
+func (h *MessageHandler) broadcastChatroom(chatroomID, excludeUserID string, command protocol.CommandType, body []byte) {
+	h.sendToChatroomMembers(chatroomID, h.chatroomService.Members(chatroomID), excludeUserID, command, body)
+}
+
+// sendToChatroomMembers 推送给聊天室成员：每种连接类型只编码一次，发送缓冲区已满的连接直接丢弃这条推送，
+// 成员的连接已经不是加入时的连接时将其移出聊天室
+func (h *MessageHandler) sendToChatroomMembers(chatroomID string, members []service.ChatroomMember, excludeUserID string, command protocol.CommandType, body []byte) {
+	var tcpData, wsData []byte
+	dropped := 0
+
+	for _, member := range members {
+		if member.UserID == excludeUserID {
+			continue
+		}
+
+		conn, ok := h.connManager.GetUserConnection(member.UserID)
+		if !ok || conn.GetID() != member.ConnID {
+			h.chatroomService.Leave(chatroomID, member.UserID, member.ConnID)
+			continue
+		}
+
+		var data []byte
+		if conn.GetType() == transport.ConnectionTypeTCP {
+			if tcpData == nil {
+				tcpData = protocol.EncodePacket(uint16(command), 0, body)
+			}
+			data = tcpData
+		} else {
+			if wsData == nil {
+				wsMsg := &protocol.WebSocketMessage{
+					Command:   command,
+					Body:      body,
+					Timestamp: utils.GetCurrentMillis(),
+				}
+				encoded, err := protocol.Marshal(wsMsg)
+				if err != nil {
+					logger.Error("Failed to marshal websocket message", zap.Error(err))
+					return
+				}
+				wsData = encoded
+			}
+			data = wsData
+		}
+
+		if err := conn.Send(data); err != nil {
+			dropped++
+		}
+	}
+
+	if dropped > 0 {
+		logger.Debug("Chatroom push dropped for slow connections",
+			zap.String("chatroom_id", chatroomID),
+			zap.String("command", command.String()),
+			zap.Int("dropped", dropped))
+	}
+}
+
+// toChatroomMessageInfo 转换聊天室消息为协议结构
+func toChatroomMessageInfo(msg *service.ChatroomMessage) *protocol.MessageInfo {
+	return &protocol.MessageInfo{
+		ServerMsgId:      msg.ServerMsgID,
+		ClientMsgId:      msg.ClientMsgID,
+		ConversationId:   service.ChatroomConversationID(msg.ChatroomID),
+		ConversationType: model.ConversationTypeChatroom,
+		SenderId:         msg.SenderID,
+		Seq:              msg.Seq,
+		MessageType:      int32(msg.MessageType),
+		Content:          []byte(msg.Content),
+		SendTime:         msg.SendTime,
+		ServerTime:       msg.ServerTime,
+		CreateTime:       msg.SendTime,
+		Status:           1, // 已发送
+	}
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// handleSyncConversations 处理同步会话列表（返回客户端版本号之后变化的会话）
+func (h *MessageHandler) handleSyncConversations(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.SyncConversationsRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.SyncConversationsResponse{
+		Version: req.Version,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SYNC_CONVERSATIONS_RSP, wsMsg.Sequence, resp)
+	}
+
+	conversations, hasMore, err := h.convService.GetConversationsSince(userID, req.Version, int(req.Limit))
+	if err != nil {
+		logger.Error("Failed to sync conversations", zap.Error(err), zap.String("user_id", userID))
+		resp.ErrorCode = protocol.ERR_UNKNOWN
+		resp.ErrorMsg = "Failed to sync conversations"
+		return h.sendResponse(conn, protocol.CMD_SYNC_CONVERSATIONS_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.HasMore = hasMore
+	resp.Conversations = make([]*protocol.ConversationInfo, 0, len(conversations))
+	for _, conv := range conversations {
+		resp.Conversations = append(resp.Conversations, toConversationInfo(conv))
+		resp.Version = conv.Version
+	}
+
+	logger.Debug("Conversations synced",
+		zap.String("user_id", userID),
+		zap.Int64("version", resp.Version),
+		zap.Int("count", len(conversations)))
+
+	return h.sendResponse(conn, protocol.CMD_SYNC_CONVERSATIONS_RSP, wsMsg.Sequence, resp)
+}
+
+// handleSetConversationSettings 处理修改会话个人设置（免打扰、置顶、隐藏、自定义字段），并同步到自己的其他设备
+func (h *MessageHandler) handleSetConversationSettings(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.SetConversationSettingsRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.SetConversationSettingsResponse{}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SET_CONVERSATION_SETTINGS_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" || len(req.Fields) == 0 {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id and fields are required"
+		return h.sendResponse(conn, protocol.CMD_SET_CONVERSATION_SETTINGS_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_SET_CONVERSATION_SETTINGS_RSP, wsMsg.Sequence, resp)
+	}
+
+	convType, targetID := h.conversationTarget(req.ConversationId, userID)
+	settings := &service.ConversationSettings{
+		MuteUntil: req.MuteUntil,
+		PinOrder:  req.PinOrder,
+		Hidden:    req.Hidden,
+		Extra:     req.Extra,
+	}
+	conv, err := h.convService.UpdateSettings(userID, req.ConversationId, convType, targetID, settings, req.Fields)
+	if err != nil {
+		if err == service.ErrInvalidSettings {
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+			resp.ErrorMsg = err.Error()
+		} else {
+			logger.Error("Failed to update conversation settings", zap.Error(err), zap.String("user_id", userID))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to update conversation settings"
+		}
+		return h.sendResponse(conn, protocol.CMD_SET_CONVERSATION_SETTINGS_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 免打扰的会话不计入角标：免打扰变化后推送新的角标；
+	// 角标使用的免打扰没有更新时返回错误，客户端重试（设置已保存，重试时会再次更新）
+	muteChanged := false
+	for _, field := range req.Fields {
+		muteChanged = muteChanged || field == service.ConversationFieldMuteUntil
+	}
+	if muteChanged {
+		state, err := h.unreadService.SetMute(userID, req.ConversationId, conv.MuteUntil)
+		if err != nil {
+			logger.Error("Failed to update conversation mute", zap.Error(err), zap.String("user_id", userID))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to update conversation mute"
+			return h.sendResponse(conn, protocol.CMD_SET_CONVERSATION_SETTINGS_RSP, wsMsg.Sequence, resp)
+		}
+		h.pushUnreadCount(state)
+	}
+
+	logger.Info("Conversation settings updated",
+		zap.String("conversation_id", req.ConversationId),
+		zap.String("user_id", userID),
+		zap.Strings("fields", req.Fields))
+
+	info := toConversationInfo(conv)
+
+	// 同步到自己的其他设备（离线设备通过同步会话列表获取）
+	push := &protocol.ConversationSettingsPush{Conversation: info}
+	if body, err := protocol.Marshal(push); err == nil {
+		h.pushToUser(userID, protocol.CMD_CONVERSATION_SETTINGS_PUSH, body)
+	} else {
+		logger.Error("Failed to marshal conversation settings push", zap.Error(err))
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.Conversation = info
+	return h.sendResponse(conn, protocol.CMD_SET_CONVERSATION_SETTINGS_RSP, wsMsg.Sequence, resp)
+}
+
+// conversationTarget 根据会话ID解析会话类型和目标（群组ID或单聊对方ID）
+func (h *MessageHandler) conversationTarget(conversationID, userID string) (int, string) {
+	if strings.HasPrefix(conversationID, "group_") {
+		return model.ConversationTypeGroup, strings.TrimPrefix(conversationID, "group_")
+	}
+
+	if peerID, ok := h.singleConversationPeer(conversationID, userID); ok {
+		return model.ConversationTypeSingle, peerID
+	}
+	return model.ConversationTypeSingle, userID
+}
+
+// updateConversationsOnMessage 新消息：接收者的未读数加一，更新所有参与者的会话列表，并推送未读数变化
+// 在推送消息之前调用，保证离线推送带上最新的角标
+func (h *MessageHandler) updateConversationsOnMessage(msg *model.Message) {
+	participants, err := h.getMessageParticipants(msg)
+	if err != nil {
+		logger.Error("Failed to get message participants", zap.Error(err), zap.String("conversation_id", msg.ConversationID))
+		return
+	}
+
+	recipients := make([]string, 0, len(participants))
+	for _, userID := range participants {
+		if userID != msg.SenderID {
+			recipients = append(recipients, userID)
+		}
+	}
+
+	// 未读数更新失败时不覆盖会话中已有的未读数
+	var unreadCounts map[string]int64
+	states, err := h.unreadService.Increment(msg.ConversationID, recipients)
+	if err != nil {
+		logger.Error("Failed to increment unread counts", zap.Error(err), zap.String("conversation_id", msg.ConversationID))
+	} else {
+		unreadCounts = make(map[string]int64, len(states))
+		for _, state := range states {
+			unreadCounts[state.UserID] = state.UnreadCount
+		}
+	}
+
+	preview := h.contentService.Preview(msg.MessageType, msg.Content)
+	if err := h.convService.UpdateOnNewMessage(msg, preview, participants, unreadCounts); err != nil {
+		logger.Error("Failed to update conversations", zap.Error(err), zap.String("conversation_id", msg.ConversationID))
+	}
+
+	for _, state := range states {
+		h.pushUnreadCount(state)
+	}
+}
+
+// toConversationInfo 转换会话列表项为协议结构
+func toConversationInfo(conv *model.Conversation) *protocol.ConversationInfo {
+	return &protocol.ConversationInfo{
+		ConversationId:  conv.ConversationID,
+		Type:            int32(conv.Type),
+		TargetId:        conv.TargetID,
+		LastServerMsgId: conv.LastMessageID,
+		LastSeq:         conv.LastMessageSeq,
+		LastSenderId:    conv.LastSenderID,
+		LastMessage:     conv.LastMessage,
+		LastMessageTime: conv.LastMessageAt,
+		UnreadCount:     int32(conv.UnreadCount),
+		Hidden:          conv.Status == model.ConversationStatusHidden,
+		Version:         conv.Version,
+		CreatedAt:       conv.CreatedAt.UnixMilli(),
+		UpdatedAt:       conv.UpdatedAt.UnixMilli(),
+		MuteUntil:       conv.MuteUntil,
+		PinOrder:        conv.PinOrder,
+		Extra:           conv.Extra,
+		Draft:           toDraftInfo(conv),
+	}
+}
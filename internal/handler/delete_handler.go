@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// maxDeleteMessages 单次最多删除的消息数
+const maxDeleteMessages = 100
+
+// handleDeleteMessage 处理删除消息（仅对自己隐藏）
+func (h *MessageHandler) handleDeleteMessage(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.DeleteMessageRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.DeleteMessageResponse{
+		ConversationId: req.ConversationId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_DELETE_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" || len(req.Seqs) == 0 || len(req.Seqs) > maxDeleteMessages {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id and 1-100 seqs are required"
+		return h.sendResponse(conn, protocol.CMD_DELETE_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_DELETE_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	seqs, err := h.msgService.DeleteMessagesForUser(userID, req.ConversationId, req.Seqs)
+	if err != nil {
+		if err == service.ErrMessageNotFound {
+			resp.ErrorCode = protocol.ERR_MESSAGE_NOT_EXIST
+			resp.ErrorMsg = "Message not found"
+		} else {
+			logger.Error("Failed to delete messages", zap.Error(err))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to delete messages"
+		}
+		return h.sendResponse(conn, protocol.CMD_DELETE_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.Seqs = seqs
+	if err := h.sendResponse(conn, protocol.CMD_DELETE_MSG_RSP, wsMsg.Sequence, resp); err != nil {
+		return err
+	}
+
+	// 同步到自己的其他设备（离线设备在批量同步时通过 deleted_seqs 获取）
+	h.pushDeletion(userID, &protocol.DeleteMessagePush{
+		ConversationId: req.ConversationId,
+		Seqs:           seqs,
+		Time:           utils.GetCurrentMillis(),
+	})
+
+	// 删除的消息可能还未读
+	h.refreshUnread(userID, req.ConversationId)
+
+	logger.Info("Messages deleted for user",
+		zap.String("user_id", userID),
+		zap.String("conversation_id", req.ConversationId),
+		zap.Int("count", len(seqs)))
+
+	return nil
+}
+
+// handleClearHistory 处理清空会话历史（仅对自己生效）
+func (h *MessageHandler) handleClearHistory(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.ClearHistoryRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.ClearHistoryResponse{
+		ConversationId: req.ConversationId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_CLEAR_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id is required"
+		return h.sendResponse(conn, protocol.CMD_CLEAR_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_CLEAR_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	clearedSeq, err := h.msgService.ClearConversationHistory(userID, req.ConversationId, req.ClearSeq)
+	if err != nil {
+		if err == service.ErrMessageNotFound {
+			resp.ErrorCode = protocol.ERR_CONVERSATION_NOT_EXIST
+			resp.ErrorMsg = "Conversation has no messages"
+		} else {
+			logger.Error("Failed to clear conversation history", zap.Error(err))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to clear conversation history"
+		}
+		return h.sendResponse(conn, protocol.CMD_CLEAR_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.ClearedSeq = clearedSeq
+	if err := h.sendResponse(conn, protocol.CMD_CLEAR_HISTORY_RSP, wsMsg.Sequence, resp); err != nil {
+		return err
+	}
+
+	// 同步到自己的其他设备（离线设备在批量同步时通过 cleared_seq 获取）
+	h.pushDeletion(userID, &protocol.DeleteMessagePush{
+		ConversationId: req.ConversationId,
+		ClearedSeq:     clearedSeq,
+		Time:           utils.GetCurrentMillis(),
+	})
+
+	h.refreshUnread(userID, req.ConversationId)
+
+	logger.Info("Conversation history cleared",
+		zap.String("user_id", userID),
+		zap.String("conversation_id", req.ConversationId),
+		zap.Int64("cleared_seq", clearedSeq))
+
+	return nil
+}
+
+// pushDeletion 推送删除/清空通知给用户自己的设备
+func (h *MessageHandler) pushDeletion(userID string, push *protocol.DeleteMessagePush) {
+	body, err := protocol.Marshal(push)
+	if err != nil {
+		logger.Error("Failed to marshal delete message push", zap.Error(err))
+		return
+	}
+	h.pushToUser(userID, protocol.CMD_DELETE_MSG_PUSH, body)
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// handleSetDraft 处理保存/清除会话草稿（多端以客户端时间最新的为准），并同步到自己的其他设备
+func (h *MessageHandler) handleSetDraft(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.SetDraftRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.SetDraftResponse{
+		ConversationId: req.ConversationId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SET_DRAFT_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id is required"
+		return h.sendResponse(conn, protocol.CMD_SET_DRAFT_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_SET_DRAFT_RSP, wsMsg.Sequence, resp)
+	}
+
+	convType, targetID := h.conversationTarget(req.ConversationId, userID)
+	conv, applied, err := h.convService.SaveDraft(userID, req.ConversationId, convType, targetID, req.Text, req.ReplyToMsgId, req.ClientTime)
+	if err != nil {
+		if err == service.ErrInvalidDraft {
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+			resp.ErrorMsg = err.Error()
+		} else {
+			logger.Error("Failed to save draft", zap.Error(err), zap.String("user_id", userID))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to save draft"
+		}
+		return h.sendResponse(conn, protocol.CMD_SET_DRAFT_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.Applied = applied
+	resp.Draft = toDraftInfo(conv)
+
+	// 同步到自己的其他设备（离线设备通过同步会话列表获取）
+	if applied {
+		push := &protocol.DraftPush{
+			ConversationId: req.ConversationId,
+			Draft:          resp.Draft,
+		}
+		if body, err := protocol.Marshal(push); err == nil {
+			h.pushToUser(userID, protocol.CMD_DRAFT_PUSH, body)
+		} else {
+			logger.Error("Failed to marshal draft push", zap.Error(err))
+		}
+	}
+
+	logger.Debug("Draft saved",
+		zap.String("conversation_id", req.ConversationId),
+		zap.String("user_id", userID),
+		zap.Bool("applied", applied))
+
+	return h.sendResponse(conn, protocol.CMD_SET_DRAFT_RSP, wsMsg.Sequence, resp)
+}
+
+// toDraftInfo 转换会话草稿为协议结构（从未保存过草稿时返回 nil）
+func toDraftInfo(conv *model.Conversation) *protocol.DraftInfo {
+	if conv.DraftTime == 0 {
+		return nil
+	}
+	return &protocol.DraftInfo{
+		Text:         conv.DraftText,
+		ReplyToMsgId: conv.DraftReplyTo,
+		ClientTime:   conv.DraftTime,
+	}
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// handleSetEphemeral 处理设置会话默认阅后即焚
+func (h *MessageHandler) handleSetEphemeral(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.SetEphemeralRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.EphemeralSettingResponse{
+		ConversationId: req.ConversationId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SET_EPHEMERAL_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id is required"
+		return h.sendResponse(conn, protocol.CMD_SET_EPHEMERAL_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_SET_EPHEMERAL_RSP, wsMsg.Sequence, resp)
+	}
+
+	setting, err := h.ephemeralService.SetConversationSetting(context.Background(), req.ConversationId, userID, int(req.ExpireTtl), int(req.ExpireMode))
+	if err != nil {
+		resp.ErrorMsg = err.Error()
+		switch err {
+		case service.ErrInvalidExpireTTL:
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		case service.ErrPermissionDenied:
+			resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+			resp.ErrorMsg = "Only group owner or admin can change ephemeral setting"
+		case service.ErrNotGroupMember:
+			resp.ErrorCode = protocol.ERR_NOT_GROUP_MEMBER
+		default:
+			logger.Error("Failed to set ephemeral setting", zap.Error(err))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to set ephemeral setting"
+		}
+		return h.sendResponse(conn, protocol.CMD_SET_EPHEMERAL_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.ExpireTtl = int32(setting.ExpireTTL)
+	resp.ExpireMode = int32(setting.ExpireMode)
+	if err := h.sendResponse(conn, protocol.CMD_SET_EPHEMERAL_RSP, wsMsg.Sequence, resp); err != nil {
+		return err
+	}
+
+	// 推送给会话中的其他成员
+	push := &protocol.EphemeralSettingPush{
+		ConversationId: setting.ConversationID,
+		ExpireTtl:      int32(setting.ExpireTTL),
+		ExpireMode:     int32(setting.ExpireMode),
+		OperatorId:     userID,
+		Time:           utils.GetCurrentMillis(),
+	}
+	pushData, err := protocol.Marshal(push)
+	if err != nil {
+		logger.Error("Failed to marshal ephemeral setting push", zap.Error(err))
+		return nil
+	}
+	go func() {
+		userIDs, err := h.getConversationParticipants(setting.ConversationID, userID)
+		if err != nil {
+			logger.Error("Failed to get conversation participants", zap.Error(err), zap.String("conversation_id", setting.ConversationID))
+			return
+		}
+		for _, uid := range userIDs {
+			if uid != userID {
+				h.pushToUser(uid, protocol.CMD_EPHEMERAL_SETTING_PUSH, pushData)
+			}
+		}
+	}()
+
+	logger.Info("Ephemeral setting updated",
+		zap.String("conversation_id", setting.ConversationID),
+		zap.String("user_id", userID),
+		zap.Int("expire_ttl", setting.ExpireTTL),
+		zap.Int("expire_mode", setting.ExpireMode))
+
+	return nil
+}
+
+// handleGetEphemeral 处理获取会话默认阅后即焚设置
+func (h *MessageHandler) handleGetEphemeral(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.GetEphemeralRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.EphemeralSettingResponse{
+		ConversationId: req.ConversationId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_GET_EPHEMERAL_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_GET_EPHEMERAL_RSP, wsMsg.Sequence, resp)
+	}
+
+	setting, err := h.ephemeralService.GetConversationSetting(req.ConversationId)
+	if err != nil {
+		logger.Error("Failed to get ephemeral setting", zap.Error(err))
+		resp.ErrorCode = protocol.ERR_UNKNOWN
+		resp.ErrorMsg = "Failed to get ephemeral setting"
+		return h.sendResponse(conn, protocol.CMD_GET_EPHEMERAL_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.ExpireTtl = int32(setting.ExpireTTL)
+	resp.ExpireMode = int32(setting.ExpireMode)
+
+	return h.sendResponse(conn, protocol.CMD_GET_EPHEMERAL_RSP, wsMsg.Sequence, resp)
+}
+
+// NotifyExpiredMessages 推送阅后即焚消息过期事件给会话参与者（由过期清理器调用）
+func (h *MessageHandler) NotifyExpiredMessages(expired []*service.ExpiredMessages) {
+	for _, e := range expired {
+		push := &protocol.MessageExpiredPush{
+			ConversationId: e.ConversationID,
+			Seqs:           e.Seqs,
+			ExpireTime:     e.ExpireTime,
+		}
+		pushData, err := protocol.Marshal(push)
+		if err != nil {
+			logger.Error("Failed to marshal message expired push", zap.Error(err))
+			continue
+		}
+		h.pushToMessageParticipants(e.Message, "", protocol.CMD_MSG_EXPIRED_PUSH, pushData)
+		h.refreshUnreadAfterRemoval(e.Message, e.Seqs)
+	}
+}
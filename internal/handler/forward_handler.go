@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// handleForwardMessage 处理转发消息（逐条转发或合并转发到一个或多个会话）
+func (h *MessageHandler) handleForwardMessage(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.ForwardMessageRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.ForwardMessageResponse{
+		RequestId: req.RequestId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_FORWARD_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	ctx := context.Background()
+
+	if err := h.forwardService.ValidateTargetCount(len(req.Targets)); err != nil {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = err.Error()
+		return h.sendResponse(conn, protocol.CMD_FORWARD_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 加载来源消息并检查读取权限
+	sources := make([]service.ForwardSource, 0, len(req.Sources))
+	for _, src := range req.Sources {
+		sources = append(sources, service.ForwardSource{
+			ConversationID: src.ConversationId,
+			Seq:            src.Seq,
+		})
+	}
+	sourceMessages, err := h.forwardService.LoadSources(ctx, userID, sources)
+	if err != nil {
+		resp.ErrorCode = forwardErrorCode(err)
+		resp.ErrorMsg = err.Error()
+		if resp.ErrorCode == protocol.ERR_UNKNOWN {
+			logger.Error("Failed to load forward sources", zap.Error(err))
+			resp.ErrorMsg = "Failed to load forward sources"
+		}
+		return h.sendResponse(conn, protocol.CMD_FORWARD_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 逐个目标会话生成并保存消息（各目标互不影响）
+	var delivered []*model.Message
+	now := utils.GetCurrentMillis()
+	for _, target := range req.Targets {
+		result := &protocol.ForwardResult{}
+		resp.Results = append(resp.Results, result)
+
+		// 单聊目标：任意一方拉黑了对方都不能转发
+		if int(target.ConversationType) == model.ConversationTypeSingle {
+			blocked, err := h.friendService.IsBlockedEither(userID, target.TargetId)
+			if err != nil {
+				logger.Error("Failed to check block relation", zap.Error(err), zap.String("user_id", userID))
+				result.ErrorCode = protocol.ERR_UNKNOWN
+				result.ErrorMsg = "Failed to check block relation"
+				continue
+			}
+			if blocked {
+				result.ErrorCode = forwardErrorCode(service.ErrUserBlocked)
+				result.ErrorMsg = service.ErrUserBlocked.Error()
+				continue
+			}
+		}
+
+		messages, err := h.forwardService.BuildForwardMessages(ctx, userID, sourceMessages, service.ForwardTarget{
+			ConversationType: int(target.ConversationType),
+			TargetID:         target.TargetId,
+		}, req.Merged, req.MergedTitle, now)
+		if err != nil {
+			result.ErrorCode = forwardErrorCode(err)
+			result.ErrorMsg = err.Error()
+			if result.ErrorCode == protocol.ERR_UNKNOWN {
+				logger.Error("Failed to resolve forward target", zap.Error(err), zap.String("target_id", target.TargetId))
+				result.ErrorMsg = "Failed to resolve forward target"
+			}
+			continue
+		}
+		result.ConversationId = messages[0].ConversationID
+
+		// 与普通发送一样审核目标会话中的每条消息，任意一条被拒绝则该目标整体不转发
+		moderations := make([]*service.ModerationDecision, len(messages))
+		for i, msg := range messages {
+			moderation, ok := h.moderateMessage(msg)
+			if !ok {
+				result.ErrorCode = protocol.ERR_CONTENT_REJECTED
+				result.ErrorMsg = service.ErrContentRejected.Error()
+				break
+			}
+			moderations[i] = moderation
+		}
+		if result.ErrorCode != protocol.ERR_SUCCESS {
+			continue
+		}
+
+		for i, msg := range messages {
+			if err := h.ephemeralService.ApplyExpiry(msg, 0, 0); err != nil {
+				logger.Error("Failed to apply ephemeral setting", zap.Error(err), zap.String("conversation_id", msg.ConversationID))
+			}
+			if err := h.msgService.SaveMessage(msg); err != nil {
+				logger.Error("Failed to save forwarded message", zap.Error(err), zap.String("conversation_id", msg.ConversationID))
+				result.ErrorCode = protocol.ERR_UNKNOWN
+				result.ErrorMsg = "Failed to save message"
+				break
+			}
+			h.moderationService.Audit(msg, moderations[i])
+			h.indexMessage(msg)
+			result.ServerMsgIds = append(result.ServerMsgIds, msg.ServerMsgID)
+			result.Seqs = append(result.Seqs, msg.Seq)
+			delivered = append(delivered, msg)
+		}
+		if result.ErrorCode == protocol.ERR_SUCCESS {
+			result.ErrorMsg = "Success"
+		}
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	if err := h.sendResponse(conn, protocol.CMD_FORWARD_MSG_RSP, wsMsg.Sequence, resp); err != nil {
+		return err
+	}
+
+	// 推送给目标会话的接收者（与普通发送消息走同样的推送路径）
+	for _, msg := range delivered {
+		h.pushNewMessage(msg)
+	}
+
+	logger.Info("Messages forwarded",
+		zap.String("user_id", userID),
+		zap.Int("source_count", len(sourceMessages)),
+		zap.Int("target_count", len(req.Targets)),
+		zap.Bool("merged", req.Merged),
+		zap.Int("delivered_count", len(delivered)))
+
+	return nil
+}
+
+// forwardErrorCode 转发错误转换为协议错误码
+func forwardErrorCode(err error) protocol.ErrorCode {
+	switch err {
+	case service.ErrInvalidForward:
+		return protocol.ERR_INVALID_PARAM
+	case service.ErrMessageNotFound:
+		return protocol.ERR_MESSAGE_NOT_EXIST
+	case service.ErrUserNotFound:
+		return protocol.ERR_USER_NOT_EXIST
+	case service.ErrPermissionDenied, service.ErrUserBlocked:
+		return protocol.ERR_PERMISSION_DENIED
+	case service.ErrNotGroupMember:
+		return protocol.ERR_NOT_GROUP_MEMBER
+	default:
+		return protocol.ERR_UNKNOWN
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// handleHistory 处理向前分页拉取历史消息（加载更早的消息）
+func (h *MessageHandler) handleHistory(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.HistoryRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.HistoryResponse{
+		RequestId:      req.RequestId,
+		ConversationId: req.ConversationId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" || req.BeforeSeq < 0 {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id is required and before_seq must not be negative"
+		return h.sendResponse(conn, protocol.CMD_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	messages, hasMore, err := h.msgService.GetMessagesBefore(userID, req.ConversationId, req.BeforeSeq, int(req.Count))
+	if err != nil {
+		logger.Error("Failed to get history messages", zap.Error(err))
+		resp.ErrorCode = protocol.ERR_UNKNOWN
+		resp.ErrorMsg = "Failed to get history messages"
+		return h.sendResponse(conn, protocol.CMD_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	minSeq, err := h.msgService.GetMinSeq(req.ConversationId)
+	if err != nil {
+		logger.Error("Failed to get min seq", zap.Error(err))
+	}
+
+	nextBeforeSeq := req.BeforeSeq
+	if len(messages) > 0 {
+		nextBeforeSeq = messages[0].Seq
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.Messages = h.toMessageInfoList(messages, userID)
+	resp.NextBeforeSeq = nextBeforeSeq
+	resp.HasMore = hasMore
+	resp.MinSeq = minSeq
+
+	logger.Debug("History response",
+		zap.String("user_id", userID),
+		zap.String("conversation_id", req.ConversationId),
+		zap.Int64("before_seq", req.BeforeSeq),
+		zap.Int("count", len(messages)),
+		zap.Bool("has_more", hasMore))
+
+	return h.sendResponse(conn, protocol.CMD_HISTORY_RSP, wsMsg.Sequence, resp)
+}
@@ -0,0 +1,368 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/arwen/im-server/internal/middleware"
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// MediaHandler 媒体文件上传/下载 HTTP 处理器
+type MediaHandler struct {
+	mediaService *service.MediaService
+	jwtSecret    string
+	baseURL      string // 下载地址前缀（为空时使用请求的 Host）
+}
+
+// NewMediaHandler 创建媒体文件 HTTP 处理器
+func NewMediaHandler(mediaService *service.MediaService, jwtSecret, baseURL string) *MediaHandler {
+	return &MediaHandler{
+		mediaService: mediaService,
+		jwtSecret:    jwtSecret,
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// MediaFileDTO 媒体文件数据传输对象（url 为签名下载地址，可直接写入消息内容）
+type MediaFileDTO struct {
+	FileID       string `json:"fileID"`
+	Size         int64  `json:"size"`
+	MimeType     string `json:"mimeType"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailURL,omitempty"`
+	ExpireTime   int64  `json:"expireTime"` // 下载地址过期时间（毫秒）
+}
+
+// MediaUploadDTO 上传任务数据传输对象
+type MediaUploadDTO struct {
+	UploadID       string        `json:"uploadID"`
+	FileName       string        `json:"fileName"`
+	Size           int64         `json:"size"`
+	ChunkSize      int64         `json:"chunkSize"`
+	ChunkCount     int           `json:"chunkCount"`
+	ReceivedChunks []int         `json:"receivedChunks"`
+	Status         int           `json:"status"`
+	ExpireTime     int64         `json:"expireTime"`
+	File           *MediaFileDTO `json:"file,omitempty"`
+}
+
+// InitUploadRequest 创建上传任务请求
+type InitUploadRequest struct {
+	FileName string `json:"fileName"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Hash     string `json:"hash"` // 文件 SHA-256（十六进制，可选，完成时校验）
+}
+
+// RegisterRoutes 注册路由
+func (h *MediaHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/media/upload", middleware.AuthMiddleware(h.jwtSecret, h.HandleUpload))
+	mux.HandleFunc("/api/media/upload/", middleware.AuthMiddleware(h.jwtSecret, h.HandleUpload))
+	// 下载使用签名地址，不需要登录（可以直接嵌入消息内容）
+	mux.HandleFunc("/api/media/files/", h.HandleFile)
+}
+
+// HandleUpload 处理上传相关请求（路由分发）
+//   - POST /api/media/upload                        创建上传任务
+//   - PUT  /api/media/upload/{id}/chunks/{index}    上传分片（请求体为分片内容）
+//   - GET  /api/media/upload/{id}                   查询上传进度（断点续传）
+//   - POST /api/media/upload/{id}/complete          完成上传
+func (h *MediaHandler) HandleUpload(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/media/upload"), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "" && r.Method == "POST":
+		h.InitUpload(w, r)
+	case len(parts) == 3 && parts[1] == "chunks" && r.Method == "PUT":
+		h.UploadChunk(w, r, parts[0], parts[2])
+	case len(parts) == 1 && r.Method == "GET":
+		h.GetUpload(w, r, parts[0])
+	case len(parts) == 2 && parts[1] == "complete" && r.Method == "POST":
+		h.CompleteUpload(w, r, parts[0])
+	default:
+		h.writeError(w, http.StatusNotFound, "Route not found")
+	}
+}
+
+// InitUpload 创建上传任务
+func (h *MediaHandler) InitUpload(w http.ResponseWriter, r *http.Request) {
+	var req InitUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Size <= 0 {
+		h.writeError(w, http.StatusBadRequest, "size is required")
+		return
+	}
+
+	upload, err := h.mediaService.InitUpload(r.Header.Get("X-User-ID"), req.FileName, req.Size, req.MimeType, req.Hash)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    h.toUploadDTO(r, upload, []int{}, nil),
+	})
+}
+
+// UploadChunk 上传分片
+func (h *MediaHandler) UploadChunk(w http.ResponseWriter, r *http.Request, uploadID, indexStr string) {
+	index, err := strconv.Atoi(indexStr)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid chunk index")
+		return
+	}
+
+	if err := h.mediaService.UploadChunk(r.Header.Get("X-User-ID"), uploadID, index, r.Body); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+	})
+}
+
+// GetUpload 查询上传进度
+func (h *MediaHandler) GetUpload(w http.ResponseWriter, r *http.Request, uploadID string) {
+	upload, received, err := h.mediaService.GetUpload(r.Header.Get("X-User-ID"), uploadID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	var file *model.MediaFile
+	if upload.Status == model.MediaUploadStatusCompleted {
+		if file, err = h.mediaService.GetFile(upload.FileID); err != nil {
+			h.writeServiceError(w, err)
+			return
+		}
+	}
+
+	h.writeJSON(w, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    h.toUploadDTO(r, upload, received, file),
+	})
+}
+
+// CompleteUpload 完成上传（合并分片）
+func (h *MediaHandler) CompleteUpload(w http.ResponseWriter, r *http.Request, uploadID string) {
+	file, err := h.mediaService.CompleteUpload(r.Header.Get("X-User-ID"), uploadID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    h.toFileDTO(r, file),
+	})
+}
+
+// HandleFile 处理文件相关请求（路由分发）
+//   - GET /api/media/files/{id}?expires=&sig=              下载文件（签名地址）
+//   - GET /api/media/files/{id}/thumbnail?expires=&sig=    下载缩略图（签名地址）
+//   - GET /api/media/files/{id}/url                        重新获取签名地址（需要登录）
+func (h *MediaHandler) HandleFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		h.writeError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/media/files"), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		h.Download(w, r, parts[0], service.MediaVariantFile)
+	case len(parts) == 2 && parts[1] == "thumbnail":
+		h.Download(w, r, parts[0], service.MediaVariantThumbnail)
+	case len(parts) == 2 && parts[1] == "url":
+		middleware.AuthMiddleware(h.jwtSecret, func(w http.ResponseWriter, r *http.Request) {
+			h.RefreshURL(w, r, parts[0])
+		})(w, r)
+	default:
+		h.writeError(w, http.StatusNotFound, "Route not found")
+	}
+}
+
+// Download 通过签名地址下载文件（支持 Range）
+func (h *MediaHandler) Download(w http.ResponseWriter, r *http.Request, fileID, variant string) {
+	query := r.URL.Query()
+	expires, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err := h.mediaService.VerifyURL(fileID, variant, expires, query.Get("sig")); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	file, err := h.mediaService.GetFile(fileID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	content, err := h.mediaService.OpenFile(file, variant)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = service.ErrMediaNotFound
+		}
+		h.writeServiceError(w, err)
+		return
+	}
+	defer content.Close()
+
+	contentType := file.MimeType
+	if variant == service.MediaVariantThumbnail {
+		contentType = "image/jpeg"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=3600")
+	// 文件与 API 同源，禁止其中的脚本在 API 域下执行
+	w.Header().Set("Content-Security-Policy", "sandbox")
+	if !isInlineMimeType(contentType) {
+		w.Header().Set("Content-Disposition", "attachment")
+	}
+	http.ServeContent(w, r, "", file.CreatedAt, content)
+}
+
+// RefreshURL 重新获取文件的签名下载地址（消息中的地址过期后使用）
+func (h *MediaHandler) RefreshURL(w http.ResponseWriter, r *http.Request, fileID string) {
+	file, err := h.mediaService.GetFile(fileID)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	// 只有上传者和引用该文件的会话的参与者可以获取下载地址
+	allowed, err := h.mediaService.CanAccessFile(r.Header.Get("X-User-ID"), file)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+	if !allowed {
+		h.writeServiceError(w, service.ErrMediaNotFound)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    h.toFileDTO(r, file),
+	})
+}
+
+// toFileDTO 转换为文件数据传输对象（生成签名下载地址）
+func (h *MediaHandler) toFileDTO(r *http.Request, file *model.MediaFile) *MediaFileDTO {
+	expires, sig := h.mediaService.SignURL(file.ID, service.MediaVariantFile)
+	dto := &MediaFileDTO{
+		FileID:     file.ID,
+		Size:       file.Size,
+		MimeType:   file.MimeType,
+		Width:      file.Width,
+		Height:     file.Height,
+		URL:        h.signedURL(r, "/api/media/files/"+file.ID, expires, sig),
+		ExpireTime: expires * 1000,
+	}
+	if file.ThumbnailKey != "" {
+		expires, sig := h.mediaService.SignURL(file.ID, service.MediaVariantThumbnail)
+		dto.ThumbnailURL = h.signedURL(r, "/api/media/files/"+file.ID+"/thumbnail", expires, sig)
+	}
+	return dto
+}
+
+// toUploadDTO 转换为上传任务数据传输对象
+func (h *MediaHandler) toUploadDTO(r *http.Request, upload *model.MediaUpload, received []int, file *model.MediaFile) *MediaUploadDTO {
+	dto := &MediaUploadDTO{
+		UploadID:       upload.ID,
+		FileName:       upload.FileName,
+		Size:           upload.Size,
+		ChunkSize:      upload.ChunkSize,
+		ChunkCount:     upload.ChunkCount,
+		ReceivedChunks: received,
+		Status:         upload.Status,
+		ExpireTime:     upload.ExpiresAt.UnixMilli(),
+	}
+	if file != nil {
+		dto.File = h.toFileDTO(r, file)
+	}
+	return dto
+}
+
+// signedURL 拼接签名下载地址
+func (h *MediaHandler) signedURL(r *http.Request, path string, expires int64, sig string) string {
+	base := h.baseURL
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		base = scheme + "://" + r.Host
+	}
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("sig", sig)
+	return fmt.Sprintf("%s%s?%s", base, path, query.Encode())
+}
+
+// isInlineMimeType 可以在浏览器中直接展示的类型（其他类型以附件形式下载）
+// SVG 等会作为文档渲染的类型即使是图片也以附件形式下载
+func isInlineMimeType(mimeType string) bool {
+	if service.IsActiveMimeType(mimeType) {
+		return false
+	}
+	return strings.HasPrefix(mimeType, "image/") || strings.HasPrefix(mimeType, "audio/") || strings.HasPrefix(mimeType, "video/")
+}
+
+// writeServiceError 将媒体服务错误转换为 HTTP 状态码
+func (h *MediaHandler) writeServiceError(w http.ResponseWriter, err error) {
+	switch err {
+	case service.ErrInvalidChunk, service.ErrHashMismatch, service.ErrUploadIncomplete:
+		h.writeError(w, http.StatusBadRequest, err.Error())
+	case service.ErrInvalidSignature:
+		h.writeError(w, http.StatusForbidden, err.Error())
+	case service.ErrUploadNotFound, service.ErrMediaNotFound:
+		h.writeError(w, http.StatusNotFound, err.Error())
+	case service.ErrFileTooLarge:
+		h.writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+	case service.ErrMimeNotAllowed:
+		h.writeError(w, http.StatusUnsupportedMediaType, err.Error())
+	case service.ErrTooManyUploads:
+		h.writeError(w, http.StatusTooManyRequests, err.Error())
+	default:
+		logger.Error("Media operation failed", zap.Error(err))
+		h.writeError(w, http.StatusInternalServerError, "Internal error")
+	}
+}
+
+func (h *MediaHandler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *MediaHandler) writeError(w http.ResponseWriter, statusCode int, message string) {
+	h.writeJSON(w, statusCode, Response{
+		Code:    statusCode,
+		Message: message,
+	})
+}
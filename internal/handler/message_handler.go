@@ -1,6 +1,11 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
 	"github.com/arwen/im-server/internal/model"
 	"github.com/arwen/im-server/internal/protocol"
 	"github.com/arwen/im-server/internal/service"
@@ -13,11 +18,28 @@ import (
 
 // MessageHandler 消息处理器
 type MessageHandler struct {
-	connManager  *transport.ConnectionManager
-	userService  *service.UserService
-	msgService   *service.MessageService
-	convService  *service.ConversationService
-	groupService *service.GroupService
+	connManager        *transport.ConnectionManager
+	userService        *service.UserService
+	msgService         *service.MessageService
+	convService        *service.ConversationService
+	groupService       *service.GroupService
+	reactionService    *service.ReactionService
+	mentionService     *service.MentionService
+	offlinePushService *service.OfflinePushService
+	forwardService     *service.ForwardService
+	friendService      *service.FriendService
+	pinService         *service.PinService
+	scheduledService   *service.ScheduledMessageService
+	ephemeralService   *service.EphemeralService
+	retentionService   *service.RetentionService
+	searchService      *service.SearchService
+	contentService     *service.ContentService
+	moderationService  *service.ModerationService
+	readReceiptService *service.ReadReceiptService
+	unreadService      *service.UnreadService
+	typingService      *service.TypingService
+	presenceService    *service.PresenceService
+	chatroomService    *service.ChatroomService
 }
 
 // NewMessageHandler 创建消息处理器
@@ -27,13 +49,47 @@ func NewMessageHandler(
 	msgService *service.MessageService,
 	convService *service.ConversationService,
 	groupService *service.GroupService,
+	reactionService *service.ReactionService,
+	mentionService *service.MentionService,
+	offlinePushService *service.OfflinePushService,
+	forwardService *service.ForwardService,
+	friendService *service.FriendService,
+	pinService *service.PinService,
+	scheduledService *service.ScheduledMessageService,
+	ephemeralService *service.EphemeralService,
+	retentionService *service.RetentionService,
+	searchService *service.SearchService,
+	contentService *service.ContentService,
+	moderationService *service.ModerationService,
+	readReceiptService *service.ReadReceiptService,
+	unreadService *service.UnreadService,
+	typingService *service.TypingService,
+	presenceService *service.PresenceService,
+	chatroomService *service.ChatroomService,
 ) *MessageHandler {
 	return &MessageHandler{
-		connManager:  connManager,
-		userService:  userService,
-		msgService:   msgService,
-		convService:  convService,
-		groupService: groupService,
+		connManager:        connManager,
+		userService:        userService,
+		msgService:         msgService,
+		convService:        convService,
+		groupService:       groupService,
+		reactionService:    reactionService,
+		mentionService:     mentionService,
+		offlinePushService: offlinePushService,
+		forwardService:     forwardService,
+		friendService:      friendService,
+		pinService:         pinService,
+		scheduledService:   scheduledService,
+		ephemeralService:   ephemeralService,
+		retentionService:   retentionService,
+		searchService:      searchService,
+		contentService:     contentService,
+		moderationService:  moderationService,
+		readReceiptService: readReceiptService,
+		unreadService:      unreadService,
+		typingService:      typingService,
+		presenceService:    presenceService,
+		chatroomService:    chatroomService,
 	}
 }
 
@@ -88,12 +144,70 @@ func (h *MessageHandler) handleMessage(conn transport.Connection, wsMsg *protoco
 		return h.handleBatchSync(conn, wsMsg)
 	case protocol.CommandType_CMD_SYNC_RANGE_REQ:
 		return h.handleSyncRange(conn, wsMsg)
+	case protocol.CMD_HISTORY_REQ:
+		return h.handleHistory(conn, wsMsg)
+	case protocol.CMD_SEARCH_MSG_REQ:
+		return h.handleSearchMessages(conn, wsMsg)
+	case protocol.CMD_SYNC_CONVERSATIONS_REQ:
+		return h.handleSyncConversations(conn, wsMsg)
+	case protocol.CMD_SET_CONVERSATION_SETTINGS_REQ:
+		return h.handleSetConversationSettings(conn, wsMsg)
+	case protocol.CMD_SET_DRAFT_REQ:
+		return h.handleSetDraft(conn, wsMsg)
+	case protocol.CMD_JOIN_CHATROOM_REQ:
+		return h.handleJoinChatroom(conn, wsMsg)
+	case protocol.CMD_LEAVE_CHATROOM_REQ:
+		return h.handleLeaveChatroom(conn, wsMsg)
+	case protocol.CMD_THREAD_REPLIES_REQ:
+		return h.handleThreadReplies(conn, wsMsg)
 	case protocol.CommandType_CMD_READ_RECEIPT_REQ:
 		return h.handleReadReceipt(conn, wsMsg)
+	case protocol.CMD_READ_MEMBERS_REQ:
+		return h.handleReadMembers(conn, wsMsg)
 	case protocol.CommandType_CMD_TYPING_STATUS_REQ:
 		return h.handleTypingStatus(conn, wsMsg)
 	case protocol.CMD_REVOKE_MSG_REQ:
 		return h.handleRevokeMessage(conn, wsMsg)
+	case protocol.CMD_EDIT_MSG_REQ:
+		return h.handleEditMessage(conn, wsMsg)
+	case protocol.CMD_EDIT_HISTORY_REQ:
+		return h.handleEditHistory(conn, wsMsg)
+	case protocol.CMD_ADD_REACTION_REQ:
+		return h.handleAddReaction(conn, wsMsg)
+	case protocol.CMD_REMOVE_REACTION_REQ:
+		return h.handleRemoveReaction(conn, wsMsg)
+	case protocol.CMD_FORWARD_MSG_REQ:
+		return h.handleForwardMessage(conn, wsMsg)
+	case protocol.CMD_DELETE_MSG_REQ:
+		return h.handleDeleteMessage(conn, wsMsg)
+	case protocol.CMD_CLEAR_HISTORY_REQ:
+		return h.handleClearHistory(conn, wsMsg)
+	case protocol.CMD_PIN_MSG_REQ:
+		return h.handlePinMessage(conn, wsMsg)
+	case protocol.CMD_UNPIN_MSG_REQ:
+		return h.handleUnpinMessage(conn, wsMsg)
+	case protocol.CMD_PINNED_LIST_REQ:
+		return h.handlePinnedList(conn, wsMsg)
+	case protocol.CMD_SCHEDULE_MSG_REQ:
+		return h.handleScheduleMessage(conn, wsMsg)
+	case protocol.CMD_SCHEDULED_LIST_REQ:
+		return h.handleScheduledList(conn, wsMsg)
+	case protocol.CMD_SCHEDULED_EDIT_REQ:
+		return h.handleEditScheduled(conn, wsMsg)
+	case protocol.CMD_SCHEDULED_CANCEL_REQ:
+		return h.handleCancelScheduled(conn, wsMsg)
+	case protocol.CMD_SET_EPHEMERAL_REQ:
+		return h.handleSetEphemeral(conn, wsMsg)
+	case protocol.CMD_GET_EPHEMERAL_REQ:
+		return h.handleGetEphemeral(conn, wsMsg)
+	case protocol.CMD_SET_RETENTION_REQ:
+		return h.handleSetRetention(conn, wsMsg)
+	case protocol.CMD_GET_RETENTION_REQ:
+		return h.handleGetRetention(conn, wsMsg)
+	case protocol.CMD_SET_MODERATION_REQ:
+		return h.handleSetModeration(conn, wsMsg)
+	case protocol.CMD_GET_MODERATION_REQ:
+		return h.handleGetModeration(conn, wsMsg)
 	default:
 		logger.Warn("Unknown command", zap.String("command", wsMsg.Command.String()))
 	}
@@ -112,6 +226,7 @@ func (h *MessageHandler) handleAuth(conn transport.Connection, wsMsg *protocol.W
 
 	// 验证Token（开发模式：允许 demo_token_xxx 格式）
 	var userID string
+	platform := req.Platform
 	if len(req.Token) > 11 && req.Token[:11] == "demo_token_" {
 		// 开发模式：从 token 中提取 userID
 		userID = req.Token[11:]
@@ -128,10 +243,16 @@ func (h *MessageHandler) handleAuth(conn transport.Connection, wsMsg *protocol.W
 			return h.sendResponse(conn, protocol.CommandType_CMD_AUTH_RSP, wsMsg.Sequence, resp)
 		}
 		userID = claims.UserID
+		if platform == "" {
+			platform = claims.Platform
+		}
+	}
+	if platform == "" {
+		platform = "unknown"
 	}
 
 	// 绑定用户连接
-	if err := h.connManager.BindUser(conn.GetID(), userID); err != nil {
+	if err := h.connManager.BindUser(conn.GetID(), userID, platform); err != nil {
 		resp := &protocol.AuthResponse{
 			ErrorCode: protocol.ERR_UNKNOWN,
 			ErrorMsg:  "Failed to bind connection",
@@ -155,6 +276,13 @@ func (h *MessageHandler) handleAuth(conn transport.Connection, wsMsg *protocol.W
 
 // handleHeartbeat 处理心跳
 func (h *MessageHandler) handleHeartbeat(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	// 续期在线状态
+	if userID := conn.GetUserID(); userID != "" {
+		if err := h.presenceService.Touch(userID); err != nil {
+			logger.Warn("Failed to refresh online status", zap.String("user_id", userID), zap.Error(err))
+		}
+	}
+
 	resp := &protocol.HeartbeatResponse{
 		ServerTime: utils.GetCurrentMillis(),
 	}
@@ -205,6 +333,22 @@ func (h *MessageHandler) handleSendMessage(conn transport.Connection, wsMsg *pro
 		}
 	}
 
+	// 聊天室消息不写入消息表，走单独的转发路径
+	if chatroomID, ok := service.ParseChatroomConversationID(conversationID); ok {
+		return h.handleSendChatroomMessage(conn, wsMsg.Sequence, chatroomID, msgInfo)
+	}
+
+	// 按消息类型校验内容
+	if err := h.contentService.Validate(int(msgInfo.MessageType), string(msgInfo.Content)); err != nil {
+		errorCode, _ := contentErrorCode(err)
+		resp := &protocol.SendMessageResponse{
+			ErrorCode:   errorCode,
+			ErrorMsg:    err.Error(),
+			ClientMsgId: msgInfo.ClientMsgId,
+		}
+		return h.sendResponse(conn, protocol.CommandType_CMD_SEND_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
 	// 创建消息
 	msg := &model.Message{
 		ClientMsgID:    msgInfo.ClientMsgId,
@@ -217,9 +361,73 @@ func (h *MessageHandler) handleSendMessage(conn transport.Connection, wsMsg *pro
 		SendTime:       msgInfo.SendTime, // ✅ 统一使用 sendTime
 		ServerTime:     now,
 		Status:         1, // 已发送
+		ReplyToMsgID:   msgInfo.ReplyToMsgId,
+		ReplyToSeq:     msgInfo.ReplyToSeq,
+		ThreadRootSeq:  msgInfo.ThreadRootSeq,
 		// Seq 由 SaveMessage 内部分配
 	}
 
+	// 校验引用回复和话题（填充被引用消息快照）
+	if err := h.msgService.ResolveReplyAndThread(msg); err != nil {
+		resp := &protocol.SendMessageResponse{
+			ErrorCode:   protocol.ERR_MESSAGE_NOT_EXIST,
+			ErrorMsg:    err.Error(),
+			ClientMsgId: msg.ClientMsgID,
+		}
+		if err != service.ErrReplyTargetNotFound && err != service.ErrThreadRootNotFound {
+			logger.Error("Failed to resolve reply and thread", zap.Error(err), zap.String("conversation_id", msg.ConversationID))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to resolve reply and thread"
+		}
+		return h.sendResponse(conn, protocol.CommandType_CMD_SEND_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 校验 @ 提醒（仅群聊）
+	mentionTargets, err := h.mentionService.ResolveMentions(context.Background(), msg, msgInfo.MentionUserIds, msgInfo.MentionAll)
+	if err != nil {
+		resp := &protocol.SendMessageResponse{
+			ErrorMsg:    err.Error(),
+			ClientMsgId: msg.ClientMsgID,
+		}
+		switch err {
+		case service.ErrMentionNotMember:
+			resp.ErrorCode = protocol.ERR_NOT_GROUP_MEMBER
+		case service.ErrMentionAllDenied:
+			resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		default:
+			logger.Error("Failed to resolve mentions", zap.Error(err))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to resolve mentions"
+		}
+		return h.sendResponse(conn, protocol.CommandType_CMD_SEND_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 阅后即焚（未指定时使用会话默认设置）
+	if err := h.ephemeralService.ApplyExpiry(msg, int(msgInfo.ExpireTtl), int(msgInfo.ExpireMode)); err != nil {
+		resp := &protocol.SendMessageResponse{
+			ErrorCode:   protocol.ERR_INVALID_PARAM,
+			ErrorMsg:    err.Error(),
+			ClientMsgId: msg.ClientMsgID,
+		}
+		if err != service.ErrInvalidExpireTTL {
+			logger.Error("Failed to apply ephemeral setting", zap.Error(err))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to apply ephemeral setting"
+		}
+		return h.sendResponse(conn, protocol.CommandType_CMD_SEND_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 内容审核（敏感词替换、拒绝或标记待复核）
+	moderation, ok := h.moderateMessage(msg)
+	if !ok {
+		resp := &protocol.SendMessageResponse{
+			ErrorCode:   protocol.ERR_CONTENT_REJECTED,
+			ErrorMsg:    service.ErrContentRejected.Error(),
+			ClientMsgId: msg.ClientMsgID,
+		}
+		return h.sendResponse(conn, protocol.CommandType_CMD_SEND_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
 	// 保存消息（会自动分配 Seq）
 	if err := h.msgService.SaveMessage(msg); err != nil {
 		logger.Error("Failed to save message", zap.Error(err))
@@ -230,8 +438,16 @@ func (h *MessageHandler) handleSendMessage(conn transport.Connection, wsMsg *pro
 		return h.sendResponse(conn, protocol.CommandType_CMD_SEND_MSG_RSP, wsMsg.Sequence, resp)
 	}
 
-	// 更新会话（使用服务端生成的 conversationID）
-	h.convService.UpdateLastMessage(conversationID, msg.ClientMsgID, string(msgInfo.Content), now)
+	// 记录 @ 提醒
+	if err := h.mentionService.RecordMentions(msg, mentionTargets); err != nil {
+		logger.Error("Failed to record mentions", zap.Error(err), zap.String("server_msg_id", msg.ServerMsgID))
+	}
+
+	// 记录审核结果
+	h.moderationService.Audit(msg, moderation)
+
+	// 写入搜索索引
+	h.indexMessage(msg)
 
 	// 发送响应（返回服务端生成的 ID 和 Seq）
 	resp := &protocol.SendMessageResponse{
@@ -242,10 +458,20 @@ func (h *MessageHandler) handleSendMessage(conn transport.Connection, wsMsg *pro
 		Seq:         msg.Seq,         // ✅ 会话内的序列号（全局有序）
 		ServerTime:  now,
 	}
+	if msg.Content != moderation.Original {
+		resp.ContentMasked = true
+		resp.Content = []byte(msg.Content)
+	}
 	if err := h.sendResponse(conn, protocol.CMD_SEND_MSG_RSP, wsMsg.Sequence, resp); err != nil {
 		return err
 	}
 
+	// 更新参与者的会话列表和未读数（先于消息推送，离线推送带上最新角标）
+	h.updateConversationsOnMessage(msg)
+
+	// 发送消息即结束输入状态（接收者收到消息时自行清除，不再推送停止输入）
+	h.typingService.Clear(msg.ConversationID, userID)
+
 	// 推送给接收者
 	if msgInfo.ReceiverId != "" {
 		// 单聊消息：推送给接收者
@@ -307,7 +533,7 @@ func (h *MessageHandler) handleBatchSync(conn transport.Connection, wsMsg *proto
 	}
 
 	// 批量同步
-	results, err := h.msgService.BatchSyncMessages(userID, conversationStates, maxCountPerConv)
+	results, err := h.msgService.BatchSyncMessages(userID, conversationStates, maxCountPerConv, req.LastSyncTime)
 	if err != nil {
 		logger.Error("Failed to batch sync messages", zap.Error(err))
 		resp := &protocol.BatchSyncResponse{
@@ -317,66 +543,64 @@ func (h *MessageHandler) handleBatchSync(conn transport.Connection, wsMsg *proto
 		return h.sendResponse(conn, protocol.CommandType_CMD_BATCH_SYNC_RSP, wsMsg.Sequence, resp)
 	}
 
+	// 本次返回的会话的置顶列表
+	conversationIDs := make([]string, 0, len(results))
+	for _, result := range results {
+		conversationIDs = append(conversationIDs, result.ConversationID)
+	}
+	pinnedMap, err := h.pinService.GetPinnedMessagesMap(conversationIDs)
+	if err != nil {
+		logger.Error("Failed to get pinned messages", zap.Error(err))
+		pinnedMap = nil
+	}
+
+	// 本次返回的会话中未读的 @ 提醒，以及有未读 @ 的会话总数（包括本次没有新消息的会话）
+	mentionSeqs, err := h.mentionService.GetUnreadMentionSeqs(userID, conversationIDs)
+	if err != nil {
+		logger.Error("Failed to get unread mentions", zap.Error(err))
+		mentionSeqs = nil
+	}
+	mentionedCount, err := h.mentionService.CountMentionedConversations(userID)
+	if err != nil {
+		logger.Error("Failed to count mentioned conversations", zap.Error(err))
+	}
+
+	// 已读位置和未读数
+	readSeqs, err := h.readReceiptService.GetReadSeqs(userID, conversationIDs)
+	if err != nil {
+		logger.Error("Failed to get read seqs", zap.Error(err))
+		readSeqs = nil
+	}
+	unreadCounts, totalUnread, err := h.unreadService.GetUnreadCounts(userID, conversationIDs)
+	if err != nil {
+		logger.Error("Failed to get unread counts", zap.Error(err))
+		unreadCounts = nil
+	}
+
 	// 转换结果
 	var conversationMessagesList []*protocol.ConversationMessages
 	totalMessageCount := 0
 
 	for _, result := range results {
-		// 收集消息 ClientMsgID 用于批量查询已读状态
-		messageIDs := make([]string, len(result.Messages))
-		for i, msg := range result.Messages {
-			messageIDs[i] = msg.ClientMsgID
-		}
-
-		// 批量查询已读状态
-		readStatusMap, err := h.msgService.CheckMessagesReadStatus(messageIDs, userID)
-		if err != nil {
-			logger.Error("Failed to check read status", zap.Error(err))
-			readStatusMap = make(map[string]bool)
-		}
-
 		// 转换消息
-		var messageInfoList []*protocol.MessageInfo
-		for _, msg := range result.Messages {
-			isRead := readStatusMap[msg.ClientMsgID]
-			if msg.SenderID == userID {
-				isRead = true
-			}
-
-			// 推断会话类型
-			var conversationType int32
-			if msg.GroupID != "" {
-				conversationType = 2 // 群聊
-			} else {
-				conversationType = 1 // 单聊
-			}
-
-			msgInfo := &protocol.MessageInfo{
-				ServerMsgId:      msg.ServerMsgID,
-				ClientMsgId:      msg.ClientMsgID,
-				ConversationId:   msg.ConversationID,
-				ConversationType: conversationType, // ✅ 添加 ConversationType
-				SenderId:         msg.SenderID,
-				ReceiverId:       msg.ReceiverID,
-				GroupId:          msg.GroupID,
-				Seq:              msg.Seq,
-				MessageType:      int32(msg.MessageType),
-				Content:          []byte(msg.Content),
-				SendTime:         msg.SendTime,
-				ServerTime:       msg.ServerTime,
-				CreateTime:       msg.SendTime,
-				Status:           int32(msg.Status),
-				IsRead:           isRead,
-			}
-			messageInfoList = append(messageInfoList, msgInfo)
-		}
+		messageInfoList := h.toMessageInfoList(result.Messages, userID)
+		updatedMessageInfoList := h.toMessageInfoList(result.UpdatedMessages, userID)
 
 		conversationMessagesList = append(conversationMessagesList, &protocol.ConversationMessages{
-			ConversationId: result.ConversationID,
-			Messages:       messageInfoList,
-			MaxSeq:         result.MaxSeq,
-			SyncedSeq:      result.SyncedSeq,
-			HasMore:        result.HasMore,
+			ConversationId:  result.ConversationID,
+			Messages:        messageInfoList,
+			MaxSeq:          result.MaxSeq,
+			SyncedSeq:       result.SyncedSeq,
+			HasMore:         result.HasMore,
+			UpdatedMessages: updatedMessageInfoList,
+			MentionedSeqs:   mentionSeqs[result.ConversationID],
+			DeletedSeqs:     result.DeletedSeqs,
+			ExpiredSeqs:     result.ExpiredSeqs,
+			MinSeq:          result.MinSeq,
+			ClearedSeq:      result.ClearedSeq,
+			PinnedMessages:  toPinnedMessageInfoList(pinnedMap[result.ConversationID]),
+			ReadSeq:         readSeqs[result.ConversationID],
+			UnreadCount:     int32(unreadCounts[result.ConversationID]),
 		})
 
 		totalMessageCount += len(messageInfoList)
@@ -388,6 +612,9 @@ func (h *MessageHandler) handleBatchSync(conn transport.Connection, wsMsg *proto
 		ConversationMessages: conversationMessagesList,
 		ServerTime:           utils.GetCurrentMillis(),
 		TotalMessageCount:    int32(totalMessageCount),
+		// 有未读 @ 的会话数（包括本次没有新消息的会话）
+		MentionedConversationCount: int32(mentionedCount),
+		TotalUnread:                int32(totalUnread),
 	}
 
 	logger.Info("Batch sync response",
@@ -425,120 +652,102 @@ func (h *MessageHandler) handleReadReceipt(conn transport.Connection, wsMsg *pro
 		return h.sendResponse(conn, protocol.CommandType_CMD_READ_RECEIPT_RSP, wsMsg.Sequence, resp)
 	}
 
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp := &protocol.ReadReceiptResponse{
+			ErrorCode: protocol.ErrorCode_ERR_PERMISSION_DENIED,
+			ErrorMsg:  "Not a participant of this conversation",
+		}
+		return h.sendResponse(conn, protocol.CommandType_CMD_READ_RECEIPT_RSP, wsMsg.Sequence, resp)
+	}
+
 	now := utils.GetCurrentMillis()
 
-	// ✅ 如果 serverMsgIds 为空，说明要标记该会话所有未读消息
-	messageIDs := req.ServerMsgIds
-	if len(messageIDs) == 0 {
-		// 查询该会话的所有未读消息
-		unreadMessageIDs, err := h.msgService.GetUnreadMessagesInConversation(req.ConversationId, userID)
-		if err != nil {
-			logger.Error("Failed to get unread messages", zap.Error(err))
-			resp := &protocol.ReadReceiptResponse{
-				ErrorCode: protocol.ErrorCode_ERR_UNKNOWN,
-				ErrorMsg:  "Failed to get unread messages",
-			}
-			return h.sendResponse(conn, protocol.CommandType_CMD_READ_RECEIPT_RSP, wsMsg.Sequence, resp)
+	// 推进已读游标（read_seq 和 server_msg_ids 都为空表示读到会话最新的消息）
+	progress, err := h.readReceiptService.MarkRead(req.ConversationId, userID, req.ReadSeq, req.ServerMsgIds, now)
+	if err != nil {
+		logger.Error("Failed to mark messages as read", zap.Error(err))
+		resp := &protocol.ReadReceiptResponse{
+			ErrorCode: protocol.ErrorCode_ERR_UNKNOWN,
+			ErrorMsg:  "Failed to mark messages as read",
 		}
-		messageIDs = unreadMessageIDs
-		logger.Debug("Mark all unread messages in conversation",
-			zap.String("conversation_id", req.ConversationId),
-			zap.Int("count", len(messageIDs)))
+		return h.sendResponse(conn, protocol.CommandType_CMD_READ_RECEIPT_RSP, wsMsg.Sequence, resp)
 	}
 
-	// 批量标记消息为已读
-	if len(messageIDs) > 0 {
-		err := h.msgService.MarkMessagesAsRead(req.ConversationId, messageIDs, userID, now)
-		if err != nil {
-			logger.Error("Failed to mark messages as read", zap.Error(err))
-			resp := &protocol.ReadReceiptResponse{
-				ErrorCode: protocol.ErrorCode_ERR_UNKNOWN,
-				ErrorMsg:  "Failed to mark messages as read",
-			}
-			return h.sendResponse(conn, protocol.CommandType_CMD_READ_RECEIPT_RSP, wsMsg.Sequence, resp)
+	if progress.ReadSeq > progress.FromSeq {
+		// 从读取时开始计时的阅后即焚消息开始倒计时
+		if err := h.ephemeralService.StartReadTimers(req.ConversationId, userID, progress.FromSeq, progress.ReadSeq, now); err != nil {
+			logger.Error("Failed to start ephemeral timers", zap.Error(err))
 		}
 	}
 
+	// 清除已读位置之前的 @ 提醒
+	if err := h.mentionService.ClearMentions(userID, req.ConversationId, progress.ReadSeq); err != nil {
+		logger.Error("Failed to clear mentions", zap.Error(err))
+	}
+
+	// 按新的已读位置重新计算未读数
+	h.refreshUnread(userID, req.ConversationId)
+
 	// 发送响应
 	resp := &protocol.ReadReceiptResponse{
 		ErrorCode: protocol.ErrorCode_ERR_SUCCESS,
 		ErrorMsg:  "Success",
+		ReadSeq:   progress.ReadSeq,
 	}
 	h.sendResponse(conn, protocol.CommandType_CMD_READ_RECEIPT_RSP, wsMsg.Sequence, resp)
 
-	// 推送已读回执给会话中的其他用户（多端同步）
-	// ✅ 使用实际标记的 messageIDs（可能是从数据库查询出来的）
-	if len(messageIDs) > 0 {
-		go h.pushReadReceiptToOthers(req.ConversationId, messageIDs, userID, now)
+	// 推送已读回执给消息发送者（只在本次读到了别人发送的消息时推送）
+	if progress.ReadCount > 0 {
+		go h.pushReadReceiptToOthers(req.ConversationId, progress, userID, now)
 	}
 
 	logger.Info("Read receipt processed",
 		zap.String("user_id", userID),
 		zap.String("conversation_id", req.ConversationId),
-		zap.Int("message_count", len(messageIDs)))
+		zap.Int64("read_seq", progress.ReadSeq),
+		zap.Int64("message_count", progress.ReadCount))
 
 	return nil
 }
 
-// pushReadReceiptToOthers 推送已读回执给会话中的其他在线用户
-func (h *MessageHandler) pushReadReceiptToOthers(conversationID string, messageIDs []string, readerUserID string, readTime int64) {
-	// 创建推送消息
+// pushReadReceiptToOthers 推送已读回执给消息发送者
+// 单聊立即把新的已读位置推送给对方；群聊交给已读回执服务聚合，定期推送最新的已读人数（见 NotifyGroupReadReceipts）
+func (h *MessageHandler) pushReadReceiptToOthers(conversationID string, progress *service.ReadProgress, readerUserID string, readTime int64) {
+	if strings.HasPrefix(conversationID, "group_") {
+		groupID := strings.TrimPrefix(conversationID, "group_")
+		h.readReceiptService.RecordGroupReads(groupID, conversationID, progress.FromSeq, progress.ReadSeq)
+		return
+	}
+
+	participants, err := h.getConversationParticipants(conversationID, readerUserID)
+	if err != nil {
+		logger.Error("Failed to get conversation participants", zap.Error(err))
+		return
+	}
+
 	push := &protocol.ReadReceiptPush{
-		ServerMsgIds:   messageIDs, // ✅ 使用 ServerMsgIds
 		ConversationId: conversationID,
 		UserId:         readerUserID,
 		ReadTime:       readTime,
+		ReadSeq:        progress.ReadSeq,
 	}
-
 	pushData, err := protocol.Marshal(push)
 	if err != nil {
 		logger.Error("Failed to marshal read receipt push", zap.Error(err))
 		return
 	}
 
-	// 获取会话中消息的发送者（需要通知的用户）
-	// 这里简化处理：从第一条消息获取对方用户ID
-	if len(messageIDs) == 0 {
-		return
-	}
-
-	msg, err := h.msgService.GetMessageByClientMsgID(conversationID, messageIDs[0])
-	if err != nil {
-		logger.Error("Failed to get message", zap.Error(err))
-		return
-	}
+	for _, userID := range participants {
+		if userID == readerUserID {
+			continue
+		}
+		h.pushToUser(userID, protocol.CommandType_CMD_READ_RECEIPT_PUSH, pushData)
 
-	// 确定要推送的目标用户（消息发送者）
-	targetUserID := msg.SenderID
-	if targetUserID == readerUserID {
-		// 如果读者是发送者，说明是自己的消息，不需要推送
-		return
+		logger.Debug("Read receipt pushed",
+			zap.String("to_user", userID),
+			zap.String("reader", readerUserID),
+			zap.Int64("read_seq", progress.ReadSeq))
 	}
-
-	// 推送给目标用户的所有在线设备
-	h.pushToUser(targetUserID, protocol.CommandType_CMD_READ_RECEIPT_PUSH, pushData)
-
-	logger.Debug("Read receipt pushed",
-		zap.String("to_user", targetUserID),
-		zap.String("reader", readerUserID),
-		zap.Int("message_count", len(messageIDs)))
-}
-
-// handleTypingStatus 处理输入状态
-func (h *MessageHandler) handleTypingStatus(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
-	var req protocol.TypingStatusRequest
-	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
-		return err
-	}
-
-	userID := conn.GetUserID()
-	if userID == "" {
-		return nil
-	}
-
-	// TODO: 推送给会话中的其他用户
-	logger.Debug("Typing status", zap.String("user_id", userID), zap.String("conversation", req.ConversationId), zap.Int32("status", req.Status))
-	return nil
 }
 
 // handleRevokeMessage 处理撤回消息
@@ -558,7 +767,8 @@ func (h *MessageHandler) handleRevokeMessage(conn transport.Connection, wsMsg *p
 	}
 
 	// 撤回消息
-	if err := h.msgService.RevokeMessage(req.ServerMsgId, userID); err != nil {
+	msg, err := h.msgService.RevokeMessage(req.ServerMsgId, userID)
+	if err != nil {
 		resp := &protocol.RevokeMessageResponse{
 			ErrorCode: protocol.ERR_PERMISSION_DENIED,
 			ErrorMsg:  err.Error(),
@@ -566,6 +776,14 @@ func (h *MessageHandler) handleRevokeMessage(conn transport.Connection, wsMsg *p
 		return h.sendResponse(conn, protocol.CommandType_CMD_REVOKE_MSG_RSP, wsMsg.Sequence, resp)
 	}
 
+	// 从搜索索引中删除
+	if err := h.searchService.RemoveMessages(msg.ConversationID, []int64{msg.Seq}); err != nil {
+		logger.Warn("Failed to remove revoked message from search index", zap.Error(err), zap.String("msg_id", req.ServerMsgId))
+	}
+
+	// 还没读到这条消息的参与者未读数减少
+	go h.refreshUnreadAfterRemoval(msg, []int64{msg.Seq})
+
 	resp := &protocol.RevokeMessageResponse{
 		ErrorCode: protocol.ERR_SUCCESS,
 		ErrorMsg:  "Success",
@@ -575,6 +793,189 @@ func (h *MessageHandler) handleRevokeMessage(conn transport.Connection, wsMsg *p
 	return h.sendResponse(conn, protocol.CMD_REVOKE_MSG_RSP, wsMsg.Sequence, resp)
 }
 
+// handleEditMessage 处理编辑消息
+func (h *MessageHandler) handleEditMessage(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.EditMessageRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp := &protocol.EditMessageResponse{
+			ErrorCode: protocol.ERR_AUTH_FAILED,
+			ErrorMsg:  "Not authenticated",
+		}
+		return h.sendResponse(conn, protocol.CommandType_CMD_EDIT_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ServerMsgId == "" || len(req.Content) == 0 {
+		resp := &protocol.EditMessageResponse{
+			ErrorCode:   protocol.ERR_INVALID_PARAM,
+			ErrorMsg:    "server_msg_id and content are required",
+			ServerMsgId: req.ServerMsgId,
+		}
+		return h.sendResponse(conn, protocol.CommandType_CMD_EDIT_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	now := utils.GetCurrentMillis()
+
+	// 内容审核（只审核自己的消息，其他情况由 EditMessage 返回对应的错误）
+	content := string(req.Content)
+	var moderation *service.ModerationDecision
+	if original, err := h.msgService.GetMessageByServerMsgID(req.ServerMsgId); err == nil && original.SenderID == userID {
+		edited := *original
+		edited.Content = content
+		var ok bool
+		if moderation, ok = h.moderateMessage(&edited); !ok {
+			resp := &protocol.EditMessageResponse{
+				ErrorCode:   protocol.ERR_CONTENT_REJECTED,
+				ErrorMsg:    service.ErrContentRejected.Error(),
+				ServerMsgId: req.ServerMsgId,
+			}
+			return h.sendResponse(conn, protocol.CommandType_CMD_EDIT_MSG_RSP, wsMsg.Sequence, resp)
+		}
+		content = edited.Content
+	}
+
+	// 编辑消息（权限和时间窗口检查在服务层完成）
+	msg, err := h.msgService.EditMessage(req.ServerMsgId, userID, content, now)
+	if err != nil {
+		errorCode, isContentErr := contentErrorCode(err)
+		switch {
+		case isContentErr:
+		case err == service.ErrMessageNotFound:
+			errorCode = protocol.ERR_MESSAGE_NOT_EXIST
+		case err == service.ErrPermissionDenied, err == service.ErrMessageRevoked:
+			errorCode = protocol.ERR_PERMISSION_DENIED
+		case err == service.ErrEditTimeExpired:
+			errorCode = protocol.ERR_EDIT_TIME_EXPIRED
+		case err == service.ErrEditConflict:
+			errorCode = protocol.ERR_EDIT_CONFLICT
+		case err == service.ErrMessageNotModified:
+			errorCode = protocol.ERR_INVALID_PARAM
+		default:
+			logger.Error("Failed to edit message", zap.Error(err), zap.String("msg_id", req.ServerMsgId))
+		}
+		resp := &protocol.EditMessageResponse{
+			ErrorCode:   errorCode,
+			ErrorMsg:    err.Error(),
+			ServerMsgId: req.ServerMsgId,
+		}
+		return h.sendResponse(conn, protocol.CommandType_CMD_EDIT_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 记录审核结果
+	h.moderationService.Audit(msg, moderation)
+
+	// 更新搜索索引
+	h.indexMessage(msg)
+
+	// 如果编辑的是会话最后一条消息，同步更新会话预览
+	if err := h.convService.UpdateLastMessageContent(msg.ConversationID, msg.ServerMsgID, h.contentService.Preview(msg.MessageType, msg.Content)); err != nil {
+		logger.Warn("Failed to update conversation preview", zap.Error(err), zap.String("conversation_id", msg.ConversationID))
+	}
+
+	resp := &protocol.EditMessageResponse{
+		ErrorCode:   protocol.ERR_SUCCESS,
+		ErrorMsg:    "Success",
+		ServerMsgId: msg.ServerMsgID,
+		EditVersion: int32(msg.EditVersion),
+		EditTime:    msg.EditTime,
+	}
+	if moderation != nil && msg.Content != moderation.Original {
+		resp.ContentMasked = true
+		resp.Content = []byte(msg.Content)
+	}
+	if err := h.sendResponse(conn, protocol.CMD_EDIT_MSG_RSP, wsMsg.Sequence, resp); err != nil {
+		return err
+	}
+
+	// 推送给会话中的所有用户（包括编辑者，同步到自己的其他设备）
+	push := &protocol.EditMessagePush{
+		ServerMsgId:    msg.ServerMsgID,
+		ConversationId: msg.ConversationID,
+		Seq:            msg.Seq,
+		Content:        []byte(msg.Content),
+		EditedBy:       userID,
+		EditVersion:    int32(msg.EditVersion),
+		EditTime:       msg.EditTime,
+	}
+	pushData, err := protocol.Marshal(push)
+	if err != nil {
+		logger.Error("Failed to marshal edit message push", zap.Error(err))
+		return nil
+	}
+	go h.pushToMessageParticipants(msg, "", protocol.CMD_EDIT_MSG_PUSH, pushData)
+
+	logger.Info("Message edited",
+		zap.String("msg_id", msg.ServerMsgID),
+		zap.String("conversation_id", msg.ConversationID),
+		zap.String("user_id", userID),
+		zap.Int("edit_version", msg.EditVersion))
+
+	return nil
+}
+
+// handleEditHistory 处理查询消息编辑历史（会话参与者可以查看，每条记录包含编辑前后的内容）
+func (h *MessageHandler) handleEditHistory(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.EditHistoryRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.EditHistoryResponse{
+		ConversationId: req.ConversationId,
+		Seq:            req.Seq,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_EDIT_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" || req.Seq <= 0 {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id and seq are required"
+		return h.sendResponse(conn, protocol.CMD_EDIT_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_EDIT_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	edits, err := h.msgService.GetMessageEditHistory(userID, req.ConversationId, req.Seq)
+	if err != nil {
+		if err == service.ErrMessageNotFound {
+			resp.ErrorCode = protocol.ERR_MESSAGE_NOT_EXIST
+			resp.ErrorMsg = "Message not found"
+		} else {
+			logger.Error("Failed to get edit history", zap.Error(err), zap.String("conversation_id", req.ConversationId))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to get edit history"
+		}
+		return h.sendResponse(conn, protocol.CMD_EDIT_HISTORY_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.Edits = make([]*protocol.MessageEditRecord, 0, len(edits))
+	for _, edit := range edits {
+		resp.Edits = append(resp.Edits, &protocol.MessageEditRecord{
+			EditVersion: int32(edit.EditVersion),
+			EditorId:    edit.EditorID,
+			OldContent:  []byte(edit.OldContent),
+			NewContent:  []byte(edit.NewContent),
+			EditTime:    edit.EditTime,
+		})
+	}
+	return h.sendResponse(conn, protocol.CMD_EDIT_HISTORY_RSP, wsMsg.Sequence, resp)
+}
+
 // handleSyncRange 处理范围同步请求（补拉丢失消息）
 func (h *MessageHandler) handleSyncRange(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
 	var req protocol.SyncRangeRequest
@@ -602,6 +1003,7 @@ func (h *MessageHandler) handleSyncRange(conn transport.Connection, wsMsg *proto
 
 	// 调用服务层进行范围同步
 	messages, actualStartSeq, actualEndSeq, hasMore, err := h.msgService.SyncMessagesInRange(
+		userID,
 		req.ConversationId,
 		req.StartSeq,
 		req.EndSeq,
@@ -617,52 +1019,13 @@ func (h *MessageHandler) handleSyncRange(conn transport.Connection, wsMsg *proto
 		return h.sendResponse(conn, protocol.CommandType_CMD_SYNC_RANGE_RSP, wsMsg.Sequence, resp)
 	}
 
-	// 转换为 Protocol MessageInfo
-	var messageInfoList []*protocol.MessageInfo
-	messageIDs := make([]string, len(messages))
-	for i, msg := range messages {
-		messageIDs[i] = msg.ClientMsgID
-	}
+	// 转换为 Protocol MessageInfo（包含已读状态）
+	messageInfoList := h.toMessageInfoList(messages, userID)
 
-	// 批量查询已读状态
-	readStatusMap, err := h.msgService.CheckMessagesReadStatus(messageIDs, userID)
+	// 超过保留期限被清理的位置（请求范围内 seq <= minSeq 的消息不会再返回）
+	minSeq, err := h.msgService.GetMinSeq(req.ConversationId)
 	if err != nil {
-		logger.Error("Failed to check read status in range sync", zap.Error(err))
-		readStatusMap = make(map[string]bool) // 失败时使用空 map，默认为未读
-	}
-
-	for _, msg := range messages {
-		isRead := readStatusMap[msg.ClientMsgID]
-		if msg.SenderID == userID {
-			isRead = true
-		}
-
-		// 推断会话类型
-		var conversationType int32
-		if msg.GroupID != "" {
-			conversationType = 2 // 群聊
-		} else {
-			conversationType = 1 // 单聊
-		}
-
-		msgInfo := &protocol.MessageInfo{
-			ServerMsgId:      msg.ServerMsgID,
-			ClientMsgId:      msg.ClientMsgID,
-			ConversationId:   msg.ConversationID,
-			ConversationType: conversationType, // ✅ 添加 ConversationType
-			SenderId:         msg.SenderID,
-			ReceiverId:       msg.ReceiverID,
-			GroupId:          msg.GroupID,
-			MessageType:      int32(msg.MessageType),
-			Content:          []byte(msg.Content),
-			SendTime:         msg.SendTime,
-			ServerTime:       msg.ServerTime,
-			CreateTime:       msg.SendTime,
-			Status:           int32(msg.Status),
-			IsRead:           isRead,
-			Seq:              msg.Seq, // ✅ 包含 seq 字段
-		}
-		messageInfoList = append(messageInfoList, msgInfo)
+		logger.Error("Failed to get min seq", zap.Error(err))
 	}
 
 	resp := &protocol.SyncRangeResponse{
@@ -674,6 +1037,7 @@ func (h *MessageHandler) handleSyncRange(conn transport.Connection, wsMsg *proto
 		StartSeq:       actualStartSeq,
 		EndSeq:         actualEndSeq,
 		HasMore:        hasMore,
+		MinSeq:         minSeq,
 	}
 
 	logger.Info("Range sync response",
@@ -687,6 +1051,18 @@ func (h *MessageHandler) handleSyncRange(conn transport.Connection, wsMsg *proto
 	return h.sendResponse(conn, protocol.CommandType_CMD_SYNC_RANGE_RSP, wsMsg.Sequence, resp)
 }
 
+// contentErrorCode 消息内容校验错误转换为协议错误码（ok 为 false 表示不是内容校验错误，返回 ERR_UNKNOWN）
+func contentErrorCode(err error) (code protocol.ErrorCode, ok bool) {
+	switch {
+	case errors.Is(err, service.ErrContentTooLong):
+		return protocol.ERR_MESSAGE_TOO_LARGE, true
+	case errors.Is(err, service.ErrInvalidContent):
+		return protocol.ERR_INVALID_PARAM, true
+	default:
+		return protocol.ERR_UNKNOWN, false
+	}
+}
+
 // sendResponse 发送响应
 func (h *MessageHandler) sendResponse(conn transport.Connection, command protocol.CommandType, sequence uint32, message proto.Message) error {
 	body, err := proto.Marshal(message)
@@ -730,8 +1106,8 @@ func (h *MessageHandler) sendResponse(conn transport.Connection, command protoco
 	return conn.Send(data)
 }
 
-// pushMessageToUser 推送消息给用户（✅ 使用 MessageInfo 结构）
-func (h *MessageHandler) pushMessageToUser(userID string, msg *model.Message) {
+// toMessageInfo 将 model.Message 转换为协议层 MessageInfo（推送和同步共用）
+func toMessageInfo(msg *model.Message) *protocol.MessageInfo {
 	// 推断会话类型
 	var conversationType int32
 	if msg.GroupID != "" {
@@ -740,22 +1116,223 @@ func (h *MessageHandler) pushMessageToUser(userID string, msg *model.Message) {
 		conversationType = 1 // 单聊
 	}
 
+	return &protocol.MessageInfo{
+		ServerMsgId:         msg.ServerMsgID,
+		ClientMsgId:         msg.ClientMsgID,
+		ConversationId:      msg.ConversationID,
+		ConversationType:    conversationType,
+		SenderId:            msg.SenderID,
+		ReceiverId:          msg.ReceiverID,
+		GroupId:             msg.GroupID,
+		Seq:                 msg.Seq,
+		MessageType:         int32(msg.MessageType),
+		Content:             []byte(msg.Content),
+		SendTime:            msg.SendTime,
+		ServerTime:          msg.ServerTime,
+		CreateTime:          msg.SendTime, // 创建时间（使用发送时间）
+		Status:              int32(msg.Status),
+		EditVersion:         int32(msg.EditVersion),
+		EditTime:            msg.EditTime,
+		ReplyToMsgId:        msg.ReplyToMsgID,
+		ReplyToSeq:          msg.ReplyToSeq,
+		QuotedMessage:       toQuotedMessage(msg.QuoteSnapshot),
+		ThreadRootSeq:       msg.ThreadRootSeq,
+		ThreadReplyCount:    int32(msg.ReplyCount),
+		ThreadLastReplyTime: msg.LastReplyTime,
+		MentionUserIds:      service.ParseMentionUserIDs(msg.MentionUserIDs),
+		MentionAll:          msg.MentionAll,
+		ForwardFromMsgId:    msg.ForwardFromID,
+		ExpireTtl:           int32(msg.ExpireTTL),
+		ExpireMode:          int32(msg.ExpireMode),
+		ExpireAt:            msg.ExpireAt,
+	}
+}
+
+// toQuotedMessage 解析被引用消息快照
+func toQuotedMessage(snapshot string) *protocol.QuotedMessage {
+	if snapshot == "" {
+		return nil
+	}
+
+	var quoted model.QuotedMessage
+	if err := json.Unmarshal([]byte(snapshot), &quoted); err != nil {
+		logger.Warn("Invalid quote snapshot", zap.Error(err))
+		return nil
+	}
+
+	return &protocol.QuotedMessage{
+		ServerMsgId: quoted.ServerMsgID,
+		Seq:         quoted.Seq,
+		SenderId:    quoted.SenderID,
+		MessageType: int32(quoted.MessageType),
+		Content:     []byte(quoted.Content),
+		SendTime:    quoted.SendTime,
+		Status:      int32(quoted.Status),
+	}
+}
+
+// toMessageInfoList 批量转换同一会话的消息，并填充当前用户视角的已读状态和表情回应（同步时使用）
+func (h *MessageHandler) toMessageInfoList(messages []*model.Message, userID string) []*protocol.MessageInfo {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	// 已读位置之前的消息为已读
+	readSeq, err := h.readReceiptService.GetReadSeq(userID, messages[0].ConversationID)
+	if err != nil {
+		logger.Error("Failed to get read seq", zap.Error(err))
+		readSeq = 0 // 失败时默认为未读
+	}
+
+	// 批量查询表情回应汇总（同一批消息属于同一会话）
+	seqs := make([]int64, len(messages))
+	for i, msg := range messages {
+		seqs[i] = msg.Seq
+	}
+	reactions, err := h.reactionService.GetReactionSummaries(messages[0].ConversationID, seqs, userID)
+	if err != nil {
+		logger.Error("Failed to get reaction summaries", zap.Error(err))
+		reactions = make(map[int64][]*service.ReactionSummary)
+	}
+
+	// 群聊中自己发送的消息附带已读人数
+	readStates := h.getOwnReadStates(messages, userID)
+
+	messageInfoList := make([]*protocol.MessageInfo, 0, len(messages))
+	for _, msg := range messages {
+		msgInfo := toMessageInfo(msg)
+		msgInfo.IsRead = msg.Seq <= readSeq || msg.SenderID == userID
+		msgInfo.Reactions = toReactionSummaries(reactions[msg.Seq])
+		if state, ok := readStates[msg.ServerMsgID]; ok {
+			msgInfo.ReadCount = int32(state.ReadCount)
+			msgInfo.UnreadCount = int32(state.UnreadCount)
+			msgInfo.ReadBy = state.ReadBy
+		}
+		messageInfoList = append(messageInfoList, msgInfo)
+	}
+	return messageInfoList
+}
+
+// toMixedMessageInfoList 批量转换属于多个会话的消息（如搜索结果）：按会话分组后分别填充已读状态和表情回应，保持原有顺序
+func (h *MessageHandler) toMixedMessageInfoList(messages []*model.Message, userID string) []*protocol.MessageInfo {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	var conversationIDs []string
+	groups := make(map[string][]int) // 会话 -> 消息下标
+	for i, msg := range messages {
+		if _, ok := groups[msg.ConversationID]; !ok {
+			conversationIDs = append(conversationIDs, msg.ConversationID)
+		}
+		groups[msg.ConversationID] = append(groups[msg.ConversationID], i)
+	}
+
+	messageInfoList := make([]*protocol.MessageInfo, len(messages))
+	for _, conversationID := range conversationIDs {
+		indexes := groups[conversationID]
+		batch := make([]*model.Message, len(indexes))
+		for i, index := range indexes {
+			batch[i] = messages[index]
+		}
+		for i, info := range h.toMessageInfoList(batch, userID) {
+			messageInfoList[indexes[i]] = info
+		}
+	}
+	return messageInfoList
+}
+
+// getMessageParticipants 获取消息所在会话的参与者（单聊为收发双方，群聊为群成员）
+func (h *MessageHandler) getMessageParticipants(msg *model.Message) ([]string, error) {
+	if msg.GroupID != "" {
+		return h.groupService.GetGroupMemberIDs(context.Background(), msg.GroupID)
+	}
+	return []string{msg.SenderID, msg.ReceiverID}, nil
+}
+
+// isMessageParticipant 检查用户是否是消息所在会话的参与者
+func (h *MessageHandler) isMessageParticipant(msg *model.Message, userID string) bool {
+	if msg.GroupID != "" {
+		isMember, err := h.groupService.IsGroupMember(context.Background(), msg.GroupID, userID)
+		if err != nil {
+			logger.Error("Failed to check group member", zap.Error(err), zap.String("group_id", msg.GroupID))
+			return false
+		}
+		return isMember
+	}
+	return msg.SenderID == userID || msg.ReceiverID == userID
+}
+
+// isConversationParticipant 根据会话ID检查用户是否是会话参与者（single_{a}_{b} 或 group_{groupID}）
+func (h *MessageHandler) isConversationParticipant(conversationID, userID string) bool {
+	switch {
+	case strings.HasPrefix(conversationID, "group_"):
+		groupID := strings.TrimPrefix(conversationID, "group_")
+		isMember, err := h.groupService.IsGroupMember(context.Background(), groupID, userID)
+		if err != nil {
+			logger.Error("Failed to check group member", zap.Error(err), zap.String("group_id", groupID))
+			return false
+		}
+		return isMember
+	case strings.HasPrefix(conversationID, "single_"):
+		_, ok := h.singleConversationPeer(conversationID, userID)
+		return ok
+	default:
+		return false
+	}
+}
+
+// getConversationParticipants 根据会话ID获取会话参与者（userID 必须是单聊的一方，用于解析对方ID）
+func (h *MessageHandler) getConversationParticipants(conversationID, userID string) ([]string, error) {
+	if strings.HasPrefix(conversationID, "group_") {
+		return h.groupService.GetGroupMemberIDs(context.Background(), strings.TrimPrefix(conversationID, "group_"))
+	}
+
+	if peerID, ok := h.singleConversationPeer(conversationID, userID); ok {
+		return []string{userID, peerID}, nil
+	}
+	return []string{userID}, nil
+}
+
+// singleConversationPeer 解析单聊会话中 userID 的对方ID：userID 必须是会话ID的完整一方
+// 只解析会话ID、不查询用户表，演示账号和 token 认证用户没有用户记录也能正常使用
+func (h *MessageHandler) singleConversationPeer(conversationID, userID string) (string, bool) {
+	return utils.SingleConversationPeer(conversationID, userID)
+}
+
+// pushToMessageParticipants 推送通知给消息所在会话的参与者（排除操作者本人）
+func (h *MessageHandler) pushToMessageParticipants(msg *model.Message, excludeUserID string, command protocol.CommandType, body []byte) {
+	userIDs, err := h.getMessageParticipants(msg)
+	if err != nil {
+		logger.Error("Failed to get conversation participants",
+			zap.Error(err),
+			zap.String("conversation_id", msg.ConversationID))
+		return
+	}
+
+	for _, userID := range userIDs {
+		if userID == "" || userID == excludeUserID {
+			continue
+		}
+		h.pushToUser(userID, command, body)
+	}
+}
+
+// pushNewMessage 推送新消息给接收者（单聊推送给接收者，群聊推送给除发送者外的群成员）
+func (h *MessageHandler) pushNewMessage(msg *model.Message) {
+	h.updateConversationsOnMessage(msg)
+
+	if msg.GroupID != "" {
+		h.pushMessageToGroup(msg.GroupID, msg.SenderID, msg)
+	} else {
+		h.pushMessageToUser(msg.ReceiverID, msg)
+	}
+}
+
+// pushMessageToUser 推送消息给用户（✅ 使用 MessageInfo 结构）
+func (h *MessageHandler) pushMessageToUser(userID string, msg *model.Message) {
 	pushMsg := &protocol.PushMessage{
-		Message: &protocol.MessageInfo{ // ✅ 通过 Message 字段包装
-			ServerMsgId:      msg.ServerMsgID, // ✅ 使用服务端消息ID
-			ClientMsgId:      msg.ClientMsgID, // ✅ 客户端消息ID
-			ConversationId:   msg.ConversationID,
-			ConversationType: conversationType, // ✅ 添加 ConversationType
-			SenderId:         msg.SenderID,
-			ReceiverId:       msg.ReceiverID,
-			GroupId:          msg.GroupID,
-			MessageType:      int32(msg.MessageType),
-			Content:          []byte(msg.Content),
-			SendTime:         msg.SendTime,   // 发送时间
-			ServerTime:       msg.ServerTime, // 服务器时间
-			CreateTime:       msg.SendTime,   // ✅ 创建时间（使用发送时间）
-			Seq:              msg.Seq,
-		},
+		Message: toMessageInfo(msg), // ✅ 通过 Message 字段包装
 	}
 
 	body, err := proto.Marshal(pushMsg)
@@ -768,6 +1345,7 @@ func (h *MessageHandler) pushMessageToUser(userID string, msg *model.Message) {
 	conn, exists := h.connManager.GetUserConnection(userID)
 	if !exists {
 		logger.Debug("User not online", zap.String("user_id", userID))
+		h.offlinePushService.NotifyNewMessage(userID, msg)
 		return
 	}
 
@@ -801,36 +1379,14 @@ func (h *MessageHandler) pushMessageToUser(userID string, msg *model.Message) {
 // pushMessageToGroup 推送消息给群组所有成员（除了发送者）
 func (h *MessageHandler) pushMessageToGroup(groupID, senderID string, msg *model.Message) {
 	// 获取群组成员
-	members, err := h.groupService.GetGroupMembers(nil, groupID)
+	memberIDs, err := h.groupService.GetGroupMemberIDs(context.Background(), groupID)
 	if err != nil {
 		logger.Error("Failed to get group members", zap.Error(err), zap.String("group_id", groupID))
 		return
 	}
 
-	// 推断会话类型
-	var conversationType int32
-	if msg.GroupID != "" {
-		conversationType = 2 // 群聊
-	} else {
-		conversationType = 1 // 单聊
-	}
-
 	pushMsg := &protocol.PushMessage{
-		Message: &protocol.MessageInfo{
-			ServerMsgId:      msg.ServerMsgID,
-			ClientMsgId:      msg.ClientMsgID,
-			ConversationId:   msg.ConversationID,
-			ConversationType: conversationType, // ✅ 添加 ConversationType
-			SenderId:         msg.SenderID,
-			ReceiverId:       msg.ReceiverID,
-			GroupId:          msg.GroupID,
-			MessageType:      int32(msg.MessageType),
-			Content:          []byte(msg.Content),
-			SendTime:         msg.SendTime,
-			ServerTime:       msg.ServerTime,
-			CreateTime:       msg.SendTime,
-			Seq:              msg.Seq,
-		},
+		Message: toMessageInfo(msg),
 	}
 
 	body, err := proto.Marshal(pushMsg)
@@ -841,15 +1397,17 @@ func (h *MessageHandler) pushMessageToGroup(groupID, senderID string, msg *model
 
 	// 推送给所有成员（除了发送者）
 	pushCount := 0
-	for _, member := range members {
-		if member.ID == senderID {
+	for _, memberID := range memberIDs {
+		if memberID == senderID {
 			continue // 跳过发送者
 		}
 
 		// 获取成员连接
-		conn, exists := h.connManager.GetUserConnection(member.ID)
+		conn, exists := h.connManager.GetUserConnection(memberID)
 		if !exists {
-			continue // 成员不在线
+			// 成员不在线：离线推送（被 @ 的成员会带上提醒标记）
+			h.offlinePushService.NotifyNewMessage(memberID, msg)
+			continue
 		}
 
 		var data []byte
@@ -876,7 +1434,7 @@ func (h *MessageHandler) pushMessageToGroup(groupID, senderID string, msg *model
 		// 发送给成员
 		if err := conn.Send(data); err != nil {
 			logger.Warn("Failed to push message to group member",
-				zap.String("user_id", member.ID),
+				zap.String("user_id", memberID),
 				zap.String("group_id", groupID),
 				zap.Error(err))
 		} else {
@@ -886,7 +1444,7 @@ func (h *MessageHandler) pushMessageToGroup(groupID, senderID string, msg *model
 
 	logger.Info("Group message pushed",
 		zap.String("group_id", groupID),
-		zap.Int("member_count", len(members)),
+		zap.Int("member_count", len(memberIDs)),
 		zap.Int("push_count", pushCount))
 }
 
@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// handleSetModeration 处理设置群组内容审核严格程度
+func (h *MessageHandler) handleSetModeration(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.SetModerationRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.ModerationResponse{
+		GroupId: req.GroupId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SET_MODERATION_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.GroupId == "" {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "group_id is required"
+		return h.sendResponse(conn, protocol.CMD_SET_MODERATION_RSP, wsMsg.Sequence, resp)
+	}
+
+	if err := h.moderationService.SetGroupStrictness(context.Background(), req.GroupId, userID, int(req.Strictness)); err != nil {
+		resp.ErrorMsg = err.Error()
+		switch err {
+		case service.ErrInvalidStrictness:
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		case service.ErrPermissionDenied:
+			resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+			resp.ErrorMsg = "Only group owner or admin can change moderation"
+		case service.ErrNotGroupMember:
+			resp.ErrorCode = protocol.ERR_NOT_GROUP_MEMBER
+		default:
+			logger.Error("Failed to set moderation strictness", zap.Error(err))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to set moderation strictness"
+		}
+		return h.sendResponse(conn, protocol.CMD_SET_MODERATION_RSP, wsMsg.Sequence, resp)
+	}
+
+	logger.Info("Group moderation strictness updated",
+		zap.String("group_id", req.GroupId),
+		zap.String("user_id", userID),
+		zap.Int32("strictness", req.Strictness))
+
+	h.fillModerationResponse(resp)
+	return h.sendResponse(conn, protocol.CMD_SET_MODERATION_RSP, wsMsg.Sequence, resp)
+}
+
+// handleGetModeration 处理获取群组内容审核严格程度
+func (h *MessageHandler) handleGetModeration(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.GetModerationRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.ModerationResponse{
+		GroupId: req.GroupId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_GET_MODERATION_RSP, wsMsg.Sequence, resp)
+	}
+
+	isMember, err := h.groupService.IsGroupMember(context.Background(), req.GroupId, userID)
+	if err != nil || !isMember {
+		resp.ErrorCode = protocol.ERR_NOT_GROUP_MEMBER
+		resp.ErrorMsg = "Not a group member"
+		return h.sendResponse(conn, protocol.CMD_GET_MODERATION_RSP, wsMsg.Sequence, resp)
+	}
+
+	h.fillModerationResponse(resp)
+	return h.sendResponse(conn, protocol.CMD_GET_MODERATION_RSP, wsMsg.Sequence, resp)
+}
+
+// fillModerationResponse 填充群组当前生效的审核严格程度
+func (h *MessageHandler) fillModerationResponse(resp *protocol.ModerationResponse) {
+	strictness, override, err := h.moderationService.GetGroupStrictness(resp.GroupId)
+	if err != nil {
+		logger.Error("Failed to get moderation strictness", zap.Error(err))
+		resp.ErrorCode = protocol.ERR_UNKNOWN
+		resp.ErrorMsg = "Failed to get moderation strictness"
+		return
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.Strictness = int32(strictness)
+	resp.IsOverride = override
+}
+
+// moderateMessage 审核即将保存的消息（敏感词可能被替换到 msg.Content 中）
+// 返回 false 表示内容被拒绝，拒绝的结果在这里直接写入审核记录；其他结果在消息保存后调用 moderationService.Audit
+func (h *MessageHandler) moderateMessage(msg *model.Message) (*service.ModerationDecision, bool) {
+	decision := h.moderationService.Moderate(context.Background(), msg)
+	if decision.Action != model.ModerationActionReject {
+		return decision, true
+	}
+
+	h.moderationService.Audit(msg, decision)
+	logger.Info("Message rejected by moderation",
+		zap.String("conversation_id", msg.ConversationID),
+		zap.String("sender_id", msg.SenderID),
+		zap.String("source", decision.Source))
+	return decision, false
+}
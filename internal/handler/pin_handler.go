@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	pinActionPin   = 1 // 置顶
+	pinActionUnpin = 2 // 取消置顶
+)
+
+// handlePinMessage 处理置顶消息
+func (h *MessageHandler) handlePinMessage(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	return h.handlePin(conn, wsMsg, pinActionPin)
+}
+
+// handleUnpinMessage 处理取消置顶消息
+func (h *MessageHandler) handleUnpinMessage(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	return h.handlePin(conn, wsMsg, pinActionUnpin)
+}
+
+// handlePin 置顶/取消置顶的公共处理逻辑
+func (h *MessageHandler) handlePin(conn transport.Connection, wsMsg *protocol.WebSocketMessage, action int32) error {
+	rspCommand := protocol.CMD_PIN_MSG_RSP
+	if action == pinActionUnpin {
+		rspCommand = protocol.CMD_UNPIN_MSG_RSP
+	}
+
+	var req protocol.PinMessageRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.PinMessageResponse{
+		ConversationId: req.ConversationId,
+		Seq:            req.Seq,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" || req.Seq <= 0 {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id and seq are required"
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+
+	msg, err := h.msgService.GetMessageBySeq(req.ConversationId, req.Seq)
+	if err != nil || (action == pinActionPin && (msg.Status == 4 || msg.Status == model.MessageStatusExpired)) {
+		resp.ErrorCode = protocol.ERR_MESSAGE_NOT_EXIST
+		resp.ErrorMsg = "Message not found"
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+
+	canPin, err := h.pinService.CanPin(context.Background(), msg, userID)
+	if err != nil {
+		logger.Error("Failed to check pin permission", zap.Error(err))
+		resp.ErrorCode = protocol.ERR_UNKNOWN
+		resp.ErrorMsg = "Failed to check permission"
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+	if !canPin {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Only group owner or admin can pin messages"
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+
+	if action == pinActionPin {
+		var pin *model.PinnedMessage
+		pin, err = h.pinService.PinMessage(msg, userID)
+		if err == nil {
+			resp.PinnedAt = pin.PinnedAt
+		}
+	} else {
+		err = h.pinService.UnpinMessage(msg.ConversationID, msg.Seq)
+	}
+	if err != nil {
+		if err == service.ErrTooManyPins {
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+			resp.ErrorMsg = err.Error()
+		} else {
+			logger.Error("Failed to update pinned message", zap.Error(err))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to update pinned message"
+		}
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	if err := h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp); err != nil {
+		return err
+	}
+
+	// 推送给会话中的其他在线成员（携带操作后的完整置顶列表）
+	pins, err := h.pinService.GetPinnedMessages(msg.ConversationID)
+	if err != nil {
+		logger.Error("Failed to get pinned messages", zap.Error(err))
+		return nil
+	}
+	push := &protocol.PinMessagePush{
+		ConversationId: msg.ConversationID,
+		Seq:            msg.Seq,
+		ServerMsgId:    msg.ServerMsgID,
+		OperatorId:     userID,
+		Action:         action,
+		Time:           utils.GetCurrentMillis(),
+		PinnedMessages: toPinnedMessageInfoList(pins),
+	}
+	pushData, err := protocol.Marshal(push)
+	if err != nil {
+		logger.Error("Failed to marshal pin push", zap.Error(err))
+		return nil
+	}
+	go h.pushToMessageParticipants(msg, userID, protocol.CMD_PIN_PUSH, pushData)
+
+	logger.Info("Pinned messages updated",
+		zap.String("conversation_id", msg.ConversationID),
+		zap.Int64("seq", msg.Seq),
+		zap.String("user_id", userID),
+		zap.Int32("action", action),
+		zap.Int("pinned_count", len(pins)))
+
+	return nil
+}
+
+// handlePinnedList 处理获取会话置顶消息列表
+func (h *MessageHandler) handlePinnedList(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.PinnedListRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.PinnedListResponse{
+		RequestId:      req.RequestId,
+		ConversationId: req.ConversationId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_PINNED_LIST_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id is required"
+		return h.sendResponse(conn, protocol.CMD_PINNED_LIST_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_PINNED_LIST_RSP, wsMsg.Sequence, resp)
+	}
+
+	pins, err := h.pinService.GetPinnedMessages(req.ConversationId)
+	if err != nil {
+		logger.Error("Failed to get pinned messages", zap.Error(err))
+		resp.ErrorCode = protocol.ERR_UNKNOWN
+		resp.ErrorMsg = "Failed to get pinned messages"
+		return h.sendResponse(conn, protocol.CMD_PINNED_LIST_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 查询完整消息（已撤回的消息不再返回）
+	messages := make([]*model.Message, 0, len(pins))
+	validPins := make([]*model.PinnedMessage, 0, len(pins))
+	for _, pin := range pins {
+		msg, err := h.msgService.GetMessageBySeq(pin.ConversationID, pin.Seq)
+		if err != nil || msg.Status == 4 || msg.Status == model.MessageStatusExpired {
+			continue
+		}
+		messages = append(messages, msg)
+		validPins = append(validPins, pin)
+	}
+
+	pinnedList := toPinnedMessageInfoList(validPins)
+	for i, info := range h.toMessageInfoList(messages, userID) {
+		pinnedList[i].Message = info
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.PinnedMessages = pinnedList
+
+	return h.sendResponse(conn, protocol.CMD_PINNED_LIST_RSP, wsMsg.Sequence, resp)
+}
+
+// toPinnedMessageInfoList 转换置顶列表为协议结构（不含完整消息）
+func toPinnedMessageInfoList(pins []*model.PinnedMessage) []*protocol.PinnedMessageInfo {
+	if len(pins) == 0 {
+		return nil
+	}
+
+	result := make([]*protocol.PinnedMessageInfo, 0, len(pins))
+	for _, pin := range pins {
+		result = append(result, &protocol.PinnedMessageInfo{
+			Seq:         pin.Seq,
+			ServerMsgId: pin.ServerMsgID,
+			PinnedBy:    pin.PinnedBy,
+			PinnedAt:    pin.PinnedAt,
+		})
+	}
+	return result
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// handleOnlineStatus 处理查询/订阅在线状态
+func (h *MessageHandler) handleOnlineStatus(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.OnlineStatusRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.OnlineStatusResponse{}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_ONLINE_STATUS_RSP, wsMsg.Sequence, resp)
+	}
+
+	if len(req.UnsubscribeIds) > 0 {
+		h.presenceService.Unsubscribe(userID, req.UnsubscribeIds)
+	}
+
+	userIDs := uniqueStrings(req.UserIds)
+	var presences []*service.Presence
+	var err error
+	if req.Subscribe {
+		presences, err = h.presenceService.Subscribe(userID, userIDs)
+	} else {
+		presences, err = h.presenceService.GetPresences(userIDs)
+	}
+	if err != nil {
+		switch err {
+		case service.ErrTooManyPresenceUsers, service.ErrTooManySubscriptions:
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+			resp.ErrorMsg = err.Error()
+		default:
+			logger.Error("Failed to get presences", zap.Error(err), zap.String("user_id", userID))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to get online status"
+		}
+		return h.sendResponse(conn, protocol.CMD_ONLINE_STATUS_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 按被查询者的设置隐藏最后在线时间
+	if err := h.presenceService.ApplyPrivacy(userID, presences); err != nil {
+		logger.Error("Failed to apply last seen privacy", zap.Error(err), zap.String("user_id", userID))
+		for _, p := range presences {
+			if p.UserID != userID {
+				p.LastSeen = 0
+			}
+		}
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.Presences = make([]*protocol.UserPresence, 0, len(presences))
+	for _, p := range presences {
+		resp.Presences = append(resp.Presences, toUserPresence(p))
+	}
+	return h.sendResponse(conn, protocol.CMD_ONLINE_STATUS_RSP, wsMsg.Sequence, resp)
+}
+
+// handleSetPresence 处理设置自定义状态和最后在线时间可见范围（变化推送给订阅者）
+func (h *MessageHandler) handleSetPresence(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.SetPresenceRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.SetPresenceResponse{}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SET_PRESENCE_RSP, wsMsg.Sequence, resp)
+	}
+
+	var status *service.CustomStatus
+	if req.Status != nil {
+		status = &service.CustomStatus{
+			Type:     int(req.Status.Type),
+			Text:     req.Status.Text,
+			Emoji:    req.Status.Emoji,
+			ExpireAt: req.Status.ExpireAt,
+		}
+	}
+	if err := h.presenceService.UpdateSettings(userID, req.Fields, status, int(req.LastSeenVisibility)); err != nil {
+		if err == service.ErrInvalidPresence {
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+			resp.ErrorMsg = err.Error()
+		} else {
+			logger.Error("Failed to update presence settings", zap.Error(err), zap.String("user_id", userID))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to update presence settings"
+		}
+		return h.sendResponse(conn, protocol.CMD_SET_PRESENCE_RSP, wsMsg.Sequence, resp)
+	}
+
+	logger.Info("Presence settings updated", zap.String("user_id", userID), zap.Strings("fields", req.Fields))
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	if presences, err := h.presenceService.GetPresences([]string{userID}); err == nil && len(presences) == 1 {
+		resp.Presence = toUserPresence(presences[0])
+	} else if err != nil {
+		logger.Error("Failed to get presence", zap.Error(err), zap.String("user_id", userID))
+	}
+	if visibility, err := h.presenceService.GetLastSeenVisibility(userID); err == nil {
+		resp.LastSeenVisibility = int32(visibility)
+	}
+	return h.sendResponse(conn, protocol.CMD_SET_PRESENCE_RSP, wsMsg.Sequence, resp)
+}
+
+// HandlePresenceChange 用户上线/下线（由连接管理器回调）：更新在线状态，下线时清除该用户的订阅
+func (h *MessageHandler) HandlePresenceChange(userID, platform string, online bool) {
+	// 聊天室成员关系绑定到连接：新连接替换旧连接或下线时离开旧连接加入的聊天室
+	h.leaveStaleChatrooms(userID)
+
+	var err error
+	if online {
+		err = h.presenceService.SetOnline(userID, platform)
+	} else {
+		// 订阅关系随连接断开而清除（重新连接后客户端重新订阅）
+		h.presenceService.UnsubscribeAll(userID)
+		err = h.presenceService.SetOffline(userID, platform)
+	}
+	if err != nil {
+		logger.Error("Failed to update online status",
+			zap.Error(err),
+			zap.String("user_id", userID),
+			zap.String("platform", platform),
+			zap.Bool("online", online))
+	}
+}
+
+// NotifyPresenceChange 推送在线状态变化给订阅者（由在线状态服务的推送任务调用）
+// 无权看到最后在线时间的订阅者收到的推送不带最后在线时间
+func (h *MessageHandler) NotifyPresenceChange(presence *service.Presence, subscriberIDs []string) {
+	info := toUserPresence(presence)
+	body, err := protocol.Marshal(&protocol.StatusChangePush{Presence: info})
+	if err != nil {
+		logger.Error("Failed to marshal status change push", zap.Error(err))
+		return
+	}
+
+	hiddenBody := body
+	var viewers map[string]bool
+	if presence.LastSeen != 0 {
+		if viewers, err = h.presenceService.LastSeenViewers(presence, subscriberIDs); err != nil {
+			logger.Error("Failed to get last seen viewers", zap.Error(err), zap.String("user_id", presence.UserID))
+		}
+		info.LastSeen = 0
+		if hiddenBody, err = protocol.Marshal(&protocol.StatusChangePush{Presence: info}); err != nil {
+			logger.Error("Failed to marshal status change push", zap.Error(err))
+			return
+		}
+	}
+
+	for _, subscriberID := range subscriberIDs {
+		if presence.LastSeen == 0 || viewers[subscriberID] {
+			h.pushToUser(subscriberID, protocol.CMD_STATUS_CHANGE_PUSH, body)
+		} else {
+			h.pushToUser(subscriberID, protocol.CMD_STATUS_CHANGE_PUSH, hiddenBody)
+		}
+	}
+}
+
+// toUserPresence 转换在线状态为协议结构
+func toUserPresence(p *service.Presence) *protocol.UserPresence {
+	info := &protocol.UserPresence{
+		UserId:   p.UserID,
+		Online:   p.Online,
+		LastSeen: p.LastSeen,
+	}
+	for _, platform := range p.Platforms {
+		info.Platforms = append(info.Platforms, &protocol.PlatformPresence{
+			Platform:    platform.Platform,
+			OnlineSince: platform.OnlineSince,
+		})
+	}
+	if p.Status != nil {
+		info.Status = &protocol.CustomStatus{
+			Type:     int32(p.Status.Type),
+			Text:     p.Status.Text,
+			Emoji:    p.Status.Emoji,
+			ExpireAt: p.Status.ExpireAt,
+		}
+	}
+	return info
+}
+
+// uniqueStrings 去重（保持原有顺序，忽略空字符串）
+func uniqueStrings(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	result := make([]string, 0, len(list))
+	for _, s := range list {
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		result = append(result, s)
+	}
+	return result
+}
@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	reactionActionAdd    = 1 // 添加回应
+	reactionActionRemove = 2 // 取消回应
+)
+
+// handleAddReaction 处理添加表情回应
+func (h *MessageHandler) handleAddReaction(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	return h.handleReaction(conn, wsMsg, reactionActionAdd)
+}
+
+// handleRemoveReaction 处理取消表情回应
+func (h *MessageHandler) handleRemoveReaction(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	return h.handleReaction(conn, wsMsg, reactionActionRemove)
+}
+
+// handleReaction 添加/取消表情回应的公共处理逻辑
+func (h *MessageHandler) handleReaction(conn transport.Connection, wsMsg *protocol.WebSocketMessage, action int32) error {
+	rspCommand := protocol.CMD_ADD_REACTION_RSP
+	if action == reactionActionRemove {
+		rspCommand = protocol.CMD_REMOVE_REACTION_RSP
+	}
+
+	var req protocol.ReactionRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.ReactionResponse{
+		ConversationId: req.ConversationId,
+		Seq:            req.Seq,
+		Emoji:          req.Emoji,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" || req.Seq <= 0 || req.Emoji == "" {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id, seq and emoji are required"
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+
+	// 查询用户可见的消息并检查当前用户是否是会话参与者
+	msg, err := h.msgService.GetVisibleMessageBySeq(userID, req.ConversationId, req.Seq)
+	if err != nil || msg.Status == 4 || msg.Status == model.MessageStatusExpired {
+		resp.ErrorCode = protocol.ERR_MESSAGE_NOT_EXIST
+		resp.ErrorMsg = "Message not found"
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+	if !h.isMessageParticipant(msg, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+
+	var count int
+	if action == reactionActionAdd {
+		count, err = h.reactionService.AddReaction(req.ConversationId, req.Seq, req.Emoji, userID)
+	} else {
+		count, err = h.reactionService.RemoveReaction(req.ConversationId, req.Seq, req.Emoji, userID)
+	}
+	if err != nil {
+		if err == service.ErrInvalidEmoji {
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		} else {
+			logger.Error("Failed to update reaction", zap.Error(err))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+		}
+		resp.ErrorMsg = err.Error()
+		return h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.Count = int32(count)
+	if err := h.sendResponse(conn, rspCommand, wsMsg.Sequence, resp); err != nil {
+		return err
+	}
+
+	// 实时推送给会话中的其他在线用户
+	push := &protocol.ReactionPush{
+		ConversationId: msg.ConversationID,
+		Seq:            msg.Seq,
+		ServerMsgId:    msg.ServerMsgID,
+		Emoji:          req.Emoji,
+		UserId:         userID,
+		Action:         action,
+		Count:          int32(count),
+		Time:           utils.GetCurrentMillis(),
+	}
+	pushData, err := protocol.Marshal(push)
+	if err != nil {
+		logger.Error("Failed to marshal reaction push", zap.Error(err))
+		return nil
+	}
+	go h.pushToMessageParticipants(msg, userID, protocol.CMD_REACTION_PUSH, pushData)
+
+	logger.Debug("Reaction updated",
+		zap.String("conversation_id", msg.ConversationID),
+		zap.Int64("seq", msg.Seq),
+		zap.String("emoji", req.Emoji),
+		zap.String("user_id", userID),
+		zap.Int32("action", action),
+		zap.Int("count", count))
+
+	return nil
+}
+
+// toReactionSummaries 转换表情回应汇总为协议结构
+func toReactionSummaries(summaries []*service.ReactionSummary) []*protocol.ReactionSummary {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	result := make([]*protocol.ReactionSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		result = append(result, &protocol.ReactionSummary{
+			Emoji:       summary.Emoji,
+			Count:       int32(summary.Count),
+			ReactedByMe: summary.ReactedByMe,
+		})
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// handleReadMembers 处理查询消息的已读/未读成员（仅消息发送者可以查询）
+func (h *MessageHandler) handleReadMembers(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.ReadMembersRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.ReadMembersResponse{
+		ServerMsgId: req.ServerMsgId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_READ_MEMBERS_RSP, wsMsg.Sequence, resp)
+	}
+
+	msg, err := h.msgService.GetMessageByServerMsgID(req.ServerMsgId)
+	if err != nil || (req.ConversationId != "" && msg.ConversationID != req.ConversationId) {
+		resp.ErrorCode = protocol.ERR_MESSAGE_NOT_EXIST
+		resp.ErrorMsg = "Message not found"
+		return h.sendResponse(conn, protocol.CMD_READ_MEMBERS_RSP, wsMsg.Sequence, resp)
+	}
+	if msg.SenderID != userID {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Only the sender can view read members"
+		return h.sendResponse(conn, protocol.CMD_READ_MEMBERS_RSP, wsMsg.Sequence, resp)
+	}
+
+	readers, unread, err := h.readReceiptService.GetReadMembers(msg)
+	if err != nil {
+		logger.Error("Failed to get read members", zap.Error(err), zap.String("server_msg_id", req.ServerMsgId))
+		resp.ErrorCode = protocol.ERR_UNKNOWN
+		resp.ErrorMsg = "Failed to get read members"
+		return h.sendResponse(conn, protocol.CMD_READ_MEMBERS_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.ReadMembers = make([]*protocol.ReadMember, 0, len(readers))
+	for _, r := range readers {
+		resp.ReadMembers = append(resp.ReadMembers, &protocol.ReadMember{
+			UserId:   r.UserID,
+			ReadTime: r.ReadTime,
+		})
+	}
+	resp.UnreadUserIds = unread
+	resp.ReadCount = int32(len(readers))
+	resp.UnreadCount = int32(len(unread))
+	return h.sendResponse(conn, protocol.CMD_READ_MEMBERS_RSP, wsMsg.Sequence, resp)
+}
+
+// NotifyGroupReadReceipts 推送聚合后的群聊已读人数给消息发送者（由已读回执服务定期调用）
+func (h *MessageHandler) NotifyGroupReadReceipts(senderID, conversationID string, states []*service.MessageReadState) {
+	push := &protocol.GroupReadReceiptPush{
+		ConversationId: conversationID,
+		States:         toMessageReadStates(states),
+	}
+	pushData, err := protocol.Marshal(push)
+	if err != nil {
+		logger.Error("Failed to marshal group read receipt push", zap.Error(err))
+		return
+	}
+	h.pushToUser(senderID, protocol.CMD_GROUP_READ_RECEIPT_PUSH, pushData)
+}
+
+// getOwnReadStates 查询群聊中自己发送的消息的已读人数（同步时使用，同一批消息属于同一会话）
+func (h *MessageHandler) getOwnReadStates(messages []*model.Message, userID string) map[string]*service.MessageReadState {
+	if messages[0].GroupID == "" {
+		return nil
+	}
+
+	var own []*model.Message
+	for _, msg := range messages {
+		if msg.SenderID == userID {
+			own = append(own, msg)
+		}
+	}
+	if len(own) == 0 {
+		return nil
+	}
+
+	states, err := h.readReceiptService.GetReadStates(own[0].GroupID, own[0].ConversationID, own)
+	if err != nil {
+		logger.Error("Failed to get read states", zap.Error(err))
+		return nil
+	}
+	return states
+}
+
+// toMessageReadStates 转换已读情况为协议结构
+func toMessageReadStates(states []*service.MessageReadState) []*protocol.MessageReadState {
+	result := make([]*protocol.MessageReadState, 0, len(states))
+	for _, s := range states {
+		result = append(result, &protocol.MessageReadState{
+			ServerMsgId: s.ServerMsgID,
+			Seq:         s.Seq,
+			ReadCount:   int32(s.ReadCount),
+			UnreadCount: int32(s.UnreadCount),
+			ReadBy:      s.ReadBy,
+		})
+	}
+	return result
+}
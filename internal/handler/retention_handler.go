@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// handleSetRetention 处理设置会话消息保留期限
+func (h *MessageHandler) handleSetRetention(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.SetRetentionRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.RetentionResponse{
+		ConversationId: req.ConversationId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SET_RETENTION_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id is required"
+		return h.sendResponse(conn, protocol.CMD_SET_RETENTION_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_SET_RETENTION_RSP, wsMsg.Sequence, resp)
+	}
+
+	if err := h.retentionService.SetRetention(context.Background(), req.ConversationId, userID, int(req.RetentionDays)); err != nil {
+		resp.ErrorMsg = err.Error()
+		switch err {
+		case service.ErrInvalidRetention:
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		case service.ErrPermissionDenied:
+			resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+			resp.ErrorMsg = "Only group owner can change retention"
+		case service.ErrNotGroupMember:
+			resp.ErrorCode = protocol.ERR_NOT_GROUP_MEMBER
+		default:
+			logger.Error("Failed to set retention", zap.Error(err))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to set retention"
+		}
+		return h.sendResponse(conn, protocol.CMD_SET_RETENTION_RSP, wsMsg.Sequence, resp)
+	}
+
+	logger.Info("Conversation retention updated",
+		zap.String("conversation_id", req.ConversationId),
+		zap.String("user_id", userID),
+		zap.Int32("retention_days", req.RetentionDays))
+
+	h.fillRetentionResponse(resp)
+	return h.sendResponse(conn, protocol.CMD_SET_RETENTION_RSP, wsMsg.Sequence, resp)
+}
+
+// handleGetRetention 处理获取会话消息保留期限
+func (h *MessageHandler) handleGetRetention(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.GetRetentionRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.RetentionResponse{
+		ConversationId: req.ConversationId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_GET_RETENTION_RSP, wsMsg.Sequence, resp)
+	}
+
+	if !h.isConversationParticipant(req.ConversationId, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_GET_RETENTION_RSP, wsMsg.Sequence, resp)
+	}
+
+	h.fillRetentionResponse(resp)
+	return h.sendResponse(conn, protocol.CMD_GET_RETENTION_RSP, wsMsg.Sequence, resp)
+}
+
+// fillRetentionResponse 填充会话当前生效的保留期限和清理位置
+func (h *MessageHandler) fillRetentionResponse(resp *protocol.RetentionResponse) {
+	days, override, err := h.retentionService.GetRetention(resp.ConversationId)
+	if err != nil {
+		logger.Error("Failed to get retention", zap.Error(err))
+		resp.ErrorCode = protocol.ERR_UNKNOWN
+		resp.ErrorMsg = "Failed to get retention"
+		return
+	}
+	minSeq, err := h.msgService.GetMinSeq(resp.ConversationId)
+	if err != nil {
+		logger.Error("Failed to get min seq", zap.Error(err))
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.RetentionDays = int32(days)
+	resp.IsOverride = override
+	resp.MinSeq = minSeq
+}
@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// handleScheduleMessage 处理创建定时消息
+func (h *MessageHandler) handleScheduleMessage(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.ScheduleMessageRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.ScheduleMessageResponse{}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SCHEDULE_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.Message == nil {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "message is required"
+		return h.sendResponse(conn, protocol.CMD_SCHEDULE_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	msgInfo := req.Message
+	sm := &model.ScheduledMessage{
+		SenderID:       userID,
+		ReceiverID:     msgInfo.ReceiverId,
+		GroupID:        msgInfo.GroupId,
+		ClientMsgID:    msgInfo.ClientMsgId,
+		MessageType:    int(msgInfo.MessageType),
+		Content:        string(msgInfo.Content),
+		ReplyToSeq:     msgInfo.ReplyToSeq,
+		ThreadRootSeq:  msgInfo.ThreadRootSeq,
+		MentionUserIDs: strings.Join(msgInfo.MentionUserIds, ","),
+		MentionAll:     msgInfo.MentionAll,
+		ScheduledAt:    req.ScheduledTime,
+	}
+	if err := h.scheduledService.CreateScheduledMessage(context.Background(), sm); err != nil {
+		resp.ErrorCode, resp.ErrorMsg = scheduleError(err)
+		return h.sendResponse(conn, protocol.CMD_SCHEDULE_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.ScheduledMessage = toScheduledMessageInfo(sm)
+
+	logger.Info("Scheduled message created",
+		zap.String("id", sm.ID),
+		zap.String("user_id", userID),
+		zap.String("conversation_id", sm.ConversationID),
+		zap.Int64("scheduled_at", sm.ScheduledAt))
+
+	return h.sendResponse(conn, protocol.CMD_SCHEDULE_MSG_RSP, wsMsg.Sequence, resp)
+}
+
+// handleScheduledList 处理获取待发送定时消息列表
+func (h *MessageHandler) handleScheduledList(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.ScheduledListRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.ScheduledListResponse{
+		RequestId: req.RequestId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SCHEDULED_LIST_RSP, wsMsg.Sequence, resp)
+	}
+
+	list, err := h.scheduledService.ListPendingScheduledMessages(userID, req.ConversationId)
+	if err != nil {
+		resp.ErrorCode, resp.ErrorMsg = scheduleError(err)
+		return h.sendResponse(conn, protocol.CMD_SCHEDULED_LIST_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	for _, sm := range list {
+		resp.ScheduledMessages = append(resp.ScheduledMessages, toScheduledMessageInfo(sm))
+	}
+
+	return h.sendResponse(conn, protocol.CMD_SCHEDULED_LIST_RSP, wsMsg.Sequence, resp)
+}
+
+// handleEditScheduled 处理修改定时消息
+func (h *MessageHandler) handleEditScheduled(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.EditScheduledRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.ScheduleMessageResponse{}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SCHEDULED_EDIT_RSP, wsMsg.Sequence, resp)
+	}
+
+	sm, err := h.scheduledService.EditScheduledMessage(req.ScheduleId, userID, string(req.Content), req.ScheduledTime)
+	if err != nil {
+		resp.ErrorCode, resp.ErrorMsg = scheduleError(err)
+		return h.sendResponse(conn, protocol.CMD_SCHEDULED_EDIT_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.ScheduledMessage = toScheduledMessageInfo(sm)
+
+	return h.sendResponse(conn, protocol.CMD_SCHEDULED_EDIT_RSP, wsMsg.Sequence, resp)
+}
+
+// handleCancelScheduled 处理取消定时消息
+func (h *MessageHandler) handleCancelScheduled(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.CancelScheduledRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.CancelScheduledResponse{
+		ScheduleId: req.ScheduleId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SCHEDULED_CANCEL_RSP, wsMsg.Sequence, resp)
+	}
+
+	if err := h.scheduledService.CancelScheduledMessage(req.ScheduleId, userID); err != nil {
+		resp.ErrorCode, resp.ErrorMsg = scheduleError(err)
+		return h.sendResponse(conn, protocol.CMD_SCHEDULED_CANCEL_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+
+	logger.Info("Scheduled message cancelled", zap.String("id", req.ScheduleId), zap.String("user_id", userID))
+
+	return h.sendResponse(conn, protocol.CMD_SCHEDULED_CANCEL_RSP, wsMsg.Sequence, resp)
+}
+
+// ReleaseScheduledMessage 投递到期的定时消息（与发送消息走同样的 SaveMessage → 推送流程，由调度器调用）
+func (h *MessageHandler) ReleaseScheduledMessage(sm *model.ScheduledMessage) (*model.Message, error) {
+	// 幂等：上次投递已保存消息但没来得及标记发送成功（如节点崩溃），消息可能还没有推送，
+	// 按定时消息ID找到已保存的消息后重新推送（重复的推送由客户端按 server_msg_id 去重）
+	msg, err := h.msgService.GetScheduledDelivery(sm.ConversationID, sm.ClientMsgID, sm.ID)
+	if err == nil {
+		h.pushScheduledMessage(msg)
+		return msg, nil
+	}
+	if err != service.ErrMessageNotFound {
+		return nil, err
+	}
+
+	// 群聊按投递时的群成员校验（发送者已退出或被移出群组时不再投递）
+	if sm.GroupID != "" {
+		isMember, err := h.groupService.IsGroupMember(context.Background(), sm.GroupID, sm.SenderID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, service.ErrNotGroupMember
+		}
+	}
+
+	now := utils.GetCurrentMillis()
+	msg = &model.Message{
+		ScheduleID:     sm.ID,
+		ClientMsgID:    sm.ClientMsgID,
+		ConversationID: sm.ConversationID,
+		SenderID:       sm.SenderID,
+		ReceiverID:     sm.ReceiverID,
+		GroupID:        sm.GroupID,
+		MessageType:    sm.MessageType,
+		Content:        sm.Content,
+		SendTime:       now,
+		ServerTime:     now,
+		Status:         1,
+		ReplyToSeq:     sm.ReplyToSeq,
+		ThreadRootSeq:  sm.ThreadRootSeq,
+	}
+
+	if err := h.msgService.ResolveReplyAndThread(msg); err != nil {
+		return nil, err
+	}
+	mentionTargets, err := h.mentionService.ResolveMentions(context.Background(), msg, service.ParseMentionUserIDs(sm.MentionUserIDs), sm.MentionAll)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.ephemeralService.ApplyExpiry(msg, 0, 0); err != nil {
+		return nil, err
+	}
+
+	// 内容审核（按投递时的敏感词表和群组设置）
+	moderation, ok := h.moderateMessage(msg)
+	if !ok {
+		return nil, service.ErrContentRejected
+	}
+
+	if err := h.msgService.SaveMessage(msg); err != nil {
+		return nil, err
+	}
+	if err := h.mentionService.RecordMentions(msg, mentionTargets); err != nil {
+		logger.Error("Failed to record mentions", zap.Error(err), zap.String("server_msg_id", msg.ServerMsgID))
+	}
+	h.moderationService.Audit(msg, moderation)
+	h.indexMessage(msg)
+
+	h.pushScheduledMessage(msg)
+	return msg, nil
+}
+
+// pushScheduledMessage 推送定时投递的消息给接收者，同时推送给发送者自己的设备（发送者本地只有定时消息，没有正式消息）
+func (h *MessageHandler) pushScheduledMessage(msg *model.Message) {
+	h.pushNewMessage(msg)
+	if body, err := protocol.Marshal(&protocol.PushMessage{Message: toMessageInfo(msg)}); err == nil {
+		h.pushToUser(msg.SenderID, protocol.CMD_PUSH_MSG, body)
+	}
+}
+
+// toScheduledMessageInfo 转换定时消息为协议结构
+func toScheduledMessageInfo(sm *model.ScheduledMessage) *protocol.ScheduledMessageInfo {
+	conversationType := int32(1)
+	if sm.GroupID != "" {
+		conversationType = 2
+	}
+
+	return &protocol.ScheduledMessageInfo{
+		ScheduleId: sm.ID,
+		Message: &protocol.MessageInfo{
+			ServerMsgId:      sm.ServerMsgID,
+			ClientMsgId:      sm.ClientMsgID,
+			ConversationId:   sm.ConversationID,
+			ConversationType: conversationType,
+			SenderId:         sm.SenderID,
+			ReceiverId:       sm.ReceiverID,
+			GroupId:          sm.GroupID,
+			Seq:              sm.Seq,
+			MessageType:      int32(sm.MessageType),
+			Content:          []byte(sm.Content),
+			ReplyToSeq:       sm.ReplyToSeq,
+			ThreadRootSeq:    sm.ThreadRootSeq,
+			MentionUserIds:   service.ParseMentionUserIDs(sm.MentionUserIDs),
+			MentionAll:       sm.MentionAll,
+		},
+		ScheduledTime: sm.ScheduledAt,
+		Status:        int32(sm.Status),
+		ErrorMsg:      sm.LastError,
+		CreateTime:    sm.CreatedAt.UnixMilli(),
+		UpdateTime:    sm.UpdatedAt.UnixMilli(),
+	}
+}
+
+// scheduleError 定时消息错误转换为协议错误码
+func scheduleError(err error) (protocol.ErrorCode, string) {
+	if code, ok := contentErrorCode(err); ok {
+		return code, err.Error()
+	}
+
+	switch err {
+	case service.ErrInvalidScheduleTime, service.ErrNoScheduleTarget, service.ErrTooManyScheduled:
+		return protocol.ERR_INVALID_PARAM, err.Error()
+	case service.ErrScheduleNotFound:
+		return protocol.ERR_MESSAGE_NOT_EXIST, err.Error()
+	case service.ErrScheduleNotPending:
+		return protocol.ERR_SCHEDULE_NOT_PENDING, err.Error()
+	case service.ErrNotGroupMember, service.ErrMentionNotMember:
+		return protocol.ERR_NOT_GROUP_MEMBER, err.Error()
+	case service.ErrMentionAllDenied:
+		return protocol.ERR_PERMISSION_DENIED, err.Error()
+	default:
+		logger.Error("Scheduled message operation failed", zap.Error(err))
+		return protocol.ERR_UNKNOWN, "Internal error"
+	}
+}
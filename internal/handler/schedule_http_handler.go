@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/arwen/im-server/internal/middleware"
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+)
+
+// ScheduleHandler 定时消息 HTTP 处理器
+type ScheduleHandler struct {
+	scheduledService *service.ScheduledMessageService
+	jwtSecret        string
+}
+
+// NewScheduleHandler 创建定时消息 HTTP 处理器
+func NewScheduleHandler(scheduledService *service.ScheduledMessageService, jwtSecret string) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduledService: scheduledService,
+		jwtSecret:        jwtSecret,
+	}
+}
+
+// ScheduledMessageDTO 定时消息数据传输对象
+type ScheduledMessageDTO struct {
+	ScheduleID     string   `json:"scheduleID"`
+	ConversationID string   `json:"conversationID"`
+	ReceiverID     string   `json:"receiverID,omitempty"`
+	GroupID        string   `json:"groupID,omitempty"`
+	ClientMsgID    string   `json:"clientMsgID"`
+	MessageType    int      `json:"messageType"`
+	Content        string   `json:"content"`
+	ReplyToSeq     int64    `json:"replyToSeq,omitempty"`
+	ThreadRootSeq  int64    `json:"threadRootSeq,omitempty"`
+	MentionUserIDs []string `json:"mentionUserIDs,omitempty"`
+	MentionAll     bool     `json:"mentionAll,omitempty"`
+	ScheduledTime  int64    `json:"scheduledTime"`
+	Status         int      `json:"status"`
+	ServerMsgID    string   `json:"serverMsgID,omitempty"`
+	Seq            int64    `json:"seq,omitempty"`
+	ErrorMsg       string   `json:"errorMsg,omitempty"`
+	CreateTime     int64    `json:"createTime"`
+	UpdateTime     int64    `json:"updateTime"`
+}
+
+// toScheduledMessageDTO 将 model.ScheduledMessage 转换为 ScheduledMessageDTO
+func toScheduledMessageDTO(sm *model.ScheduledMessage) *ScheduledMessageDTO {
+	return &ScheduledMessageDTO{
+		ScheduleID:     sm.ID,
+		ConversationID: sm.ConversationID,
+		ReceiverID:     sm.ReceiverID,
+		GroupID:        sm.GroupID,
+		ClientMsgID:    sm.ClientMsgID,
+		MessageType:    sm.MessageType,
+		Content:        sm.Content,
+		ReplyToSeq:     sm.ReplyToSeq,
+		ThreadRootSeq:  sm.ThreadRootSeq,
+		MentionUserIDs: service.ParseMentionUserIDs(sm.MentionUserIDs),
+		MentionAll:     sm.MentionAll,
+		ScheduledTime:  sm.ScheduledAt,
+		Status:         sm.Status,
+		ServerMsgID:    sm.ServerMsgID,
+		Seq:            sm.Seq,
+		ErrorMsg:       sm.LastError,
+		CreateTime:     sm.CreatedAt.UnixMilli(),
+		UpdateTime:     sm.UpdatedAt.UnixMilli(),
+	}
+}
+
+// CreateScheduledRequest 创建定时消息请求
+type CreateScheduledRequest struct {
+	ReceiverID     string   `json:"receiverID"`
+	GroupID        string   `json:"groupID"`
+	ClientMsgID    string   `json:"clientMsgID"`
+	MessageType    int      `json:"messageType"`
+	Content        string   `json:"content"`
+	ReplyToSeq     int64    `json:"replyToSeq"`
+	ThreadRootSeq  int64    `json:"threadRootSeq"`
+	MentionUserIDs []string `json:"mentionUserIDs"`
+	MentionAll     bool     `json:"mentionAll"`
+	ScheduledTime  int64    `json:"scheduledTime"`
+}
+
+// EditScheduledRequest 修改定时消息请求
+type EditScheduledRequest struct {
+	Content       string `json:"content"`
+	ScheduledTime int64  `json:"scheduledTime"`
+}
+
+// RegisterRoutes 注册路由
+func (h *ScheduleHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/api/message/schedule", middleware.AuthMiddleware(h.jwtSecret, h.HandleSchedule))
+	mux.HandleFunc("/api/message/schedule/", middleware.AuthMiddleware(h.jwtSecret, h.HandleSchedule))
+}
+
+// HandleSchedule 处理定时消息相关请求（路由分发）
+//   - POST /api/message/schedule                 创建
+//   - GET  /api/message/schedule/list            待发送列表（可选 ?conversationID=）
+//   - POST /api/message/schedule/{id}/edit       修改
+//   - POST /api/message/schedule/{id}/cancel     取消
+func (h *ScheduleHandler) HandleSchedule(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/message/schedule"), "/")
+	parts := strings.Split(path, "/")
+
+	switch {
+	case path == "" && r.Method == "POST":
+		h.CreateScheduled(w, r)
+	case path == "list" && r.Method == "GET":
+		h.ListScheduled(w, r)
+	case len(parts) == 2 && parts[1] == "edit" && r.Method == "POST":
+		h.EditScheduled(w, r, parts[0])
+	case len(parts) == 2 && parts[1] == "cancel" && r.Method == "POST":
+		h.CancelScheduled(w, r, parts[0])
+	default:
+		h.writeError(w, http.StatusNotFound, "Route not found")
+	}
+}
+
+// CreateScheduled 创建定时消息
+func (h *ScheduleHandler) CreateScheduled(w http.ResponseWriter, r *http.Request) {
+	var req CreateScheduledRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	sm := &model.ScheduledMessage{
+		SenderID:       r.Header.Get("X-User-ID"),
+		ReceiverID:     req.ReceiverID,
+		GroupID:        req.GroupID,
+		ClientMsgID:    req.ClientMsgID,
+		MessageType:    req.MessageType,
+		Content:        req.Content,
+		ReplyToSeq:     req.ReplyToSeq,
+		ThreadRootSeq:  req.ThreadRootSeq,
+		MentionUserIDs: strings.Join(req.MentionUserIDs, ","),
+		MentionAll:     req.MentionAll,
+		ScheduledAt:    req.ScheduledTime,
+	}
+	if err := h.scheduledService.CreateScheduledMessage(r.Context(), sm); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    toScheduledMessageDTO(sm),
+	})
+}
+
+// ListScheduled 获取待发送的定时消息
+func (h *ScheduleHandler) ListScheduled(w http.ResponseWriter, r *http.Request) {
+	list, err := h.scheduledService.ListPendingScheduledMessages(r.Header.Get("X-User-ID"), r.URL.Query().Get("conversationID"))
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	dtos := make([]*ScheduledMessageDTO, 0, len(list))
+	for _, sm := range list {
+		dtos = append(dtos, toScheduledMessageDTO(sm))
+	}
+
+	h.writeJSON(w, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    dtos,
+	})
+}
+
+// EditScheduled 修改定时消息
+func (h *ScheduleHandler) EditScheduled(w http.ResponseWriter, r *http.Request, scheduleID string) {
+	var req EditScheduledRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	sm, err := h.scheduledService.EditScheduledMessage(scheduleID, r.Header.Get("X-User-ID"), req.Content, req.ScheduledTime)
+	if err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+		Data:    toScheduledMessageDTO(sm),
+	})
+}
+
+// CancelScheduled 取消定时消息
+func (h *ScheduleHandler) CancelScheduled(w http.ResponseWriter, r *http.Request, scheduleID string) {
+	if err := h.scheduledService.CancelScheduledMessage(scheduleID, r.Header.Get("X-User-ID")); err != nil {
+		h.writeServiceError(w, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, Response{
+		Code:    0,
+		Message: "success",
+	})
+}
+
+// writeServiceError 将定时消息服务错误转换为 HTTP 状态码
+func (h *ScheduleHandler) writeServiceError(w http.ResponseWriter, err error) {
+	code, msg := scheduleError(err)
+	switch code {
+	case protocol.ERR_INVALID_PARAM:
+		h.writeError(w, http.StatusBadRequest, msg)
+	case protocol.ERR_MESSAGE_TOO_LARGE:
+		h.writeError(w, http.StatusRequestEntityTooLarge, msg)
+	case protocol.ERR_MESSAGE_NOT_EXIST:
+		h.writeError(w, http.StatusNotFound, msg)
+	case protocol.ERR_SCHEDULE_NOT_PENDING:
+		h.writeError(w, http.StatusConflict, msg)
+	case protocol.ERR_NOT_GROUP_MEMBER, protocol.ERR_PERMISSION_DENIED:
+		h.writeError(w, http.StatusForbidden, msg)
+	default:
+		h.writeError(w, http.StatusInternalServerError, msg)
+	}
+}
+
+func (h *ScheduleHandler) writeJSON(w http.ResponseWriter, statusCode int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *ScheduleHandler) writeError(w http.ResponseWriter, statusCode int, message string) {
+	h.writeJSON(w, statusCode, Response{
+		Code:    statusCode,
+		Message: message,
+	})
+}
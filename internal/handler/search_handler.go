@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// handleSearchMessages 处理搜索消息
+func (h *MessageHandler) handleSearchMessages(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.SearchMessageRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.SearchMessageResponse{
+		RequestId: req.RequestId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_SEARCH_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	query := &service.SearchQuery{
+		SenderID:    req.SenderId,
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		BeforeTime:  req.BeforeTime,
+		BeforeMsgID: req.BeforeMsgId,
+		Limit:       int(req.Limit),
+	}
+	for _, t := range req.MessageTypes {
+		query.MessageTypes = append(query.MessageTypes, int(t))
+	}
+
+	if req.ConversationId != "" {
+		if !h.isConversationParticipant(req.ConversationId, userID) {
+			resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+			resp.ErrorMsg = "Not a participant of this conversation"
+			return h.sendResponse(conn, protocol.CMD_SEARCH_MSG_RSP, wsMsg.Sequence, resp)
+		}
+		query.ConversationIDs = []string{req.ConversationId}
+	}
+
+	result, err := h.searchService.SearchMessages(context.Background(), userID, req.Keyword, query)
+	if err != nil {
+		if err == service.ErrInvalidKeyword {
+			resp.ErrorCode = protocol.ERR_INVALID_PARAM
+			resp.ErrorMsg = err.Error()
+		} else {
+			logger.Error("Failed to search messages", zap.Error(err))
+			resp.ErrorCode = protocol.ERR_UNKNOWN
+			resp.ErrorMsg = "Failed to search messages"
+		}
+		return h.sendResponse(conn, protocol.CMD_SEARCH_MSG_RSP, wsMsg.Sequence, resp)
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.Messages = h.toMixedMessageInfoList(result.Messages, userID)
+	resp.HasMore = result.HasMore
+	resp.NextBeforeTime = result.NextBeforeTime
+	resp.NextBeforeMsgId = result.NextBeforeMsgID
+
+	logger.Debug("Search messages response",
+		zap.String("user_id", userID),
+		zap.String("conversation_id", req.ConversationId),
+		zap.Int("count", len(result.Messages)),
+		zap.Bool("has_more", result.HasMore))
+
+	return h.sendResponse(conn, protocol.CMD_SEARCH_MSG_RSP, wsMsg.Sequence, resp)
+}
+
+// indexMessage 将新消息或编辑后的消息写入搜索索引（失败只记录日志，不影响消息发送）
+func (h *MessageHandler) indexMessage(msg *model.Message) {
+	if err := h.searchService.IndexMessage(msg); err != nil {
+		logger.Warn("Failed to index message", zap.Error(err), zap.String("server_msg_id", msg.ServerMsgID))
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// handleThreadReplies 处理分页拉取话题回复
+func (h *MessageHandler) handleThreadReplies(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.ThreadRepliesRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	resp := &protocol.ThreadRepliesResponse{
+		RequestId:      req.RequestId,
+		ConversationId: req.ConversationId,
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" {
+		resp.ErrorCode = protocol.ERR_AUTH_FAILED
+		resp.ErrorMsg = "Not authenticated"
+		return h.sendResponse(conn, protocol.CMD_THREAD_REPLIES_RSP, wsMsg.Sequence, resp)
+	}
+
+	if req.ConversationId == "" || req.RootSeq <= 0 {
+		resp.ErrorCode = protocol.ERR_INVALID_PARAM
+		resp.ErrorMsg = "conversation_id and root_seq are required"
+		return h.sendResponse(conn, protocol.CMD_THREAD_REPLIES_RSP, wsMsg.Sequence, resp)
+	}
+
+	// 查询话题根消息（用户已删除或已清空的根消息视为不存在），并检查当前用户是否是会话参与者
+	root, err := h.msgService.GetVisibleMessageBySeq(userID, req.ConversationId, req.RootSeq)
+	if err != nil || root.Status == 4 || root.Status == model.MessageStatusExpired {
+		resp.ErrorCode = protocol.ERR_MESSAGE_NOT_EXIST
+		resp.ErrorMsg = "Thread root message not found"
+		return h.sendResponse(conn, protocol.CMD_THREAD_REPLIES_RSP, wsMsg.Sequence, resp)
+	}
+	if !h.isMessageParticipant(root, userID) {
+		resp.ErrorCode = protocol.ERR_PERMISSION_DENIED
+		resp.ErrorMsg = "Not a participant of this conversation"
+		return h.sendResponse(conn, protocol.CMD_THREAD_REPLIES_RSP, wsMsg.Sequence, resp)
+	}
+
+	replies, hasMore, err := h.msgService.GetThreadReplies(userID, req.ConversationId, req.RootSeq, req.AfterSeq, int(req.Count))
+	if err != nil {
+		logger.Error("Failed to get thread replies", zap.Error(err))
+		resp.ErrorCode = protocol.ERR_UNKNOWN
+		resp.ErrorMsg = "Failed to get thread replies"
+		return h.sendResponse(conn, protocol.CMD_THREAD_REPLIES_RSP, wsMsg.Sequence, resp)
+	}
+
+	nextAfterSeq := req.AfterSeq
+	if len(replies) > 0 {
+		nextAfterSeq = replies[len(replies)-1].Seq
+	}
+
+	resp.ErrorCode = protocol.ERR_SUCCESS
+	resp.ErrorMsg = "Success"
+	resp.RootMessage = toMessageInfo(root)
+	resp.Replies = h.toMessageInfoList(replies, userID)
+	resp.HasMore = hasMore
+	resp.NextAfterSeq = nextAfterSeq
+
+	logger.Debug("Thread replies response",
+		zap.String("user_id", userID),
+		zap.String("conversation_id", req.ConversationId),
+		zap.Int64("root_seq", req.RootSeq),
+		zap.Int("count", len(replies)),
+		zap.Bool("has_more", hasMore))
+
+	return h.sendResponse(conn, protocol.CMD_THREAD_REPLIES_RSP, wsMsg.Sequence, resp)
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/transport"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// 输入状态
+const (
+	typingStatusStopped = 0 // 停止输入
+	typingStatusTyping  = 1 // 正在输入
+)
+
+// handleTypingStatus 处理输入状态：转发给会话中的其他在线参与者（单聊的对方、群聊的在线成员）
+// "正在输入"按会话人数节流；客户端没有发送停止时由服务端超时结束（见 NotifyTypingExpired）
+func (h *MessageHandler) handleTypingStatus(conn transport.Connection, wsMsg *protocol.WebSocketMessage) error {
+	var req protocol.TypingStatusRequest
+	if err := protocol.Unmarshal(wsMsg.Body, &req); err != nil {
+		return err
+	}
+
+	userID := conn.GetUserID()
+	if userID == "" || req.ConversationId == "" {
+		return nil
+	}
+
+	// 先节流再查询接收者（频繁发送输入状态不会导致频繁查询）
+	if req.Status == typingStatusTyping {
+		if !h.typingService.Typing(req.ConversationId, userID) {
+			return nil
+		}
+	} else if !h.typingService.Clear(req.ConversationId, userID) {
+		// 不在输入状态（已超时或已发送消息），不需要再推送停止
+		return nil
+	}
+
+	recipients, ok := h.typingRecipients(req.ConversationId, userID)
+	if !ok {
+		if req.Status == typingStatusTyping {
+			h.typingService.Reject(req.ConversationId, userID)
+		}
+		return nil
+	}
+	if req.Status == typingStatusTyping {
+		h.typingService.SetRecipients(req.ConversationId, userID, len(recipients))
+	}
+
+	h.pushTypingStatus(req.ConversationId, userID, req.Status, recipients)
+	return nil
+}
+
+// NotifyTypingExpired 输入状态超时：推送停止输入（由输入状态服务的超时检查任务调用）
+func (h *MessageHandler) NotifyTypingExpired(conversationID, userID string) {
+	recipients, ok := h.typingRecipients(conversationID, userID)
+	if !ok {
+		return
+	}
+	h.pushTypingStatus(conversationID, userID, typingStatusStopped, recipients)
+}
+
+// typingRecipients 获取输入状态的在线接收者（不包括自己），userID 不是会话参与者时返回 false
+func (h *MessageHandler) typingRecipients(conversationID, userID string) ([]string, bool) {
+	if !h.isConversationParticipant(conversationID, userID) {
+		return nil, false
+	}
+
+	participants, err := h.getConversationParticipants(conversationID, userID)
+	if err != nil {
+		logger.Error("Failed to get conversation participants", zap.Error(err), zap.String("conversation_id", conversationID))
+		return nil, false
+	}
+
+	recipients := make([]string, 0, len(participants))
+	for _, id := range participants {
+		if id == userID {
+			continue
+		}
+		if _, online := h.connManager.GetUserConnection(id); online {
+			recipients = append(recipients, id)
+		}
+	}
+	return recipients, true
+}
+
+// pushTypingStatus 推送输入状态（跳过拉黑了对方的用户和对会话设置了免打扰的用户）
+func (h *MessageHandler) pushTypingStatus(conversationID, userID string, status int32, recipients []string) {
+	if len(recipients) == 0 {
+		return
+	}
+
+	blockers := make(map[string]bool)
+	if convType, peerID := h.conversationTarget(conversationID, userID); convType == model.ConversationTypeSingle {
+		// 单聊：任意一方拉黑了对方都不推送
+		blocked, err := h.friendService.IsBlockedEither(userID, peerID)
+		if err != nil {
+			logger.Error("Failed to check block relation", zap.Error(err), zap.String("user_id", userID))
+			return
+		}
+		if blocked {
+			return
+		}
+	} else {
+		// 群聊：不推送给拉黑了输入者的成员
+		var err error
+		if blockers, err = h.friendService.GetBlockers(userID, recipients); err != nil {
+			logger.Error("Failed to get blockers", zap.Error(err), zap.String("user_id", userID))
+			return
+		}
+	}
+	muted, err := h.unreadService.GetMutedUsers(conversationID, recipients)
+	if err != nil {
+		logger.Warn("Failed to get muted users", zap.Error(err), zap.String("conversation_id", conversationID))
+	}
+
+	push := &protocol.TypingStatusPush{
+		ConversationId: conversationID,
+		UserId:         userID,
+		Status:         status,
+	}
+	body, err := protocol.Marshal(push)
+	if err != nil {
+		logger.Error("Failed to marshal typing status push", zap.Error(err))
+		return
+	}
+
+	for _, id := range recipients {
+		if blockers[id] || muted[id] {
+			continue
+		}
+		h.pushToUser(id, protocol.CMD_TYPING_STATUS_PUSH, body)
+	}
+}
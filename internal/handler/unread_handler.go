@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/protocol"
+	"github.com/arwen/im-server/internal/service"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// refreshUnread 按已读游标重新计算用户在会话中的未读数并推送（已读、删除消息、清空历史后调用）
+func (h *MessageHandler) refreshUnread(userID, conversationID string) {
+	state, err := h.unreadService.Recompute(userID, conversationID)
+	if err != nil {
+		logger.Error("Failed to recompute unread count",
+			zap.Error(err),
+			zap.String("user_id", userID),
+			zap.String("conversation_id", conversationID))
+		return
+	}
+	h.applyUnreadChange(state)
+}
+
+// refreshUnreadAfterRemoval 消息被撤回或过期后，重新计算还没读到这些消息的参与者的未读数并推送
+func (h *MessageHandler) refreshUnreadAfterRemoval(msg *model.Message, seqs []int64) {
+	if len(seqs) == 0 {
+		return
+	}
+	minSeq := seqs[0]
+	for _, seq := range seqs[1:] {
+		if seq < minSeq {
+			minSeq = seq
+		}
+	}
+
+	participants, err := h.getMessageParticipants(msg)
+	if err != nil {
+		logger.Error("Failed to get message participants", zap.Error(err), zap.String("conversation_id", msg.ConversationID))
+		return
+	}
+
+	states, err := h.unreadService.RecomputeAfterRemoval(msg.ConversationID, participants, minSeq)
+	if err != nil {
+		logger.Error("Failed to recompute unread counts", zap.Error(err), zap.String("conversation_id", msg.ConversationID))
+		return
+	}
+	for _, state := range states {
+		h.applyUnreadChange(state)
+	}
+}
+
+// applyUnreadChange 把重新计算的未读数写入用户的会话列表，并推送给用户自己的设备
+func (h *MessageHandler) applyUnreadChange(state *service.UnreadState) {
+	if err := h.convService.UpdateUnreadCount(state.UserID, state.ConversationID, state.UnreadCount); err != nil {
+		logger.Error("Failed to update conversation unread count",
+			zap.Error(err),
+			zap.String("user_id", state.UserID),
+			zap.String("conversation_id", state.ConversationID))
+	}
+	h.pushUnreadCount(state)
+}
+
+// pushUnreadCount 推送未读数变化给用户自己的设备
+func (h *MessageHandler) pushUnreadCount(state *service.UnreadState) {
+	if _, online := h.connManager.GetUserConnection(state.UserID); !online {
+		return
+	}
+
+	push := &protocol.UnreadCountPush{
+		ConversationId: state.ConversationID,
+		UnreadCount:    int32(state.UnreadCount),
+		TotalUnread:    int32(state.TotalUnread),
+	}
+	body, err := protocol.Marshal(push)
+	if err != nil {
+		logger.Error("Failed to marshal unread count push", zap.Error(err))
+		return
+	}
+	h.pushToUser(state.UserID, protocol.CMD_UNREAD_COUNT_PUSH, body)
+}
@@ -4,17 +4,43 @@ import (
 	"time"
 )
 
-// Conversation 会话模型
+// 会话类型
+const (
+	ConversationTypeSingle   = 1 // 单聊
+	ConversationTypeGroup    = 2 // 群聊
+	ConversationTypeChatroom = 3 // 聊天室（消息不写入消息表，不进入会话列表）
+)
+
+// 会话状态
+const (
+	ConversationStatusNormal = 1 // 正常
+	ConversationStatusHidden = 2 // 已隐藏（有新消息时重新出现）
+)
+
+// MuteForever 永久免打扰
+const MuteForever = -1
+
+// Conversation 用户的会话列表项（每个用户在每个会话中一行，发送消息时由服务端维护）
 type Conversation struct {
 	ID             string    `gorm:"primaryKey;size:64" json:"id"`
 	Type           int       `gorm:"not null" json:"type"` // 1: 单聊, 2: 群聊
-	UserID         string    `gorm:"index;size:64" json:"user_id"`
-	TargetID       string    `gorm:"index;size:64" json:"target_id"` // 对方用户ID或群组ID
-	LastMessageID  string    `gorm:"size:64" json:"last_message_id"`
-	LastMessage    string    `gorm:"size:512" json:"last_message"`
+	UserID         string    `gorm:"uniqueIndex:idx_user_conversation,priority:1;index:idx_user_conversation_version,priority:1;size:64" json:"user_id"`
+	ConversationID string    `gorm:"uniqueIndex:idx_user_conversation,priority:2;size:64" json:"conversation_id"` // single_{a}_{b} 或 group_{groupID}
+	TargetID       string    `gorm:"index;size:64" json:"target_id"`                                              // 对方用户ID或群组ID
+	LastMessageID  string    `gorm:"size:64" json:"last_message_id"`                                              // 最后一条消息的 server_msg_id
+	LastMessageSeq int64     `json:"last_message_seq"`
+	LastSenderID   string    `gorm:"size:64" json:"last_sender_id"`
+	LastMessage    string    `gorm:"size:512" json:"last_message"` // 最后一条消息的内容预览
 	LastMessageAt  int64     `json:"last_message_at"`
 	UnreadCount    int       `gorm:"default:0" json:"unread_count"`
-	Status         int       `gorm:"default:1" json:"status"` // 1: 正常, 2: 已删除
+	Status         int       `gorm:"default:1" json:"status"`                                                 // 1: 正常, 2: 已隐藏
+	MuteUntil      int64     `gorm:"default:0" json:"mute_until"`                                             // 免打扰（0: 关闭, -1: 永久, >0: 免打扰到该时间，毫秒）
+	PinOrder       int64     `gorm:"default:0" json:"pin_order"`                                              // 置顶顺序（0: 不置顶，越大越靠前）
+	Extra          string    `gorm:"size:1024" json:"extra"`                                                  // 自定义字段（如聊天背景、备注标签）
+	DraftText      string    `gorm:"type:text" json:"draft_text"`                                             // 草稿内容
+	DraftReplyTo   string    `gorm:"size:64" json:"draft_reply_to"`                                           // 草稿引用回复的消息 server_msg_id
+	DraftTime      int64     `gorm:"default:0" json:"draft_time"`                                             // 客户端最后一次修改草稿的时间（毫秒，多端以最新的为准）
+	Version        int64     `gorm:"index:idx_user_conversation_version,priority:2;default:0" json:"version"` // 每次变化时递增（每个用户的会话共用一个递增序列）
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
@@ -24,3 +50,18 @@ func (Conversation) TableName() string {
 	return "conversations"
 }
 
+// IsMuted 会话在 now（毫秒）时是否处于免打扰
+func (c *Conversation) IsMuted(now int64) bool {
+	return c.MuteUntil == MuteForever || c.MuteUntil > now
+}
+
+// ConversationVersion 用户的会话列表版本号序列（每个用户一行；在修改该用户会话的事务中递增并锁定到提交）
+type ConversationVersion struct {
+	UserID  string `gorm:"primaryKey;size:64" json:"user_id"`
+	Version int64  `gorm:"not null;default:0" json:"version"`
+}
+
+// TableName 表名
+func (ConversationVersion) TableName() string {
+	return "conversation_versions"
+}
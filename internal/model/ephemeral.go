@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+)
+
+// MessageStatusExpired 阅后即焚消息已过期（内容已删除）
+const MessageStatusExpired = 5
+
+// 阅后即焚计时方式
+const (
+	ExpireModeNone      = 0 // 不过期
+	ExpireModeAfterSend = 1 // 从发送时开始计时
+	ExpireModeAfterRead = 2 // 从第一次被读取时开始计时（发送者本人读取不计）
+)
+
+// ConversationEphemeralSetting 会话的默认阅后即焚设置（对之后发送的、未单独指定时长的消息生效）
+type ConversationEphemeralSetting struct {
+	ConversationID string    `gorm:"primaryKey;size:64" json:"conversation_id"`
+	ExpireTTL      int       `gorm:"default:0" json:"expire_ttl"` // 默认时长（秒，0 表示关闭）
+	ExpireMode     int       `gorm:"default:0" json:"expire_mode"`
+	UpdatedBy      string    `gorm:"size:64" json:"updated_by"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (ConversationEphemeralSetting) TableName() string {
+	return "conversation_ephemeral_settings"
+}
@@ -4,6 +4,13 @@ import (
 	"time"
 )
 
+// 好友关系状态
+const (
+	FriendStatusNormal  = 1 // 正常
+	FriendStatusDeleted = 2 // 已删除
+	FriendStatusBlocked = 3 // 已拉黑
+)
+
 // Friend 好友关系模型
 type Friend struct {
 	ID        string    `gorm:"primaryKey;size:64" json:"id"`
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"time"
+)
+
+// 分片上传状态
+const (
+	MediaUploadStatusUploading = 0 // 上传中
+	MediaUploadStatusCompleted = 1 // 已完成
+)
+
+// MediaFile 已上传的媒体文件（同一内容只存储一份，按 SHA-256 去重）
+type MediaFile struct {
+	ID           string    `gorm:"primaryKey;size:64" json:"id"`
+	Hash         string    `gorm:"uniqueIndex;size:64;not null" json:"hash"` // 内容 SHA-256（十六进制）
+	Size         int64     `json:"size"`
+	MimeType     string    `gorm:"size:128" json:"mime_type"`
+	StorageKey   string    `gorm:"size:255;not null" json:"storage_key"`
+	ThumbnailKey string    `gorm:"size:255" json:"thumbnail_key"` // 缩略图（仅图片）
+	Width        int       `json:"width"`                         // 图片宽高（仅图片）
+	Height       int       `json:"height"`
+	UploaderID   string    `gorm:"index;size:64" json:"uploader_id"` // 第一次上传该内容的用户
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (MediaFile) TableName() string {
+	return "media_files"
+}
+
+// MediaUpload 分片上传任务（未在过期时间前完成的任务由后台任务清理）
+type MediaUpload struct {
+	ID         string    `gorm:"primaryKey;size:64" json:"id"`
+	UserID     string    `gorm:"index;size:64;not null" json:"user_id"`
+	FileName   string    `gorm:"size:255" json:"file_name"`
+	Size       int64     `json:"size"`                      // 文件总大小
+	MimeType   string    `gorm:"size:128" json:"mime_type"` // 客户端声明的类型
+	Hash       string    `gorm:"size:64" json:"hash"`       // 客户端声明的 SHA-256（可选，完成时校验）
+	ChunkSize  int64     `json:"chunk_size"`
+	ChunkCount int       `json:"chunk_count"`
+	Status     int       `gorm:"default:0" json:"status"`
+	FileID     string    `gorm:"size:64" json:"file_id"` // 完成后对应的文件
+	ExpiresAt  time.Time `gorm:"index" json:"expires_at"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (MediaUpload) TableName() string {
+	return "media_uploads"
+}
+
+// MediaUploadChunk 已接收的分片
+type MediaUploadChunk struct {
+	UploadID   string    `gorm:"primaryKey;size:64" json:"upload_id"`
+	ChunkIndex int       `gorm:"primaryKey;autoIncrement:false" json:"chunk_index"`
+	Size       int64     `json:"size"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (MediaUploadChunk) TableName() string {
+	return "media_upload_chunks"
+}
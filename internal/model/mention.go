@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+)
+
+// UserMention 用户被 @ 的记录（用户读过该会话后清除）
+type UserMention struct {
+	ID             string    `gorm:"primaryKey;size:64" json:"id"`
+	UserID         string    `gorm:"uniqueIndex:idx_mentions_user_conv_seq,priority:1;size:64;not null" json:"user_id"`
+	ConversationID string    `gorm:"uniqueIndex:idx_mentions_user_conv_seq,priority:2;size:64;not null" json:"conversation_id"`
+	Seq            int64     `gorm:"uniqueIndex:idx_mentions_user_conv_seq,priority:3" json:"seq"`
+	ServerMsgID    string    `gorm:"size:64" json:"server_msg_id"`
+	SenderID       string    `gorm:"size:64" json:"sender_id"`
+	MentionAll     bool      `gorm:"default:false" json:"mention_all"` // 是否来自 @所有人
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (UserMention) TableName() string {
+	return "user_mentions"
+}
@@ -6,18 +6,33 @@ import (
 
 // Message 消息模型
 type Message struct {
-	ConversationID string    `gorm:"primaryKey;size:64;not null" json:"conversation_id"` // 会话ID（复合主键1）
+	ConversationID string    `gorm:"primaryKey;size:64;not null;index:idx_messages_thread,priority:1" json:"conversation_id"` // 会话ID（复合主键1）
 	Seq            int64     `gorm:"primaryKey;autoIncrement:false" json:"seq"`           // 消息序列号（复合主键2，会话内递增）
 	ServerMsgID    string    `gorm:"uniqueIndex;size:64;not null" json:"server_msg_id"`  // 服务端消息ID（服务端生成，全局唯一）
 	ClientMsgID    string    `gorm:"size:64;not null" json:"client_msg_id"`              // 客户端消息ID（会话内幂等检查）
 	SenderID       string    `gorm:"index;size:64;not null" json:"sender_id"`
 	ReceiverID     string    `gorm:"index;size:64" json:"receiver_id"`
 	GroupID        string    `gorm:"index;size:64" json:"group_id"`
-	MessageType    int       `gorm:"not null" json:"message_type"` // 1: 文本, 2: 图片, 3: 语音, 4: 视频, 5: 文件
+	MessageType    int       `gorm:"not null" json:"message_type"` // 1: 文本, 2: 图片, 3: 语音, 4: 视频, 5: 文件, 10: 合并转发
 	Content        string    `gorm:"type:text" json:"content"`
-	Status         int       `gorm:"default:1" json:"status"` // 1: 已发送, 2: 已送达, 3: 已读, 4: 已撤回
+	Status         int       `gorm:"default:1" json:"status"` // 1: 已发送, 2: 已送达, 3: 已读, 4: 已撤回, 5: 已过期
 	SendTime       int64     `json:"send_time"`
-	ServerTime     int64     `json:"server_time"`
+	ServerTime     int64     `gorm:"index" json:"server_time"`
+	EditVersion    int       `gorm:"default:0" json:"edit_version"` // 编辑版本号（0: 未编辑，每编辑一次 +1）
+	EditTime       int64     `json:"edit_time"`                     // 最后编辑时间
+	ReplyToMsgID   string    `gorm:"size:64" json:"reply_to_msg_id"` // 引用回复的消息ID（server_msg_id）
+	ReplyToSeq     int64     `json:"reply_to_seq"`                   // 引用回复的消息 seq
+	QuoteSnapshot  string    `gorm:"type:text" json:"quote_snapshot"` // 被引用消息快照（QuotedMessage JSON）
+	ThreadRootSeq  int64     `gorm:"default:0;index:idx_messages_thread,priority:2" json:"thread_root_seq"` // 所属话题根消息 seq（0: 不在话题中）
+	ReplyCount     int       `gorm:"default:0" json:"reply_count"`  // 话题回复数（仅话题根消息）
+	LastReplyTime  int64     `json:"last_reply_time"`               // 话题最后回复时间（仅话题根消息）
+	MentionUserIDs string    `gorm:"type:text" json:"mention_user_ids"` // @的用户ID列表（逗号分隔）
+	MentionAll     bool      `gorm:"default:false" json:"mention_all"`  // 是否 @所有人
+	ForwardFromID  string    `gorm:"size:64" json:"forward_from_id"`    // 转发来源消息ID（server_msg_id，逐条转发时）
+	ExpireTTL      int       `gorm:"default:0" json:"expire_ttl"`       // 阅后即焚时长（秒，0 表示不过期）
+	ExpireMode     int       `gorm:"default:0" json:"expire_mode"`      // 计时方式（见 ExpireMode 常量）
+	ExpireAt       int64     `gorm:"default:0;index" json:"expire_at"`  // 过期时间（毫秒，0 表示尚未开始计时）
+	ScheduleID     string    `gorm:"size:64" json:"schedule_id"`        // 定时消息ID（由定时消息投递时，用于投递幂等）
 	CreatedAt      time.Time `json:"created_at"`
 	UpdatedAt      time.Time `json:"updated_at"`
 }
@@ -27,11 +42,51 @@ func (Message) TableName() string {
 	return "messages"
 }
 
+// MessageTypeMergedForward 合并转发消息类型（Content 为 MergedForwardContent JSON）
+const MessageTypeMergedForward = 10
+
+// QuotedMessage 被引用消息快照（发送引用回复时由服务端填充，存储在 Message.QuoteSnapshot 中）
+type QuotedMessage struct {
+	ServerMsgID string `json:"server_msg_id"`
+	Seq         int64  `json:"seq"`
+	SenderID    string `json:"sender_id"`
+	MessageType int    `json:"message_type"`
+	Content     string `json:"content"`
+	SendTime    int64  `json:"send_time"`
+	Status      int    `json:"status"`
+}
+
+// MergedForwardContent 合并转发消息内容（被转发消息的快照，按发送时间排序）
+type MergedForwardContent struct {
+	Title    string           `json:"title"`
+	Messages []*QuotedMessage `json:"messages"`
+}
+
+// MessageEdit 消息编辑历史（每次编辑保存一条，记录编辑前的内容）
+type MessageEdit struct {
+	ID             string    `gorm:"primaryKey;size:64" json:"id"`
+	ConversationID string    `gorm:"index:idx_message_edits_conv_seq;size:64;not null" json:"conversation_id"`
+	Seq            int64     `gorm:"index:idx_message_edits_conv_seq" json:"seq"`
+	ServerMsgID    string    `gorm:"index;size:64;not null" json:"server_msg_id"`
+	EditorID       string    `gorm:"size:64;not null" json:"editor_id"`
+	EditVersion    int       `json:"edit_version"`                 // 本次编辑产生的版本号
+	OldContent     string    `gorm:"type:text" json:"old_content"` // 编辑前的内容
+	NewContent     string    `gorm:"type:text" json:"new_content"` // 编辑后的内容
+	EditTime       int64     `json:"edit_time"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (MessageEdit) TableName() string {
+	return "message_edits"
+}
+
 // MessageSequence 消息序列号（每个会话维护独立的序列）
 type MessageSequence struct {
 	ID             string    `gorm:"primaryKey;size:64" json:"id"`
 	ConversationID string    `gorm:"uniqueIndex;size:64;not null" json:"conversation_id"` // 会话ID
 	MaxSeq         int64     `gorm:"default:0" json:"max_seq"`                             // 当前最大序列号（会话内）
+	MinSeq         int64     `gorm:"default:0" json:"min_seq"`                             // 保留策略清理到的位置（seq <= MinSeq 的消息已删除）
 	UpdatedAt      time.Time `json:"updated_at"`
 }
 
@@ -40,18 +95,30 @@ func (MessageSequence) TableName() string {
 	return "message_sequences"
 }
 
-// MessageReadReceipt 消息已读回执
-type MessageReadReceipt struct {
-	ID             string    `gorm:"primaryKey" json:"id"`
-	MessageID      string    `gorm:"index;size:64;not null" json:"message_id"`
-	ConversationID string    `gorm:"index;size:64;not null" json:"conversation_id"`
-	UserID         string    `gorm:"index;size:64;not null" json:"user_id"`
-	ReadTime       int64     `json:"read_time"`
-	CreatedAt      time.Time `json:"created_at"`
+
+// MessageDeletion 用户删除的消息（仅对自己隐藏，不影响会话中的其他人）
+type MessageDeletion struct {
+	ID             string    `gorm:"primaryKey;size:64" json:"id"`
+	UserID         string    `gorm:"uniqueIndex:idx_message_deletions_user_conv_seq,priority:1;size:64;not null" json:"user_id"`
+	ConversationID string    `gorm:"uniqueIndex:idx_message_deletions_user_conv_seq,priority:2;size:64;not null" json:"conversation_id"`
+	Seq            int64     `gorm:"uniqueIndex:idx_message_deletions_user_conv_seq,priority:3" json:"seq"`
+	CreatedAt      time.Time `gorm:"index" json:"created_at"`
 }
 
 // TableName 表名
-func (MessageReadReceipt) TableName() string {
-	return "message_read_receipts"
+func (MessageDeletion) TableName() string {
+	return "message_deletions"
 }
 
+// ConversationClear 用户清空会话历史的标记（seq <= ClearSeq 的消息对该用户不可见）
+type ConversationClear struct {
+	UserID         string    `gorm:"primaryKey;size:64" json:"user_id"`
+	ConversationID string    `gorm:"primaryKey;size:64" json:"conversation_id"`
+	ClearSeq       int64     `gorm:"default:0" json:"clear_seq"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (ConversationClear) TableName() string {
+	return "conversation_clears"
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+)
+
+// 内容审核动作（按严重程度递增，多个结果取最严重的）
+const (
+	ModerationActionPass   = 0 // 通过
+	ModerationActionFlag   = 1 // 正常投递，标记待人工复核
+	ModerationActionMask   = 2 // 敏感词替换为 * 后投递
+	ModerationActionReject = 3 // 拒绝发送
+)
+
+// 群组审核严格程度（敏感词等级为 1~3，严格程度越高检查的等级越多）
+const (
+	ModerationStrictnessRelaxed  = 1 // 宽松：只检查高危词
+	ModerationStrictnessStandard = 2 // 标准：检查中、高危词
+	ModerationStrictnessStrict   = 3 // 严格：检查全部敏感词
+)
+
+// 审核记录复核状态
+const (
+	ModerationReviewNone    = 0 // 不需要复核
+	ModerationReviewPending = 1 // 待复核
+)
+
+// GroupModerationSetting 群组的内容审核严格程度（覆盖全局默认值）
+type GroupModerationSetting struct {
+	GroupID    string    `gorm:"primaryKey;size:64" json:"group_id"`
+	Strictness int       `gorm:"not null" json:"strictness"`
+	UpdatedBy  string    `gorm:"size:64" json:"updated_by"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (GroupModerationSetting) TableName() string {
+	return "group_moderation_settings"
+}
+
+// ModerationLog 内容审核记录（只记录未通过的结果；被拒绝发送的消息没有 ServerMsgID）
+type ModerationLog struct {
+	ID             string    `gorm:"primaryKey;size:64" json:"id"`
+	ConversationID string    `gorm:"index;size:64;not null" json:"conversation_id"`
+	ServerMsgID    string    `gorm:"index;size:64" json:"server_msg_id"`
+	ClientMsgID    string    `gorm:"size:64" json:"client_msg_id"`
+	SenderID       string    `gorm:"index;size:64;not null" json:"sender_id"`
+	MessageType    int       `json:"message_type"`
+	Action         int       `gorm:"not null" json:"action"`
+	Source         string    `gorm:"size:32" json:"source"` // wordlist / classifier
+	Strictness     int       `json:"strictness"`
+	MatchedWords   string    `gorm:"size:1024" json:"matched_words"` // 命中的敏感词（逗号分隔）
+	Reason         string    `gorm:"size:512" json:"reason"`         // 外部分类器给出的原因
+	Content        string    `gorm:"type:text" json:"content"`       // 原始内容
+	ReviewStatus   int       `gorm:"index;default:0" json:"review_status"`
+	CreatedAt      time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName 表名
+func (ModerationLog) TableName() string {
+	return "moderation_logs"
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// PinnedMessage 会话置顶消息
+type PinnedMessage struct {
+	ID             string    `gorm:"primaryKey;size:64" json:"id"`
+	ConversationID string    `gorm:"uniqueIndex:idx_pinned_conv_seq,priority:1;size:64;not null" json:"conversation_id"`
+	Seq            int64     `gorm:"uniqueIndex:idx_pinned_conv_seq,priority:2" json:"seq"`
+	ServerMsgID    string    `gorm:"size:64;not null" json:"server_msg_id"`
+	PinnedBy       string    `gorm:"size:64;not null" json:"pinned_by"` // 置顶操作者
+	PinnedAt       int64     `json:"pinned_at"`                         // 置顶时间（毫秒）
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (PinnedMessage) TableName() string {
+	return "pinned_messages"
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// MessageReaction 消息表情回应（同一用户对同一消息的同一表情只能回应一次）
+type MessageReaction struct {
+	ID             string    `gorm:"primaryKey;size:64" json:"id"`
+	ConversationID string    `gorm:"uniqueIndex:idx_reactions_msg_emoji_user,priority:1;index:idx_reactions_msg,priority:1;size:64;not null" json:"conversation_id"`
+	Seq            int64     `gorm:"uniqueIndex:idx_reactions_msg_emoji_user,priority:2;index:idx_reactions_msg,priority:2;not null" json:"seq"`
+	Emoji          string    `gorm:"uniqueIndex:idx_reactions_msg_emoji_user,priority:3;size:32;not null" json:"emoji"`
+	UserID         string    `gorm:"uniqueIndex:idx_reactions_msg_emoji_user,priority:4;size:64;not null" json:"user_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// TableName 表名
+func (MessageReaction) TableName() string {
+	return "message_reactions"
+}
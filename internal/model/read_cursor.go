@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// ConversationReadCursor 用户在会话中的已读位置（seq <= ReadSeq 的消息视为已读，只前进不后退）
+type ConversationReadCursor struct {
+	UserID         string    `gorm:"primaryKey;size:64" json:"user_id"`
+	ConversationID string    `gorm:"primaryKey;size:64;index:idx_read_cursor_conv_seq,priority:1" json:"conversation_id"`
+	ReadSeq        int64     `gorm:"not null;default:0;index:idx_read_cursor_conv_seq,priority:2" json:"read_seq"`
+	ReadTime       int64     `json:"read_time"` // 游标最近一次前进的时间（毫秒）
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (ConversationReadCursor) TableName() string {
+	return "conversation_read_cursors"
+}
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+)
+
+// ConversationRetention 会话的消息保留期限（覆盖全局默认值；群聊的会话ID为 group_{groupID}）
+type ConversationRetention struct {
+	ConversationID string    `gorm:"primaryKey;size:64" json:"conversation_id"`
+	RetentionDays  int       `gorm:"not null" json:"retention_days"` // 保留天数
+	UpdatedBy      string    `gorm:"size:64" json:"updated_by"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (ConversationRetention) TableName() string {
+	return "conversation_retentions"
+}
+
+// MessageArchive 超过保留期限的归档消息（Data 为完整的 Message JSON）
+type MessageArchive struct {
+	ConversationID string    `gorm:"primaryKey;size:64" json:"conversation_id"`
+	Seq            int64     `gorm:"primaryKey;autoIncrement:false" json:"seq"`
+	ServerMsgID    string    `gorm:"index;size:64;not null" json:"server_msg_id"`
+	SenderID       string    `gorm:"index;size:64;not null" json:"sender_id"`
+	ServerTime     int64     `json:"server_time"`
+	Data           string    `gorm:"type:text" json:"data"`
+	ArchivedAt     time.Time `json:"archived_at"`
+}
+
+// TableName 表名
+func (MessageArchive) TableName() string {
+	return "message_archives"
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+)
+
+// 定时消息状态
+const (
+	ScheduledStatusPending    = 1 // 等待发送
+	ScheduledStatusProcessing = 2 // 发送中（已被某个节点领取）
+	ScheduledStatusSent       = 3 // 已发送
+	ScheduledStatusCancelled  = 4 // 已取消
+	ScheduledStatusFailed     = 5 // 发送失败
+)
+
+// ScheduledMessage 定时消息（到期后由调度器通过正常的发送流程投递）
+type ScheduledMessage struct {
+	ID             string    `gorm:"primaryKey;size:64" json:"id"`
+	SenderID       string    `gorm:"index;size:64;not null" json:"sender_id"`
+	ConversationID string    `gorm:"index;size:64;not null" json:"conversation_id"`
+	ReceiverID     string    `gorm:"size:64" json:"receiver_id"`
+	GroupID        string    `gorm:"size:64" json:"group_id"`
+	ClientMsgID    string    `gorm:"size:64;not null" json:"client_msg_id"` // 投递时作为消息的 client_msg_id（保证幂等）
+	MessageType    int       `gorm:"not null" json:"message_type"`
+	Content        string    `gorm:"type:text" json:"content"`
+	ReplyToSeq     int64     `json:"reply_to_seq"`
+	ThreadRootSeq  int64     `json:"thread_root_seq"`
+	MentionUserIDs string    `gorm:"type:text" json:"mention_user_ids"`
+	MentionAll     bool      `gorm:"default:false" json:"mention_all"`
+	ScheduledAt    int64     `gorm:"index:idx_scheduled_status_time,priority:2" json:"scheduled_at"` // 计划发送时间（毫秒）
+	Status         int       `gorm:"default:1;index:idx_scheduled_status_time,priority:1" json:"status"`
+	LockedBy       string    `gorm:"size:128" json:"locked_by"` // 领取该消息的节点
+	LockedUntil    int64     `json:"locked_until"`              // 领取租约到期时间（节点崩溃后可被其他节点重新领取）
+	Attempts       int       `gorm:"default:0" json:"attempts"`
+	LastError      string    `gorm:"size:512" json:"last_error"`
+	ServerMsgID    string    `gorm:"size:64" json:"server_msg_id"` // 发送成功后的消息ID
+	Seq            int64     `json:"seq"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (ScheduledMessage) TableName() string {
+	return "scheduled_messages"
+}
@@ -0,0 +1,19 @@
+package model
+
+// MessageSearchDoc 消息搜索文档（每条可搜索的消息一行）
+// Terms 为从消息内容中提取并分词后的词项（空格分隔，中日韩文字按单字和相邻双字切分），
+// PostgreSQL 上通过 GIN 表达式索引 to_tsvector('simple', terms) 检索
+type MessageSearchDoc struct {
+	ConversationID string `gorm:"primaryKey;size:64" json:"conversation_id"`
+	Seq            int64  `gorm:"primaryKey;autoIncrement:false" json:"seq"`
+	ServerMsgID    string `gorm:"size:64;not null" json:"server_msg_id"`
+	SenderID       string `gorm:"index;size:64;not null" json:"sender_id"`
+	MessageType    int    `json:"message_type"`
+	ServerTime     int64  `gorm:"index" json:"server_time"`
+	Terms          string `gorm:"type:text" json:"terms"`
+}
+
+// TableName 表名
+func (MessageSearchDoc) TableName() string {
+	return "message_search_docs"
+}
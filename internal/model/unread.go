@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// ConversationUnread 用户在会话中的未读数（Redis 计数器的数据库备份，Redis 数据丢失时从这里恢复）
+type ConversationUnread struct {
+	UserID         string    `gorm:"primaryKey;size:64" json:"user_id"`
+	ConversationID string    `gorm:"primaryKey;size:64" json:"conversation_id"`
+	UnreadCount    int64     `gorm:"not null;default:0" json:"unread_count"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName 表名
+func (ConversationUnread) TableName() string {
+	return "conversation_unreads"
+}
@@ -4,18 +4,38 @@ import (
 	"time"
 )
 
+// 自定义状态类型
+const (
+	PresenceStatusNone   = 0 // 未设置
+	PresenceStatusBusy   = 1 // 忙碌
+	PresenceStatusAway   = 2 // 离开
+	PresenceStatusCustom = 3 // 自定义（文字和表情）
+)
+
+// 最后在线时间可见范围
+const (
+	LastSeenEveryone = 0 // 所有人
+	LastSeenFriends  = 1 // 仅好友
+	LastSeenNobody   = 2 // 所有人都不可见
+)
+
 // User 用户模型
 type User struct {
-	ID        string    `gorm:"primaryKey;size:64" json:"id"`
-	Username  string    `gorm:"uniqueIndex;size:64;not null" json:"username"`
-	Nickname  string    `gorm:"size:128" json:"nickname"`
-	Avatar    string    `gorm:"size:512" json:"avatar"`
-	Email     string    `gorm:"size:128" json:"email"`
-	Phone     string    `gorm:"size:32" json:"phone"`
-	Password  string    `gorm:"size:256;not null" json:"-"`
-	Status    int       `gorm:"default:1" json:"status"` // 1: 正常, 2: 禁用
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID                 string    `gorm:"primaryKey;size:64" json:"id"`
+	Username           string    `gorm:"uniqueIndex;size:64;not null" json:"username"`
+	Nickname           string    `gorm:"size:128" json:"nickname"`
+	Avatar             string    `gorm:"size:512" json:"avatar"`
+	Email              string    `gorm:"size:128" json:"email"`
+	Phone              string    `gorm:"size:32" json:"phone"`
+	Password           string    `gorm:"size:256;not null" json:"-"`
+	Status             int       `gorm:"default:1" json:"status"`               // 1: 正常, 2: 禁用
+	PresenceStatus     int       `gorm:"default:0" json:"presence_status"`      // 自定义状态（0: 未设置, 1: 忙碌, 2: 离开, 3: 自定义）
+	PresenceText       string    `gorm:"size:256" json:"presence_text"`         // 自定义状态文字
+	PresenceEmoji      string    `gorm:"size:32" json:"presence_emoji"`         // 自定义状态表情
+	PresenceExpireAt   int64     `gorm:"default:0" json:"presence_expire_at"`   // 自定义状态过期时间（毫秒，0 表示不过期）
+	LastSeenVisibility int       `gorm:"default:0" json:"last_seen_visibility"` // 最后在线时间可见范围（0: 所有人, 1: 仅好友, 2: 所有人都不可见）
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
 }
 
 // TableName 表名
@@ -53,4 +73,3 @@ type OnlineStatus struct {
 func (OnlineStatus) TableName() string {
 	return "online_status"
 }
-
@@ -32,6 +32,60 @@ const (
 	CMD_REVOKE_MSG_REQ  = CommandType_CMD_REVOKE_MSG_REQ
 	CMD_REVOKE_MSG_RSP  = CommandType_CMD_REVOKE_MSG_RSP
 	CMD_REVOKE_MSG_PUSH = CommandType_CMD_REVOKE_MSG_PUSH
+	CMD_EDIT_MSG_REQ    = CommandType_CMD_EDIT_MSG_REQ
+	CMD_EDIT_MSG_RSP    = CommandType_CMD_EDIT_MSG_RSP
+	CMD_EDIT_MSG_PUSH   = CommandType_CMD_EDIT_MSG_PUSH
+	
+	CMD_EDIT_HISTORY_REQ = CommandType_CMD_EDIT_HISTORY_REQ
+	CMD_EDIT_HISTORY_RSP = CommandType_CMD_EDIT_HISTORY_RSP
+	
+	// 表情回应
+	CMD_ADD_REACTION_REQ    = CommandType_CMD_ADD_REACTION_REQ
+	CMD_ADD_REACTION_RSP    = CommandType_CMD_ADD_REACTION_RSP
+	CMD_REMOVE_REACTION_REQ = CommandType_CMD_REMOVE_REACTION_REQ
+	CMD_REMOVE_REACTION_RSP = CommandType_CMD_REMOVE_REACTION_RSP
+	CMD_REACTION_PUSH       = CommandType_CMD_REACTION_PUSH
+	
+	// 消息转发
+	CMD_FORWARD_MSG_REQ = CommandType_CMD_FORWARD_MSG_REQ
+	CMD_FORWARD_MSG_RSP = CommandType_CMD_FORWARD_MSG_RSP
+	
+	// 删除消息
+	CMD_DELETE_MSG_REQ    = CommandType_CMD_DELETE_MSG_REQ
+	CMD_DELETE_MSG_RSP    = CommandType_CMD_DELETE_MSG_RSP
+	CMD_CLEAR_HISTORY_REQ = CommandType_CMD_CLEAR_HISTORY_REQ
+	CMD_CLEAR_HISTORY_RSP = CommandType_CMD_CLEAR_HISTORY_RSP
+	CMD_DELETE_MSG_PUSH   = CommandType_CMD_DELETE_MSG_PUSH
+	
+	// 定时消息
+	CMD_SCHEDULE_MSG_REQ     = CommandType_CMD_SCHEDULE_MSG_REQ
+	CMD_SCHEDULE_MSG_RSP     = CommandType_CMD_SCHEDULE_MSG_RSP
+	CMD_SCHEDULED_LIST_REQ   = CommandType_CMD_SCHEDULED_LIST_REQ
+	CMD_SCHEDULED_LIST_RSP   = CommandType_CMD_SCHEDULED_LIST_RSP
+	CMD_SCHEDULED_EDIT_REQ   = CommandType_CMD_SCHEDULED_EDIT_REQ
+	CMD_SCHEDULED_EDIT_RSP   = CommandType_CMD_SCHEDULED_EDIT_RSP
+	CMD_SCHEDULED_CANCEL_REQ = CommandType_CMD_SCHEDULED_CANCEL_REQ
+	CMD_SCHEDULED_CANCEL_RSP = CommandType_CMD_SCHEDULED_CANCEL_RSP
+	
+	// 阅后即焚
+	CMD_SET_EPHEMERAL_REQ      = CommandType_CMD_SET_EPHEMERAL_REQ
+	CMD_SET_EPHEMERAL_RSP      = CommandType_CMD_SET_EPHEMERAL_RSP
+	CMD_EPHEMERAL_SETTING_PUSH = CommandType_CMD_EPHEMERAL_SETTING_PUSH
+	CMD_MSG_EXPIRED_PUSH       = CommandType_CMD_MSG_EXPIRED_PUSH
+	CMD_GET_EPHEMERAL_REQ      = CommandType_CMD_GET_EPHEMERAL_REQ
+	CMD_GET_EPHEMERAL_RSP      = CommandType_CMD_GET_EPHEMERAL_RSP
+	
+	// 消息保留期限
+	CMD_SET_RETENTION_REQ = CommandType_CMD_SET_RETENTION_REQ
+	CMD_SET_RETENTION_RSP = CommandType_CMD_SET_RETENTION_RSP
+	CMD_GET_RETENTION_REQ = CommandType_CMD_GET_RETENTION_REQ
+	CMD_GET_RETENTION_RSP = CommandType_CMD_GET_RETENTION_RSP
+	
+	// 内容审核
+	CMD_SET_MODERATION_REQ = CommandType_CMD_SET_MODERATION_REQ
+	CMD_SET_MODERATION_RSP = CommandType_CMD_SET_MODERATION_RSP
+	CMD_GET_MODERATION_REQ = CommandType_CMD_GET_MODERATION_REQ
+	CMD_GET_MODERATION_RSP = CommandType_CMD_GET_MODERATION_RSP
 	
 	// 同步相关
 	CMD_BATCH_SYNC_REQ = CommandType_CMD_BATCH_SYNC_REQ
@@ -39,16 +93,59 @@ const (
 	CMD_SYNC_FINISHED  = CommandType_CMD_SYNC_FINISHED
 	CMD_SYNC_RANGE_REQ = CommandType_CMD_SYNC_RANGE_REQ
 	CMD_SYNC_RANGE_RSP = CommandType_CMD_SYNC_RANGE_RSP
+	CMD_HISTORY_REQ    = CommandType_CMD_HISTORY_REQ
+	CMD_HISTORY_RSP    = CommandType_CMD_HISTORY_RSP
+	CMD_SEARCH_MSG_REQ = CommandType_CMD_SEARCH_MSG_REQ
+	CMD_SEARCH_MSG_RSP = CommandType_CMD_SEARCH_MSG_RSP
+	
+	// 会话列表
+	CMD_SYNC_CONVERSATIONS_REQ = CommandType_CMD_SYNC_CONVERSATIONS_REQ
+	CMD_SYNC_CONVERSATIONS_RSP = CommandType_CMD_SYNC_CONVERSATIONS_RSP
+	
+	CMD_SET_CONVERSATION_SETTINGS_REQ = CommandType_CMD_SET_CONVERSATION_SETTINGS_REQ
+	CMD_SET_CONVERSATION_SETTINGS_RSP = CommandType_CMD_SET_CONVERSATION_SETTINGS_RSP
+	CMD_CONVERSATION_SETTINGS_PUSH    = CommandType_CMD_CONVERSATION_SETTINGS_PUSH
+	CMD_SET_DRAFT_REQ                 = CommandType_CMD_SET_DRAFT_REQ
+	CMD_SET_DRAFT_RSP                 = CommandType_CMD_SET_DRAFT_RSP
+	CMD_DRAFT_PUSH                    = CommandType_CMD_DRAFT_PUSH
+	
+	// 聊天室
+	CMD_JOIN_CHATROOM_REQ          = CommandType_CMD_JOIN_CHATROOM_REQ
+	CMD_JOIN_CHATROOM_RSP          = CommandType_CMD_JOIN_CHATROOM_RSP
+	CMD_LEAVE_CHATROOM_REQ         = CommandType_CMD_LEAVE_CHATROOM_REQ
+	CMD_LEAVE_CHATROOM_RSP         = CommandType_CMD_LEAVE_CHATROOM_RSP
+	CMD_CHATROOM_MSG_PUSH          = CommandType_CMD_CHATROOM_MSG_PUSH
+	CMD_CHATROOM_MEMBER_COUNT_PUSH = CommandType_CMD_CHATROOM_MEMBER_COUNT_PUSH
+	
+	// 话题
+	CMD_THREAD_REPLIES_REQ = CommandType_CMD_THREAD_REPLIES_REQ
+	CMD_THREAD_REPLIES_RSP = CommandType_CMD_THREAD_REPLIES_RSP
+	
+	// 置顶消息
+	CMD_PIN_MSG_REQ     = CommandType_CMD_PIN_MSG_REQ
+	CMD_PIN_MSG_RSP     = CommandType_CMD_PIN_MSG_RSP
+	CMD_UNPIN_MSG_REQ   = CommandType_CMD_UNPIN_MSG_REQ
+	CMD_UNPIN_MSG_RSP   = CommandType_CMD_UNPIN_MSG_RSP
+	CMD_PIN_PUSH        = CommandType_CMD_PIN_PUSH
+	CMD_PINNED_LIST_REQ = CommandType_CMD_PINNED_LIST_REQ
+	CMD_PINNED_LIST_RSP = CommandType_CMD_PINNED_LIST_RSP
 	
 	// 在线状态
 	CMD_ONLINE_STATUS_REQ  = CommandType_CMD_ONLINE_STATUS_REQ
 	CMD_ONLINE_STATUS_RSP  = CommandType_CMD_ONLINE_STATUS_RSP
 	CMD_STATUS_CHANGE_PUSH = CommandType_CMD_STATUS_CHANGE_PUSH
+	CMD_SET_PRESENCE_REQ   = CommandType_CMD_SET_PRESENCE_REQ
+	CMD_SET_PRESENCE_RSP   = CommandType_CMD_SET_PRESENCE_RSP
 	
 	// 已读回执
 	CMD_READ_RECEIPT_REQ  = CommandType_CMD_READ_RECEIPT_REQ
 	CMD_READ_RECEIPT_RSP  = CommandType_CMD_READ_RECEIPT_RSP
 	CMD_READ_RECEIPT_PUSH = CommandType_CMD_READ_RECEIPT_PUSH
+	CMD_READ_MEMBERS_REQ  = CommandType_CMD_READ_MEMBERS_REQ
+	CMD_READ_MEMBERS_RSP  = CommandType_CMD_READ_MEMBERS_RSP
+	
+	CMD_GROUP_READ_RECEIPT_PUSH = CommandType_CMD_GROUP_READ_RECEIPT_PUSH
+	CMD_UNREAD_COUNT_PUSH       = CommandType_CMD_UNREAD_COUNT_PUSH
 	
 	// 输入状态
 	CMD_TYPING_STATUS_REQ  = CommandType_CMD_TYPING_STATUS_REQ
@@ -67,6 +164,14 @@ const (
 	ERR_MESSAGE_TOO_LARGE      = ErrorCode_ERR_MESSAGE_TOO_LARGE
 	ERR_SEND_TOO_FAST          = ErrorCode_ERR_SEND_TOO_FAST
 	ERR_CONVERSATION_NOT_EXIST = ErrorCode_ERR_CONVERSATION_NOT_EXIST
+	ERR_MESSAGE_NOT_EXIST      = ErrorCode_ERR_MESSAGE_NOT_EXIST
+	ERR_EDIT_TIME_EXPIRED      = ErrorCode_ERR_EDIT_TIME_EXPIRED
+	ERR_NOT_GROUP_MEMBER       = ErrorCode_ERR_NOT_GROUP_MEMBER
+	ERR_SCHEDULE_NOT_PENDING   = ErrorCode_ERR_SCHEDULE_NOT_PENDING
+	ERR_CONTENT_REJECTED       = ErrorCode_ERR_CONTENT_REJECTED
+	ERR_NOT_CHATROOM_MEMBER    = ErrorCode_ERR_NOT_CHATROOM_MEMBER
+	ERR_CHATROOM_FULL          = ErrorCode_ERR_CHATROOM_FULL
+	ERR_EDIT_CONFLICT          = ErrorCode_ERR_EDIT_CONFLICT
 )
 
 // Marshal 序列化消息
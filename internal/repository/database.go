@@ -8,6 +8,7 @@ import (
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -78,8 +79,26 @@ func autoMigrate() error {
 		&model.OnlineStatus{},
 		&model.Message{},
 		&model.MessageSequence{},
-		&model.MessageReadReceipt{},
+		&model.ConversationReadCursor{},
+		&model.ConversationUnread{},
+		&model.MessageEdit{},
+		&model.MessageReaction{},
+		&model.UserMention{},
+		&model.MessageDeletion{},
+		&model.ConversationClear{},
+		&model.PinnedMessage{},
+		&model.ScheduledMessage{},
+		&model.ConversationEphemeralSetting{},
+		&model.ConversationRetention{},
+		&model.MessageArchive{},
+		&model.MessageSearchDoc{},
+		&model.MediaFile{},
+		&model.MediaUpload{},
+		&model.MediaUploadChunk{},
+		&model.GroupModerationSetting{},
+		&model.ModerationLog{},
 		&model.Conversation{},
+		&model.ConversationVersion{},
 		&model.Friend{},
 		&model.FriendRequest{},
 		&model.Group{},
@@ -95,10 +114,83 @@ func autoMigrate() error {
 	`).Error; err != nil {
 		return fmt.Errorf("failed to create unique index: %w", err)
 	}
+
+	// 旧的逐条已读回执迁移为会话已读游标
+	if err := migrateReadReceipts(); err != nil {
+		return fmt.Errorf("failed to migrate read receipts: %w", err)
+	}
 	
 	return nil
 }
 
+// legacyReceiptCursor 旧回执表按用户和会话聚合后的已读位置
+type legacyReceiptCursor struct {
+	UserID         string
+	ConversationID string
+	ReadSeq        int64
+	ReadTime       int64
+}
+
+// migrateReadReceipts 将 message_read_receipts 中的逐条回执迁移为会话已读游标
+// 回执的 message_id 可能是 server_msg_id，也可能是早期记录的 client_msg_id，两种都按同一会话内的消息匹配
+// 迁移完成后旧表改名为 message_read_receipts_migrated 保留一个版本（确认数据无误后在之后的版本中删除），不直接删除
+func migrateReadReceipts() error {
+	const (
+		legacyTable   = "message_read_receipts"
+		migratedTable = "message_read_receipts_migrated"
+	)
+	if !DB.Migrator().HasTable(legacyTable) {
+		return nil
+	}
+
+	merged := make(map[[2]string]*legacyReceiptCursor)
+	for _, column := range []string{"server_msg_id", "client_msg_id"} {
+		var rows []*legacyReceiptCursor
+		err := DB.Table(legacyTable+" r").
+			Select("r.user_id, r.conversation_id, MAX(m.seq) AS read_seq, MAX(r.read_time) AS read_time").
+			Joins("JOIN messages m ON m.conversation_id = r.conversation_id AND m."+column+" = r.message_id").
+			Group("r.user_id, r.conversation_id").
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			key := [2]string{row.UserID, row.ConversationID}
+			existing, ok := merged[key]
+			if !ok {
+				merged[key] = row
+				continue
+			}
+			if row.ReadSeq > existing.ReadSeq {
+				existing.ReadSeq = row.ReadSeq
+			}
+			if row.ReadTime > existing.ReadTime {
+				existing.ReadTime = row.ReadTime
+			}
+		}
+	}
+
+	cursors := make([]*model.ConversationReadCursor, 0, len(merged))
+	for _, row := range merged {
+		cursors = append(cursors, &model.ConversationReadCursor{
+			UserID:         row.UserID,
+			ConversationID: row.ConversationID,
+			ReadSeq:        row.ReadSeq,
+			ReadTime:       row.ReadTime,
+		})
+	}
+	// 游标写入和旧表改名在同一个事务中（MySQL 的 DDL 会先隐式提交已写入的游标，
+	// 此时改名失败下次启动会重新迁移：已存在的游标保留已写入的结果，重复迁移不会改变数据）
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if len(cursors) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(cursors, 500).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().RenameTable(legacyTable, migratedTable)
+	})
+}
+
 // GetDB 获取数据库实例
 func GetDB() *gorm.DB {
 	return DB
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/arwen/im-server/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestMigrateReadReceipts(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	previous := DB
+	DB = db
+	t.Cleanup(func() { DB = previous })
+
+	if err := db.AutoMigrate(&model.Message{}, &model.ConversationReadCursor{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	// 旧版本的逐条回执表
+	if err := db.Exec(`CREATE TABLE message_read_receipts (
+		id VARCHAR(64) PRIMARY KEY, message_id VARCHAR(64), conversation_id VARCHAR(64), user_id VARCHAR(64), read_time BIGINT)`).Error; err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+
+	for seq, id := range []string{"", "m1", "m2", "m3"} {
+		if id == "" {
+			continue
+		}
+		msg := &model.Message{ConversationID: "c1", Seq: int64(seq), ServerMsgID: id, ClientMsgID: "client-" + id, SenderID: "a"}
+		if err := db.Create(msg).Error; err != nil {
+			t.Fatalf("create message: %v", err)
+		}
+	}
+	receipts := [][]interface{}{
+		{"r1", "m1", "c1", "b", 100},
+		{"r2", "client-m3", "c1", "b", 300}, // 早期按 client_msg_id 记录的回执
+		{"r3", "m2", "c1", "c", 200},
+		{"r4", "m2", "c1", "d", 200},
+		{"r5", "unknown", "c1", "e", 400}, // 消息已不存在
+	}
+	for _, r := range receipts {
+		if err := db.Exec("INSERT INTO message_read_receipts (id, message_id, conversation_id, user_id, read_time) VALUES (?, ?, ?, ?, ?)", r...).Error; err != nil {
+			t.Fatalf("insert receipt: %v", err)
+		}
+	}
+	// 已经存在的游标保留（可能是上次迁移写入后改名失败，或迁移前已经按新协议读过）
+	if err := db.Create(&model.ConversationReadCursor{UserID: "d", ConversationID: "c1", ReadSeq: 3, ReadTime: 500}).Error; err != nil {
+		t.Fatalf("create cursor: %v", err)
+	}
+
+	if err := migrateReadReceipts(); err != nil {
+		t.Fatalf("migrateReadReceipts() error = %v", err)
+	}
+
+	var cursors []model.ConversationReadCursor
+	db.Order("user_id ASC").Find(&cursors)
+	want := map[string][2]int64{"b": {3, 300}, "c": {2, 200}, "d": {3, 500}}
+	if len(cursors) != len(want) {
+		t.Fatalf("cursors = %+v, want %v", cursors, want)
+	}
+	for _, c := range cursors {
+		if w := want[c.UserID]; c.ReadSeq != w[0] || c.ReadTime != w[1] {
+			t.Errorf("cursor %s = seq %d time %d, want %v", c.UserID, c.ReadSeq, c.ReadTime, w)
+		}
+	}
+
+	if db.Migrator().HasTable("message_read_receipts") || !db.Migrator().HasTable("message_read_receipts_migrated") {
+		t.Error("legacy table should be renamed to message_read_receipts_migrated")
+	}
+	var kept int64
+	db.Table("message_read_receipts_migrated").Count(&kept)
+	if kept != int64(len(receipts)) {
+		t.Errorf("migrated table rows = %d, want %d", kept, len(receipts))
+	}
+
+	// 已迁移时再次调用不做任何事
+	if err := migrateReadReceipts(); err != nil {
+		t.Errorf("migrateReadReceipts() again error = %v", err)
+	}
+}
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	chatroomConversationPrefix = "chatroom_"
+	maxChatroomIDLen           = 64 - len(chatroomConversationPrefix) // conversation_id 最长 64
+	defaultChatroomHistory     = 20                                   // 加入时默认返回的最近消息条数
+)
+
+// chatroomHistoryKey 聊天室最近消息（list，按 seq 升序，只保留最近 HistorySize 条）
+func chatroomHistoryKey(chatroomID string) string {
+	return fmt.Sprintf("chatroom:history:%s", chatroomID)
+}
+
+// chatroomSeqKey 聊天室消息序列号（与最近消息一起过期，过期后从 1 重新开始）
+func chatroomSeqKey(chatroomID string) string {
+	return fmt.Sprintf("chatroom:seq:%s", chatroomID)
+}
+
+// ChatroomConversationID 聊天室的会话 ID
+func ChatroomConversationID(chatroomID string) string {
+	return utils.GetConversationID(model.ConversationTypeChatroom, "", chatroomID)
+}
+
+// ParseChatroomConversationID 从会话 ID 中解析聊天室 ID（不是聊天室会话时返回 false）
+func ParseChatroomConversationID(conversationID string) (string, bool) {
+	if !strings.HasPrefix(conversationID, chatroomConversationPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(conversationID, chatroomConversationPrefix), true
+}
+
+// ChatroomConfig 聊天室配置
+type ChatroomConfig struct {
+	MaxMembers  int           // 单个聊天室最大在线人数
+	MaxJoined   int           // 每个用户最多同时加入的聊天室数
+	HistorySize int           // 保留的最近消息条数
+	HistoryTTL  time.Duration // 聊天室没有新消息后，最近消息的保留时长
+}
+
+// ChatroomMember 聊天室成员（成员关系绑定到加入时的连接）
+type ChatroomMember struct {
+	UserID string
+	ConnID string
+}
+
+// ChatroomMessage 聊天室消息（只保存在 Redis 中并在一段时间后过期，不写入消息表）
+type ChatroomMessage struct {
+	ServerMsgID string `json:"server_msg_id"`
+	ClientMsgID string `json:"client_msg_id"`
+	ChatroomID  string `json:"chatroom_id"`
+	SenderID    string `json:"sender_id"`
+	MessageType int    `json:"message_type"`
+	Content     string `json:"content"`
+	Seq         int64  `json:"seq"`
+	SendTime    int64  `json:"send_time"`
+	ServerTime  int64  `json:"server_time"`
+}
+
+// ChatroomCountFunc 推送聊天室在线人数给成员（由消息处理器实现）
+type ChatroomCountFunc func(chatroomID string, count int, members []ChatroomMember)
+
+// ChatroomConnAliveFunc 成员加入时的连接是否仍是用户当前的连接（由消息处理器实现）
+type ChatroomConnAliveFunc func(userID, connID string) bool
+
+// ChatroomService 聊天室服务：聊天室不需要创建，第一个人加入时出现，最后一个人离开时消失；
+// 成员关系保存在本节点内存中并绑定到连接，连接断开（或被新连接踢下线）时自动离开；
+// 消息不写入消息表，只在 Redis 中保留最近若干条用于新成员加入时回放；在线人数变化合并后定时推送
+type ChatroomService struct {
+	config ChatroomConfig
+
+	mu     sync.RWMutex
+	rooms  map[string]map[string]string // 聊天室 -> 用户 -> 连接
+	joined map[string]map[string]string // 用户 -> 聊天室 -> 连接
+	dirty  map[string]struct{}          // 在线人数有变化、等待推送的聊天室
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewChatroomService 创建聊天室服务
+func NewChatroomService(config ChatroomConfig) *ChatroomService {
+	if config.MaxMembers <= 0 {
+		config.MaxMembers = 10000
+	}
+	if config.MaxJoined <= 0 {
+		config.MaxJoined = 10
+	}
+	if config.HistorySize < 0 {
+		config.HistorySize = 0
+	}
+	if config.HistoryTTL <= 0 {
+		config.HistoryTTL = 10 * time.Minute
+	}
+	return &ChatroomService{
+		config: config,
+		rooms:  make(map[string]map[string]string),
+		joined: make(map[string]map[string]string),
+		dirty:  make(map[string]struct{}),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// validChatroomID 聊天室 ID 不能为空、不能过长，也不能包含空白字符
+func validChatroomID(chatroomID string) bool {
+	return chatroomID != "" && len(chatroomID) <= maxChatroomIDLen && !strings.ContainsAny(chatroomID, " \t\r\n")
+}
+
+// Join 用户通过连接 connID 加入聊天室，返回当前在线人数（重复加入只更新绑定的连接）
+func (s *ChatroomService) Join(chatroomID, userID, connID string) (int, error) {
+	if !validChatroomID(chatroomID) {
+		return 0, ErrInvalidChatroom
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members := s.rooms[chatroomID]
+	if _, ok := members[userID]; ok {
+		members[userID] = connID
+		s.joined[userID][chatroomID] = connID
+		return len(members), nil
+	}
+
+	if len(s.joined[userID]) >= s.config.MaxJoined {
+		return 0, ErrTooManyChatrooms
+	}
+	if len(members) >= s.config.MaxMembers {
+		return 0, ErrChatroomFull
+	}
+
+	if members == nil {
+		members = make(map[string]string)
+		s.rooms[chatroomID] = members
+	}
+	members[userID] = connID
+	if s.joined[userID] == nil {
+		s.joined[userID] = make(map[string]string)
+	}
+	s.joined[userID][chatroomID] = connID
+	s.dirty[chatroomID] = struct{}{}
+	return len(members), nil
+}
+
+// Leave 用户离开聊天室（只有 connID 为加入时的连接才离开），返回是否离开
+func (s *ChatroomService) Leave(chatroomID, userID, connID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if connID == "" || s.rooms[chatroomID][userID] != connID {
+		return false
+	}
+	s.remove(chatroomID, userID)
+	return true
+}
+
+// LeaveStale 用户离开不是通过 connID 加入的聊天室（connID 为空时离开全部），返回离开的聊天室
+// 用户上线（新连接替换旧连接）和下线时调用
+func (s *ChatroomService) LeaveStale(userID, connID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var left []string
+	for chatroomID, joinedConnID := range s.joined[userID] {
+		if connID != "" && joinedConnID == connID {
+			continue
+		}
+		s.remove(chatroomID, userID)
+		left = append(left, chatroomID)
+	}
+	return left
+}
+
+// remove 移除成员（调用方需持有写锁）
+func (s *ChatroomService) remove(chatroomID, userID string) {
+	if members := s.rooms[chatroomID]; members != nil {
+		delete(members, userID)
+		if len(members) == 0 {
+			delete(s.rooms, chatroomID)
+		}
+	}
+	if rooms := s.joined[userID]; rooms != nil {
+		delete(rooms, chatroomID)
+		if len(rooms) == 0 {
+			delete(s.joined, userID)
+		}
+	}
+	s.dirty[chatroomID] = struct{}{}
+}
+
+// IsMember 用户是否通过 connID 加入了聊天室
+func (s *ChatroomService) IsMember(chatroomID, userID, connID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	joinedConnID, ok := s.rooms[chatroomID][userID]
+	return ok && joinedConnID == connID
+}
+
+// Members 聊天室成员快照（用于消息扇出）
+func (s *ChatroomService) Members(chatroomID string) []ChatroomMember {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members := make([]ChatroomMember, 0, len(s.rooms[chatroomID]))
+	for userID, connID := range s.rooms[chatroomID] {
+		members = append(members, ChatroomMember{UserID: userID, ConnID: connID})
+	}
+	return members
+}
+
+// MemberCount 聊天室在线人数
+func (s *ChatroomService) MemberCount(chatroomID string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.rooms[chatroomID])
+}
+
+// SaveMessage 分配 ServerMsgID 和 Seq，并写入最近消息（不写入消息表）
+func (s *ChatroomService) SaveMessage(msg *ChatroomMessage) error {
+	ctx := context.Background()
+
+	seq, err := repository.RedisClient.Incr(ctx, chatroomSeqKey(msg.ChatroomID)).Result()
+	if err != nil {
+		return err
+	}
+	msg.Seq = seq
+	msg.ServerMsgID = utils.GenerateMessageID(msg.SenderID)
+
+	pipe := repository.RedisClient.TxPipeline()
+	pipe.Expire(ctx, chatroomSeqKey(msg.ChatroomID), s.config.HistoryTTL)
+	if s.config.HistorySize > 0 {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		key := chatroomHistoryKey(msg.ChatroomID)
+		pipe.RPush(ctx, key, data)
+		pipe.LTrim(ctx, key, int64(-s.config.HistorySize), -1)
+		pipe.Expire(ctx, key, s.config.HistoryTTL)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetHistory 获取聊天室最近的 count 条消息（按 seq 升序，count <= 0 时使用默认值）
+func (s *ChatroomService) GetHistory(chatroomID string, count int) ([]*ChatroomMessage, error) {
+	if count <= 0 {
+		count = defaultChatroomHistory
+	}
+	if count > s.config.HistorySize {
+		count = s.config.HistorySize
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	values, err := repository.RedisClient.LRange(context.Background(), chatroomHistoryKey(chatroomID), int64(-count), -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]*ChatroomMessage, 0, len(values))
+	for _, value := range values {
+		var msg ChatroomMessage
+		if err := json.Unmarshal([]byte(value), &msg); err != nil {
+			logger.Warn("Invalid chatroom history entry", zap.String("chatroom_id", chatroomID), zap.Error(err))
+			continue
+		}
+		messages = append(messages, &msg)
+	}
+	return messages, nil
+}
+
+// Start 启动在线人数推送任务（人数有变化的聊天室每个周期最多推送一次，推送前移出连接已经失效的成员）
+func (s *ChatroomService) Start(interval time.Duration, alive ChatroomConnAliveFunc, notify ChatroomCountFunc) {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.flushCounts(alive, notify)
+			}
+		}
+	}()
+}
+
+// Stop 停止在线人数推送任务
+func (s *ChatroomService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// flushCounts 推送人数有变化的聊天室的在线人数（聊天室已经没有成员时不推送）
+func (s *ChatroomService) flushCounts(alive ChatroomConnAliveFunc, notify ChatroomCountFunc) {
+	s.mu.Lock()
+	dirty := s.dirty
+	s.dirty = make(map[string]struct{})
+	s.mu.Unlock()
+
+	for chatroomID := range dirty {
+		members := s.pruneStale(chatroomID, alive)
+		if len(members) == 0 {
+			continue
+		}
+		notify(chatroomID, len(members), members)
+	}
+}
+
+// pruneStale 移出连接已经失效的成员（连接异常断开而没有触发离开时），返回剩余成员
+func (s *ChatroomService) pruneStale(chatroomID string, alive ChatroomConnAliveFunc) []ChatroomMember {
+	members := s.Members(chatroomID)
+	var stale []ChatroomMember
+	for _, member := range members {
+		if !alive(member.UserID, member.ConnID) {
+			stale = append(stale, member)
+		}
+	}
+	if len(stale) == 0 {
+		return members
+	}
+
+	s.mu.Lock()
+	for _, member := range stale {
+		// 检查期间重新加入（绑定了新连接）的成员保留
+		if s.rooms[chatroomID][member.UserID] == member.ConnID {
+			s.remove(chatroomID, member.UserID)
+		}
+	}
+	// 本次推送已经是移出后的人数
+	delete(s.dirty, chatroomID)
+	s.mu.Unlock()
+
+	return s.Members(chatroomID)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChatroomServiceJoinValidatesID(t *testing.T) {
+	s := NewChatroomService(ChatroomConfig{})
+	for _, id := range []string{"", strings.Repeat("a", maxChatroomIDLen+1), "room 1", "room\t1", "room1\n"} {
+		if _, err := s.Join(id, "u1", "c1"); err != ErrInvalidChatroom {
+			t.Errorf("Join(%q) error = %v, want %v", id, err, ErrInvalidChatroom)
+		}
+	}
+
+	// 最长的聊天室 ID 仍能放入 conversation_id
+	for _, id := range []string{"直播间-1", strings.Repeat("a", maxChatroomIDLen)} {
+		if _, err := s.Join(id, "u1", "c1"); err != nil {
+			t.Errorf("Join(%q) error = %v", id, err)
+		}
+		conversationID := ChatroomConversationID(id)
+		if parsed, ok := ParseChatroomConversationID(conversationID); len(conversationID) > 64 || !ok || parsed != id {
+			t.Errorf("conversation id %q parsed as (%q, %v)", conversationID, parsed, ok)
+		}
+	}
+	if _, ok := ParseChatroomConversationID("group_g1"); ok {
+		t.Error("ParseChatroomConversationID() accepted a group conversation")
+	}
+}
+
+func TestChatroomServiceHistory(t *testing.T) {
+	mr := setupTestRedis(t)
+	s := NewChatroomService(ChatroomConfig{HistorySize: 3, HistoryTTL: time.Minute})
+
+	for i := 1; i <= 5; i++ {
+		msg := &ChatroomMessage{ChatroomID: "r1", SenderID: "u1", Content: fmt.Sprintf("m%d", i)}
+		if err := s.SaveMessage(msg); err != nil {
+			t.Fatalf("SaveMessage() error = %v", err)
+		}
+		if msg.Seq != int64(i) || msg.ServerMsgID == "" {
+			t.Fatalf("SaveMessage() seq = %d, id = %q, want seq %d", msg.Seq, msg.ServerMsgID, i)
+		}
+	}
+
+	// 只保留最近 HistorySize 条，按 seq 升序返回
+	history, err := s.GetHistory("r1", 0)
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(history) != 3 || history[0].Content != "m3" || history[2].Content != "m5" {
+		t.Errorf("GetHistory() = %d messages starting at %q, want m3..m5", len(history), history[0].Content)
+	}
+	if history, err := s.GetHistory("r1", 1); err != nil || len(history) != 1 || history[0].Seq != 5 {
+		t.Errorf("GetHistory(1) = %v, %v, want the latest message", history, err)
+	}
+
+	// 没有新消息超过保留时长后，最近消息和序列号一起过期
+	mr.FastForward(time.Minute)
+	if history, err := s.GetHistory("r1", 0); err != nil || len(history) != 0 {
+		t.Errorf("GetHistory() after TTL = %d messages, %v, want none", len(history), err)
+	}
+	msg := &ChatroomMessage{ChatroomID: "r1", SenderID: "u1", Content: "again"}
+	if err := s.SaveMessage(msg); err != nil || msg.Seq != 1 {
+		t.Errorf("SaveMessage() after TTL seq = %d, %v, want 1", msg.Seq, err)
+	}
+
+	// 不保留最近消息时只分配序列号
+	s = NewChatroomService(ChatroomConfig{})
+	if err := s.SaveMessage(&ChatroomMessage{ChatroomID: "r2", SenderID: "u1"}); err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	if history, err := s.GetHistory("r2", 10); err != nil || history != nil {
+		t.Errorf("GetHistory() without history = %v, %v, want nil", history, err)
+	}
+}
+
+func TestChatroomServiceMembership(t *testing.T) {
+	s := NewChatroomService(ChatroomConfig{MaxMembers: 2, MaxJoined: 1})
+
+	if _, err := s.Join("bad id", "u1", "c1"); err != ErrInvalidChatroom {
+		t.Fatalf("Join() invalid id error = %v, want %v", err, ErrInvalidChatroom)
+	}
+	if count, err := s.Join("r1", "u1", "c1"); err != nil || count != 1 {
+		t.Fatalf("Join() = (%d, %v), want (1, nil)", count, err)
+	}
+	// 重复加入只更新绑定的连接
+	if count, err := s.Join("r1", "u1", "c2"); err != nil || count != 1 {
+		t.Fatalf("Join() rejoin = (%d, %v), want (1, nil)", count, err)
+	}
+	if s.IsMember("r1", "u1", "c1") || !s.IsMember("r1", "u1", "c2") {
+		t.Error("membership should be bound to the latest connection")
+	}
+	if _, err := s.Join("r2", "u1", "c2"); err != ErrTooManyChatrooms {
+		t.Errorf("Join() over MaxJoined error = %v, want %v", err, ErrTooManyChatrooms)
+	}
+	if _, err := s.Join("r1", "u2", "c3"); err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if _, err := s.Join("r1", "u3", "c4"); err != ErrChatroomFull {
+		t.Errorf("Join() over MaxMembers error = %v, want %v", err, ErrChatroomFull)
+	}
+
+	if s.Leave("r1", "u1", "c1") {
+		t.Error("Leave() with a stale connection should not leave")
+	}
+	if !s.Leave("r1", "u1", "c2") {
+		t.Error("Leave() with the joined connection should leave")
+	}
+	if left := s.LeaveStale("u2", "c9"); len(left) != 1 || left[0] != "r1" {
+		t.Errorf("LeaveStale() = %v, want [r1]", left)
+	}
+	if s.MemberCount("r1") != 0 {
+		t.Errorf("MemberCount() = %d, want 0", s.MemberCount("r1"))
+	}
+}
+
+func TestChatroomServiceFlushCountsPrunesStaleMembers(t *testing.T) {
+	s := NewChatroomService(ChatroomConfig{})
+	for _, m := range []ChatroomMember{{"u1", "c1"}, {"u2", "c2"}, {"u3", "c3"}} {
+		if _, err := s.Join("r1", m.UserID, m.ConnID); err != nil {
+			t.Fatalf("Join() error = %v", err)
+		}
+	}
+
+	alive := func(userID, connID string) bool { return userID != "u2" }
+	var notified int
+	var gotCount int
+	s.flushCounts(alive, func(chatroomID string, count int, members []ChatroomMember) {
+		notified++
+		gotCount = count
+		for _, m := range members {
+			if m.UserID == "u2" {
+				t.Error("stale member should not be notified")
+			}
+		}
+	})
+	if notified != 1 || gotCount != 2 {
+		t.Errorf("flushCounts() notified %d times with count %d, want 1 time with count 2", notified, gotCount)
+	}
+
+	// 移出失效成员后不需要再次推送
+	s.flushCounts(alive, func(string, int, []ChatroomMember) {
+		t.Error("no change expected after pruning")
+	})
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/arwen/im-server/internal/model"
+)
+
+const (
+	contentPreviewMaxRunes = 64  // 内容预览最大字符数（会话最后一条消息、离线推送）
+	minCustomContentType   = 100 // 自定义消息类型的最小值（更小的值保留给内置类型）
+)
+
+// 内容字段类型
+const (
+	ContentFieldString = "string"
+	ContentFieldURL    = "url"    // http/https 地址
+	ContentFieldNumber = "number" // 必填时要求大于 0（如大小、时长）
+	ContentFieldBool   = "bool"
+)
+
+// ContentField 消息内容（JSON 对象）的字段定义
+type ContentField struct {
+	Name      string
+	Kind      string // 见 ContentField* 常量
+	Required  bool
+	MaxLength int // 字符串最大长度（字符，0 表示不限）
+}
+
+// ContentType 消息内容类型定义
+type ContentType struct {
+	Type         int
+	Name         string
+	PlainText    bool           // 内容为纯文本（否则为 JSON 对象，按 Fields 校验）
+	Fields       []ContentField // JSON 字段定义（未定义的字段不校验）
+	PreviewLabel string         // 预览标签，如 "[图片]"（纯文本类型使用内容本身）
+	PreviewField string         // 追加在预览标签后的字段，如文件名
+	ServerOnly   bool           // 只能由服务端生成，客户端不能直接发送或编辑
+}
+
+// ContentService 消息内容类型注册表：按消息类型校验客户端提交的内容，生成纯文本预览
+// 内置类型在创建时注册，应用自定义类型在启动时通过 RegisterType 注册
+type ContentService struct {
+	mu        sync.RWMutex
+	types     map[int]*ContentType
+	maxLength int // 内容最大长度（字节，0 表示不限）
+}
+
+// NewContentService 创建消息内容类型注册表（maxLength 为 message.max_length）
+func NewContentService(maxLength int) *ContentService {
+	s := &ContentService{
+		types:     make(map[int]*ContentType),
+		maxLength: maxLength,
+	}
+	for _, ct := range builtinContentTypes() {
+		s.types[ct.Type] = ct
+	}
+	return s
+}
+
+// builtinContentTypes 内置消息类型
+func builtinContentTypes() []*ContentType {
+	mediaFields := func(extra ...ContentField) []ContentField {
+		return append([]ContentField{
+			{Name: "url", Kind: ContentFieldURL, Required: true},
+			{Name: "size", Kind: ContentFieldNumber, Required: true},
+			{Name: "mime_type", Kind: ContentFieldString, MaxLength: 128},
+		}, extra...)
+	}
+
+	return []*ContentType{
+		{Type: 1, Name: "text", PlainText: true},
+		{Type: 2, Name: "image", PreviewLabel: "[图片]", Fields: mediaFields(
+			ContentField{Name: "width", Kind: ContentFieldNumber},
+			ContentField{Name: "height", Kind: ContentFieldNumber},
+			ContentField{Name: "thumbnail_url", Kind: ContentFieldURL},
+		)},
+		{Type: 3, Name: "voice", PreviewLabel: "[语音]", Fields: mediaFields(
+			ContentField{Name: "duration", Kind: ContentFieldNumber, Required: true},
+		)},
+		{Type: 4, Name: "video", PreviewLabel: "[视频]", Fields: mediaFields(
+			ContentField{Name: "duration", Kind: ContentFieldNumber, Required: true},
+			ContentField{Name: "width", Kind: ContentFieldNumber},
+			ContentField{Name: "height", Kind: ContentFieldNumber},
+			ContentField{Name: "thumbnail_url", Kind: ContentFieldURL},
+		)},
+		{Type: 5, Name: "file", PreviewLabel: "[文件]", PreviewField: "name", Fields: mediaFields(
+			ContentField{Name: "name", Kind: ContentFieldString, Required: true, MaxLength: 255},
+		)},
+		{Type: model.MessageTypeMergedForward, Name: "merged_forward", PreviewLabel: "[聊天记录]", ServerOnly: true},
+	}
+}
+
+// RegisterType 注册应用自定义的消息类型（在启动时调用，类型值需要 >= 100 且不能重复）
+func (s *ContentService) RegisterType(ct *ContentType) error {
+	if ct.Type < minCustomContentType {
+		return fmt.Errorf("custom message type %d must be >= %d", ct.Type, minCustomContentType)
+	}
+	if ct.Name == "" {
+		return fmt.Errorf("custom message type %d has no name", ct.Type)
+	}
+	for _, f := range ct.Fields {
+		switch f.Kind {
+		case ContentFieldString, ContentFieldURL, ContentFieldNumber, ContentFieldBool:
+		default:
+			return fmt.Errorf("custom message type %d: field %q has unsupported kind %q", ct.Type, f.Name, f.Kind)
+		}
+	}
+	if ct.PreviewLabel == "" {
+		ct.PreviewLabel = "[" + ct.Name + "]"
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.types[ct.Type]; exists {
+		return fmt.Errorf("message type %d is already registered", ct.Type)
+	}
+	s.types[ct.Type] = ct
+	return nil
+}
+
+// lookup 查找消息类型定义
+func (s *ContentService) lookup(messageType int) (*ContentType, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ct, ok := s.types[messageType]
+	return ct, ok
+}
+
+// Validate 校验客户端提交的消息内容（发送、编辑、定时消息）
+func (s *ContentService) Validate(messageType int, content string) error {
+	ct, ok := s.lookup(messageType)
+	if !ok {
+		return fmt.Errorf("%w: unknown message type %d", ErrInvalidContent, messageType)
+	}
+	if ct.ServerOnly {
+		return fmt.Errorf("%w: message type %s cannot be sent by clients", ErrInvalidContent, ct.Name)
+	}
+	if s.maxLength > 0 && len(content) > s.maxLength {
+		return ErrContentTooLong
+	}
+	if !utf8.ValidString(content) {
+		return fmt.Errorf("%w: content is not valid UTF-8", ErrInvalidContent)
+	}
+
+	if ct.PlainText {
+		if strings.TrimSpace(content) == "" {
+			return fmt.Errorf("%w: text content is empty", ErrInvalidContent)
+		}
+		return nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(content), &fields); err != nil || fields == nil {
+		return fmt.Errorf("%w: %s content must be a JSON object", ErrInvalidContent, ct.Name)
+	}
+	for _, f := range ct.Fields {
+		if err := validateContentField(f, fields[f.Name]); err != nil {
+			return fmt.Errorf("%w: %s.%s %s", ErrInvalidContent, ct.Name, f.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// validateContentField 校验单个字段（返回的错误只包含原因）
+func validateContentField(f ContentField, value interface{}) error {
+	if value == nil {
+		if f.Required {
+			return errors.New("is required")
+		}
+		return nil
+	}
+
+	switch f.Kind {
+	case ContentFieldString, ContentFieldURL:
+		str, ok := value.(string)
+		if !ok {
+			return errors.New("must be a string")
+		}
+		if f.Required && strings.TrimSpace(str) == "" {
+			return errors.New("is required")
+		}
+		if f.MaxLength > 0 && utf8.RuneCountInString(str) > f.MaxLength {
+			return fmt.Errorf("exceeds %d characters", f.MaxLength)
+		}
+		if f.Kind == ContentFieldURL && str != "" {
+			u, err := url.Parse(str)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return errors.New("must be an http(s) URL")
+			}
+		}
+	case ContentFieldNumber:
+		num, ok := value.(float64)
+		if !ok {
+			return errors.New("must be a number")
+		}
+		if num < 0 || (f.Required && num == 0) {
+			return errors.New("must be positive")
+		}
+	case ContentFieldBool:
+		if _, ok := value.(bool); !ok {
+			return errors.New("must be a boolean")
+		}
+	}
+	return nil
+}
+
+// Preview 生成消息的纯文本预览（会话最后一条消息、离线推送）
+func (s *ContentService) Preview(messageType int, content string) string {
+	ct, ok := s.lookup(messageType)
+	if !ok {
+		return "[消息]"
+	}
+	if ct.PlainText {
+		return truncatePreview(strings.Join(strings.Fields(content), " "))
+	}
+
+	preview := ct.PreviewLabel
+	if ct.PreviewField != "" {
+		var fields map[string]interface{}
+		if err := json.Unmarshal([]byte(content), &fields); err == nil {
+			if v, ok := fields[ct.PreviewField].(string); ok && v != "" {
+				preview += " " + strings.Join(strings.Fields(v), " ")
+			}
+		}
+	}
+	return truncatePreview(preview)
+}
+
+// truncatePreview 截断过长的预览
+func truncatePreview(text string) string {
+	runes := []rune(text)
+	if len(runes) > contentPreviewMaxRunes {
+		return string(runes[:contentPreviewMaxRunes]) + "..."
+	}
+	return text
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+// newTestContentService 创建注册了自定义卡片类型（100）的内容服务
+func newTestContentService(t *testing.T, maxLength int) *ContentService {
+	t.Helper()
+	s := NewContentService(maxLength)
+	err := s.RegisterType(&ContentType{
+		Type: 100,
+		Name: "card",
+		Fields: []ContentField{
+			{Name: "title", Kind: ContentFieldString, Required: true, MaxLength: 4},
+			{Name: "pinned", Kind: ContentFieldBool},
+		},
+		PreviewField: "title",
+	})
+	if err != nil {
+		t.Fatalf("RegisterType() error = %v", err)
+	}
+	return s
+}
+
+func TestContentServiceValidatesEdits(t *testing.T) {
+	setupTestDB(t, messageTestModels...)
+	s := NewMessageService(time.Hour, newTestContentService(t, 64))
+
+	saveTyped := func(messageType int, content string) *model.Message {
+		msg := &model.Message{ClientMsgID: utils.GenerateID(), ConversationID: "single_a_b", SenderID: "a", MessageType: messageType, Content: content, ServerTime: time.Now().UnixMilli(), Status: 1}
+		if err := s.SaveMessage(msg); err != nil {
+			t.Fatalf("SaveMessage() error = %v", err)
+		}
+		return msg
+	}
+	text := saveTyped(1, "hello")
+	image := saveTyped(2, `{"url":"https://example.com/a.png","size":10}`)
+	card := saveTyped(100, `{"title":"标题"}`)
+	merged := saveTyped(model.MessageTypeMergedForward, `{"title":"聊天记录"}`)
+
+	tests := []struct {
+		name    string
+		msg     *model.Message
+		content string
+		wantErr error
+	}{
+		{"blank text", text, "  \n", ErrInvalidContent},
+		{"text over max length", text, strings.Repeat("a", 65), ErrContentTooLong},
+		{"invalid utf8", text, "\xff\xfe", ErrInvalidContent},
+		{"image url scheme", image, `{"url":"javascript:alert(1)","size":10}`, ErrInvalidContent},
+		{"image missing size", image, `{"url":"https://example.com/b.png"}`, ErrInvalidContent},
+		{"card title too long", card, `{"title":"abcde"}`, ErrInvalidContent},
+		{"card bool field", card, `{"title":"a","pinned":"yes"}`, ErrInvalidContent},
+		{"server only type", merged, `{"title":"x"}`, ErrInvalidContent},
+		{"valid text", text, "hello!", nil},
+		{"valid image", image, `{"url":"https://example.com/b.png","size":20,"width":100}`, nil},
+		{"valid card with undeclared field", card, `{"title":"新标题","extra":[1]}`, nil},
+	}
+	for _, tt := range tests {
+		_, err := s.EditMessage(tt.msg.ServerMsgID, "a", tt.content, tt.msg.ServerTime)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: EditMessage() error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestContentServiceValidatesScheduledMessages(t *testing.T) {
+	setupTestDB(t, &model.ScheduledMessage{}, &model.GroupMember{})
+	groupService := NewGroupService(repository.DB)
+	s := NewScheduledMessageService(groupService, NewMentionService(groupService), newTestContentService(t, 0))
+	ctx := context.Background()
+	at := time.Now().Add(time.Hour).UnixMilli()
+
+	err := s.CreateScheduledMessage(ctx, &model.ScheduledMessage{SenderID: "a", ReceiverID: "b", MessageType: 99, Content: "x", ScheduledAt: at})
+	if !errors.Is(err, ErrInvalidContent) {
+		t.Errorf("CreateScheduledMessage() unknown type error = %v, want %v", err, ErrInvalidContent)
+	}
+	sm := &model.ScheduledMessage{SenderID: "a", ReceiverID: "b", MessageType: 100, Content: `{"title":"卡片"}`, ScheduledAt: at}
+	if err := s.CreateScheduledMessage(ctx, sm); err != nil {
+		t.Fatalf("CreateScheduledMessage() error = %v", err)
+	}
+	if _, err := s.EditScheduledMessage(sm.ID, "a", `{"pinned":true}`, 0); !errors.Is(err, ErrInvalidContent) {
+		t.Errorf("EditScheduledMessage() missing title error = %v, want %v", err, ErrInvalidContent)
+	}
+}
+
+func TestContentServiceRegisterTypeAndPreview(t *testing.T) {
+	s := newTestContentService(t, 0)
+
+	if err := s.RegisterType(&ContentType{Type: 100, Name: "card"}); err == nil {
+		t.Error("RegisterType() duplicate type: expected error")
+	}
+	if err := s.RegisterType(&ContentType{Type: 2, Name: "image2"}); err == nil {
+		t.Error("RegisterType() reserved type: expected error")
+	}
+	if err := s.RegisterType(&ContentType{Type: 101, Name: "x", Fields: []ContentField{{Name: "a", Kind: "object"}}}); err == nil {
+		t.Error("RegisterType() unsupported field kind: expected error")
+	}
+
+	push := NewOfflinePushService(nil, s, nil)
+	previews := map[*model.Message]string{
+		{MessageType: 1, Content: "hello \n  world"}:                                              "hello world",
+		{MessageType: 1, Content: strings.Repeat("字", contentPreviewMaxRunes+1)}:                  strings.Repeat("字", contentPreviewMaxRunes) + "...",
+		{MessageType: 5, Content: `{"name":"report  2024.pdf"}`}:                                  "[文件] report 2024.pdf",
+		{MessageType: 100, Content: `{"title":"卡片"}`}:                                             "[card] 卡片",
+		{MessageType: 99, Content: "x"}:                                                           "[消息]",
+		{MessageType: 1, Content: "secret", ExpireMode: model.ExpireModeAfterSend, ExpireTTL: 10}: "[阅后即焚消息]",
+	}
+	for msg, want := range previews {
+		if got := push.messagePreview(msg); got != want {
+			t.Errorf("messagePreview(%d, %q) = %q, want %q", msg.MessageType, msg.Content, got, want)
+		}
+	}
+}
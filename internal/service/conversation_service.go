@@ -1,12 +1,41 @@
 package service
 
 import (
+	"errors"
+	"sort"
+
 	"github.com/arwen/im-server/internal/model"
 	"github.com/arwen/im-server/internal/repository"
 	"github.com/arwen/im-server/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	conversationUpsertBatch  = 200
+	defaultConversationLimit = 100
+	maxConversationLimit     = 500
+	maxConversationExtraLen  = 1024
+	maxDraftLen              = 4096
+)
+
+// 会话个人设置字段（修改设置时指定要修改的字段）
+const (
+	ConversationFieldMuteUntil = "mute_until"
+	ConversationFieldPinOrder  = "pin_order"
+	ConversationFieldHidden    = "hidden"
+	ConversationFieldExtra     = "extra"
 )
 
-// ConversationService 会话服务
+// ConversationSettings 用户对会话的个人设置
+type ConversationSettings struct {
+	MuteUntil int64  // 0: 关闭免打扰, -1: 永久, >0: 免打扰到该时间（毫秒）
+	PinOrder  int64  // 0: 不置顶, >0: 置顶顺序（越大越靠前）
+	Hidden    bool   // 隐藏会话（有新消息时自动取消隐藏）
+	Extra     string // 自定义字段（如聊天背景、备注标签）
+}
+
+// ConversationService 会话服务：维护每个用户的会话列表（最后一条消息、未读数、版本号）
 type ConversationService struct{}
 
 // NewConversationService 创建会话服务
@@ -14,65 +43,382 @@ func NewConversationService() *ConversationService {
 	return &ConversationService{}
 }
 
-// GetOrCreateConversation 获取或创建会话
-func (s *ConversationService) GetOrCreateConversation(userID, targetID string, convType int) (*model.Conversation, error) {
-	var conv model.Conversation
-	
-	// 查询已存在的会话
-	err := repository.DB.Where("user_id = ? AND target_id = ? AND type = ?", userID, targetID, convType).
-		First(&conv).Error
-	
-	if err == nil {
-		return &conv, nil
+// errConversationUnchanged 会话没有变化，回滚事务中分配的版本号
+var errConversationUnchanged = errors.New("conversation unchanged")
+
+// InitVersion 启动时为已有会话的用户创建版本序列，并保证不小于该用户会话中已有的最大版本号
+func (s *ConversationService) InitVersion() error {
+	var rows []*model.ConversationVersion
+	err := repository.DB.Model(&model.Conversation{}).
+		Select("user_id, MAX(version) AS version").
+		Group("user_id").
+		Scan(&rows).Error
+	if err != nil || len(rows) == 0 {
+		return err
 	}
 
-	// 创建新会话
-	conv = model.Conversation{
-		ID:       utils.GenerateID(),
-		Type:     convType,
-		UserID:   userID,
-		TargetID: targetID,
-		Status:   1,
+	err = repository.DB.Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(rows, conversationUpsertBatch).Error
+	if err != nil {
+		return err
 	}
+	return repository.DB.Exec(`
+		UPDATE conversation_versions SET version = (
+			SELECT MAX(c.version) FROM conversations c WHERE c.user_id = conversation_versions.user_id
+		)
+		WHERE version < (
+			SELECT COALESCE(MAX(c.version), 0) FROM conversations c WHERE c.user_id = conversation_versions.user_id
+		)
+	`).Error
+}
 
-	if err := repository.DB.Create(&conv).Error; err != nil {
+// nextConversationVersions 在事务 tx 中为每个用户生成新的会话列表版本号
+// 用户的版本号所在的行被锁定到事务提交，同一用户的版本号提交顺序与大小顺序一致：
+// 客户端同步到某个版本号之后，不会再有更小的版本号提交（否则增量同步会永久漏掉这些会话）
+// 版本号按用户独立递增，修改不同用户会话的事务互不等待
+func nextConversationVersions(tx *gorm.DB, userIDs []string) (map[string]int64, error) {
+	versions := make(map[string]int64, len(userIDs))
+	ids := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if _, ok := versions[userID]; !ok {
+			versions[userID] = 0
+			ids = append(ids, userID)
+		}
+	}
+	if len(ids) == 0 {
+		return versions, nil
+	}
+	// 按固定顺序加锁，避免同时修改多个用户会话的事务互相死锁
+	sort.Strings(ids)
+
+	rows := make([]*model.ConversationVersion, 0, len(ids))
+	for _, userID := range ids {
+		rows = append(rows, &model.ConversationVersion{UserID: userID})
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, conversationUpsertBatch).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&model.ConversationVersion{}).
+		Where("user_id IN ?", ids).
+		UpdateColumn("version", gorm.Expr("version + 1")).Error; err != nil {
 		return nil, err
 	}
 
-	return &conv, nil
+	var updated []*model.ConversationVersion
+	if err := tx.Where("user_id IN ?", ids).Find(&updated).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range updated {
+		versions[row.UserID] = row.Version
+	}
+	return versions, nil
 }
 
-// UpdateLastMessage 更新会话最后一条消息
-func (s *ConversationService) UpdateLastMessage(conversationID, messageID, lastMessage string, messageTime int64) error {
-	return repository.DB.Model(&model.Conversation{}).
-		Where("id = ?", conversationID).
+// nextConversationVersion 在事务 tx 中为用户生成新的会话列表版本号
+func nextConversationVersion(tx *gorm.DB, userID string) (int64, error) {
+	versions, err := nextConversationVersions(tx, []string{userID})
+	if err != nil {
+		return 0, err
+	}
+	return versions[userID], nil
+}
+
+// updateConversations 在事务 tx 中更新符合条件的会话，每个受影响的用户分配各自的新版本号
+func updateConversations(tx *gorm.DB, scope func(*gorm.DB) *gorm.DB, updates map[string]interface{}) error {
+	var userIDs []string
+	if err := tx.Model(&model.Conversation{}).Scopes(scope).Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+	versions, err := nextConversationVersions(tx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for userID, version := range versions {
+		columns := make(map[string]interface{}, len(updates)+1)
+		for column, value := range updates {
+			columns[column] = value
+		}
+		columns["version"] = version
+		if err := tx.Model(&model.Conversation{}).Scopes(scope).Where("user_id = ?", userID).Updates(columns).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateOnNewMessage 新消息：更新所有参与者的会话（不存在则创建；已隐藏的会话重新出现）
+// unreadCounts 为接收者更新后的未读数，发送者的未读数保持不变；unreadCounts 为 nil 表示未读数未知，已有会话的未读数保持不变
+// 最后一条消息只会被 seq 更大的消息替换（多条消息的更新乱序提交时不会回退）
+func (s *ConversationService) UpdateOnNewMessage(msg *model.Message, preview string, participants []string, unreadCounts map[string]int64) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		return updateOnNewMessage(tx, msg, preview, participants, unreadCounts)
+	})
+}
+
+// updateOnNewMessage 在事务中更新参与者的会话
+func updateOnNewMessage(tx *gorm.DB, msg *model.Message, preview string, participants []string, unreadCounts map[string]int64) error {
+	versions, err := nextConversationVersions(tx, participants)
+	if err != nil {
+		return err
+	}
+
+	var senderRows, recipientRows []*model.Conversation
+	for _, userID := range participants {
+		conv := &model.Conversation{
+			ID:             utils.GenerateID(),
+			Type:           model.ConversationTypeSingle,
+			UserID:         userID,
+			ConversationID: msg.ConversationID,
+			TargetID:       msg.GroupID,
+			LastMessageID:  msg.ServerMsgID,
+			LastMessageSeq: msg.Seq,
+			LastSenderID:   msg.SenderID,
+			LastMessage:    preview,
+			LastMessageAt:  msg.ServerTime,
+			UnreadCount:    int(unreadCounts[userID]),
+			Status:         model.ConversationStatusNormal,
+			Version:        versions[userID],
+		}
+		switch {
+		case msg.GroupID != "":
+			conv.Type = model.ConversationTypeGroup
+		case userID == msg.SenderID:
+			conv.TargetID = msg.ReceiverID
+		default:
+			conv.TargetID = msg.SenderID
+		}
+
+		if userID == msg.SenderID {
+			senderRows = append(senderRows, conv)
+		} else {
+			recipientRows = append(recipientRows, conv)
+		}
+	}
+
+	// 新会话直接写入最后一条消息；已有会话先更新状态、版本号和未读数，最后一条消息按 seq 单独更新
+	columns := []string{"status", "version", "updated_at"}
+	if err := upsertConversations(tx, senderRows, columns); err != nil {
+		return err
+	}
+	if unreadCounts != nil {
+		columns = append(columns, "unread_count")
+	}
+	if err := upsertConversations(tx, recipientRows, columns); err != nil {
+		return err
+	}
+
+	return tx.Model(&model.Conversation{}).
+		Where("conversation_id = ? AND user_id IN ? AND last_message_seq < ?", msg.ConversationID, participants, msg.Seq).
 		Updates(map[string]interface{}{
-			"last_message_id": messageID,
-			"last_message":    lastMessage,
-			"last_message_at": messageTime,
+			"last_message_id":  msg.ServerMsgID,
+			"last_message_seq": msg.Seq,
+			"last_sender_id":   msg.SenderID,
+			"last_message":     preview,
+			"last_message_at":  msg.ServerTime,
 		}).Error
 }
 
-// IncrementUnreadCount 增加未读数
-func (s *ConversationService) IncrementUnreadCount(conversationID string) error {
-	return repository.DB.Model(&model.Conversation{}).
-		Where("id = ?", conversationID).
-		Update("unread_count", repository.DB.Raw("unread_count + ?", 1)).Error
+// upsertConversations 批量创建会话，已存在时更新指定的列
+func upsertConversations(tx *gorm.DB, rows []*model.Conversation, columns []string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "conversation_id"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).CreateInBatches(rows, conversationUpsertBatch).Error
 }
 
-// ClearUnreadCount 清除未读数
-func (s *ConversationService) ClearUnreadCount(conversationID string) error {
-	return repository.DB.Model(&model.Conversation{}).
-		Where("id = ?", conversationID).
-		Update("unread_count", 0).Error
+// UpdateLastMessageContent 更新会话最后一条消息的内容预览（仅当该消息仍是会话的最后一条时生效，如消息被编辑）
+func (s *ConversationService) UpdateLastMessageContent(conversationID, serverMsgID, lastMessage string) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		return updateConversations(tx, func(db *gorm.DB) *gorm.DB {
+			return db.Where("conversation_id = ? AND last_message_id = ?", conversationID, serverMsgID)
+		}, map[string]interface{}{
+			"last_message": lastMessage,
+		})
+	})
+}
+
+// UpdateUnreadCount 更新用户会话的未读数（已读、删除等导致未读数变化时调用，未变化时不更新版本号）
+func (s *ConversationService) UpdateUnreadCount(userID, conversationID string, unreadCount int64) error {
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		version, err := nextConversationVersion(tx, userID)
+		if err != nil {
+			return err
+		}
+		result := tx.Model(&model.Conversation{}).
+			Where("user_id = ? AND conversation_id = ? AND unread_count != ?", userID, conversationID, unreadCount).
+			Updates(map[string]interface{}{
+				"unread_count": unreadCount,
+				"version":      version,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errConversationUnchanged
+		}
+		return nil
+	})
+	if errors.Is(err, errConversationUnchanged) {
+		return nil
+	}
+	return err
+}
+
+// UpdateSettings 修改用户对会话的个人设置（fields 为要修改的字段，会话不存在时先创建），返回修改后的会话
+// convType 和 targetID 仅在创建会话时使用
+func (s *ConversationService) UpdateSettings(userID, conversationID string, convType int, targetID string, settings *ConversationSettings, fields []string) (*model.Conversation, error) {
+	if len(fields) == 0 {
+		return nil, ErrInvalidSettings
+	}
+
+	updates := make(map[string]interface{}, len(fields)+1)
+	for _, field := range fields {
+		switch field {
+		case ConversationFieldMuteUntil:
+			if settings.MuteUntil < model.MuteForever {
+				return nil, ErrInvalidSettings
+			}
+			updates["mute_until"] = settings.MuteUntil
+		case ConversationFieldPinOrder:
+			if settings.PinOrder < 0 {
+				return nil, ErrInvalidSettings
+			}
+			updates["pin_order"] = settings.PinOrder
+		case ConversationFieldHidden:
+			if settings.Hidden {
+				updates["status"] = model.ConversationStatusHidden
+			} else {
+				updates["status"] = model.ConversationStatusNormal
+			}
+		case ConversationFieldExtra:
+			if len(settings.Extra) > maxConversationExtraLen {
+				return nil, ErrInvalidSettings
+			}
+			updates["extra"] = settings.Extra
+		default:
+			return nil, ErrInvalidSettings
+		}
+	}
+
+	var saved model.Conversation
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		version, err := nextConversationVersion(tx, userID)
+		if err != nil {
+			return err
+		}
+		updates["version"] = version
+
+		if err := ensureConversation(tx, newConversation(userID, conversationID, convType, targetID, version)); err != nil {
+			return err
+		}
+
+		err = tx.Model(&model.Conversation{}).
+			Where("user_id = ? AND conversation_id = ?", userID, conversationID).
+			Updates(updates).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND conversation_id = ?", userID, conversationID).First(&saved).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &saved, nil
+}
+
+// SaveDraft 保存用户在会话中的草稿（text 和 replyTo 都为空表示清除），多端修改以客户端时间最新的为准
+// 返回服务端当前的会话以及本次修改是否生效（已有更新的草稿时不生效）
+func (s *ConversationService) SaveDraft(userID, conversationID string, convType int, targetID, text, replyTo string, clientTime int64) (*model.Conversation, bool, error) {
+	if clientTime <= 0 || len(text) > maxDraftLen || len(replyTo) > 64 {
+		return nil, false, ErrInvalidDraft
+	}
+
+	var saved model.Conversation
+	applied := false
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		version, err := nextConversationVersion(tx, userID)
+		if err != nil {
+			return err
+		}
+		if err := ensureConversation(tx, newConversation(userID, conversationID, convType, targetID, version)); err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.Conversation{}).
+			Where("user_id = ? AND conversation_id = ? AND draft_time < ?", userID, conversationID, clientTime).
+			Updates(map[string]interface{}{
+				"draft_text":     text,
+				"draft_reply_to": replyTo,
+				"draft_time":     clientTime,
+				"version":        version,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		applied = result.RowsAffected > 0
+		return tx.Where("user_id = ? AND conversation_id = ?", userID, conversationID).First(&saved).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &saved, applied, nil
+}
+
+// newConversation 创建一个没有消息的会话列表项
+func newConversation(userID, conversationID string, convType int, targetID string, version int64) *model.Conversation {
+	return &model.Conversation{
+		ID:             utils.GenerateID(),
+		Type:           convType,
+		UserID:         userID,
+		ConversationID: conversationID,
+		TargetID:       targetID,
+		Status:         model.ConversationStatusNormal,
+		Version:        version,
+	}
+}
+
+// ensureConversation 会话不存在时创建（还没有消息的会话也可以设置免打扰、保存草稿）
+func ensureConversation(tx *gorm.DB, conv *model.Conversation) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "conversation_id"}},
+		DoNothing: true,
+	}).Create(conv).Error
+}
+
+// GetConversationsSince 获取用户在 version 之后发生变化的会话（按版本号升序，包括已隐藏的会话），返回是否还有更多
+func (s *ConversationService) GetConversationsSince(userID string, version int64, limit int) ([]*model.Conversation, bool, error) {
+	if limit <= 0 {
+		limit = defaultConversationLimit
+	}
+	if limit > maxConversationLimit {
+		limit = maxConversationLimit
+	}
+
+	var conversations []*model.Conversation
+	err := repository.DB.Where("user_id = ? AND version > ?", userID, version).
+		Order("version ASC").
+		Limit(limit + 1).
+		Find(&conversations).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(conversations) > limit
+	if hasMore {
+		conversations = conversations[:limit]
+	}
+	return conversations, hasMore, nil
 }
 
 // GetUserConversations 获取用户会话列表
 func (s *ConversationService) GetUserConversations(userID string) ([]*model.Conversation, error) {
 	var conversations []*model.Conversation
-	err := repository.DB.Where("user_id = ? AND status = 1", userID).
-		Order("last_message_at DESC").
+	err := repository.DB.Where("user_id = ? AND status = ?", userID, model.ConversationStatusNormal).
+		Order("pin_order DESC, last_message_at DESC").
 		Find(&conversations).Error
 	return conversations, err
 }
-
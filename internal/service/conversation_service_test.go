@@ -0,0 +1,218 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+)
+
+// newTestConversationService 创建使用测试数据库的会话服务
+func newTestConversationService(t *testing.T) *ConversationService {
+	t.Helper()
+	setupTestDB(t, &model.Conversation{}, &model.ConversationVersion{})
+	return NewConversationService()
+}
+
+// assertConversationVersions 检查增量同步返回的会话及其版本号
+func assertConversationVersions(t *testing.T, conversations []*model.Conversation, want map[string]int64) {
+	t.Helper()
+	if len(conversations) != len(want) {
+		t.Fatalf("got %d conversations, want %d", len(conversations), len(want))
+	}
+	for _, conv := range conversations {
+		if version, ok := want[conv.ConversationID]; !ok || conv.Version != version {
+			t.Errorf("conversation %s version = %d, want %d", conv.ConversationID, conv.Version, want[conv.ConversationID])
+		}
+	}
+}
+
+func TestConversationServiceVersions(t *testing.T) {
+	s := newTestConversationService(t)
+
+	// 每个用户的版本号独立递增
+	msg := &model.Message{ConversationID: "single_a_b", SenderID: "a", ReceiverID: "b", Seq: 1, ServerMsgID: "m1", ServerTime: 100}
+	if err := s.UpdateOnNewMessage(msg, "hi", []string{"a", "b"}, map[string]int64{"b": 1}); err != nil {
+		t.Fatalf("UpdateOnNewMessage() error = %v", err)
+	}
+	msg = &model.Message{ConversationID: "group_g1", GroupID: "g1", SenderID: "a", Seq: 1, ServerMsgID: "m2", ServerTime: 200}
+	if err := s.UpdateOnNewMessage(msg, "hello", []string{"a", "c"}, map[string]int64{"c": 1}); err != nil {
+		t.Fatalf("UpdateOnNewMessage() error = %v", err)
+	}
+
+	conversations, hasMore, err := s.GetConversationsSince("a", 0, 1)
+	if err != nil || !hasMore {
+		t.Fatalf("GetConversationsSince() hasMore = %v, err = %v, want more", hasMore, err)
+	}
+	assertConversationVersions(t, conversations, map[string]int64{"single_a_b": 1})
+	conversations, hasMore, err = s.GetConversationsSince("a", 1, 1)
+	if err != nil || hasMore {
+		t.Fatalf("GetConversationsSince() hasMore = %v, err = %v, want last page", hasMore, err)
+	}
+	assertConversationVersions(t, conversations, map[string]int64{"group_g1": 2})
+
+	conversations, _, err = s.GetConversationsSince("c", 0, 10)
+	if err != nil {
+		t.Fatalf("GetConversationsSince() error = %v", err)
+	}
+	assertConversationVersions(t, conversations, map[string]int64{"group_g1": 1})
+
+	// 未读数不变时不分配版本号
+	if err := s.UpdateUnreadCount("b", "single_a_b", 1); err != nil {
+		t.Fatalf("UpdateUnreadCount() unchanged error = %v", err)
+	}
+	if err := s.UpdateUnreadCount("b", "single_a_b", 0); err != nil {
+		t.Fatalf("UpdateUnreadCount() error = %v", err)
+	}
+	conversations, _, err = s.GetConversationsSince("b", 1, 10)
+	if err != nil {
+		t.Fatalf("GetConversationsSince() error = %v", err)
+	}
+	assertConversationVersions(t, conversations, map[string]int64{"single_a_b": 2})
+	if conversations[0].UnreadCount != 0 {
+		t.Errorf("unread count = %d, want 0", conversations[0].UnreadCount)
+	}
+
+	// 编辑最后一条消息时更新每个参与者的会话
+	if err := s.UpdateLastMessageContent("group_g1", "m2", "edited"); err != nil {
+		t.Fatalf("UpdateLastMessageContent() error = %v", err)
+	}
+	conversations, _, err = s.GetConversationsSince("c", 1, 10)
+	if err != nil {
+		t.Fatalf("GetConversationsSince() error = %v", err)
+	}
+	assertConversationVersions(t, conversations, map[string]int64{"group_g1": 2})
+	if conversations[0].LastMessage != "edited" {
+		t.Errorf("last message = %q, want edited", conversations[0].LastMessage)
+	}
+}
+
+func TestConversationServiceInitVersion(t *testing.T) {
+	s := newTestConversationService(t)
+	repository.DB.Create(&model.Conversation{ID: "1", UserID: "a", ConversationID: "c1", Version: 5})
+	repository.DB.Create(&model.Conversation{ID: "2", UserID: "a", ConversationID: "c2", Version: 9})
+	repository.DB.Create(&model.ConversationVersion{UserID: "b", Version: 3})
+	repository.DB.Create(&model.Conversation{ID: "3", UserID: "b", ConversationID: "c1", Version: 7})
+
+	if err := s.InitVersion(); err != nil {
+		t.Fatalf("InitVersion() error = %v", err)
+	}
+	var rows []model.ConversationVersion
+	repository.DB.Order("user_id").Find(&rows)
+	if len(rows) != 2 || rows[0].Version != 9 || rows[1].Version != 7 {
+		t.Fatalf("versions = %+v, want a=9 and b=7", rows)
+	}
+
+	msg := &model.Message{ConversationID: "c1", SenderID: "b", ReceiverID: "a", Seq: 1, ServerMsgID: "m1", ServerTime: 100}
+	if err := s.UpdateOnNewMessage(msg, "hi", []string{"a", "b"}, nil); err != nil {
+		t.Fatalf("UpdateOnNewMessage() error = %v", err)
+	}
+	conversations, _, err := s.GetConversationsSince("a", 9, 10)
+	if err != nil {
+		t.Fatalf("GetConversationsSince() error = %v", err)
+	}
+	assertConversationVersions(t, conversations, map[string]int64{"c1": 10})
+}
+
+func TestConversationServiceUpdateSettings(t *testing.T) {
+	s := newTestConversationService(t)
+
+	invalid := []struct {
+		settings ConversationSettings
+		fields   []string
+	}{
+		{ConversationSettings{}, nil},
+		{ConversationSettings{MuteUntil: -2}, []string{ConversationFieldMuteUntil}},
+		{ConversationSettings{PinOrder: -1}, []string{ConversationFieldPinOrder}},
+		{ConversationSettings{Extra: strings.Repeat("x", maxConversationExtraLen+1)}, []string{ConversationFieldExtra}},
+		{ConversationSettings{}, []string{"draft_text"}},
+	}
+	for _, tt := range invalid {
+		if _, err := s.UpdateSettings("a", "single_a_b", model.ConversationTypeSingle, "b", &tt.settings, tt.fields); err != ErrInvalidSettings {
+			t.Errorf("UpdateSettings(%+v, %v) error = %v, want %v", tt.settings, tt.fields, err, ErrInvalidSettings)
+		}
+	}
+
+	// 还没有消息的会话也可以设置，创建时使用传入的类型和目标
+	conv, err := s.UpdateSettings("a", "single_a_b", model.ConversationTypeSingle, "b",
+		&ConversationSettings{MuteUntil: model.MuteForever, PinOrder: 3}, []string{ConversationFieldMuteUntil, ConversationFieldPinOrder})
+	if err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+	if conv.TargetID != "b" || conv.MuteUntil != model.MuteForever || conv.PinOrder != 3 || conv.Version != 1 {
+		t.Errorf("UpdateSettings() = %+v", conv)
+	}
+
+	// 只修改指定的字段
+	conv, err = s.UpdateSettings("a", "single_a_b", model.ConversationTypeSingle, "b",
+		&ConversationSettings{Hidden: true, Extra: "bg=blue"}, []string{ConversationFieldHidden, ConversationFieldExtra})
+	if err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+	if conv.Status != model.ConversationStatusHidden || conv.Extra != "bg=blue" || conv.MuteUntil != model.MuteForever || conv.PinOrder != 3 || conv.Version != 2 {
+		t.Errorf("UpdateSettings() = %+v, want hidden with the earlier settings kept", conv)
+	}
+	if list, err := s.GetUserConversations("a"); err != nil || len(list) != 0 {
+		t.Errorf("GetUserConversations() = %d, %v, want the hidden conversation left out", len(list), err)
+	}
+
+	// 新消息使隐藏的会话重新出现
+	msg := &model.Message{ConversationID: "single_a_b", SenderID: "b", ReceiverID: "a", Seq: 1, ServerMsgID: "m1", ServerTime: 100}
+	if err := s.UpdateOnNewMessage(msg, "hi", []string{"a", "b"}, nil); err != nil {
+		t.Fatalf("UpdateOnNewMessage() error = %v", err)
+	}
+	list, err := s.GetUserConversations("a")
+	if err != nil || len(list) != 1 || list[0].MuteUntil != model.MuteForever || list[0].Extra != "bg=blue" {
+		t.Errorf("GetUserConversations() = %+v, %v, want the conversation back with its settings", list, err)
+	}
+}
+
+func TestConversationServiceSaveDraft(t *testing.T) {
+	s := newTestConversationService(t)
+
+	invalid := []struct {
+		text, replyTo string
+		clientTime    int64
+	}{
+		{"hi", "", 0},
+		{strings.Repeat("x", maxDraftLen+1), "", 100},
+		{"hi", strings.Repeat("x", 65), 100},
+	}
+	for _, tt := range invalid {
+		if _, _, err := s.SaveDraft("a", "group_g1", model.ConversationTypeGroup, "g1", tt.text, tt.replyTo, tt.clientTime); err != ErrInvalidDraft {
+			t.Errorf("SaveDraft(%d chars, %d chars, %d) error = %v, want %v", len(tt.text), len(tt.replyTo), tt.clientTime, err, ErrInvalidDraft)
+		}
+	}
+
+	// 多端修改以客户端时间最新的为准
+	steps := []struct {
+		text       string
+		clientTime int64
+		applied    bool
+		want       string
+	}{
+		{"from phone", 200, true, "from phone"},
+		{"stale from pc", 100, false, "from phone"},
+		{"same time", 200, false, "from phone"},
+		{"", 300, true, ""},
+	}
+	for i, step := range steps {
+		conv, applied, err := s.SaveDraft("a", "group_g1", model.ConversationTypeGroup, "g1", step.text, "m1", step.clientTime)
+		if err != nil {
+			t.Fatalf("step %d: SaveDraft() error = %v", i, err)
+		}
+		if applied != step.applied || conv.DraftText != step.want || conv.TargetID != "g1" {
+			t.Errorf("step %d: applied = %v, draft = %q, want %v and %q", i, applied, conv.DraftText, step.applied, step.want)
+		}
+	}
+
+	// 草稿变化通过增量同步到其他设备
+	conversations, _, err := s.GetConversationsSince("a", 0, 10)
+	if err != nil || len(conversations) != 1 || conversations[0].DraftTime != 300 {
+		t.Fatalf("GetConversationsSince() = %+v, %v, want the cleared draft", conversations, err)
+	}
+	if conversations, _, err = s.GetConversationsSince("a", conversations[0].Version, 10); err != nil || len(conversations) != 0 {
+		t.Errorf("GetConversationsSince() after latest version = %d, %v, want none", len(conversations), err)
+	}
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	minEphemeralTTL           = 5                // 最短阅后即焚时长（秒）
+	maxEphemeralTTL           = 7 * 24 * 60 * 60 // 最长阅后即焚时长（秒）
+	ephemeralSweepBatchSize   = 500              // 每批处理的过期消息数
+	ephemeralMaxSweepBatches  = 20               // 每轮最多处理的批数（积压时分多轮处理，避免长时间占用数据库）
+	expiredLastMessagePreview = "[消息已过期]"
+)
+
+// ExpiredMessages 同一会话中本轮过期的消息
+type ExpiredMessages struct {
+	ConversationID string
+	Message        *model.Message // 会话中的任意一条过期消息（用于确定推送对象）
+	Seqs           []int64
+	ExpireTime     int64
+}
+
+// EphemeralExpiredFunc 消息过期回调（由消息处理器实现：推送过期事件）
+type EphemeralExpiredFunc func(expired []*ExpiredMessages)
+
+// EphemeralService 阅后即焚服务
+// 消息的过期时间保存在 messages.expire_at 上，后台清理器定期删除到期消息的内容并通知会话参与者；
+// 从读取时开始计时的消息在第一次被读取时才设置过期时间
+type EphemeralService struct {
+	groupService *GroupService
+	stopCh       chan struct{}
+	stopOnce     sync.Once
+}
+
+// NewEphemeralService 创建阅后即焚服务
+func NewEphemeralService(groupService *GroupService) *EphemeralService {
+	return &EphemeralService{
+		groupService: groupService,
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// validateExpire 校验阅后即焚时长和计时方式（ttl 为 0 表示关闭）
+func validateExpire(ttl, mode int) error {
+	if ttl == 0 {
+		return nil
+	}
+	if ttl < minEphemeralTTL || ttl > maxEphemeralTTL {
+		return ErrInvalidExpireTTL
+	}
+	if mode != model.ExpireModeAfterSend && mode != model.ExpireModeAfterRead {
+		return ErrInvalidExpireTTL
+	}
+	return nil
+}
+
+// GetConversationSetting 获取会话的默认阅后即焚设置（未设置时返回关闭状态）
+func (s *EphemeralService) GetConversationSetting(conversationID string) (*model.ConversationEphemeralSetting, error) {
+	var setting model.ConversationEphemeralSetting
+	err := repository.DB.Where("conversation_id = ?", conversationID).First(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &model.ConversationEphemeralSetting{ConversationID: conversationID}, nil
+		}
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// SetConversationSetting 设置会话的默认阅后即焚（群聊仅群主/管理员，单聊双方均可；ttl 为 0 表示关闭）
+// 调用方需要先确认用户是会话参与者
+func (s *EphemeralService) SetConversationSetting(ctx context.Context, conversationID, userID string, ttl, mode int) (*model.ConversationEphemeralSetting, error) {
+	if ttl > 0 && mode == model.ExpireModeNone {
+		mode = model.ExpireModeAfterSend
+	}
+	if err := validateExpire(ttl, mode); err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		mode = model.ExpireModeNone
+	}
+
+	if strings.HasPrefix(conversationID, "group_") {
+		role, err := s.groupService.GetMemberRole(ctx, strings.TrimPrefix(conversationID, "group_"), userID)
+		if err != nil {
+			return nil, err
+		}
+		if role != 1 && role != 2 {
+			return nil, ErrPermissionDenied
+		}
+	}
+
+	setting := &model.ConversationEphemeralSetting{
+		ConversationID: conversationID,
+		ExpireTTL:      ttl,
+		ExpireMode:     mode,
+		UpdatedBy:      userID,
+	}
+	err := repository.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "conversation_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"expire_ttl", "expire_mode", "updated_by", "updated_at"}),
+	}).Create(setting).Error
+	if err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+// ApplyExpiry 设置新消息的阅后即焚信息（在 SaveMessage 之前调用）
+// ttl 为 0 时使用会话的默认设置；从发送时计时的消息直接计算过期时间
+func (s *EphemeralService) ApplyExpiry(msg *model.Message, ttl, mode int) error {
+	if ttl == 0 {
+		setting, err := s.GetConversationSetting(msg.ConversationID)
+		if err != nil {
+			return err
+		}
+		ttl, mode = setting.ExpireTTL, setting.ExpireMode
+	}
+	if ttl == 0 {
+		return nil
+	}
+	if mode == model.ExpireModeNone {
+		mode = model.ExpireModeAfterSend
+	}
+	if err := validateExpire(ttl, mode); err != nil {
+		return err
+	}
+
+	msg.ExpireTTL = ttl
+	msg.ExpireMode = mode
+	if mode == model.ExpireModeAfterSend {
+		msg.ExpireAt = msg.ServerTime + int64(ttl)*1000
+	}
+	return nil
+}
+
+// StartReadTimers 读取后开始计时：为本次读到的（fromSeq < seq <= toSeq）、尚未开始计时的消息设置过期时间（发送者本人读取不计）
+func (s *EphemeralService) StartReadTimers(conversationID, readerID string, fromSeq, toSeq int64, readTime int64) error {
+	if toSeq <= fromSeq {
+		return nil
+	}
+
+	return repository.DB.Model(&model.Message{}).
+		Where("conversation_id = ? AND seq > ? AND seq <= ?", conversationID, fromSeq, toSeq).
+		Where("expire_mode = ? AND expire_at = 0 AND sender_id != ?", model.ExpireModeAfterRead, readerID).
+		Update("expire_at", gorm.Expr("? + expire_ttl * 1000", readTime)).Error
+}
+
+// Start 启动过期消息清理器
+func (s *EphemeralService) Start(interval time.Duration, onExpired EphemeralExpiredFunc) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logger.Info("Ephemeral message sweeper started", zap.Duration("interval", interval))
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.sweep(onExpired)
+			}
+		}
+	}()
+}
+
+// Stop 停止过期消息清理器
+func (s *EphemeralService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// sweep 分批清理到期的消息（剩余的积压留到下一轮）
+func (s *EphemeralService) sweep(onExpired EphemeralExpiredFunc) {
+	for i := 0; i < ephemeralMaxSweepBatches; i++ {
+		now := utils.GetCurrentMillis()
+
+		var candidates []*model.Message
+		err := repository.DB.
+			Select("conversation_id, seq, sender_id, receiver_id, group_id").
+			Where("expire_at > 0 AND expire_at <= ? AND status != ?", now, model.MessageStatusExpired).
+			Order("expire_at ASC").
+			Limit(ephemeralSweepBatchSize).
+			Find(&candidates).Error
+		if err != nil {
+			logger.Error("Failed to query expired messages", zap.Error(err))
+			return
+		}
+		if len(candidates) == 0 {
+			return
+		}
+
+		// 按会话分组处理
+		var expired []*ExpiredMessages
+		byConversation := make(map[string]*ExpiredMessages)
+		for _, msg := range candidates {
+			e, ok := byConversation[msg.ConversationID]
+			if !ok {
+				e = &ExpiredMessages{ConversationID: msg.ConversationID, Message: msg, ExpireTime: now}
+				byConversation[msg.ConversationID] = e
+				expired = append(expired, e)
+			}
+			e.Seqs = append(e.Seqs, msg.Seq)
+		}
+
+		notified := make([]*ExpiredMessages, 0, len(expired))
+		for _, e := range expired {
+			affected, err := s.expireMessages(e.ConversationID, e.Seqs)
+			if err != nil {
+				logger.Error("Failed to expire messages", zap.Error(err), zap.String("conversation_id", e.ConversationID))
+				continue
+			}
+			// 其他节点已经处理过的不再通知
+			if affected > 0 {
+				notified = append(notified, e)
+			}
+		}
+
+		if len(notified) > 0 && onExpired != nil {
+			onExpired(notified)
+		}
+
+		logger.Info("Ephemeral messages expired",
+			zap.Int("message_count", len(candidates)),
+			zap.Int("conversation_count", len(notified)))
+
+		if len(candidates) < ephemeralSweepBatchSize {
+			return
+		}
+	}
+}
+
+// expireMessages 删除会话中过期消息的内容（包括编辑历史、引用快照、置顶和会话的最后一条消息预览）
+func (s *EphemeralService) expireMessages(conversationID string, seqs []int64) (int64, error) {
+	var affected int64
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Message{}).
+			Where("conversation_id = ? AND seq IN ? AND status != ?", conversationID, seqs, model.MessageStatusExpired).
+			Updates(map[string]interface{}{
+				"content":        "",
+				"quote_snapshot": "",
+				"status":         model.MessageStatusExpired,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+
+		if err := tx.Where("conversation_id = ? AND seq IN ?", conversationID, seqs).
+			Delete(&model.MessageEdit{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("conversation_id = ? AND seq IN ?", conversationID, seqs).
+			Delete(&model.PinnedMessage{}).Error; err != nil {
+			return err
+		}
+		// 引用了过期消息的回复：清除快照中的内容（更新时间变化后会随增量同步下发）
+		if err := tx.Model(&model.Message{}).
+			Where("conversation_id = ? AND reply_to_seq IN ? AND quote_snapshot != ''", conversationID, seqs).
+			Update("quote_snapshot", "").Error; err != nil {
+			return err
+		}
+		return updateConversations(tx, func(db *gorm.DB) *gorm.DB {
+			return db.Where("conversation_id = ? AND last_message_seq IN ?", conversationID, seqs)
+		}, map[string]interface{}{
+			"last_message": expiredLastMessagePreview,
+		})
+	})
+	return affected, err
+}
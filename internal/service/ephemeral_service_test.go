@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+func TestEphemeralServiceApplyExpiry(t *testing.T) {
+	setupTestDB(t, &model.ConversationEphemeralSetting{})
+	s := NewEphemeralService(nil)
+
+	msg := &model.Message{ConversationID: "single_a_b", ServerTime: 1000}
+	if err := s.ApplyExpiry(msg, 0, 0); err != nil || msg.ExpireTTL != 0 {
+		t.Fatalf("ApplyExpiry() without setting = ttl %d, %v, want no expiry", msg.ExpireTTL, err)
+	}
+
+	if _, err := s.SetConversationSetting(context.Background(), "single_a_b", "a", 60, model.ExpireModeAfterRead); err != nil {
+		t.Fatalf("SetConversationSetting() error = %v", err)
+	}
+	msg = &model.Message{ConversationID: "single_a_b", ServerTime: 1000}
+	if err := s.ApplyExpiry(msg, 0, 0); err != nil || msg.ExpireTTL != 60 || msg.ExpireMode != model.ExpireModeAfterRead || msg.ExpireAt != 0 {
+		t.Errorf("ApplyExpiry() with setting = %+v, %v, want read timer not started", msg, err)
+	}
+
+	// 消息指定的时长优先于会话设置
+	msg = &model.Message{ConversationID: "single_a_b", ServerTime: 1000}
+	if err := s.ApplyExpiry(msg, 10, model.ExpireModeNone); err != nil || msg.ExpireMode != model.ExpireModeAfterSend || msg.ExpireAt != 11000 {
+		t.Errorf("ApplyExpiry() explicit ttl = mode %d expire at %d, %v", msg.ExpireMode, msg.ExpireAt, err)
+	}
+	if err := s.ApplyExpiry(&model.Message{}, minEphemeralTTL-1, model.ExpireModeAfterSend); err != ErrInvalidExpireTTL {
+		t.Errorf("ApplyExpiry() short ttl error = %v, want %v", err, ErrInvalidExpireTTL)
+	}
+}
+
+func TestEphemeralServiceSweep(t *testing.T) {
+	msgService := newTestMessageService(t, &model.PinnedMessage{}, &model.Conversation{})
+	s := NewEphemeralService(nil)
+	now := utils.GetCurrentMillis()
+
+	afterRead := saveTestMessage(t, msgService, "single_a_b", "a", "read then burn")
+	expiring := saveTestMessage(t, msgService, "single_a_b", "a", "burn")
+	kept := saveTestMessage(t, msgService, "single_a_b", "a", "keep")
+	repository.DB.Model(afterRead).Updates(map[string]interface{}{"expire_mode": model.ExpireModeAfterRead, "expire_ttl": 30})
+	repository.DB.Model(expiring).Updates(map[string]interface{}{"expire_mode": model.ExpireModeAfterSend, "expire_ttl": 5, "expire_at": now - 1})
+
+	reply := &model.Message{ClientMsgID: "reply", ConversationID: "single_a_b", SenderID: "b", MessageType: 1, Content: "reply", Status: 1, ReplyToSeq: expiring.Seq}
+	if err := msgService.ResolveReplyAndThread(reply); err != nil {
+		t.Fatalf("ResolveReplyAndThread() error = %v", err)
+	}
+	if err := msgService.SaveMessage(reply); err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	if err := repository.DB.Create(&model.PinnedMessage{ID: utils.GenerateID(), ConversationID: "single_a_b", Seq: expiring.Seq, PinnedBy: "a"}).Error; err != nil {
+		t.Fatalf("create pin: %v", err)
+	}
+
+	// 发送者本人读取不开始计时
+	if err := s.StartReadTimers("single_a_b", "a", 0, kept.Seq, now); err != nil {
+		t.Fatalf("StartReadTimers() error = %v", err)
+	}
+	if saved, _ := msgService.GetMessageBySeq("single_a_b", afterRead.Seq); saved.ExpireAt != 0 {
+		t.Errorf("expire at after sender read = %d, want 0", saved.ExpireAt)
+	}
+	if err := s.StartReadTimers("single_a_b", "b", 0, kept.Seq, now); err != nil {
+		t.Fatalf("StartReadTimers() error = %v", err)
+	}
+	if saved, _ := msgService.GetMessageBySeq("single_a_b", afterRead.Seq); saved.ExpireAt != now+30000 {
+		t.Errorf("expire at after receiver read = %d, want %d", saved.ExpireAt, now+30000)
+	}
+
+	var notified []*ExpiredMessages
+	s.sweep(func(expired []*ExpiredMessages) {
+		notified = append(notified, expired...)
+	})
+	if len(notified) != 1 || len(notified[0].Seqs) != 1 || notified[0].Seqs[0] != expiring.Seq {
+		t.Fatalf("sweep() notified %+v, want only the expired message", notified)
+	}
+
+	saved, _ := msgService.GetMessageBySeq("single_a_b", expiring.Seq)
+	if saved.Status != model.MessageStatusExpired || saved.Content != "" {
+		t.Errorf("expired message = status %d content %q", saved.Status, saved.Content)
+	}
+	if saved, _ := msgService.GetMessageBySeq("single_a_b", reply.Seq); saved.QuoteSnapshot != "" {
+		t.Errorf("reply quote snapshot = %q, want cleared", saved.QuoteSnapshot)
+	}
+	var pins int64
+	repository.DB.Model(&model.PinnedMessage{}).Count(&pins)
+	if pins != 0 {
+		t.Errorf("pins = %d, want the expired message unpinned", pins)
+	}
+
+	// 已过期的消息不会重复通知
+	notified = nil
+	s.sweep(func(expired []*ExpiredMessages) {
+		notified = append(notified, expired...)
+	})
+	if len(notified) != 0 {
+		t.Errorf("second sweep() notified %d conversations, want 0", len(notified))
+	}
+}
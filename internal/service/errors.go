@@ -5,15 +5,69 @@ import "errors"
 var (
 	// Permission errors
 	ErrPermissionDenied = errors.New("permission denied")
+	ErrUserBlocked      = errors.New("blocked by or blocking the target user")
+	
+	// Message errors
+	ErrMessageNotFound     = errors.New("message not found")
+	ErrMessageRevoked      = errors.New("message has been revoked")
+	ErrEditTimeExpired     = errors.New("message edit time expired")
+	ErrMessageNotModified  = errors.New("message content not modified")
+	ErrEditConflict        = errors.New("message was edited concurrently")
+	ErrInvalidEmoji        = errors.New("invalid emoji")
+	ErrReplyTargetNotFound = errors.New("replied message not found")
+	ErrThreadRootNotFound  = errors.New("thread root message not found")
+	ErrMentionNotMember    = errors.New("mentioned user is not a group member")
+	ErrMentionAllDenied    = errors.New("only group owner or admin can mention all")
+	ErrInvalidForward      = errors.New("invalid forward sources or targets")
+	ErrTooManyPins         = errors.New("too many pinned messages")
+	ErrScheduleNotFound    = errors.New("scheduled message not found")
+	ErrScheduleNotPending  = errors.New("scheduled message is no longer pending")
+	ErrInvalidScheduleTime = errors.New("invalid scheduled time")
+	ErrTooManyScheduled    = errors.New("too many pending scheduled messages")
+	ErrNoScheduleTarget    = errors.New("scheduled message has no receiver or group")
+	ErrInvalidExpireTTL    = errors.New("invalid ephemeral message ttl or mode")
+	ErrInvalidRetention    = errors.New("invalid retention days")
+	ErrInvalidKeyword      = errors.New("invalid search keyword")
+	ErrInvalidContent      = errors.New("invalid message content")
+	ErrContentTooLong      = errors.New("message content too long")
+	ErrContentRejected     = errors.New("message content rejected by moderation")
+	ErrInvalidStrictness   = errors.New("invalid moderation strictness")
+	ErrScheduleClientMsgID = errors.New("client_msg_id of scheduled message is used by another message")
+	
+	// Conversation errors
+	ErrInvalidSettings = errors.New("invalid conversation settings")
+	ErrInvalidDraft    = errors.New("invalid draft")
 	
 	// User errors
 	ErrUserNotFound     = errors.New("user not found")
 	ErrInvalidToken     = errors.New("invalid token")
 	
+	// Presence errors
+	ErrTooManyPresenceUsers = errors.New("too many users in presence query")
+	ErrTooManySubscriptions = errors.New("too many presence subscriptions")
+	ErrInvalidPresence      = errors.New("invalid presence settings")
+	
+	// Chatroom errors
+	ErrInvalidChatroom   = errors.New("invalid chatroom id")
+	ErrNotChatroomMember = errors.New("not a chatroom member")
+	ErrChatroomFull      = errors.New("chatroom is full")
+	ErrTooManyChatrooms  = errors.New("too many joined chatrooms")
+	
 	// Group errors
 	ErrGroupNotFound       = errors.New("group not found")
 	ErrAlreadyGroupMember  = errors.New("already a group member")
 	ErrNotGroupMember      = errors.New("not a group member")
 	ErrOwnerCannotLeave    = errors.New("owner cannot leave group")
+	
+	// Media errors
+	ErrUploadNotFound   = errors.New("upload not found or expired")
+	ErrUploadIncomplete = errors.New("upload has missing chunks")
+	ErrTooManyUploads   = errors.New("too many uploads in progress")
+	ErrFileTooLarge     = errors.New("file too large")
+	ErrMimeNotAllowed   = errors.New("file type not allowed")
+	ErrInvalidChunk     = errors.New("invalid chunk index or size")
+	ErrHashMismatch     = errors.New("file hash mismatch")
+	ErrMediaNotFound    = errors.New("media file not found")
+	ErrInvalidSignature = errors.New("invalid or expired url signature")
 )
 
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+const (
+	maxForwardSources  = 100 // 单次最多转发的消息数
+	maxForwardTargets  = 20  // 单次最多转发的目标会话数
+	defaultMergedTitle = "聊天记录"
+)
+
+// ForwardSource 转发来源消息
+type ForwardSource struct {
+	ConversationID string
+	Seq            int64
+}
+
+// ForwardTarget 转发目标会话
+type ForwardTarget struct {
+	ConversationType int    // 1: 单聊, 2: 群聊
+	TargetID         string // 单聊为接收者ID，群聊为群ID
+}
+
+// ForwardService 消息转发服务
+type ForwardService struct {
+	msgService   *MessageService
+	groupService *GroupService
+	userService  *UserService
+}
+
+// NewForwardService 创建消息转发服务
+func NewForwardService(msgService *MessageService, groupService *GroupService, userService *UserService) *ForwardService {
+	return &ForwardService{
+		msgService:   msgService,
+		groupService: groupService,
+		userService:  userService,
+	}
+}
+
+// LoadSources 加载转发来源消息，并检查用户是否有权读取（必须是来源会话的参与者，消息未被撤回、未被用户删除或清空）
+// 阅后即焚消息不能转发
+func (s *ForwardService) LoadSources(ctx context.Context, userID string, sources []ForwardSource) ([]*model.Message, error) {
+	if len(sources) == 0 || len(sources) > maxForwardSources {
+		return nil, ErrInvalidForward
+	}
+
+	seen := make(map[ForwardSource]bool, len(sources))
+	messages := make([]*model.Message, 0, len(sources))
+	for _, src := range sources {
+		if seen[src] {
+			continue
+		}
+		seen[src] = true
+
+		msg, err := s.msgService.GetVisibleMessageBySeq(userID, src.ConversationID, src.Seq)
+		if err != nil || msg.Status == 4 || msg.Status == model.MessageStatusExpired {
+			return nil, ErrMessageNotFound
+		}
+		if msg.ExpireMode != model.ExpireModeNone {
+			return nil, ErrPermissionDenied
+		}
+
+		canRead, err := s.canRead(ctx, msg, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !canRead {
+			return nil, ErrPermissionDenied
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+// BuildForwardMessages 为一个目标会话构造转发消息（未保存，需要调用 SaveMessage 分配 Seq）
+// merged 为 true 时生成一条合并转发消息，否则逐条复制
+func (s *ForwardService) BuildForwardMessages(ctx context.Context, userID string, sources []*model.Message, target ForwardTarget, merged bool, title string, now int64) ([]*model.Message, error) {
+	base, err := s.resolveTarget(ctx, userID, target)
+	if err != nil {
+		return nil, err
+	}
+
+	newMessage := func(messageType int, content, forwardFromID string) *model.Message {
+		msg := *base
+		msg.ClientMsgID = utils.GenerateID()
+		msg.MessageType = messageType
+		msg.Content = content
+		msg.ForwardFromID = forwardFromID
+		msg.SendTime = now
+		msg.ServerTime = now
+		msg.Status = 1
+		return &msg
+	}
+
+	if !merged {
+		messages := make([]*model.Message, 0, len(sources))
+		for _, src := range sources {
+			messages = append(messages, newMessage(src.MessageType, src.Content, src.ServerMsgID))
+		}
+		return messages, nil
+	}
+
+	// 合并转发：按发送时间排序后打包成一条聊天记录消息
+	sorted := make([]*model.Message, len(sources))
+	copy(sorted, sources)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ServerTime < sorted[j].ServerTime
+	})
+
+	if title == "" {
+		title = defaultMergedTitle
+	}
+	content := &model.MergedForwardContent{
+		Title:    title,
+		Messages: make([]*model.QuotedMessage, 0, len(sorted)),
+	}
+	for _, src := range sorted {
+		content.Messages = append(content.Messages, &model.QuotedMessage{
+			ServerMsgID: src.ServerMsgID,
+			Seq:         src.Seq,
+			SenderID:    src.SenderID,
+			MessageType: src.MessageType,
+			Content:     src.Content,
+			SendTime:    src.SendTime,
+			Status:      src.Status,
+		})
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*model.Message{newMessage(model.MessageTypeMergedForward, string(data), "")}, nil
+}
+
+// ValidateTargetCount 检查目标会话数量
+func (s *ForwardService) ValidateTargetCount(count int) error {
+	if count == 0 || count > maxForwardTargets {
+		return ErrInvalidForward
+	}
+	return nil
+}
+
+// resolveTarget 解析目标会话，返回填好会话字段的消息模板（单聊要求接收者存在，群聊要求转发者是群成员）
+func (s *ForwardService) resolveTarget(ctx context.Context, userID string, target ForwardTarget) (*model.Message, error) {
+	if target.TargetID == "" {
+		return nil, ErrInvalidForward
+	}
+
+	msg := &model.Message{SenderID: userID}
+	switch target.ConversationType {
+	case 1:
+		if target.TargetID == userID {
+			return nil, ErrInvalidForward
+		}
+		exists, err := s.userService.UserExists(target.TargetID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrUserNotFound
+		}
+		msg.ReceiverID = target.TargetID
+	case 2:
+		isMember, err := s.groupService.IsGroupMember(ctx, target.TargetID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, ErrNotGroupMember
+		}
+		msg.GroupID = target.TargetID
+	default:
+		return nil, ErrInvalidForward
+	}
+
+	msg.ConversationID = utils.GetConversationID(target.ConversationType, userID, target.TargetID)
+	return msg, nil
+}
+
+// canRead 检查用户是否能读取消息（单聊为收发双方，群聊为当前群成员）
+func (s *ForwardService) canRead(ctx context.Context, msg *model.Message, userID string) (bool, error) {
+	if msg.GroupID != "" {
+		return s.groupService.IsGroupMember(ctx, msg.GroupID, userID)
+	}
+	return msg.SenderID == userID || msg.ReceiverID == userID, nil
+}
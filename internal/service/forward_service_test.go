@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+func TestForwardServiceLoadSources(t *testing.T) {
+	msgService := newTestMessageService(t, &model.GroupMember{}, &model.User{})
+	addTestGroupMembers(t, "g1", map[string]int{"a": 1, "b": 3})
+	s := NewForwardService(msgService, NewGroupService(repository.DB), NewUserService(""))
+	ctx := context.Background()
+
+	single := saveTestMessage(t, msgService, utils.GetConversationID(1, "a", "b"), "a", "to b")
+	repository.DB.Model(single).Update("receiver_id", "b")
+	group := saveTestMessage(t, msgService, "group_g1", "b", "in group")
+	repository.DB.Model(group).Update("group_id", "g1")
+	revoked := saveTestMessage(t, msgService, "group_g1", "b", "revoked")
+	repository.DB.Model(revoked).Updates(map[string]interface{}{"group_id": "g1", "status": 4})
+
+	sources := []ForwardSource{
+		{ConversationID: group.ConversationID, Seq: group.Seq},
+		{ConversationID: single.ConversationID, Seq: single.Seq},
+		{ConversationID: group.ConversationID, Seq: group.Seq},
+	}
+	messages, err := s.LoadSources(ctx, "a", sources)
+	if err != nil {
+		t.Fatalf("LoadSources() error = %v", err)
+	}
+	if len(messages) != 2 || messages[0].ServerMsgID != group.ServerMsgID || messages[1].ServerMsgID != single.ServerMsgID {
+		t.Errorf("LoadSources() = %d messages, want the two distinct sources in order", len(messages))
+	}
+
+	// 非会话参与者不能转发
+	if _, err := s.LoadSources(ctx, "c", sources[:1]); err != ErrPermissionDenied {
+		t.Errorf("LoadSources() by non-member error = %v, want %v", err, ErrPermissionDenied)
+	}
+	if _, err := s.LoadSources(ctx, "b", []ForwardSource{{ConversationID: revoked.ConversationID, Seq: revoked.Seq}}); err != ErrMessageNotFound {
+		t.Errorf("LoadSources() revoked error = %v, want %v", err, ErrMessageNotFound)
+	}
+	if _, err := s.LoadSources(ctx, "a", nil); err != ErrInvalidForward {
+		t.Errorf("LoadSources() empty error = %v, want %v", err, ErrInvalidForward)
+	}
+}
+
+func TestForwardServiceBuildForwardMessages(t *testing.T) {
+	msgService := newTestMessageService(t, &model.GroupMember{}, &model.User{})
+	addTestGroupMembers(t, "g1", map[string]int{"a": 3})
+	if err := repository.DB.Create(&model.User{ID: "b", Username: "b", Password: "x"}).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	s := NewForwardService(msgService, NewGroupService(repository.DB), NewUserService(""))
+	ctx := context.Background()
+
+	sources := []*model.Message{
+		{ServerMsgID: "m2", Seq: 2, SenderID: "x", MessageType: 1, Content: "second", ServerTime: 200},
+		{ServerMsgID: "m1", Seq: 1, SenderID: "y", MessageType: 1, Content: "first", ServerTime: 100},
+	}
+
+	copies, err := s.BuildForwardMessages(ctx, "a", sources, ForwardTarget{ConversationType: 1, TargetID: "b"}, false, "", 1000)
+	if err != nil {
+		t.Fatalf("BuildForwardMessages() error = %v", err)
+	}
+	if len(copies) != 2 || copies[0].ForwardFromID != "m2" || copies[0].ReceiverID != "b" || copies[0].ConversationID != utils.GetConversationID(1, "a", "b") {
+		t.Errorf("BuildForwardMessages() copies = %+v", copies[0])
+	}
+
+	merged, err := s.BuildForwardMessages(ctx, "a", sources, ForwardTarget{ConversationType: 2, TargetID: "g1"}, true, "", 1000)
+	if err != nil {
+		t.Fatalf("BuildForwardMessages() merged error = %v", err)
+	}
+	if len(merged) != 1 || merged[0].MessageType != model.MessageTypeMergedForward || merged[0].GroupID != "g1" {
+		t.Fatalf("BuildForwardMessages() merged = %+v", merged)
+	}
+	var content model.MergedForwardContent
+	if err := json.Unmarshal([]byte(merged[0].Content), &content); err != nil {
+		t.Fatalf("merged content: %v", err)
+	}
+	if content.Title != defaultMergedTitle || len(content.Messages) != 2 || content.Messages[0].ServerMsgID != "m1" {
+		t.Errorf("merged content = %+v, want default title and messages sorted by time", content)
+	}
+
+	targets := []struct {
+		target ForwardTarget
+		want   error
+	}{
+		{ForwardTarget{ConversationType: 1, TargetID: "missing"}, ErrUserNotFound},
+		{ForwardTarget{ConversationType: 1, TargetID: "a"}, ErrInvalidForward},
+		{ForwardTarget{ConversationType: 2, TargetID: "g2"}, ErrNotGroupMember},
+		{ForwardTarget{ConversationType: 3, TargetID: "x"}, ErrInvalidForward},
+	}
+	for _, tt := range targets {
+		if _, err := s.BuildForwardMessages(ctx, "a", sources, tt.target, false, "", 1000); err != tt.want {
+			t.Errorf("BuildForwardMessages(%+v) error = %v, want %v", tt.target, err, tt.want)
+		}
+	}
+}
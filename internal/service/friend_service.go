@@ -0,0 +1,84 @@
+package service
+
+import (
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+)
+
+// FriendService 好友关系服务（好友和拉黑关系的查询）
+type FriendService struct{}
+
+// NewFriendService 创建好友关系服务
+func NewFriendService() *FriendService {
+	return &FriendService{}
+}
+
+// GetBlockers 返回 userIDs 中拉黑了 targetID 的用户（map[userID]）
+func (s *FriendService) GetBlockers(targetID string, userIDs []string) (map[string]bool, error) {
+	blockers := make(map[string]bool)
+	if len(userIDs) == 0 {
+		return blockers, nil
+	}
+
+	var ids []string
+	err := repository.DB.Model(&model.Friend{}).
+		Where("friend_id = ? AND user_id IN ? AND status = ?", targetID, userIDs, model.FriendStatusBlocked).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		blockers[id] = true
+	}
+	return blockers, nil
+}
+
+// IsBlockedEither 两个用户之间是否有一方拉黑了另一方
+func (s *FriendService) IsBlockedEither(userA, userB string) (bool, error) {
+	var count int64
+	err := repository.DB.Model(&model.Friend{}).
+		Where("((user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)) AND status = ?",
+			userA, userB, userB, userA, model.FriendStatusBlocked).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetFriendsAmong 返回 candidateIDs 中在 userID 好友列表里的用户（map[userID]）
+func (s *FriendService) GetFriendsAmong(userID string, candidateIDs []string) (map[string]bool, error) {
+	friends := make(map[string]bool)
+	if len(candidateIDs) == 0 {
+		return friends, nil
+	}
+
+	var ids []string
+	err := repository.DB.Model(&model.Friend{}).
+		Where("user_id = ? AND friend_id IN ? AND status = ?", userID, candidateIDs, model.FriendStatusNormal).
+		Pluck("friend_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		friends[id] = true
+	}
+	return friends, nil
+}
+
+// GetFriendOwners 返回 userIDs 中把 friendID 加为好友的用户（map[userID]）
+func (s *FriendService) GetFriendOwners(friendID string, userIDs []string) (map[string]bool, error) {
+	owners := make(map[string]bool)
+	if len(userIDs) == 0 {
+		return owners, nil
+	}
+
+	var ids []string
+	err := repository.DB.Model(&model.Friend{}).
+		Where("friend_id = ? AND user_id IN ? AND status = ?", friendID, userIDs, model.FriendStatusNormal).
+		Pluck("user_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		owners[id] = true
+	}
+	return owners, nil
+}
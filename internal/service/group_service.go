@@ -327,6 +327,36 @@ func (s *GroupService) GetGroupMembers(ctx context.Context, groupID string) ([]*
 	return users, nil
 }
 
+// GetGroupMemberIDs 获取群成员 ID 列表（用于消息推送等只需要用户 ID 的场景）
+func (s *GroupService) GetGroupMemberIDs(ctx context.Context, groupID string) ([]string, error) {
+	var userIDs []string
+
+	err := s.db.Model(&model.GroupMember{}).
+		Where("group_id = ? AND status = 1", groupID).
+		Pluck("user_id", &userIDs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+// GetActiveMembers 获取群成员记录（包含角色和入群时间）
+func (s *GroupService) GetActiveMembers(ctx context.Context, groupID string) ([]*model.GroupMember, error) {
+	var members []*model.GroupMember
+
+	err := s.db.
+		Where("group_id = ? AND status = 1", groupID).
+		Find(&members).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
 // IsGroupMember 检查是否是群成员
 func (s *GroupService) IsGroupMember(ctx context.Context, groupID, userID string) (bool, error) {
 	var count int64
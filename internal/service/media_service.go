@@ -0,0 +1,659 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // 注册 GIF 解码器
+	"image/jpeg"
+	_ "image/png" // 注册 PNG 解码器
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	maxActiveUploads       = 20       // 每个用户同时进行的上传任务数上限
+	maxThumbnailPixels     = 40000000 // 生成缩略图的图片像素上限（防止解压炸弹）
+	mediaCleanupBatchSize  = 100      // 每轮清理的过期上传任务数
+	thumbnailJPEGQuality   = 80
+	thumbnailSampleDensity = 4 // 缩略图每个像素在源图中的采样点数（每个方向）
+)
+
+// 签名 URL 的文件变体
+const (
+	MediaVariantFile      = "file"
+	MediaVariantThumbnail = "thumbnail"
+)
+
+var sha256HexPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// MediaConfig 媒体上传配置
+type MediaConfig struct {
+	MaxFileSize      int64         // 单个文件大小上限（字节）
+	ChunkSize        int64         // 分片大小（字节）
+	AllowedMimeTypes []string      // 允许的文件类型（支持 image/* 形式的通配）
+	URLSecret        string        // 下载地址签名密钥
+	URLExpire        time.Duration // 下载地址有效期
+	UploadExpire     time.Duration // 上传任务有效期（超时未完成的任务被清理）
+	ThumbnailSize    int           // 缩略图最长边（像素）
+}
+
+// MediaService 媒体文件上传服务
+// 客户端先创建上传任务，再按分片上传（可断点续传），全部分片到齐后由服务端合并、计算 SHA-256、
+// 检测文件类型并生成图片缩略图；内容相同的文件只存储一份。
+// 去重只在服务端合并后进行，不支持客户端声明哈希直接秒传（避免仅凭哈希值获取他人的文件）
+type MediaService struct {
+	storage  MediaStorage
+	config   MediaConfig
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMediaService 创建媒体文件上传服务
+func NewMediaService(storage MediaStorage, config MediaConfig) *MediaService {
+	return &MediaService{
+		storage: storage,
+		config:  config,
+		stopCh:  make(chan struct{}),
+	}
+}
+
+// InitUpload 创建分片上传任务（hash 为客户端声明的 SHA-256，可为空，完成时校验）
+func (s *MediaService) InitUpload(userID, fileName string, size int64, mimeType, hash string) (*model.MediaUpload, error) {
+	if size <= 0 {
+		return nil, ErrInvalidChunk
+	}
+	if size > s.config.MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+	mimeType = normalizeMimeType(mimeType)
+	if mimeType != "" && !s.mimeAllowed(mimeType) {
+		return nil, ErrMimeNotAllowed
+	}
+	hash = strings.ToLower(hash)
+	if hash != "" && !sha256HexPattern.MatchString(hash) {
+		return nil, ErrHashMismatch
+	}
+
+	var active int64
+	if err := repository.DB.Model(&model.MediaUpload{}).
+		Where("user_id = ? AND status = ? AND expires_at > ?", userID, model.MediaUploadStatusUploading, time.Now()).
+		Count(&active).Error; err != nil {
+		return nil, err
+	}
+	if active >= maxActiveUploads {
+		return nil, ErrTooManyUploads
+	}
+
+	upload := &model.MediaUpload{
+		ID:         utils.GenerateID(),
+		UserID:     userID,
+		FileName:   fileName,
+		Size:       size,
+		MimeType:   mimeType,
+		Hash:       hash,
+		ChunkSize:  s.config.ChunkSize,
+		ChunkCount: int((size + s.config.ChunkSize - 1) / s.config.ChunkSize),
+		Status:     model.MediaUploadStatusUploading,
+		ExpiresAt:  time.Now().Add(s.config.UploadExpire),
+	}
+	if err := repository.DB.Create(upload).Error; err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// GetUpload 获取上传任务及已接收的分片序号（用于断点续传）
+func (s *MediaService) GetUpload(userID, uploadID string) (*model.MediaUpload, []int, error) {
+	upload, err := s.getUpload(userID, uploadID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var received []int
+	if err := repository.DB.Model(&model.MediaUploadChunk{}).
+		Where("upload_id = ?", uploadID).
+		Order("chunk_index ASC").
+		Pluck("chunk_index", &received).Error; err != nil {
+		return nil, nil, err
+	}
+	return upload, received, nil
+}
+
+// UploadChunk 上传一个分片（重复上传同一分片会覆盖）
+// 除最后一个分片外，每个分片的大小必须等于任务的分片大小
+func (s *MediaService) UploadChunk(userID, uploadID string, index int, r io.Reader) error {
+	upload, err := s.getUpload(userID, uploadID)
+	if err != nil {
+		return err
+	}
+	if upload.Status != model.MediaUploadStatusUploading {
+		return nil
+	}
+	if index < 0 || index >= upload.ChunkCount {
+		return ErrInvalidChunk
+	}
+
+	expected := upload.ChunkSize
+	if index == upload.ChunkCount-1 {
+		expected = upload.Size - upload.ChunkSize*int64(upload.ChunkCount-1)
+	}
+
+	key := chunkStorageKey(uploadID, index)
+	n, err := s.storage.Put(key, io.LimitReader(r, expected+1))
+	if err != nil {
+		return err
+	}
+	if n != expected {
+		s.storage.Delete(key)
+		return ErrInvalidChunk
+	}
+
+	return repository.DB.Save(&model.MediaUploadChunk{
+		UploadID:   uploadID,
+		ChunkIndex: index,
+		Size:       n,
+		CreatedAt:  time.Now(),
+	}).Error
+}
+
+// CompleteUpload 合并分片并保存文件（重复调用返回同一文件）
+func (s *MediaService) CompleteUpload(userID, uploadID string) (*model.MediaFile, error) {
+	upload, err := s.getUpload(userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status == model.MediaUploadStatusCompleted {
+		return s.GetFile(upload.FileID)
+	}
+
+	var received int64
+	if err := repository.DB.Model(&model.MediaUploadChunk{}).
+		Where("upload_id = ?", uploadID).
+		Count(&received).Error; err != nil {
+		return nil, err
+	}
+	if int(received) != upload.ChunkCount {
+		return nil, ErrUploadIncomplete
+	}
+
+	// 第一遍：计算哈希并检测文件类型
+	hash, sniffed, err := s.hashChunks(upload)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Hash != "" && upload.Hash != hash {
+		return nil, ErrHashMismatch
+	}
+	mimeType := sniffed
+	if (sniffed == "application/octet-stream" || sniffed == "text/plain") && declaredMimeAllowed(upload.MimeType) {
+		// 无法从内容识别的类型使用客户端声明的类型
+		mimeType = upload.MimeType
+	}
+	if !s.mimeAllowed(mimeType) {
+		return nil, ErrMimeNotAllowed
+	}
+
+	file, err := s.getFileByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		// 第二遍：写入正式存储
+		file, err = s.storeFile(upload, hash, mimeType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := repository.DB.Model(&model.MediaUpload{}).
+		Where("id = ?", uploadID).
+		Updates(map[string]interface{}{
+			"status":  model.MediaUploadStatusCompleted,
+			"file_id": file.ID,
+		}).Error; err != nil {
+		return nil, err
+	}
+	s.deleteChunks(upload)
+
+	logger.Info("Media upload completed",
+		zap.String("upload_id", uploadID),
+		zap.String("file_id", file.ID),
+		zap.String("user_id", userID),
+		zap.Int64("size", file.Size),
+		zap.String("mime_type", file.MimeType))
+
+	return file, nil
+}
+
+// declaredMimeAllowed 客户端声明的类型是否可以采用：图片类型只能由内容识别（防止把 SVG 等可执行脚本的内容声明为图片），
+// 浏览器会渲染的 HTML/XML 类型也不采用
+func declaredMimeAllowed(mimeType string) bool {
+	if mimeType == "" {
+		return false
+	}
+	return !strings.HasPrefix(mimeType, "image/") && !IsActiveMimeType(mimeType)
+}
+
+// IsActiveMimeType 浏览器会作为文档渲染（可能执行脚本）的类型
+func IsActiveMimeType(mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.SplitN(mimeType, ";", 2)[0]))
+	switch {
+	case mimeType == "text/html", mimeType == "text/xml", mimeType == "application/xml":
+		return true
+	case strings.HasSuffix(mimeType, "+xml"): // image/svg+xml、application/xhtml+xml 等
+		return true
+	}
+	return false
+}
+
+// CanAccessFile 用户能否获取文件的下载地址：上传过该文件，或参与的会话中有消息引用了该文件
+func (s *MediaService) CanAccessFile(userID string, file *model.MediaFile) (bool, error) {
+	if file.UploaderID == userID {
+		return true, nil
+	}
+
+	var count int64
+	if err := repository.DB.Model(&model.MediaUpload{}).
+		Where("user_id = ? AND file_id = ?", userID, file.ID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+
+	// 消息内容中的下载地址包含 /api/media/files/{fileID}
+	pattern := "%/api/media/files/" + escapeLike(file.ID) + "%"
+	groups := repository.DB.Model(&model.GroupMember{}).
+		Select("group_id").
+		Where("user_id = ? AND status = 1", userID)
+	if err := repository.DB.Model(&model.Message{}).
+		Where("content LIKE ?", pattern).
+		Where("sender_id = ? OR receiver_id = ? OR group_id IN (?)", userID, userID, groups).
+		Limit(1).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// escapeLike 转义 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// GetFile 获取文件信息
+func (s *MediaService) GetFile(fileID string) (*model.MediaFile, error) {
+	var file model.MediaFile
+	if err := repository.DB.Where("id = ?", fileID).First(&file).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMediaNotFound
+		}
+		return nil, err
+	}
+	return &file, nil
+}
+
+// OpenFile 打开文件内容（variant 为 file 或 thumbnail）
+func (s *MediaService) OpenFile(file *model.MediaFile, variant string) (io.ReadSeekCloser, error) {
+	key := file.StorageKey
+	if variant == MediaVariantThumbnail {
+		if file.ThumbnailKey == "" {
+			return nil, ErrMediaNotFound
+		}
+		key = file.ThumbnailKey
+	}
+	return s.storage.Open(key)
+}
+
+// SignURL 生成下载地址签名，返回过期时间（Unix 秒）和签名
+func (s *MediaService) SignURL(fileID, variant string) (expires int64, signature string) {
+	expires = time.Now().Add(s.config.URLExpire).Unix()
+	return expires, s.sign(fileID, variant, expires)
+}
+
+// VerifyURL 校验下载地址签名
+func (s *MediaService) VerifyURL(fileID, variant string, expires int64, signature string) error {
+	if expires < time.Now().Unix() {
+		return ErrInvalidSignature
+	}
+	expected := s.sign(fileID, variant, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// sign HMAC-SHA256(fileID:variant:expires)
+func (s *MediaService) sign(fileID, variant string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.config.URLSecret))
+	fmt.Fprintf(mac, "%s:%s:%d", fileID, variant, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Start 启动过期上传任务清理
+func (s *MediaService) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logger.Info("Media upload cleanup started", zap.Duration("interval", interval))
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.cleanupExpiredUploads()
+			}
+		}
+	}()
+}
+
+// Stop 停止过期上传任务清理
+func (s *MediaService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// cleanupExpiredUploads 删除过期的上传任务及其分片
+func (s *MediaService) cleanupExpiredUploads() {
+	for {
+		var uploads []*model.MediaUpload
+		if err := repository.DB.Where("expires_at < ?", time.Now()).
+			Limit(mediaCleanupBatchSize).
+			Find(&uploads).Error; err != nil {
+			logger.Error("Failed to query expired uploads", zap.Error(err))
+			return
+		}
+		if len(uploads) == 0 {
+			return
+		}
+
+		ids := make([]string, 0, len(uploads))
+		for _, upload := range uploads {
+			if upload.Status == model.MediaUploadStatusUploading {
+				s.deleteChunks(upload)
+			}
+			ids = append(ids, upload.ID)
+		}
+		if err := repository.DB.Where("upload_id IN ?", ids).Delete(&model.MediaUploadChunk{}).Error; err != nil {
+			logger.Error("Failed to delete upload chunks", zap.Error(err))
+			return
+		}
+		if err := repository.DB.Where("id IN ?", ids).Delete(&model.MediaUpload{}).Error; err != nil {
+			logger.Error("Failed to delete expired uploads", zap.Error(err))
+			return
+		}
+
+		logger.Info("Expired media uploads cleaned", zap.Int("count", len(uploads)))
+
+		if len(uploads) < mediaCleanupBatchSize {
+			return
+		}
+	}
+}
+
+// getUpload 获取用户未过期的上传任务
+func (s *MediaService) getUpload(userID, uploadID string) (*model.MediaUpload, error) {
+	var upload model.MediaUpload
+	err := repository.DB.Where("id = ? AND user_id = ? AND expires_at > ?", uploadID, userID, time.Now()).
+		First(&upload).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+	return &upload, nil
+}
+
+// getFileByHash 按内容哈希查找已存储的文件（不存在返回 nil）
+func (s *MediaService) getFileByHash(hash string) (*model.MediaFile, error) {
+	var file model.MediaFile
+	if err := repository.DB.Where("hash = ?", hash).First(&file).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &file, nil
+}
+
+// hashChunks 顺序读取所有分片，计算 SHA-256 并根据开头的内容检测文件类型
+func (s *MediaService) hashChunks(upload *model.MediaUpload) (hash, mimeType string, err error) {
+	reader := s.chunkReader(upload)
+	defer reader.Close()
+
+	hasher := sha256.New()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", "", err
+	}
+	hasher.Write(head[:n])
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return "", "", err
+	}
+
+	return hex.EncodeToString(hasher.Sum(nil)), normalizeMimeType(http.DetectContentType(head[:n])), nil
+}
+
+// storeFile 合并分片写入正式存储，图片额外生成缩略图
+func (s *MediaService) storeFile(upload *model.MediaUpload, hash, mimeType string) (*model.MediaFile, error) {
+	file := &model.MediaFile{
+		ID:         utils.GenerateID(),
+		Hash:       hash,
+		Size:       upload.Size,
+		MimeType:   mimeType,
+		StorageKey: "files/" + hash[:2] + "/" + hash,
+		UploaderID: upload.UserID,
+	}
+
+	reader := s.chunkReader(upload)
+	_, err := s.storage.Put(file.StorageKey, reader)
+	reader.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(mimeType, "image/") {
+		if err := s.generateThumbnail(file); err != nil {
+			// 缩略图失败不影响上传
+			logger.Warn("Failed to generate thumbnail", zap.Error(err), zap.String("file_id", file.ID))
+		}
+	}
+
+	if err := repository.DB.Create(file).Error; err != nil {
+		// 并发上传了相同内容：使用已保存的记录（存储的内容相同，无需删除）
+		if existing, findErr := s.getFileByHash(hash); findErr == nil && existing != nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// generateThumbnail 生成缩略图（等比缩放到最长边不超过 ThumbnailSize 的 JPEG），并记录原图尺寸
+func (s *MediaService) generateThumbnail(file *model.MediaFile) error {
+	src, err := s.storage.Open(file.StorageKey)
+	if err != nil {
+		return err
+	}
+	cfg, _, err := image.DecodeConfig(src)
+	if err != nil {
+		src.Close()
+		return err
+	}
+	file.Width, file.Height = cfg.Width, cfg.Height
+	if cfg.Width*cfg.Height > maxThumbnailPixels {
+		src.Close()
+		return fmt.Errorf("image too large for thumbnail: %dx%d", cfg.Width, cfg.Height)
+	}
+
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		src.Close()
+		return err
+	}
+	img, _, err := image.Decode(src)
+	src.Close()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, resizeImage(img, s.config.ThumbnailSize), &jpeg.Options{Quality: thumbnailJPEGQuality}); err != nil {
+		return err
+	}
+	key := "thumbnails/" + file.Hash[:2] + "/" + file.Hash + ".jpg"
+	if _, err := s.storage.Put(key, &buf); err != nil {
+		return err
+	}
+	file.ThumbnailKey = key
+	return nil
+}
+
+// resizeImage 等比缩小图片（每个目标像素取源区域内若干采样点的平均值）
+func resizeImage(src image.Image, maxSize int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxSize && h <= maxSize {
+		maxSize = max(w, h)
+	}
+	dw, dh := maxSize, maxSize
+	if w > h {
+		dh = max(1, h*maxSize/w)
+	} else {
+		dw = max(1, w*maxSize/h)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	const n = thumbnailSampleDensity
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var r, g, b, a uint32
+			for sy := 0; sy < n; sy++ {
+				for sx := 0; sx < n; sx++ {
+					px := bounds.Min.X + (x*n+sx)*w/(dw*n)
+					py := bounds.Min.Y + (y*n+sy)*h/(dh*n)
+					cr, cg, cb, ca := src.At(px, py).RGBA()
+					r, g, b, a = r+cr, g+cg, b+cb, a+ca
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8(r / (n * n) >> 8)
+			dst.Pix[i+1] = uint8(g / (n * n) >> 8)
+			dst.Pix[i+2] = uint8(b / (n * n) >> 8)
+			dst.Pix[i+3] = uint8(a / (n * n) >> 8)
+		}
+	}
+	return dst
+}
+
+// chunkReader 按顺序读取上传任务的所有分片
+func (s *MediaService) chunkReader(upload *model.MediaUpload) *mediaChunkReader {
+	return &mediaChunkReader{storage: s.storage, uploadID: upload.ID, count: upload.ChunkCount}
+}
+
+// deleteChunks 删除上传任务的分片文件
+func (s *MediaService) deleteChunks(upload *model.MediaUpload) {
+	for i := 0; i < upload.ChunkCount; i++ {
+		if err := s.storage.Delete(chunkStorageKey(upload.ID, i)); err != nil {
+			logger.Warn("Failed to delete upload chunk", zap.Error(err), zap.String("upload_id", upload.ID), zap.Int("index", i))
+		}
+	}
+}
+
+// mimeAllowed 文件类型是否在允许列表中（列表为空表示不限制）
+func (s *MediaService) mimeAllowed(mimeType string) bool {
+	if len(s.config.AllowedMimeTypes) == 0 {
+		return true
+	}
+	for _, allowed := range s.config.AllowedMimeTypes {
+		if allowed == mimeType || (strings.HasSuffix(allowed, "/*") && strings.HasPrefix(mimeType, strings.TrimSuffix(allowed, "*"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeMimeType 去掉参数部分（如 "; charset=utf-8"）并转小写
+func normalizeMimeType(mimeType string) string {
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return strings.ToLower(strings.TrimSpace(mimeType))
+}
+
+// chunkStorageKey 分片的存储路径
+func chunkStorageKey(uploadID string, index int) string {
+	return fmt.Sprintf("uploads/%s/%d", uploadID, index)
+}
+
+// mediaChunkReader 依次打开并读取各个分片（同一时间只打开一个分片）
+type mediaChunkReader struct {
+	storage  MediaStorage
+	uploadID string
+	count    int
+	next     int
+	current  io.ReadCloser
+}
+
+// Read 实现 io.Reader
+func (r *mediaChunkReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if r.next >= r.count {
+				return 0, io.EOF
+			}
+			chunk, err := r.storage.Open(chunkStorageKey(r.uploadID, r.next))
+			if err != nil {
+				return 0, err
+			}
+			r.current = chunk
+			r.next++
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+		return n, err
+	}
+}
+
+// Close 关闭当前打开的分片
+func (r *mediaChunkReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+)
+
+// newTestMediaService 创建使用临时目录存储和测试数据库的媒体服务
+func newTestMediaService(t *testing.T, config MediaConfig) *MediaService {
+	t.Helper()
+	setupTestDB(t, &model.MediaFile{}, &model.MediaUpload{}, &model.MediaUploadChunk{}, &model.Message{}, &model.GroupMember{})
+	storage, err := NewLocalMediaStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalMediaStorage() error = %v", err)
+	}
+	if config.MaxFileSize == 0 {
+		config.MaxFileSize = 1 << 20
+	}
+	if config.ChunkSize == 0 {
+		config.ChunkSize = 256
+	}
+	config.URLSecret = "secret"
+	config.URLExpire = time.Minute
+	config.UploadExpire = time.Hour
+	config.ThumbnailSize = 8
+	return NewMediaService(storage, config)
+}
+
+// uploadTestFile 按分片上传数据并完成上传
+func uploadTestFile(t *testing.T, s *MediaService, userID, mimeType string, data []byte) (*model.MediaFile, error) {
+	t.Helper()
+	upload, err := s.InitUpload(userID, "file", int64(len(data)), mimeType, "")
+	if err != nil {
+		t.Fatalf("InitUpload() error = %v", err)
+	}
+	// 倒序上传分片，合并时按分片序号读取
+	for i := upload.ChunkCount - 1; i >= 0; i-- {
+		end := int64(i+1) * upload.ChunkSize
+		if end > upload.Size {
+			end = upload.Size
+		}
+		if err := s.UploadChunk(userID, upload.ID, i, bytes.NewReader(data[int64(i)*upload.ChunkSize:end])); err != nil {
+			t.Fatalf("UploadChunk(%d) error = %v", i, err)
+		}
+	}
+	return s.CompleteUpload(userID, upload.ID)
+}
+
+// testPNG 生成一张 w×h 的 PNG 图片
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, color.RGBA{uint8(x * 7), uint8(y * 5), 100, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestMediaServiceUploadImage(t *testing.T) {
+	s := newTestMediaService(t, MediaConfig{AllowedMimeTypes: []string{"image/*"}})
+	data := testPNG(t, 40, 20)
+
+	file, err := uploadTestFile(t, s, "a", "image/png", data)
+	if err != nil {
+		t.Fatalf("CompleteUpload() error = %v", err)
+	}
+	sum := sha256.Sum256(data)
+	if file.Hash != hex.EncodeToString(sum[:]) || file.MimeType != "image/png" || file.Width != 40 || file.Height != 20 {
+		t.Errorf("file = %+v", file)
+	}
+
+	stored, err := s.OpenFile(file, MediaVariantFile)
+	if err != nil {
+		t.Fatalf("OpenFile() error = %v", err)
+	}
+	content, _ := io.ReadAll(stored)
+	stored.Close()
+	if !bytes.Equal(content, data) {
+		t.Errorf("stored file differs from the uploaded data")
+	}
+
+	thumb, err := s.OpenFile(file, MediaVariantThumbnail)
+	if err != nil {
+		t.Fatalf("OpenFile() thumbnail error = %v", err)
+	}
+	cfg, _, err := image.DecodeConfig(thumb)
+	thumb.Close()
+	if err != nil || cfg.Width != 8 || cfg.Height != 4 {
+		t.Errorf("thumbnail = %dx%d, %v, want 8x4", cfg.Width, cfg.Height, err)
+	}
+
+	// 内容相同的文件只存储一份
+	again, err := uploadTestFile(t, s, "b", "image/png", data)
+	if err != nil || again.ID != file.ID {
+		t.Errorf("second upload = %v, %v, want the existing file", again, err)
+	}
+	var uploads []model.MediaUpload
+	repository.DB.Find(&uploads)
+	for _, upload := range uploads {
+		if upload.Status != model.MediaUploadStatusCompleted || upload.FileID != file.ID {
+			t.Errorf("upload %s = status %d file %q", upload.ID, upload.Status, upload.FileID)
+		}
+	}
+}
+
+func TestMediaServiceUploadRejects(t *testing.T) {
+	s := newTestMediaService(t, MediaConfig{MaxFileSize: 1024, AllowedMimeTypes: []string{"image/*", "application/pdf"}})
+
+	if _, err := s.InitUpload("a", "big", 1025, "", ""); err != ErrFileTooLarge {
+		t.Errorf("InitUpload() too large error = %v, want %v", err, ErrFileTooLarge)
+	}
+	if _, err := s.InitUpload("a", "x", 10, "text/html", ""); err != ErrMimeNotAllowed {
+		t.Errorf("InitUpload() declared type error = %v, want %v", err, ErrMimeNotAllowed)
+	}
+
+	upload, err := s.InitUpload("a", "a.txt", 300, "", strings.Repeat("0", 64))
+	if err != nil {
+		t.Fatalf("InitUpload() error = %v", err)
+	}
+	if upload.ChunkCount != 2 {
+		t.Fatalf("chunk count = %d, want 2", upload.ChunkCount)
+	}
+	if err := s.UploadChunk("a", upload.ID, 0, strings.NewReader("short")); err != ErrInvalidChunk {
+		t.Errorf("UploadChunk() short chunk error = %v, want %v", err, ErrInvalidChunk)
+	}
+	if err := s.UploadChunk("b", upload.ID, 0, strings.NewReader(strings.Repeat("a", 256))); err == nil {
+		t.Error("UploadChunk() by another user: expected error")
+	}
+	if err := s.UploadChunk("a", upload.ID, 0, strings.NewReader(strings.Repeat("a", 256))); err != nil {
+		t.Fatalf("UploadChunk() error = %v", err)
+	}
+	if _, err := s.CompleteUpload("a", upload.ID); err != ErrUploadIncomplete {
+		t.Errorf("CompleteUpload() incomplete error = %v, want %v", err, ErrUploadIncomplete)
+	}
+	if _, received, err := s.GetUpload("a", upload.ID); err != nil || len(received) != 1 || received[0] != 0 {
+		t.Errorf("GetUpload() received = %v, %v, want [0]", received, err)
+	}
+	if err := s.UploadChunk("a", upload.ID, 1, strings.NewReader(strings.Repeat("a", 44))); err != nil {
+		t.Fatalf("UploadChunk() error = %v", err)
+	}
+	if _, err := s.CompleteUpload("a", upload.ID); err != ErrHashMismatch {
+		t.Errorf("CompleteUpload() wrong hash error = %v, want %v", err, ErrHashMismatch)
+	}
+
+	// 图片类型只能由内容识别：声明为 SVG 的文本按文本处理，不在允许列表中
+	if _, err := uploadTestFile(t, s, "a", "image/svg+xml", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`)); err != ErrMimeNotAllowed {
+		t.Errorf("CompleteUpload() svg error = %v, want %v", err, ErrMimeNotAllowed)
+	}
+}
+
+func TestMediaServiceAccess(t *testing.T) {
+	s := newTestMediaService(t, MediaConfig{})
+	file, err := uploadTestFile(t, s, "a", "application/pdf", []byte("%PDF-1.4 test"))
+	if err != nil {
+		t.Fatalf("CompleteUpload() error = %v", err)
+	}
+
+	msg := &model.Message{Seq: 1, ServerMsgID: "m1", ClientMsgID: "m1", ConversationID: "single_a_b", SenderID: "a", ReceiverID: "b", MessageType: 5,
+		Content: `{"url":"https://example.com/api/media/files/` + file.ID + `","size":13,"name":"a.pdf"}`}
+	if err := repository.DB.Create(msg).Error; err != nil {
+		t.Fatalf("create message: %v", err)
+	}
+
+	for userID, want := range map[string]bool{"a": true, "b": true, "c": false} {
+		if got, err := s.CanAccessFile(userID, file); err != nil || got != want {
+			t.Errorf("CanAccessFile(%q) = %v, %v, want %v", userID, got, err, want)
+		}
+	}
+
+	expires, signature := s.SignURL(file.ID, MediaVariantFile)
+	if err := s.VerifyURL(file.ID, MediaVariantFile, expires, signature); err != nil {
+		t.Errorf("VerifyURL() error = %v", err)
+	}
+	if err := s.VerifyURL(file.ID, MediaVariantThumbnail, expires, signature); err != ErrInvalidSignature {
+		t.Errorf("VerifyURL() other variant error = %v, want %v", err, ErrInvalidSignature)
+	}
+	if err := s.VerifyURL(file.ID, MediaVariantFile, time.Now().Add(-time.Second).Unix(), signature); err != ErrInvalidSignature {
+		t.Errorf("VerifyURL() expired error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MediaStorage 媒体文件存储（本地文件系统，后续可接入 S3 兼容存储）
+// key 为以 / 分隔的相对路径
+type MediaStorage interface {
+	// Put 写入对象（已存在时覆盖），返回写入的字节数
+	Put(key string, r io.Reader) (int64, error)
+	// Open 打开对象用于读取（不存在时返回 os.ErrNotExist）
+	Open(key string) (io.ReadSeekCloser, error)
+	// Delete 删除对象（不存在时不报错）
+	Delete(key string) error
+}
+
+// localMediaStorage 本地文件系统存储
+type localMediaStorage struct {
+	root string
+}
+
+// NewLocalMediaStorage 创建本地文件系统存储
+func NewLocalMediaStorage(root string) (MediaStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create media directory: %w", err)
+	}
+	return &localMediaStorage{root: root}, nil
+}
+
+// path 将 key 转换为本地路径（拒绝越出根目录的 key）
+func (s *localMediaStorage) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("invalid storage key: %q", key)
+		}
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// Put 先写入临时文件再重命名，避免读到写了一半的文件
+func (s *localMediaStorage) Put(key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return 0, err
+	}
+	return n, nil
+}
+
+// Open 打开文件
+func (s *localMediaStorage) Open(key string) (io.ReadSeekCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Delete 删除文件
+func (s *localMediaStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	// 顺带删除空目录（如分片目录），非空时忽略错误
+	if dir := filepath.Dir(path); dir != filepath.Clean(s.root) {
+		os.Remove(dir)
+	}
+	return nil
+}
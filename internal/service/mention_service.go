@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	mentionBatchSize              = 500 // @ 记录批量写入大小（@所有人时大群会一次写入很多行）
+	maxMentionSeqsPerConversation = 100 // 同步时每个会话最多返回的未读 @ 消息数（最近的）
+)
+
+// MentionService @ 提醒服务
+type MentionService struct {
+	groupService *GroupService
+}
+
+// NewMentionService 创建 @ 提醒服务
+func NewMentionService(groupService *GroupService) *MentionService {
+	return &MentionService{
+		groupService: groupService,
+	}
+}
+
+// ResolveMentions 校验并填充群消息的 @ 信息，返回需要记录 @ 提醒的用户 ID
+// 被 @ 的用户必须是群成员；@所有人 仅群主/管理员可用，此时提醒除发送者外的全部群成员
+func (s *MentionService) ResolveMentions(ctx context.Context, msg *model.Message, mentionUserIDs []string, mentionAll bool) ([]string, error) {
+	msg.MentionUserIDs = ""
+	msg.MentionAll = false
+
+	// 单聊不支持 @
+	if msg.GroupID == "" || (len(mentionUserIDs) == 0 && !mentionAll) {
+		return nil, nil
+	}
+
+	memberIDs, err := s.groupService.GetGroupMemberIDs(ctx, msg.GroupID)
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[string]bool, len(memberIDs))
+	for _, id := range memberIDs {
+		members[id] = true
+	}
+
+	if mentionAll {
+		role, err := s.groupService.GetMemberRole(ctx, msg.GroupID, msg.SenderID)
+		if err != nil {
+			if err == ErrNotGroupMember {
+				return nil, ErrMentionAllDenied
+			}
+			return nil, err
+		}
+		if role != 1 && role != 2 {
+			return nil, ErrMentionAllDenied
+		}
+	}
+
+	// 去重并校验被 @ 的用户（@自己直接忽略）
+	seen := make(map[string]bool, len(mentionUserIDs))
+	userIDs := make([]string, 0, len(mentionUserIDs))
+	for _, id := range mentionUserIDs {
+		if id == "" || id == msg.SenderID || seen[id] {
+			continue
+		}
+		if !members[id] {
+			return nil, ErrMentionNotMember
+		}
+		seen[id] = true
+		userIDs = append(userIDs, id)
+	}
+
+	msg.MentionUserIDs = strings.Join(userIDs, ",")
+	msg.MentionAll = mentionAll
+
+	if !mentionAll {
+		return userIDs, nil
+	}
+
+	targets := make([]string, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		if id != msg.SenderID {
+			targets = append(targets, id)
+		}
+	}
+	return targets, nil
+}
+
+// RecordMentions 记录用户被 @ 的提醒（消息保存后调用，需要已分配的 Seq）
+func (s *MentionService) RecordMentions(msg *model.Message, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	mentions := make([]*model.UserMention, 0, len(userIDs))
+	for _, userID := range userIDs {
+		mentions = append(mentions, &model.UserMention{
+			ID:             utils.GenerateID(),
+			UserID:         userID,
+			ConversationID: msg.ConversationID,
+			Seq:            msg.Seq,
+			ServerMsgID:    msg.ServerMsgID,
+			SenderID:       msg.SenderID,
+			MentionAll:     msg.MentionAll && !containsString(ParseMentionUserIDs(msg.MentionUserIDs), userID),
+		})
+	}
+
+	return repository.DB.Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(mentions, mentionBatchSize).Error
+}
+
+// ClearMentions 清除用户在会话中已读位置之前（seq <= readSeq）的 @ 提醒
+func (s *MentionService) ClearMentions(userID, conversationID string, readSeq int64) error {
+	return repository.DB.Where("user_id = ? AND conversation_id = ? AND seq <= ?", userID, conversationID, readSeq).
+		Delete(&model.UserMention{}).Error
+}
+
+// GetUnreadMentionSeqs 获取用户在指定会话中未读的 @ 提醒（map[conversationID][]seq，升序）
+// 每个会话最多返回最近的 maxMentionSeqsPerConversation 条
+func (s *MentionService) GetUnreadMentionSeqs(userID string, conversationIDs []string) (map[string][]int64, error) {
+	result := make(map[string][]int64)
+	if len(conversationIDs) == 0 {
+		return result, nil
+	}
+
+	ranked := repository.DB.Model(&model.UserMention{}).
+		Select("conversation_id, seq, ROW_NUMBER() OVER (PARTITION BY conversation_id ORDER BY seq DESC) AS rn").
+		Where("user_id = ? AND conversation_id IN ?", userID, conversationIDs)
+	var mentions []model.UserMention
+	err := repository.DB.Table("(?) AS ranked", ranked).
+		Select("conversation_id, seq").
+		Where("rn <= ?", maxMentionSeqsPerConversation).
+		Order("conversation_id ASC, seq ASC").
+		Find(&mentions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range mentions {
+		result[m.ConversationID] = append(result[m.ConversationID], m.Seq)
+	}
+	return result, nil
+}
+
+// CountMentionedConversations 统计用户有未读 @ 提醒的会话数
+func (s *MentionService) CountMentionedConversations(userID string) (int64, error) {
+	var count int64
+	err := repository.DB.Model(&model.UserMention{}).
+		Where("user_id = ?", userID).
+		Distinct("conversation_id").
+		Count(&count).Error
+	return count, err
+}
+
+// IsMentioned 判断消息是否 @ 了指定用户（包括 @所有人）
+func IsMentioned(msg *model.Message, userID string) bool {
+	if msg.SenderID == userID {
+		return false
+	}
+	if msg.MentionAll {
+		return true
+	}
+	return containsString(ParseMentionUserIDs(msg.MentionUserIDs), userID)
+}
+
+// ParseMentionUserIDs 解析消息中存储的 @ 用户 ID 列表
+func ParseMentionUserIDs(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// containsString 判断字符串切片中是否包含指定值
+func containsString(list []string, target string) bool {
+	for _, s := range list {
+		if s == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+// addTestGroupMembers 添加群成员（roles 为 map[userID]role，1: 群主, 2: 管理员, 3: 普通成员）
+func addTestGroupMembers(t *testing.T, groupID string, roles map[string]int) {
+	t.Helper()
+	for userID, role := range roles {
+		member := &model.GroupMember{ID: utils.GenerateID(), GroupID: groupID, UserID: userID, Role: role, Status: 1}
+		if err := repository.DB.Create(member).Error; err != nil {
+			t.Fatalf("create group member: %v", err)
+		}
+	}
+}
+
+func TestMentionServiceResolveMentions(t *testing.T) {
+	setupTestDB(t, &model.GroupMember{}, &model.UserMention{})
+	addTestGroupMembers(t, "g1", map[string]int{"owner": 1, "a": 3, "b": 3})
+	s := NewMentionService(NewGroupService(repository.DB))
+	ctx := context.Background()
+
+	msg := &model.Message{GroupID: "g1", SenderID: "a"}
+	targets, err := s.ResolveMentions(ctx, msg, []string{"b", "a", "b", ""}, false)
+	if err != nil {
+		t.Fatalf("ResolveMentions() error = %v", err)
+	}
+	if !reflect.DeepEqual(targets, []string{"b"}) || msg.MentionUserIDs != "b" {
+		t.Errorf("ResolveMentions() = %v (stored %q), want [b]", targets, msg.MentionUserIDs)
+	}
+
+	if _, err := s.ResolveMentions(ctx, &model.Message{GroupID: "g1", SenderID: "a"}, []string{"outsider"}, false); err != ErrMentionNotMember {
+		t.Errorf("ResolveMentions() non-member error = %v, want %v", err, ErrMentionNotMember)
+	}
+	if _, err := s.ResolveMentions(ctx, &model.Message{GroupID: "g1", SenderID: "a"}, nil, true); err != ErrMentionAllDenied {
+		t.Errorf("ResolveMentions() @all by member error = %v, want %v", err, ErrMentionAllDenied)
+	}
+
+	msg = &model.Message{GroupID: "g1", SenderID: "owner"}
+	targets, err = s.ResolveMentions(ctx, msg, []string{"a"}, true)
+	if err != nil {
+		t.Fatalf("ResolveMentions() @all error = %v", err)
+	}
+	sort.Strings(targets)
+	if !reflect.DeepEqual(targets, []string{"a", "b"}) || !msg.MentionAll {
+		t.Errorf("ResolveMentions() @all = %v, want every member except the sender", targets)
+	}
+
+	single := &model.Message{ReceiverID: "b", SenderID: "a"}
+	if targets, err := s.ResolveMentions(ctx, single, []string{"b"}, true); err != nil || targets != nil || single.MentionAll {
+		t.Errorf("ResolveMentions() single chat = %v, %v, want mentions ignored", targets, err)
+	}
+}
+
+func TestMentionServiceUnreadMentions(t *testing.T) {
+	setupTestDB(t, &model.GroupMember{}, &model.UserMention{})
+	s := NewMentionService(NewGroupService(repository.DB))
+
+	for seq := int64(1); seq <= 3; seq++ {
+		msg := &model.Message{ConversationID: "group_g1", GroupID: "g1", SenderID: "owner", Seq: seq, ServerMsgID: utils.GenerateID()}
+		if err := s.RecordMentions(msg, []string{"a"}); err != nil {
+			t.Fatalf("RecordMentions() error = %v", err)
+		}
+	}
+	msg := &model.Message{ConversationID: "group_g2", GroupID: "g2", SenderID: "owner", Seq: 7, ServerMsgID: utils.GenerateID()}
+	if err := s.RecordMentions(msg, []string{"a", "b"}); err != nil {
+		t.Fatalf("RecordMentions() error = %v", err)
+	}
+
+	if err := s.ClearMentions("a", "group_g1", 2); err != nil {
+		t.Fatalf("ClearMentions() error = %v", err)
+	}
+
+	seqs, err := s.GetUnreadMentionSeqs("a", []string{"group_g1", "group_g2", "group_g3"})
+	if err != nil {
+		t.Fatalf("GetUnreadMentionSeqs() error = %v", err)
+	}
+	want := map[string][]int64{"group_g1": {3}, "group_g2": {7}}
+	if !reflect.DeepEqual(seqs, want) {
+		t.Errorf("GetUnreadMentionSeqs() = %v, want %v", seqs, want)
+	}
+
+	count, err := s.CountMentionedConversations("a")
+	if err != nil || count != 2 {
+		t.Errorf("CountMentionedConversations() = %d, %v, want 2", count, err)
+	}
+}
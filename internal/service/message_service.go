@@ -1,20 +1,31 @@
 package service
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/arwen/im-server/internal/model"
 	"github.com/arwen/im-server/internal/repository"
 	"github.com/arwen/im-server/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // MessageService 消息服务
-type MessageService struct{}
+type MessageService struct {
+	editTimeLimit  time.Duration // 消息可编辑时长（<= 0 表示不限制）
+	contentService *ContentService
+}
 
 // NewMessageService 创建消息服务
-func NewMessageService() *MessageService {
-	return &MessageService{}
+func NewMessageService(editTimeLimit time.Duration, contentService *ContentService) *MessageService {
+	return &MessageService{
+		editTimeLimit:  editTimeLimit,
+		contentService: contentService,
+	}
 }
 
 // SaveMessage 保存消息
@@ -30,7 +41,111 @@ func (s *MessageService) SaveMessage(msg *model.Message) error {
 	msg.ServerMsgID = utils.GenerateMessageID(msg.SenderID)
 
 	// 保存消息（会话内的 client_msg_id 唯一性由数据库索引保证）
-	return repository.DB.Create(msg).Error
+	if err := repository.DB.Create(msg).Error; err != nil {
+		return err
+	}
+
+	// 话题回复：更新根消息的回复数和最后回复时间
+	if msg.ThreadRootSeq > 0 {
+		err = repository.DB.Model(&model.Message{}).
+			Where("conversation_id = ? AND seq = ?", msg.ConversationID, msg.ThreadRootSeq).
+			Updates(map[string]interface{}{
+				"reply_count":     gorm.Expr("reply_count + ?", 1),
+				"last_reply_time": msg.ServerTime,
+			}).Error
+		if err != nil {
+			log.Printf("⚠️ Failed to update thread root %s/%d: %v", msg.ConversationID, msg.ThreadRootSeq, err)
+		}
+	}
+
+	return nil
+}
+
+// ResolveReplyAndThread 校验引用回复和话题字段，并填充被引用消息快照（在 SaveMessage 之前调用）
+// 引用的消息和话题根消息都必须属于同一会话且未被撤回；话题只有一层，回复话题中的消息会归入同一话题
+func (s *MessageService) ResolveReplyAndThread(msg *model.Message) error {
+	if msg.ReplyToSeq > 0 || msg.ReplyToMsgID != "" {
+		var quoted *model.Message
+		var err error
+		if msg.ReplyToSeq > 0 {
+			quoted, err = s.GetMessageBySeq(msg.ConversationID, msg.ReplyToSeq)
+		} else {
+			quoted, err = s.GetMessageByServerMsgID(msg.ReplyToMsgID)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, ErrMessageNotFound) {
+			return ErrReplyTargetNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if quoted.ConversationID != msg.ConversationID || quoted.Status == 4 || quoted.Status == model.MessageStatusExpired ||
+			(msg.ReplyToMsgID != "" && quoted.ServerMsgID != msg.ReplyToMsgID) {
+			return ErrReplyTargetNotFound
+		}
+
+		snapshot, err := json.Marshal(&model.QuotedMessage{
+			ServerMsgID: quoted.ServerMsgID,
+			Seq:         quoted.Seq,
+			SenderID:    quoted.SenderID,
+			MessageType: quoted.MessageType,
+			Content:     quoted.Content,
+			SendTime:    quoted.SendTime,
+			Status:      quoted.Status,
+		})
+		if err != nil {
+			return err
+		}
+		msg.ReplyToMsgID = quoted.ServerMsgID
+		msg.ReplyToSeq = quoted.Seq
+		msg.QuoteSnapshot = string(snapshot)
+	}
+
+	if msg.ThreadRootSeq > 0 {
+		root, err := s.GetMessageBySeq(msg.ConversationID, msg.ThreadRootSeq)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrThreadRootNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if root.Status == 4 || root.Status == model.MessageStatusExpired {
+			return ErrThreadRootNotFound
+		}
+		// 话题只有一层：根消息本身在话题中时，归入其所属话题
+		if root.ThreadRootSeq > 0 {
+			msg.ThreadRootSeq = root.ThreadRootSeq
+		}
+	}
+
+	return nil
+}
+
+// GetThreadReplies 分页获取话题回复（游标分页，返回 seq 大于 afterSeq 的回复，过滤用户已删除和已清空的回复）
+func (s *MessageService) GetThreadReplies(userID, conversationID string, rootSeq, afterSeq int64, count int) ([]*model.Message, bool, error) {
+	if count <= 0 {
+		count = 50
+	}
+	if count > 200 {
+		count = 200
+	}
+
+	var messages []*model.Message
+	err := repository.DB.Where("conversation_id = ? AND thread_root_seq = ? AND seq > ? AND status NOT IN ?", conversationID, rootSeq, afterSeq, []int{4, model.MessageStatusExpired}).
+		Scopes(visibleToUser(userID, conversationID)).
+		Order("seq ASC").
+		Limit(count + 1).
+		Find(&messages).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 多查一条用于判断是否还有更多
+	hasMore := len(messages) > count
+	if hasMore {
+		messages = messages[:count]
+	}
+
+	return messages, hasMore, nil
 }
 
 // GetMessageByClientMsgID 根据会话ID和客户端消息ID获取消息（用于幂等）
@@ -43,6 +158,22 @@ func (s *MessageService) GetMessageByClientMsgID(conversationID, clientMsgID str
 	return &msg, nil
 }
 
+// GetScheduledDelivery 获取定时消息投递产生的消息（按 client_msg_id 定位后校验定时消息ID），未投递时返回 ErrMessageNotFound
+// client_msg_id 已被其他消息占用时返回 ErrScheduleClientMsgID
+func (s *MessageService) GetScheduledDelivery(conversationID, clientMsgID, scheduleID string) (*model.Message, error) {
+	msg, err := s.GetMessageByClientMsgID(conversationID, clientMsgID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+	if msg.ScheduleID != scheduleID {
+		return nil, ErrScheduleClientMsgID
+	}
+	return msg, nil
+}
+
 // GetMessageBySeq 根据会话ID和Seq获取消息
 func (s *MessageService) GetMessageBySeq(conversationID string, seq int64) (*model.Message, error) {
 	var msg model.Message
@@ -53,10 +184,39 @@ func (s *MessageService) GetMessageBySeq(conversationID string, seq int64) (*mod
 	return &msg, nil
 }
 
-// GetConversationMessages 获取会话消息
-func (s *MessageService) GetConversationMessages(conversationID string, limit, offset int) ([]*model.Message, error) {
+// GetVisibleMessageBySeq 根据会话ID和Seq获取用户可见的消息（用户已删除或已清空的消息视为不存在）
+func (s *MessageService) GetVisibleMessageBySeq(userID, conversationID string, seq int64) (*model.Message, error) {
+	var msg model.Message
+	err := repository.DB.Where("conversation_id = ? AND seq = ?", conversationID, seq).
+		Scopes(visibleToUser(userID, conversationID)).
+		First(&msg).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// GetMessageByServerMsgID 根据服务端消息ID获取消息
+func (s *MessageService) GetMessageByServerMsgID(serverMsgID string) (*model.Message, error) {
+	var msg model.Message
+	err := repository.DB.Where("server_msg_id = ?", serverMsgID).First(&msg).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMessageNotFound
+		}
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// GetConversationMessages 获取会话消息（过滤用户已删除和已清空的消息）
+func (s *MessageService) GetConversationMessages(userID, conversationID string, limit, offset int) ([]*model.Message, error) {
 	var messages []*model.Message
 	err := repository.DB.Where("conversation_id = ? AND status != 4", conversationID).
+		Scopes(visibleToUser(userID, conversationID)).
 		Order("seq DESC").
 		Limit(limit).
 		Offset(offset).
@@ -64,6 +224,44 @@ func (s *MessageService) GetConversationMessages(conversationID string, limit, o
 	return messages, err
 }
 
+// GetMessagesBefore 向前分页获取历史消息（游标分页，返回 seq 小于 beforeSeq 的消息，按 seq 升序）
+// beforeSeq <= 0 表示从最新消息开始；过滤已撤回、已过期以及用户已删除和已清空的消息
+func (s *MessageService) GetMessagesBefore(userID, conversationID string, beforeSeq int64, count int) ([]*model.Message, bool, error) {
+	if count <= 0 {
+		count = 50
+	}
+	if count > 200 {
+		count = 200
+	}
+
+	query := repository.DB.Where("conversation_id = ? AND status NOT IN ?", conversationID, []int{4, model.MessageStatusExpired})
+	if beforeSeq > 0 {
+		query = query.Where("seq < ?", beforeSeq)
+	}
+
+	var messages []*model.Message
+	err := query.Scopes(visibleToUser(userID, conversationID)).
+		Order("seq DESC").
+		Limit(count + 1).
+		Find(&messages).Error
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 多查一条用于判断是否还有更多
+	hasMore := len(messages) > count
+	if hasMore {
+		messages = messages[:count]
+	}
+
+	// 转为升序返回
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+
+	return messages, hasMore, nil
+}
+
 // GenerateSeq 生成消息序列号（基于会话ID）
 func (s *MessageService) GenerateSeq(conversationID string) (int64, error) {
 	var seq model.MessageSequence
@@ -102,6 +300,37 @@ func (s *MessageService) GetMaxSeq(conversationID string) (int64, error) {
 	return seq.MaxSeq, nil
 }
 
+// GetMinSeq 获取会话的最小可用序列号位置（seq <= minSeq 的消息已超过保留期限被清理）
+func (s *MessageService) GetMinSeq(conversationID string) (int64, error) {
+	var seq model.MessageSequence
+	err := repository.DB.Where("conversation_id = ?", conversationID).First(&seq).Error
+	if err != nil {
+		return 0, nil // 不存在则返回 0
+	}
+	return seq.MinSeq, nil
+}
+
+// GetConversationMinSeqMap 获取多个会话的 min_seq（只返回不为 0 的会话）
+func (s *MessageService) GetConversationMinSeqMap(conversationIDs []string) (map[string]int64, error) {
+	seqMap := make(map[string]int64)
+	if len(conversationIDs) == 0 {
+		return seqMap, nil
+	}
+
+	var sequences []model.MessageSequence
+	err := repository.DB.Select("conversation_id, min_seq").
+		Where("conversation_id IN ? AND min_seq > 0", conversationIDs).
+		Find(&sequences).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, seq := range sequences {
+		seqMap[seq.ConversationID] = seq.MinSeq
+	}
+	return seqMap, nil
+}
+
 // GetUserConversationIDs 获取用户的所有会话ID（用于重装App场景）
 func (s *MessageService) GetUserConversationIDs(userID string) ([]string, error) {
 	var conversationIDs []string
@@ -143,12 +372,13 @@ func (s *MessageService) GetConversationMaxSeqMap(conversationIDs []string) (map
 	return seqMap, nil
 }
 
-// SyncConversationMessages 增量同步单个会话消息（从 lastSeq 之后拉取）
-func (s *MessageService) SyncConversationMessages(conversationID string, lastSeq int64, count int) ([]*model.Message, int64, bool, int64, error) {
+// SyncConversationMessages 增量同步单个会话消息（从 lastSeq 之后拉取，过滤用户已删除和已清空的消息）
+func (s *MessageService) SyncConversationMessages(userID, conversationID string, lastSeq int64, count int) ([]*model.Message, int64, bool, int64, error) {
 	var messages []*model.Message
 	
 	// 查询该会话在 lastSeq 之后的消息
 	err := repository.DB.Where("conversation_id = ? AND seq > ? AND status != 4", conversationID, lastSeq).
+		Scopes(visibleToUser(userID, conversationID)).
 		Order("seq ASC").
 		Limit(count).
 		Find(&messages).Error
@@ -170,105 +400,135 @@ func (s *MessageService) SyncConversationMessages(conversationID string, lastSeq
 	var totalCount int64
 	repository.DB.Model(&model.Message{}).
 		Where("conversation_id = ? AND seq > ? AND status != 4", conversationID, lastSeq).
+		Scopes(visibleToUser(userID, conversationID)).
 		Count(&totalCount)
 
 	return messages, serverMaxSeq, hasMore, totalCount, nil
 }
 
-// RevokeMessage 撤回消息（通过客户端消息ID）
-func (s *MessageService) RevokeMessage(clientMsgID, userID string) error {
+// RevokeMessage 撤回消息（通过客户端消息ID），返回被撤回的消息
+func (s *MessageService) RevokeMessage(clientMsgID, userID string) (*model.Message, error) {
 	// 查询消息
 	var msg model.Message
 	err := repository.DB.Where("client_msg_id = ?", clientMsgID).First(&msg).Error
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// 检查权限（只有发送者可以撤回）
 	if msg.SenderID != userID {
-		return ErrPermissionDenied
+		return nil, ErrPermissionDenied
 	}
 
 	// 更新状态为已撤回
-	return repository.DB.Model(&msg).Update("status", 4).Error
+	if err := repository.DB.Model(&msg).Update("status", 4).Error; err != nil {
+		return nil, err
+	}
+	return &msg, nil
 }
 
-// SaveReadReceipt 保存已读回执
-func (s *MessageService) SaveReadReceipt(messageID, conversationID, userID string, readTime int64) error {
-	receipt := &model.MessageReadReceipt{
+// EditMessage 编辑消息（只有发送者可以在可编辑时间内编辑，编辑前的内容写入编辑历史）
+func (s *MessageService) EditMessage(serverMsgID, userID, newContent string, editTime int64) (*model.Message, error) {
+	msg, err := s.GetMessageByServerMsgID(serverMsgID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查权限（只有发送者可以编辑）
+	if msg.SenderID != userID {
+		return nil, ErrPermissionDenied
+	}
+	if msg.Status == 4 {
+		return nil, ErrMessageRevoked
+	}
+	if msg.Status == model.MessageStatusExpired {
+		return nil, ErrMessageNotFound
+	}
+	if s.editTimeLimit > 0 && editTime-msg.ServerTime > s.editTimeLimit.Milliseconds() {
+		return nil, ErrEditTimeExpired
+	}
+	if msg.Content == newContent {
+		return nil, ErrMessageNotModified
+	}
+	if err := s.contentService.Validate(msg.MessageType, newContent); err != nil {
+		return nil, err
+	}
+
+	edit := &model.MessageEdit{
 		ID:             utils.GenerateID(),
-		MessageID:      messageID,
-		ConversationID: conversationID,
-		UserID:         userID,
-		ReadTime:       readTime,
+		ConversationID: msg.ConversationID,
+		Seq:            msg.Seq,
+		ServerMsgID:    msg.ServerMsgID,
+		EditorID:       userID,
+		EditVersion:    msg.EditVersion + 1,
+		OldContent:     msg.Content,
+		NewContent:     newContent,
+		EditTime:       editTime,
 	}
-	return repository.DB.Create(receipt).Error
-}
 
-// MarkMessagesAsRead 批量标记消息为已读
-func (s *MessageService) MarkMessagesAsRead(conversationID string, messageIDs []string, userID string, readTime int64) error {
-	// 1. 更新消息状态为已读（如果需要在 messages 表记录已读状态）
-	// 注意：这里假设是单聊场景，群聊需要单独的已读表
-	
-	// 2. 批量保存已读回执记录
-	for _, msgID := range messageIDs {
-		receipt := &model.MessageReadReceipt{
-			ID:             utils.GenerateID(),
-			MessageID:      msgID,
-			ConversationID: conversationID,
-			UserID:         userID,
-			ReadTime:       readTime,
+	err = repository.DB.Transaction(func(tx *gorm.DB) error {
+		// 以 edit_version 做乐观锁，避免并发编辑互相覆盖
+		result := tx.Model(&model.Message{}).
+			Where("conversation_id = ? AND seq = ? AND edit_version = ?", msg.ConversationID, msg.Seq, msg.EditVersion).
+			Updates(map[string]interface{}{
+				"content":      newContent,
+				"edit_version": edit.EditVersion,
+				"edit_time":    editTime,
+			})
+		if result.Error != nil {
+			return result.Error
 		}
-		if err := repository.DB.Create(receipt).Error; err != nil {
-			return err
+		if result.RowsAffected == 0 {
+			return ErrEditConflict
 		}
+		return tx.Create(edit).Error
+	})
+	if err != nil {
+		return nil, err
 	}
-	
-	return nil
-}
 
-// GetUnreadMessagesInConversation 获取会话中的未读消息ID列表
-func (s *MessageService) GetUnreadMessagesInConversation(conversationID string, userID string) ([]string, error) {
-	var messageIDs []string
-	
-	// 查询该会话中所有发给该用户且未被该用户读取的消息
-	err := repository.DB.Table("messages").
-		Select("messages.client_msg_id").
-		Joins("LEFT JOIN message_read_receipts ON messages.client_msg_id = message_read_receipts.message_id AND message_read_receipts.user_id = ?", userID).
-		Where("messages.conversation_id = ? AND messages.receiver_id = ? AND messages.status != 4 AND message_read_receipts.id IS NULL", conversationID, userID).
-		Pluck("messages.client_msg_id", &messageIDs).Error
-	
-	return messageIDs, err
+	msg.Content = newContent
+	msg.EditVersion = edit.EditVersion
+	msg.EditTime = editTime
+	return msg, nil
 }
 
-// CheckMessagesReadStatus 批量检查消息的已读状态
-// 返回一个 map[messageID]isRead，用于同步时判断消息是否已读
-func (s *MessageService) CheckMessagesReadStatus(messageIDs []string, userID string) (map[string]bool, error) {
-	result := make(map[string]bool)
-	
-	// 初始化所有消息为未读
-	for _, msgID := range messageIDs {
-		result[msgID] = false
-	}
-	
-	// 查询已读回执表
-	var readReceipts []model.MessageReadReceipt
-	err := repository.DB.Where("message_id IN ? AND user_id = ?", messageIDs, userID).
-		Find(&readReceipts).Error
+// GetMessageEditHistory 获取消息的编辑历史（按版本升序）
+// 消息已撤回、已过期或已被用户删除/清空时返回 ErrMessageNotFound
+func (s *MessageService) GetMessageEditHistory(userID, conversationID string, seq int64) ([]*model.MessageEdit, error) {
+	var count int64
+	err := repository.DB.Model(&model.Message{}).
+		Where("conversation_id = ? AND seq = ? AND status != 4", conversationID, seq).
+		Scopes(visibleToUser(userID, conversationID)).
+		Count(&count).Error
 	if err != nil {
-		return result, err
+		return nil, err
 	}
-	
-	// 标记已读的消息
-	for _, receipt := range readReceipts {
-		result[receipt.MessageID] = true
+	if count == 0 {
+		return nil, ErrMessageNotFound
 	}
-	
-	return result, nil
+
+	var edits []*model.MessageEdit
+	err = repository.DB.Where("conversation_id = ? AND seq = ?", conversationID, seq).
+		Order("edit_version ASC").
+		Find(&edits).Error
+	return edits, err
+}
+
+// GetUpdatedMessages 获取客户端已同步范围内（seq <= maxSeq）在 since 之后发生变更的消息（如编辑）
+func (s *MessageService) GetUpdatedMessages(userID, conversationID string, maxSeq int64, since time.Time, limit int) ([]*model.Message, error) {
+	var messages []*model.Message
+	err := repository.DB.Where("conversation_id = ? AND seq <= ? AND updated_at > ?", conversationID, maxSeq, since).
+		Scopes(visibleToUser(userID, conversationID)).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&messages).Error
+	return messages, err
 }
 
 // BatchSyncMessages 批量同步多个会话的消息（一次请求返回所有结果）
-func (s *MessageService) BatchSyncMessages(userID string, conversationStates map[string]int64, maxCountPerConv int) ([]*BatchSyncResult, error) {
+// lastSyncTime > 0 时，额外返回已同步范围内在该时间之后发生变更的消息
+func (s *MessageService) BatchSyncMessages(userID string, conversationStates map[string]int64, maxCountPerConv int, lastSyncTime int64) ([]*BatchSyncResult, error) {
 	if maxCountPerConv <= 0 {
 		maxCountPerConv = 100
 	}
@@ -282,6 +542,24 @@ func (s *MessageService) BatchSyncMessages(userID string, conversationStates map
 		return nil, fmt.Errorf("failed to get user conversations: %w", err)
 	}
 
+	// 用户的删除和清空标记（清空标记全量返回，删除标记只返回 lastSyncTime 之后的）
+	clearSeqs, err := s.GetClearSeqs(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation clears: %w", err)
+	}
+	// 超过保留期限被清理的位置
+	minSeqs, err := s.GetConversationMinSeqMap(conversationIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversation min seqs: %w", err)
+	}
+	var deletedSeqs map[string][]int64
+	if lastSyncTime > 0 {
+		deletedSeqs, err = s.GetDeletedSeqsSince(userID, utils.MillisToTime(lastSyncTime))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get message deletions: %w", err)
+		}
+	}
+
 	// 2. 对每个会话进行同步
 	results := make([]*BatchSyncResult, 0, len(conversationIDs))
 	
@@ -290,7 +568,7 @@ func (s *MessageService) BatchSyncMessages(userID string, conversationStates map
 		lastSeq := conversationStates[conversationID]
 		
 		// 同步该会话的消息
-		messages, maxSeq, hasMore, _, err := s.SyncConversationMessages(conversationID, lastSeq, maxCountPerConv)
+		messages, maxSeq, hasMore, _, err := s.SyncConversationMessages(userID, conversationID, lastSeq, maxCountPerConv)
 		if err != nil {
 			log.Printf("⚠️ Failed to sync conversation %s: %v", conversationID, err)
 			continue  // 单个会话失败不影响其他会话
@@ -301,15 +579,42 @@ func (s *MessageService) BatchSyncMessages(userID string, conversationStates map
 		if len(messages) > 0 {
 			syncedSeq = messages[len(messages)-1].Seq
 		}
+		// 清空范围内的消息对用户不可见，同步游标直接跳过
+		if clearSeq := clearSeqs[conversationID]; !hasMore && syncedSeq < clearSeq {
+			syncedSeq = clearSeq
+		}
+		// 超过保留期限的消息已被清理，同步游标同样跳过
+		if minSeq := minSeqs[conversationID]; !hasMore && syncedSeq < minSeq {
+			syncedSeq = minSeq
+		}
+		
+		// 已同步范围内的变更消息（编辑等）和过期的阅后即焚消息
+		var updatedMessages []*model.Message
+		var expiredSeqs []int64
+		if lastSyncTime > 0 && lastSeq > 0 {
+			updatedMessages, err = s.GetUpdatedMessages(userID, conversationID, lastSeq, utils.MillisToTime(lastSyncTime), maxCountPerConv)
+			if err != nil {
+				log.Printf("⚠️ Failed to get updated messages of conversation %s: %v", conversationID, err)
+			}
+			expiredSeqs, err = s.GetExpiredSeqsSince(conversationID, lastSeq, utils.MillisToTime(lastSyncTime))
+			if err != nil {
+				log.Printf("⚠️ Failed to get expired messages of conversation %s: %v", conversationID, err)
+			}
+		}
 		
 		// 如果有消息或者需要同步，加入结果
-		if len(messages) > 0 || maxSeq > lastSeq {
+		if len(messages) > 0 || maxSeq > lastSeq || len(updatedMessages) > 0 || len(deletedSeqs[conversationID]) > 0 || len(expiredSeqs) > 0 {
 			results = append(results, &BatchSyncResult{
-				ConversationID: conversationID,
-				Messages:       messages,
-				MaxSeq:         maxSeq,
-				SyncedSeq:      syncedSeq,
-				HasMore:        hasMore,
+				ConversationID:  conversationID,
+				Messages:        messages,
+				UpdatedMessages: updatedMessages,
+				DeletedSeqs:     deletedSeqs[conversationID],
+				ExpiredSeqs:     expiredSeqs,
+				MinSeq:          minSeqs[conversationID],
+				ClearedSeq:      clearSeqs[conversationID],
+				MaxSeq:          maxSeq,
+				SyncedSeq:       syncedSeq,
+				HasMore:         hasMore,
 			})
 		}
 	}
@@ -319,15 +624,21 @@ func (s *MessageService) BatchSyncMessages(userID string, conversationStates map
 
 // BatchSyncResult 批量同步的单个会话结果
 type BatchSyncResult struct {
-	ConversationID string
-	Messages       []*model.Message
-	MaxSeq         int64
-	SyncedSeq      int64
-	HasMore        bool
+	ConversationID  string
+	Messages        []*model.Message
+	UpdatedMessages []*model.Message // 已同步范围内发生变更的消息
+	DeletedSeqs     []int64          // 用户在其他设备上删除的消息
+	ExpiredSeqs     []int64          // 已同步范围内过期的阅后即焚消息
+	MinSeq          int64            // seq <= MinSeq 的消息已超过保留期限被清理
+	ClearedSeq      int64            // 用户清空历史的位置（seq <= ClearedSeq 的消息不可见）
+	MaxSeq          int64
+	SyncedSeq       int64
+	HasMore         bool
 }
 
 // SyncMessagesInRange 范围同步消息（用于补拉丢失的消息）
 // 参数：
+//   - userID: 当前用户（过滤用户已删除和已清空的消息）
 //   - conversationID: 会话ID
 //   - startSeq: 起始 seq（包含）
 //   - endSeq: 结束 seq（包含）
@@ -337,7 +648,7 @@ type BatchSyncResult struct {
 //   - actualStartSeq: 实际返回的起始 seq
 //   - actualEndSeq: 实际返回的结束 seq
 //   - hasMore: 是否还有更多消息
-func (s *MessageService) SyncMessagesInRange(conversationID string, startSeq, endSeq int64, count int) (messages []*model.Message, actualStartSeq, actualEndSeq int64, hasMore bool, err error) {
+func (s *MessageService) SyncMessagesInRange(userID, conversationID string, startSeq, endSeq int64, count int) (messages []*model.Message, actualStartSeq, actualEndSeq int64, hasMore bool, err error) {
 	// 参数校验
 	if count <= 0 {
 		count = 100
@@ -353,6 +664,7 @@ func (s *MessageService) SyncMessagesInRange(conversationID string, startSeq, en
 	// 查询指定范围的消息
 	err = repository.DB.
 		Where("conversation_id = ? AND seq >= ? AND seq <= ?", conversationID, startSeq, endSeq).
+		Scopes(visibleToUser(userID, conversationID)).
 		Order("seq ASC").
 		Limit(count).
 		Find(&messages).Error
@@ -370,8 +682,8 @@ func (s *MessageService) SyncMessagesInRange(conversationID string, startSeq, en
 	actualStartSeq = messages[0].Seq
 	actualEndSeq = messages[len(messages)-1].Seq
 	
-	// 判断是否还有更多消息（实际返回的结束 seq 小于请求的结束 seq）
-	hasMore = actualEndSeq < endSeq
+	// 判断是否还有更多消息（取满一页且实际返回的结束 seq 小于请求的结束 seq，末尾的消息可能已被用户删除）
+	hasMore = len(messages) >= count && actualEndSeq < endSeq
 	
 	log.Printf("✅ Range sync: conversation=%s, range=[%d,%d], returned=[%d,%d], count=%d, hasMore=%v", 
 		conversationID, startSeq, endSeq, actualStartSeq, actualEndSeq, len(messages), hasMore)
@@ -379,3 +691,129 @@ func (s *MessageService) SyncMessagesInRange(conversationID string, startSeq, en
 	return messages, actualStartSeq, actualEndSeq, hasMore, nil
 }
 
+// DeleteMessagesForUser 删除消息（仅对当前用户隐藏），返回实际存在的 seq
+func (s *MessageService) DeleteMessagesForUser(userID, conversationID string, seqs []int64) ([]int64, error) {
+	var existing []int64
+	err := repository.DB.Model(&model.Message{}).
+		Where("conversation_id = ? AND seq IN ?", conversationID, seqs).
+		Order("seq ASC").
+		Pluck("seq", &existing).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return nil, ErrMessageNotFound
+	}
+
+	deletions := make([]*model.MessageDeletion, 0, len(existing))
+	for _, seq := range existing {
+		deletions = append(deletions, &model.MessageDeletion{
+			ID:             utils.GenerateID(),
+			UserID:         userID,
+			ConversationID: conversationID,
+			Seq:            seq,
+		})
+	}
+	err = repository.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&deletions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// ClearConversationHistory 清空会话历史（seq <= clearSeq 的消息对当前用户不可见）
+// clearSeq <= 0 或超过会话最大 seq 时清空到当前最大 seq；清空位置只前进不后退，返回生效的清空位置
+func (s *MessageService) ClearConversationHistory(userID, conversationID string, clearSeq int64) (int64, error) {
+	maxSeq, err := s.GetMaxSeq(conversationID)
+	if err != nil {
+		return 0, err
+	}
+	if maxSeq == 0 {
+		return 0, ErrMessageNotFound
+	}
+	if clearSeq <= 0 || clearSeq > maxSeq {
+		clearSeq = maxSeq
+	}
+
+	err = repository.DB.Transaction(func(tx *gorm.DB) error {
+		var marker model.ConversationClear
+		err := tx.Where("user_id = ? AND conversation_id = ?", userID, conversationID).First(&marker).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(&model.ConversationClear{
+				UserID:         userID,
+				ConversationID: conversationID,
+				ClearSeq:       clearSeq,
+			}).Error
+		}
+		if err != nil {
+			return err
+		}
+		if marker.ClearSeq >= clearSeq {
+			clearSeq = marker.ClearSeq
+			return nil
+		}
+		return tx.Model(&marker).Update("clear_seq", clearSeq).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// 清空范围内的单条删除标记已经没有意义
+	repository.DB.Where("user_id = ? AND conversation_id = ? AND seq <= ?", userID, conversationID, clearSeq).
+		Delete(&model.MessageDeletion{})
+
+	return clearSeq, nil
+}
+
+// GetClearSeqs 获取用户所有会话的清空位置（map[conversationID]clearSeq）
+func (s *MessageService) GetClearSeqs(userID string) (map[string]int64, error) {
+	var markers []model.ConversationClear
+	if err := repository.DB.Where("user_id = ?", userID).Find(&markers).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64, len(markers))
+	for _, m := range markers {
+		result[m.ConversationID] = m.ClearSeq
+	}
+	return result, nil
+}
+
+// GetDeletedSeqsSince 获取用户在 since 之后删除的消息（map[conversationID][]seq），用于多端同步
+func (s *MessageService) GetDeletedSeqsSince(userID string, since time.Time) (map[string][]int64, error) {
+	var deletions []model.MessageDeletion
+	err := repository.DB.Select("conversation_id, seq").
+		Where("user_id = ? AND created_at > ?", userID, since).
+		Order("conversation_id ASC, seq ASC").
+		Find(&deletions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string][]int64)
+	for _, d := range deletions {
+		result[d.ConversationID] = append(result[d.ConversationID], d.Seq)
+	}
+	return result, nil
+}
+
+// GetExpiredSeqsSince 获取会话中已同步范围内（seq <= maxSeq）在 since 之后过期的阅后即焚消息
+func (s *MessageService) GetExpiredSeqsSince(conversationID string, maxSeq int64, since time.Time) ([]int64, error) {
+	var seqs []int64
+	err := repository.DB.Model(&model.Message{}).
+		Where("conversation_id = ? AND seq <= ? AND status = ? AND updated_at > ?", conversationID, maxSeq, model.MessageStatusExpired, since).
+		Order("seq ASC").
+		Pluck("seq", &seqs).Error
+	return seqs, err
+}
+
+// visibleToUser 过滤已过期的阅后即焚消息，以及用户已删除（message_deletions）和已清空（conversation_clears）的消息
+func visibleToUser(userID, conversationID string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("messages.status != ?", model.MessageStatusExpired).
+			Where("seq > COALESCE((SELECT clear_seq FROM conversation_clears WHERE user_id = ? AND conversation_id = ?), 0)", userID, conversationID).
+			Where("NOT EXISTS (SELECT 1 FROM message_deletions d WHERE d.user_id = ? AND d.conversation_id = messages.conversation_id AND d.seq = messages.seq)", userID)
+	}
+}
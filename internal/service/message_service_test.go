@@ -0,0 +1,294 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"gorm.io/gorm"
+)
+
+// messageTestModels 消息服务测试需要的表
+var messageTestModels = []interface{}{
+	&model.Message{},
+	&model.MessageSequence{},
+	&model.MessageEdit{},
+	&model.MessageDeletion{},
+	&model.ConversationClear{},
+}
+
+// newTestMessageService 创建使用测试数据库的消息服务（models 为额外需要的表）
+func newTestMessageService(t *testing.T, models ...interface{}) *MessageService {
+	t.Helper()
+	setupTestDB(t, append(messageTestModels, models...)...)
+	return NewMessageService(time.Hour, NewContentService(0))
+}
+
+// saveTestMessage 保存一条文本消息
+func saveTestMessage(t *testing.T, s *MessageService, conversationID, senderID, content string) *model.Message {
+	t.Helper()
+	msg := &model.Message{
+		ClientMsgID:    content,
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		MessageType:    1,
+		Content:        content,
+		ServerTime:     time.Now().UnixMilli(),
+		Status:         1,
+	}
+	if err := s.SaveMessage(msg); err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	return msg
+}
+
+func TestMessageServiceEditMessage(t *testing.T) {
+	s := newTestMessageService(t)
+	msg := saveTestMessage(t, s, "single_a_b", "a", "hello")
+
+	if _, err := s.EditMessage(msg.ServerMsgID, "b", "hi", msg.ServerTime); err != ErrPermissionDenied {
+		t.Fatalf("EditMessage() by receiver error = %v, want %v", err, ErrPermissionDenied)
+	}
+	if _, err := s.EditMessage(msg.ServerMsgID, "a", "hello", msg.ServerTime); err != ErrMessageNotModified {
+		t.Fatalf("EditMessage() same content error = %v, want %v", err, ErrMessageNotModified)
+	}
+	if _, err := s.EditMessage(msg.ServerMsgID, "a", "late", msg.ServerTime+time.Hour.Milliseconds()+1); err != ErrEditTimeExpired {
+		t.Fatalf("EditMessage() after limit error = %v, want %v", err, ErrEditTimeExpired)
+	}
+
+	for i, content := range []string{"hello 1", "hello 2"} {
+		edited, err := s.EditMessage(msg.ServerMsgID, "a", content, msg.ServerTime+int64(i))
+		if err != nil {
+			t.Fatalf("EditMessage(%q) error = %v", content, err)
+		}
+		if edited.EditVersion != i+1 || edited.Content != content {
+			t.Fatalf("EditMessage(%q) = version %d content %q", content, edited.EditVersion, edited.Content)
+		}
+	}
+
+	history, err := s.GetMessageEditHistory("b", msg.ConversationID, msg.Seq)
+	if err != nil {
+		t.Fatalf("GetMessageEditHistory() error = %v", err)
+	}
+	if len(history) != 2 || history[0].OldContent != "hello" || history[1].OldContent != "hello 1" || history[1].NewContent != "hello 2" {
+		t.Fatalf("GetMessageEditHistory() = %+v", history)
+	}
+}
+
+func TestMessageServiceEditMessageConflict(t *testing.T) {
+	s := newTestMessageService(t)
+	msg := saveTestMessage(t, s, "single_a_b", "a", "hello")
+
+	// 编辑读取消息之后、写入之前，另一个编辑先提交
+	concurrent := false
+	err := repository.DB.Callback().Query().After("gorm:query").Register("test:concurrent_edit", func(db *gorm.DB) {
+		if concurrent {
+			return
+		}
+		concurrent = true
+		db.Session(&gorm.Session{NewDB: true}).Model(&model.Message{}).
+			Where("server_msg_id = ?", msg.ServerMsgID).
+			Updates(map[string]interface{}{"content": "other", "edit_version": 1})
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	if _, err := s.EditMessage(msg.ServerMsgID, "a", "mine", msg.ServerTime); err != ErrEditConflict {
+		t.Fatalf("EditMessage() error = %v, want %v", err, ErrEditConflict)
+	}
+
+	saved, err := s.GetMessageByServerMsgID(msg.ServerMsgID)
+	if err != nil {
+		t.Fatalf("GetMessageByServerMsgID() error = %v", err)
+	}
+	if saved.Content != "other" || saved.EditVersion != 1 {
+		t.Errorf("message = %q version %d, want the concurrent edit kept", saved.Content, saved.EditVersion)
+	}
+	var edits int64
+	repository.DB.Model(&model.MessageEdit{}).Count(&edits)
+	if edits != 0 {
+		t.Errorf("edit history rows = %d, want 0", edits)
+	}
+}
+
+func TestMessageServiceResolveReplyAndThread(t *testing.T) {
+	s := newTestMessageService(t)
+	root := saveTestMessage(t, s, "group_g1", "a", "root")
+	reply := saveTestMessage(t, s, "group_g1", "b", "reply")
+	other := saveTestMessage(t, s, "group_g2", "a", "other")
+
+	// 引用回复：填充被引用消息快照
+	msg := &model.Message{ConversationID: "group_g1", SenderID: "c", ReplyToSeq: root.Seq}
+	if err := s.ResolveReplyAndThread(msg); err != nil {
+		t.Fatalf("ResolveReplyAndThread() error = %v", err)
+	}
+	var quoted model.QuotedMessage
+	if err := json.Unmarshal([]byte(msg.QuoteSnapshot), &quoted); err != nil {
+		t.Fatalf("quote snapshot %q: %v", msg.QuoteSnapshot, err)
+	}
+	if msg.ReplyToMsgID != root.ServerMsgID || quoted.Content != "root" || quoted.SenderID != "a" {
+		t.Errorf("reply = %q snapshot %+v, want the root message", msg.ReplyToMsgID, quoted)
+	}
+
+	// 不能引用其他会话的消息
+	msg = &model.Message{ConversationID: "group_g1", SenderID: "c", ReplyToMsgID: other.ServerMsgID}
+	if err := s.ResolveReplyAndThread(msg); err != ErrReplyTargetNotFound {
+		t.Errorf("ResolveReplyAndThread() other conversation error = %v, want %v", err, ErrReplyTargetNotFound)
+	}
+	msg = &model.Message{ConversationID: "group_g1", SenderID: "c", ThreadRootSeq: 99}
+	if err := s.ResolveReplyAndThread(msg); err != ErrThreadRootNotFound {
+		t.Errorf("ResolveReplyAndThread() missing root error = %v, want %v", err, ErrThreadRootNotFound)
+	}
+
+	// 话题只有一层：回复话题中的消息归入同一话题
+	inThread := &model.Message{ConversationID: "group_g1", SenderID: "c", MessageType: 1, Content: "in thread", ThreadRootSeq: root.Seq}
+	if err := s.ResolveReplyAndThread(inThread); err != nil {
+		t.Fatalf("ResolveReplyAndThread() error = %v", err)
+	}
+	if err := s.SaveMessage(inThread); err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	nested := &model.Message{ConversationID: "group_g1", SenderID: "b", ThreadRootSeq: inThread.Seq}
+	if err := s.ResolveReplyAndThread(nested); err != nil {
+		t.Fatalf("ResolveReplyAndThread() error = %v", err)
+	}
+	if nested.ThreadRootSeq != root.Seq {
+		t.Errorf("nested thread root = %d, want %d", nested.ThreadRootSeq, root.Seq)
+	}
+
+	saved, err := s.GetMessageBySeq("group_g1", root.Seq)
+	if err != nil {
+		t.Fatalf("GetMessageBySeq() error = %v", err)
+	}
+	if saved.ReplyCount != 1 || saved.LastReplyTime != inThread.ServerTime {
+		t.Errorf("root reply count = %d last reply = %d, want 1 and %d", saved.ReplyCount, saved.LastReplyTime, inThread.ServerTime)
+	}
+
+	// 撤回的消息不能再引用
+	if err := repository.DB.Model(&model.Message{}).Where("server_msg_id = ?", reply.ServerMsgID).Update("status", 4).Error; err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	msg = &model.Message{ConversationID: "group_g1", SenderID: "c", ReplyToSeq: reply.Seq}
+	if err := s.ResolveReplyAndThread(msg); err != ErrReplyTargetNotFound {
+		t.Errorf("ResolveReplyAndThread() revoked error = %v, want %v", err, ErrReplyTargetNotFound)
+	}
+}
+
+func TestMessageServiceDeleteAndClearForUser(t *testing.T) {
+	s := newTestMessageService(t)
+	var seqs []int64
+	for _, content := range []string{"m1", "m2", "m3", "m4"} {
+		seqs = append(seqs, saveTestMessage(t, s, "single_a_b", "a", content).Seq)
+	}
+
+	deleted, err := s.DeleteMessagesForUser("a", "single_a_b", []int64{seqs[2], 99})
+	if err != nil || len(deleted) != 1 || deleted[0] != seqs[2] {
+		t.Fatalf("DeleteMessagesForUser() = %v, %v, want [%d]", deleted, err, seqs[2])
+	}
+	if _, err := s.DeleteMessagesForUser("a", "single_a_b", []int64{99}); err != ErrMessageNotFound {
+		t.Errorf("DeleteMessagesForUser() missing error = %v, want %v", err, ErrMessageNotFound)
+	}
+
+	clearSeq, err := s.ClearConversationHistory("a", "single_a_b", seqs[1])
+	if err != nil || clearSeq != seqs[1] {
+		t.Fatalf("ClearConversationHistory() = %d, %v, want %d", clearSeq, err, seqs[1])
+	}
+	// 清空位置只前进不后退
+	if clearSeq, err := s.ClearConversationHistory("a", "single_a_b", seqs[0]); err != nil || clearSeq != seqs[1] {
+		t.Errorf("ClearConversationHistory() backwards = %d, %v, want %d", clearSeq, err, seqs[1])
+	}
+
+	visible, err := s.GetConversationMessages("a", "single_a_b", 10, 0)
+	if err != nil {
+		t.Fatalf("GetConversationMessages() error = %v", err)
+	}
+	if len(visible) != 1 || visible[0].Seq != seqs[3] {
+		t.Errorf("GetConversationMessages() for a = %d messages, want only seq %d", len(visible), seqs[3])
+	}
+	if others, _ := s.GetConversationMessages("b", "single_a_b", 10, 0); len(others) != 4 {
+		t.Errorf("GetConversationMessages() for b = %d messages, want 4", len(others))
+	}
+
+	if _, err := s.GetVisibleMessageBySeq("a", "single_a_b", seqs[2]); err != ErrMessageNotFound {
+		t.Errorf("GetVisibleMessageBySeq() deleted error = %v, want %v", err, ErrMessageNotFound)
+	}
+	if _, err := s.GetVisibleMessageBySeq("a", "single_a_b", seqs[0]); err != ErrMessageNotFound {
+		t.Errorf("GetVisibleMessageBySeq() cleared error = %v, want %v", err, ErrMessageNotFound)
+	}
+	if _, err := s.GetVisibleMessageBySeq("b", "single_a_b", seqs[2]); err != nil {
+		t.Errorf("GetVisibleMessageBySeq() for b error = %v", err)
+	}
+}
+
+func TestMessageServiceGetThreadRepliesVisibility(t *testing.T) {
+	s := newTestMessageService(t)
+	root := saveTestMessage(t, s, "group_g1", "a", "root")
+	var replies []*model.Message
+	for _, content := range []string{"r1", "r2", "r3"} {
+		reply := &model.Message{ClientMsgID: content, ConversationID: "group_g1", SenderID: "b", MessageType: 1, Content: content, ThreadRootSeq: root.Seq, Status: 1}
+		if err := s.SaveMessage(reply); err != nil {
+			t.Fatalf("SaveMessage() error = %v", err)
+		}
+		replies = append(replies, reply)
+	}
+	if _, err := s.DeleteMessagesForUser("a", "group_g1", []int64{replies[1].Seq}); err != nil {
+		t.Fatalf("DeleteMessagesForUser() error = %v", err)
+	}
+
+	got, hasMore, err := s.GetThreadReplies("a", "group_g1", root.Seq, 0, 1)
+	if err != nil || len(got) != 1 || got[0].Seq != replies[0].Seq || !hasMore {
+		t.Fatalf("GetThreadReplies() first page = %d messages, hasMore %v, err %v", len(got), hasMore, err)
+	}
+	got, hasMore, err = s.GetThreadReplies("a", "group_g1", root.Seq, got[0].Seq, 10)
+	if err != nil || len(got) != 1 || got[0].Seq != replies[2].Seq || hasMore {
+		t.Errorf("GetThreadReplies() second page = %d messages, hasMore %v, err %v, want only the undeleted reply", len(got), hasMore, err)
+	}
+}
+
+func TestMessageServiceGetMessagesBefore(t *testing.T) {
+	s := newTestMessageService(t)
+	var seqs []int64
+	for _, content := range []string{"m1", "m2", "m3", "m4", "m5", "m6"} {
+		seqs = append(seqs, saveTestMessage(t, s, "single_a_b", "a", content).Seq)
+	}
+	repository.DB.Model(&model.Message{}).Where("seq = ?", seqs[4]).Update("status", 4)
+	repository.DB.Model(&model.Message{}).Where("seq = ?", seqs[3]).Update("status", model.MessageStatusExpired)
+	if _, err := s.DeleteMessagesForUser("a", "single_a_b", []int64{seqs[1]}); err != nil {
+		t.Fatalf("DeleteMessagesForUser() error = %v", err)
+	}
+
+	// 从最新消息开始向前翻页，跳过撤回、过期和已删除的消息
+	var got []int64
+	beforeSeq := int64(0)
+	for page := 0; ; page++ {
+		messages, hasMore, err := s.GetMessagesBefore("a", "single_a_b", beforeSeq, 2)
+		if err != nil {
+			t.Fatalf("GetMessagesBefore() error = %v", err)
+		}
+		if len(messages) == 0 || page > len(seqs) {
+			t.Fatalf("GetMessagesBefore(%d) returned no messages", beforeSeq)
+		}
+		pageSeqs := make([]int64, 0, len(messages))
+		for _, msg := range messages {
+			pageSeqs = append(pageSeqs, msg.Seq)
+		}
+		got = append(pageSeqs, got...)
+		beforeSeq = messages[0].Seq
+		if !hasMore {
+			break
+		}
+	}
+
+	want := []int64{seqs[0], seqs[2], seqs[5]}
+	if len(got) != len(want) {
+		t.Fatalf("GetMessagesBefore() pages = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("GetMessagesBefore() pages = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 敏感词等级
+const (
+	wordSeverityLow     = 1
+	wordSeverityMedium  = 2
+	wordSeverityHigh    = 3
+	wordSeverityDefault = wordSeverityMedium // 词表中未标注等级的词
+)
+
+// wordMatch 一次敏感词命中（Start/End 为内容的字符下标，左闭右开）
+type wordMatch struct {
+	Start    int
+	End      int
+	Word     string
+	Severity int
+}
+
+// acNode Aho–Corasick 自动机节点
+type acNode struct {
+	children map[rune]int
+	fail     int
+	word     int // 以该节点结尾的敏感词下标（-1 表示没有）
+	dict     int // 沿失败链最近的有敏感词的节点（-1 表示没有），避免匹配时遍历整条失败链
+}
+
+// wordMatcher 多模式敏感词匹配器（Aho–Corasick），构建后只读，可并发使用
+// 匹配不区分大小写
+type wordMatcher struct {
+	nodes      []acNode
+	words      []string
+	severities []int
+}
+
+// parseWordList 解析敏感词表：每行一个词，可用 "词|等级" 标注等级（1 低 / 2 中 / 3 高，默认 2），# 开头为注释
+func parseWordList(r io.Reader) (map[string]int, error) {
+	words := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		word, severity := line, wordSeverityDefault
+		if i := strings.LastIndex(line, "|"); i >= 0 {
+			level, err := strconv.Atoi(strings.TrimSpace(line[i+1:]))
+			if err != nil || level < wordSeverityLow || level > wordSeverityHigh {
+				return nil, fmt.Errorf("line %d: invalid severity %q", lineNo, line[i+1:])
+			}
+			word, severity = strings.TrimSpace(line[:i]), level
+		}
+		word = strings.ToLower(word)
+		if word == "" {
+			continue
+		}
+		// 重复的词取最高等级
+		if severity > words[word] {
+			words[word] = severity
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// newWordMatcher 构建匹配器（words 为小写敏感词到等级的映射）
+func newWordMatcher(words map[string]int) *wordMatcher {
+	m := &wordMatcher{
+		nodes: []acNode{{children: make(map[rune]int), word: -1, dict: -1}},
+	}
+
+	// 构建字典树
+	for word, severity := range words {
+		cur := 0
+		for _, r := range word {
+			next, ok := m.nodes[cur].children[r]
+			if !ok {
+				next = len(m.nodes)
+				m.nodes = append(m.nodes, acNode{children: make(map[rune]int), word: -1, dict: -1})
+				m.nodes[cur].children[r] = next
+			}
+			cur = next
+		}
+		m.nodes[cur].word = len(m.words)
+		m.words = append(m.words, word)
+		m.severities = append(m.severities, severity)
+	}
+
+	// 按层序计算失败指针
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].children {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range m.nodes[cur].children {
+			fail := m.nodes[cur].fail
+			for fail > 0 {
+				if _, ok := m.nodes[fail].children[r]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if next, ok := m.nodes[fail].children[r]; ok && next != child {
+				fail = next
+			} else {
+				fail = 0
+			}
+			m.nodes[child].fail = fail
+			if m.nodes[fail].word >= 0 {
+				m.nodes[child].dict = fail
+			} else {
+				m.nodes[child].dict = m.nodes[fail].dict
+			}
+			queue = append(queue, child)
+		}
+	}
+	return m
+}
+
+// Len 敏感词数量
+func (m *wordMatcher) Len() int {
+	return len(m.words)
+}
+
+// Match 查找内容中等级不低于 minSeverity 的全部敏感词（包括重叠的命中）
+func (m *wordMatcher) Match(text []rune, minSeverity int) []wordMatch {
+	var matches []wordMatch
+	cur := 0
+	for i, r := range text {
+		r = unicode.ToLower(r)
+		for cur > 0 {
+			if _, ok := m.nodes[cur].children[r]; ok {
+				break
+			}
+			cur = m.nodes[cur].fail
+		}
+		if next, ok := m.nodes[cur].children[r]; ok {
+			cur = next
+		}
+
+		for node := cur; node >= 0; node = m.nodes[node].dict {
+			idx := m.nodes[node].word
+			if idx < 0 {
+				continue
+			}
+			if m.severities[idx] < minSeverity {
+				continue
+			}
+			length := len([]rune(m.words[idx]))
+			matches = append(matches, wordMatch{
+				Start:    i + 1 - length,
+				End:      i + 1,
+				Word:     m.words[idx],
+				Severity: m.severities[idx],
+			})
+		}
+	}
+	return matches
+}
@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 审核结果来源
+const (
+	ModerationSourceWordList   = "wordlist"
+	ModerationSourceClassifier = "classifier"
+)
+
+const moderationMaxLoggedWords = 20 // 审核记录中最多保存的命中词数量
+
+// ModerationRequest 外部分类器的审核请求
+type ModerationRequest struct {
+	ConversationID string
+	SenderID       string
+	GroupID        string
+	MessageType    int
+	Content        string
+	Strictness     int // 会话的审核严格程度（分类器可据此调整阈值）
+}
+
+// ModerationVerdict 外部分类器的审核结果
+// Action 为 model.ModerationAction*；分类器无法给出敏感内容的位置，返回 Mask 时按 Reject 处理
+type ModerationVerdict struct {
+	Action int
+	Reason string
+}
+
+// ModerationClassifier 外部内容分类器（如第三方内容安全服务、自建模型，由部署方实现）
+// 在敏感词检查之后调用，调用失败时放行（只记录日志），不影响消息发送
+type ModerationClassifier interface {
+	Classify(ctx context.Context, req *ModerationRequest) (*ModerationVerdict, error)
+}
+
+// ModerationConfig 内容审核配置
+type ModerationConfig struct {
+	WordFile          string        // 敏感词表文件（为空表示不做敏感词检查）
+	DefaultStrictness int           // 单聊和未单独设置的群组使用的严格程度
+	LowAction         int           // 命中低危词的动作
+	MediumAction      int           // 命中中危词的动作
+	HighAction        int           // 命中高危词的动作
+	ClassifierTimeout time.Duration // 外部分类器超时时间
+}
+
+// ModerationDecision 一条消息的审核结果
+type ModerationDecision struct {
+	Action       int
+	Source       string
+	Strictness   int
+	MatchedWords []string
+	Reason       string
+	Review       bool   // 需要人工复核（命中了动作为 Flag 的词或分类器标记，可能同时被替换）
+	Original     string // 审核前的内容（内容被替换时用于审核记录）
+}
+
+// ModerationService 内容审核服务：消息保存前按敏感词表和外部分类器检查内容，
+// 根据结果拒绝发送、替换敏感词或标记待复核，未通过的结果写入 moderation_logs
+// 敏感词表修改后由后台任务自动重新加载，不需要重启
+type ModerationService struct {
+	contentService *ContentService
+	groupService   *GroupService
+	classifier     ModerationClassifier
+	config         ModerationConfig
+
+	mu          sync.RWMutex
+	matcher     *wordMatcher
+	wordModTime time.Time
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewModerationService 创建内容审核服务（classifier 为 nil 时只做敏感词检查）
+func NewModerationService(contentService *ContentService, groupService *GroupService, classifier ModerationClassifier, config ModerationConfig) *ModerationService {
+	if !validStrictness(config.DefaultStrictness) {
+		config.DefaultStrictness = model.ModerationStrictnessStandard
+	}
+	if config.ClassifierTimeout <= 0 {
+		config.ClassifierTimeout = 2 * time.Second
+	}
+	return &ModerationService{
+		contentService: contentService,
+		groupService:   groupService,
+		classifier:     classifier,
+		config:         config,
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// ParseModerationAction 解析配置中的审核动作（pass / flag / mask / reject）
+func ParseModerationAction(action string) (int, error) {
+	switch strings.ToLower(action) {
+	case "pass":
+		return model.ModerationActionPass, nil
+	case "flag":
+		return model.ModerationActionFlag, nil
+	case "mask":
+		return model.ModerationActionMask, nil
+	case "reject":
+		return model.ModerationActionReject, nil
+	default:
+		return 0, fmt.Errorf("unknown moderation action: %q", action)
+	}
+}
+
+// validStrictness 严格程度是否合法
+func validStrictness(strictness int) bool {
+	return strictness >= model.ModerationStrictnessRelaxed && strictness <= model.ModerationStrictnessStrict
+}
+
+// Reload 重新加载敏感词表（文件未变化时跳过；加载失败时继续使用旧词表）
+func (s *ModerationService) Reload() error {
+	if s.config.WordFile == "" {
+		return nil
+	}
+
+	info, err := os.Stat(s.config.WordFile)
+	if err != nil {
+		return err
+	}
+	s.mu.RLock()
+	unchanged := s.matcher != nil && info.ModTime().Equal(s.wordModTime)
+	s.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	file, err := os.Open(s.config.WordFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	words, err := parseWordList(file)
+	if err != nil {
+		return fmt.Errorf("failed to parse word list %s: %w", s.config.WordFile, err)
+	}
+	matcher := newWordMatcher(words)
+
+	s.mu.Lock()
+	s.matcher = matcher
+	s.wordModTime = info.ModTime()
+	s.mu.Unlock()
+
+	logger.Info("Moderation word list loaded",
+		zap.String("file", s.config.WordFile),
+		zap.Int("words", matcher.Len()))
+	return nil
+}
+
+// Start 启动敏感词表热加载任务（按间隔检查文件修改时间）
+func (s *ModerationService) Start(interval time.Duration) {
+	if s.config.WordFile == "" {
+		return
+	}
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				if err := s.Reload(); err != nil {
+					logger.Error("Failed to reload moderation word list", zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+// Stop 停止敏感词表热加载任务
+func (s *ModerationService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// Moderate 审核即将保存的消息（发送、编辑、定时消息投递前调用）
+// 动作为 Mask 时直接替换 msg.Content；动作为 Reject 时调用方应拒绝发送
+func (s *ModerationService) Moderate(ctx context.Context, msg *model.Message) *ModerationDecision {
+	decision := &ModerationDecision{
+		Action:     model.ModerationActionPass,
+		Strictness: s.GetStrictness(msg.GroupID),
+		Original:   msg.Content,
+	}
+
+	// 敏感词检查（只检查纯文本类型，JSON 内容交给外部分类器）
+	if ct, ok := s.contentService.lookup(msg.MessageType); ok && ct.PlainText {
+		s.mu.RLock()
+		matcher := s.matcher
+		s.mu.RUnlock()
+		if matcher != nil {
+			s.applyWordList(matcher, msg, decision)
+		}
+	}
+
+	if s.classifier == nil || decision.Action == model.ModerationActionReject {
+		return decision
+	}
+
+	cctx, cancel := context.WithTimeout(ctx, s.config.ClassifierTimeout)
+	defer cancel()
+	verdict, err := s.classifier.Classify(cctx, &ModerationRequest{
+		ConversationID: msg.ConversationID,
+		SenderID:       msg.SenderID,
+		GroupID:        msg.GroupID,
+		MessageType:    msg.MessageType,
+		Content:        decision.Original,
+		Strictness:     decision.Strictness,
+	})
+	if err != nil {
+		logger.Warn("Moderation classifier failed",
+			zap.String("conversation_id", msg.ConversationID),
+			zap.String("sender_id", msg.SenderID),
+			zap.Error(err))
+		return decision
+	}
+	if verdict == nil || verdict.Action <= model.ModerationActionPass {
+		return decision
+	}
+
+	action := verdict.Action
+	if action >= model.ModerationActionMask {
+		action = model.ModerationActionReject
+	}
+	if action == model.ModerationActionFlag {
+		decision.Review = true
+		decision.Reason = verdict.Reason
+	}
+	if action > decision.Action {
+		decision.Action = action
+		decision.Source = ModerationSourceClassifier
+		decision.Reason = verdict.Reason
+		if action == model.ModerationActionReject {
+			msg.Content = decision.Original
+		}
+	}
+	return decision
+}
+
+// applyWordList 按敏感词表检查内容（只检查会话严格程度对应的等级）
+func (s *ModerationService) applyWordList(matcher *wordMatcher, msg *model.Message, decision *ModerationDecision) {
+	text := []rune(msg.Content)
+	matches := matcher.Match(text, model.ModerationStrictnessStrict+1-decision.Strictness)
+	if len(matches) == 0 {
+		return
+	}
+
+	masked := make([]bool, len(text))
+	seen := make(map[string]bool)
+	for _, m := range matches {
+		action := s.severityAction(m.Severity)
+		if action > decision.Action {
+			decision.Action = action
+		}
+		if action == model.ModerationActionFlag {
+			decision.Review = true
+		}
+		if action == model.ModerationActionMask {
+			for i := m.Start; i < m.End; i++ {
+				masked[i] = true
+			}
+		}
+		if action > model.ModerationActionPass && !seen[m.Word] && len(seen) < moderationMaxLoggedWords {
+			seen[m.Word] = true
+			decision.MatchedWords = append(decision.MatchedWords, m.Word)
+		}
+	}
+	if decision.Action == model.ModerationActionPass {
+		return
+	}
+	decision.Source = ModerationSourceWordList
+	sort.Strings(decision.MatchedWords)
+
+	// 拒绝时不需要替换；否则替换需要屏蔽的词（同时命中标记的词不影响替换）
+	if decision.Action == model.ModerationActionReject {
+		return
+	}
+	for i, m := range masked {
+		if m {
+			text[i] = '*'
+		}
+	}
+	msg.Content = string(text)
+}
+
+// severityAction 敏感词等级对应的动作
+func (s *ModerationService) severityAction(severity int) int {
+	switch severity {
+	case wordSeverityLow:
+		return s.config.LowAction
+	case wordSeverityHigh:
+		return s.config.HighAction
+	default:
+		return s.config.MediumAction
+	}
+}
+
+// Audit 记录未通过的审核结果（消息保存后调用，被拒绝的消息没有 ServerMsgID）
+func (s *ModerationService) Audit(msg *model.Message, decision *ModerationDecision) {
+	if decision == nil || decision.Action == model.ModerationActionPass {
+		return
+	}
+
+	entry := &model.ModerationLog{
+		ID:             utils.GenerateID(),
+		ConversationID: msg.ConversationID,
+		ServerMsgID:    msg.ServerMsgID,
+		ClientMsgID:    msg.ClientMsgID,
+		SenderID:       msg.SenderID,
+		MessageType:    msg.MessageType,
+		Action:         decision.Action,
+		Source:         decision.Source,
+		Strictness:     decision.Strictness,
+		MatchedWords:   truncateUTF8(strings.Join(decision.MatchedWords, ","), 1024),
+		Reason:         truncateUTF8(decision.Reason, 512),
+		Content:        decision.Original,
+	}
+	if decision.Review && decision.Action != model.ModerationActionReject {
+		entry.ReviewStatus = model.ModerationReviewPending
+	}
+	if err := repository.DB.Create(entry).Error; err != nil {
+		logger.Error("Failed to write moderation log",
+			zap.String("conversation_id", msg.ConversationID),
+			zap.String("sender_id", msg.SenderID),
+			zap.Error(err))
+	}
+}
+
+// GetStrictness 获取会话的审核严格程度（单聊使用全局默认值）
+func (s *ModerationService) GetStrictness(groupID string) int {
+	strictness, _, err := s.GetGroupStrictness(groupID)
+	if err != nil {
+		logger.Warn("Failed to load group moderation setting", zap.String("group_id", groupID), zap.Error(err))
+		return s.config.DefaultStrictness
+	}
+	return strictness
+}
+
+// GetGroupStrictness 获取群组的审核严格程度，override 表示是否是群组单独设置的
+func (s *ModerationService) GetGroupStrictness(groupID string) (strictness int, override bool, err error) {
+	if groupID == "" {
+		return s.config.DefaultStrictness, false, nil
+	}
+
+	var setting model.GroupModerationSetting
+	err = repository.DB.Where("group_id = ?", groupID).First(&setting).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.config.DefaultStrictness, false, nil
+		}
+		return 0, false, err
+	}
+	return setting.Strictness, true, nil
+}
+
+// SetGroupStrictness 设置群组的审核严格程度（0 表示恢复全局默认值），仅群主和管理员可以设置
+func (s *ModerationService) SetGroupStrictness(ctx context.Context, groupID, userID string, strictness int) error {
+	if strictness != 0 && !validStrictness(strictness) {
+		return ErrInvalidStrictness
+	}
+
+	role, err := s.groupService.GetMemberRole(ctx, groupID, userID)
+	if err != nil {
+		return err
+	}
+	if role != 1 && role != 2 { // 1=群主，2=管理员
+		return ErrPermissionDenied
+	}
+
+	if strictness == 0 {
+		return repository.DB.Where("group_id = ?", groupID).Delete(&model.GroupModerationSetting{}).Error
+	}
+
+	return repository.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "group_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"strictness", "updated_by", "updated_at"}),
+	}).Create(&model.GroupModerationSetting{
+		GroupID:    groupID,
+		Strictness: strictness,
+		UpdatedBy:  userID,
+	}).Error
+}
+
+// truncateUTF8 按字节截断字符串（不截断在字符中间）
+func truncateUTF8(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	for maxBytes > 0 && !utf8.RuneStart(s[maxBytes]) {
+		maxBytes--
+	}
+	return s[:maxBytes]
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+)
+
+// stubClassifier 返回固定结果的外部分类器
+type stubClassifier struct {
+	verdict *ModerationVerdict
+	err     error
+	calls   int
+}
+
+func (c *stubClassifier) Classify(ctx context.Context, req *ModerationRequest) (*ModerationVerdict, error) {
+	c.calls++
+	return c.verdict, c.err
+}
+
+// newTestModerationService 创建使用临时敏感词表的审核服务（低危标记、中危替换、高危拒绝）
+func newTestModerationService(t *testing.T, words string, classifier ModerationClassifier) (*ModerationService, string) {
+	t.Helper()
+	setupTestDB(t, &model.GroupModerationSetting{}, &model.ModerationLog{}, &model.GroupMember{})
+	wordFile := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(wordFile, []byte(words), 0o644); err != nil {
+		t.Fatalf("write word list: %v", err)
+	}
+	s := NewModerationService(NewContentService(0), NewGroupService(repository.DB), classifier, ModerationConfig{
+		WordFile:          wordFile,
+		DefaultStrictness: model.ModerationStrictnessStandard,
+		LowAction:         model.ModerationActionFlag,
+		MediumAction:      model.ModerationActionMask,
+		HighAction:        model.ModerationActionReject,
+	})
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	return s, wordFile
+}
+
+func TestModerationServiceModerate(t *testing.T) {
+	s, _ := newTestModerationService(t, "# 敏感词\nspam|1\nScam\n炸弹|3\n", nil)
+	addTestGroupMembers(t, "g1", map[string]int{"owner": 1, "member": 3})
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		groupID     string
+		content     string
+		wantAction  int
+		wantContent string
+		wantReview  bool
+	}{
+		{"clean", "", "hello", model.ModerationActionPass, "hello", false},
+		{"medium masked case-insensitively", "", "a SCAM here", model.ModerationActionMask, "a **** here", false},
+		{"low ignored at standard strictness", "", "spam", model.ModerationActionPass, "spam", false},
+		{"high rejected", "", "有炸弹和scam", model.ModerationActionReject, "有炸弹和scam", false},
+		{"low flagged at strict group", "g1", "spam scam", model.ModerationActionMask, "spam ****", true},
+	}
+
+	if err := s.SetGroupStrictness(ctx, "g1", "member", model.ModerationStrictnessStrict); err != ErrPermissionDenied {
+		t.Fatalf("SetGroupStrictness() by member error = %v, want %v", err, ErrPermissionDenied)
+	}
+	if err := s.SetGroupStrictness(ctx, "g1", "owner", model.ModerationStrictnessStrict); err != nil {
+		t.Fatalf("SetGroupStrictness() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		msg := &model.Message{ConversationID: "c", GroupID: tt.groupID, SenderID: "a", MessageType: 1, Content: tt.content}
+		decision := s.Moderate(ctx, msg)
+		if decision.Action != tt.wantAction || msg.Content != tt.wantContent || decision.Review != tt.wantReview {
+			t.Errorf("%s: action %d content %q review %v, want %d %q %v",
+				tt.name, decision.Action, msg.Content, decision.Review, tt.wantAction, tt.wantContent, tt.wantReview)
+		}
+	}
+
+	// 非纯文本内容不做敏感词检查
+	msg := &model.Message{ConversationID: "c", SenderID: "a", MessageType: 5, Content: `{"name":"炸弹.pdf"}`}
+	if decision := s.Moderate(ctx, msg); decision.Action != model.ModerationActionPass {
+		t.Errorf("file message action = %d, want pass", decision.Action)
+	}
+}
+
+func TestModerationServiceAudit(t *testing.T) {
+	s, _ := newTestModerationService(t, "spam|1\nscam\n", nil)
+	ctx := context.Background()
+
+	clean := &model.Message{ConversationID: "c", SenderID: "a", ServerMsgID: "m1", MessageType: 1, Content: "hello"}
+	s.Audit(clean, s.Moderate(ctx, clean))
+	masked := &model.Message{ConversationID: "c", SenderID: "a", ServerMsgID: "m2", MessageType: 1, Content: "scam"}
+	s.Audit(masked, s.Moderate(ctx, masked))
+
+	var logs []model.ModerationLog
+	repository.DB.Find(&logs)
+	if len(logs) != 1 {
+		t.Fatalf("moderation logs = %d, want only the masked message", len(logs))
+	}
+	if logs[0].ServerMsgID != "m2" || logs[0].Content != "scam" || logs[0].MatchedWords != "scam" || logs[0].Source != ModerationSourceWordList {
+		t.Errorf("moderation log = %+v", logs[0])
+	}
+}
+
+func TestModerationServiceClassifier(t *testing.T) {
+	classifier := &stubClassifier{verdict: &ModerationVerdict{Action: model.ModerationActionFlag, Reason: "suspicious"}}
+	s, _ := newTestModerationService(t, "炸弹|3\n", classifier)
+	ctx := context.Background()
+
+	msg := &model.Message{ConversationID: "c", SenderID: "a", MessageType: 1, Content: "hi"}
+	decision := s.Moderate(ctx, msg)
+	s.Audit(msg, decision)
+	if decision.Action != model.ModerationActionFlag || !decision.Review || decision.Source != ModerationSourceClassifier {
+		t.Errorf("flag decision = %+v", decision)
+	}
+	var pending int64
+	repository.DB.Model(&model.ModerationLog{}).Where("review_status = ?", model.ModerationReviewPending).Count(&pending)
+	if pending != 1 {
+		t.Errorf("pending reviews = %d, want 1", pending)
+	}
+
+	// 分类器不能定位敏感内容，替换按拒绝处理
+	classifier.verdict = &ModerationVerdict{Action: model.ModerationActionMask}
+	if decision := s.Moderate(ctx, &model.Message{MessageType: 1, Content: "hi"}); decision.Action != model.ModerationActionReject {
+		t.Errorf("mask verdict action = %d, want reject", decision.Action)
+	}
+
+	// 敏感词已拒绝时不再调用分类器；分类器失败时放行
+	calls := classifier.calls
+	s.Moderate(ctx, &model.Message{MessageType: 1, Content: "炸弹"})
+	if classifier.calls != calls {
+		t.Errorf("classifier called after the word list rejected the message")
+	}
+	classifier.err = errors.New("timeout")
+	if decision := s.Moderate(ctx, &model.Message{MessageType: 1, Content: "hi"}); decision.Action != model.ModerationActionPass {
+		t.Errorf("classifier error action = %d, want pass", decision.Action)
+	}
+}
+
+func TestModerationServiceReload(t *testing.T) {
+	s, wordFile := newTestModerationService(t, "scam\n", nil)
+	ctx := context.Background()
+
+	if err := os.WriteFile(wordFile, []byte("fraud\n"), 0o644); err != nil {
+		t.Fatalf("write word list: %v", err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(wordFile, later, later); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+	if decision := s.Moderate(ctx, &model.Message{MessageType: 1, Content: "scam"}); decision.Action != model.ModerationActionPass {
+		t.Errorf("removed word action = %d, want pass", decision.Action)
+	}
+	if decision := s.Moderate(ctx, &model.Message{MessageType: 1, Content: "fraud"}); decision.Action != model.ModerationActionMask {
+		t.Errorf("added word action = %d, want mask", decision.Action)
+	}
+
+	// 加载失败时继续使用旧词表
+	if err := os.WriteFile(wordFile, []byte("bad|9\n"), 0o644); err != nil {
+		t.Fatalf("write word list: %v", err)
+	}
+	evenLater := later.Add(time.Minute)
+	os.Chtimes(wordFile, evenLater, evenLater)
+	if err := s.Reload(); err == nil {
+		t.Error("Reload() invalid severity: expected error")
+	}
+	if decision := s.Moderate(ctx, &model.Message{MessageType: 1, Content: "fraud"}); decision.Action != model.ModerationActionMask {
+		t.Errorf("after failed reload action = %d, want the previous word list", decision.Action)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// OfflineNotification 离线推送通知
+type OfflineNotification struct {
+	ConversationID string
+	ServerMsgID    string
+	Seq            int64
+	SenderID       string
+	GroupID        string
+	Preview        string // 内容预览
+	Mentioned      bool   // 是否 @ 了接收者（@ 消息可以突破会话免打扰）
+	Badge          int64  // 接收者的未读总数（应用角标）
+}
+
+// OfflinePusher 离线推送通道（APNs / FCM / 厂商通道等，由部署方实现）
+type OfflinePusher interface {
+	Push(userID string, notification *OfflineNotification) error
+}
+
+// logPusher 默认的离线推送通道，只记录日志
+type logPusher struct{}
+
+// Push 记录离线推送日志
+func (logPusher) Push(userID string, notification *OfflineNotification) error {
+	logger.Debug("Offline push",
+		zap.String("user_id", userID),
+		zap.String("conversation_id", notification.ConversationID),
+		zap.String("server_msg_id", notification.ServerMsgID),
+		zap.Bool("mentioned", notification.Mentioned),
+		zap.Int64("badge", notification.Badge))
+	return nil
+}
+
+// OfflinePushService 离线推送服务（用户不在线时决定是否以及如何发送离线通知）
+type OfflinePushService struct {
+	pusher         OfflinePusher
+	contentService *ContentService
+	unreadService  *UnreadService
+}
+
+// NewOfflinePushService 创建离线推送服务（pusher 为 nil 时只记录日志）
+func NewOfflinePushService(pusher OfflinePusher, contentService *ContentService, unreadService *UnreadService) *OfflinePushService {
+	if pusher == nil {
+		pusher = logPusher{}
+	}
+	return &OfflinePushService{
+		pusher:         pusher,
+		contentService: contentService,
+		unreadService:  unreadService,
+	}
+}
+
+// NotifyNewMessage 给不在线的用户发送新消息离线通知（免打扰的会话只推送 @ 了接收者的消息）
+func (s *OfflinePushService) NotifyNewMessage(userID string, msg *model.Message) {
+	mentioned := IsMentioned(msg, userID)
+	if !mentioned {
+		muted, err := s.unreadService.IsMuted(userID, msg.ConversationID)
+		if err != nil {
+			logger.Warn("Failed to check conversation mute", zap.String("user_id", userID), zap.Error(err))
+		}
+		if muted {
+			logger.Debug("Offline push skipped for muted conversation",
+				zap.String("user_id", userID),
+				zap.String("conversation_id", msg.ConversationID))
+			return
+		}
+	}
+
+	notification := &OfflineNotification{
+		ConversationID: msg.ConversationID,
+		ServerMsgID:    msg.ServerMsgID,
+		Seq:            msg.Seq,
+		SenderID:       msg.SenderID,
+		GroupID:        msg.GroupID,
+		Preview:        s.messagePreview(msg),
+		Mentioned:      mentioned,
+	}
+	if notification.Mentioned {
+		notification.Preview = "[有人@我] " + notification.Preview
+	}
+	if badge, err := s.unreadService.GetTotalUnread(userID); err == nil {
+		notification.Badge = badge
+	} else {
+		logger.Warn("Failed to get total unread", zap.String("user_id", userID), zap.Error(err))
+	}
+
+	if err := s.pusher.Push(userID, notification); err != nil {
+		logger.Warn("Failed to send offline push",
+			zap.String("user_id", userID),
+			zap.String("conversation_id", msg.ConversationID),
+			zap.Error(err))
+	}
+}
+
+// messagePreview 生成消息内容预览（用于离线推送）
+func (s *OfflinePushService) messagePreview(msg *model.Message) string {
+	// 阅后即焚消息不在通知中展示内容
+	if msg.ExpireMode != model.ExpireModeNone {
+		return "[阅后即焚消息]"
+	}
+	return s.contentService.Preview(msg.MessageType, msg.Content)
+}
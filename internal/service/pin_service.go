@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxPinnedMessages 每个会话最多置顶的消息数
+const maxPinnedMessages = 50
+
+// PinService 消息置顶服务
+type PinService struct {
+	groupService *GroupService
+}
+
+// NewPinService 创建消息置顶服务
+func NewPinService(groupService *GroupService) *PinService {
+	return &PinService{
+		groupService: groupService,
+	}
+}
+
+// CanPin 检查用户是否可以置顶/取消置顶消息（群聊仅群主/管理员，单聊收发双方均可）
+func (s *PinService) CanPin(ctx context.Context, msg *model.Message, userID string) (bool, error) {
+	if msg.GroupID == "" {
+		return msg.SenderID == userID || msg.ReceiverID == userID, nil
+	}
+
+	role, err := s.groupService.GetMemberRole(ctx, msg.GroupID, userID)
+	if err != nil {
+		if err == ErrNotGroupMember {
+			return false, nil
+		}
+		return false, err
+	}
+	return role == 1 || role == 2, nil
+}
+
+// PinMessage 置顶消息（重复置顶视为成功，保留最初的置顶信息）
+func (s *PinService) PinMessage(msg *model.Message, userID string) (*model.PinnedMessage, error) {
+	pin := &model.PinnedMessage{
+		ID:             utils.GenerateID(),
+		ConversationID: msg.ConversationID,
+		Seq:            msg.Seq,
+		ServerMsgID:    msg.ServerMsgID,
+		PinnedBy:       userID,
+		PinnedAt:       utils.GetCurrentMillis(),
+	}
+
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		// 锁定会话的序列号行：同一会话的置顶串行执行，数量检查到写入之间不会插入其他置顶
+		var sequence model.MessageSequence
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("conversation_id = ?", msg.ConversationID).
+			Limit(1).
+			Find(&sequence).Error
+		if err != nil {
+			return err
+		}
+
+		// 已置顶时直接返回已有记录（置顶数量已满时重复置顶也应成功）
+		var existing model.PinnedMessage
+		err = tx.Where("conversation_id = ? AND seq = ?", msg.ConversationID, msg.Seq).Limit(1).Find(&existing).Error
+		if err != nil {
+			return err
+		}
+		if existing.ID != "" {
+			*pin = existing
+			return nil
+		}
+
+		var count int64
+		if err := tx.Model(&model.PinnedMessage{}).
+			Where("conversation_id = ?", msg.ConversationID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count >= maxPinnedMessages {
+			return ErrTooManyPins
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(pin)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return tx.Where("conversation_id = ? AND seq = ?", msg.ConversationID, msg.Seq).First(pin).Error
+		}
+		return touchMessage(tx, msg.ConversationID, msg.Seq)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pin, nil
+}
+
+// UnpinMessage 取消置顶消息（未置顶视为成功）
+func (s *PinService) UnpinMessage(conversationID string, seq int64) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("conversation_id = ? AND seq = ?", conversationID, seq).Delete(&model.PinnedMessage{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return touchMessage(tx, conversationID, seq)
+	})
+}
+
+// GetPinnedMessages 获取会话的置顶列表（按置顶时间倒序，最新置顶的在前）
+func (s *PinService) GetPinnedMessages(conversationID string) ([]*model.PinnedMessage, error) {
+	var pins []*model.PinnedMessage
+	err := repository.DB.Where("conversation_id = ?", conversationID).
+		Order("pinned_at DESC").
+		Find(&pins).Error
+	return pins, err
+}
+
+// GetPinnedMessagesMap 批量获取多个会话的置顶列表（map[conversationID][]PinnedMessage）
+func (s *PinService) GetPinnedMessagesMap(conversationIDs []string) (map[string][]*model.PinnedMessage, error) {
+	result := make(map[string][]*model.PinnedMessage)
+	if len(conversationIDs) == 0 {
+		return result, nil
+	}
+
+	var pins []*model.PinnedMessage
+	err := repository.DB.Where("conversation_id IN ?", conversationIDs).
+		Order("conversation_id ASC, pinned_at DESC").
+		Find(&pins).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pin := range pins {
+		result[pin.ConversationID] = append(result[pin.ConversationID], pin)
+	}
+	return result, nil
+}
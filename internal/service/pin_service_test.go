@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+func TestPinServicePinMessage(t *testing.T) {
+	msgService := newTestMessageService(t, &model.PinnedMessage{})
+	s := NewPinService(nil)
+	first := saveTestMessage(t, msgService, "single_a_b", "a", "first")
+	second := saveTestMessage(t, msgService, "single_a_b", "a", "second")
+
+	pin, err := s.PinMessage(first, "a")
+	if err != nil {
+		t.Fatalf("PinMessage() error = %v", err)
+	}
+	// 重复置顶返回最初的置顶信息
+	again, err := s.PinMessage(first, "b")
+	if err != nil || again.ID != pin.ID || again.PinnedBy != "a" {
+		t.Fatalf("PinMessage() again = %+v, %v, want the original pin", again, err)
+	}
+
+	// 填满置顶数量
+	for seq := int64(100); seq < 100+maxPinnedMessages-1; seq++ {
+		err := repository.DB.Create(&model.PinnedMessage{ID: utils.GenerateID(), ConversationID: "single_a_b", Seq: seq, PinnedBy: "a"}).Error
+		if err != nil {
+			t.Fatalf("create pin: %v", err)
+		}
+	}
+	if _, err := s.PinMessage(second, "a"); err != ErrTooManyPins {
+		t.Errorf("PinMessage() over limit error = %v, want %v", err, ErrTooManyPins)
+	}
+	if _, err := s.PinMessage(first, "a"); err != nil {
+		t.Errorf("PinMessage() already pinned at limit error = %v", err)
+	}
+
+	if err := s.UnpinMessage("single_a_b", first.Seq); err != nil {
+		t.Fatalf("UnpinMessage() error = %v", err)
+	}
+	if err := s.UnpinMessage("single_a_b", first.Seq); err != nil {
+		t.Errorf("UnpinMessage() again error = %v", err)
+	}
+	if _, err := s.PinMessage(second, "a"); err != nil {
+		t.Errorf("PinMessage() after unpin error = %v", err)
+	}
+
+	pins, err := s.GetPinnedMessages("single_a_b")
+	if err != nil || len(pins) != maxPinnedMessages {
+		t.Errorf("GetPinnedMessages() = %d pins, %v, want %d", len(pins), err, maxPinnedMessages)
+	}
+}
+
+func TestPinServiceCanPin(t *testing.T) {
+	setupTestDB(t, &model.GroupMember{})
+	addTestGroupMembers(t, "g1", map[string]int{"owner": 1, "admin": 2, "member": 3})
+	s := NewPinService(NewGroupService(repository.DB))
+	ctx := context.Background()
+
+	tests := []struct {
+		msg    *model.Message
+		userID string
+		want   bool
+	}{
+		{&model.Message{SenderID: "a", ReceiverID: "b"}, "b", true},
+		{&model.Message{SenderID: "a", ReceiverID: "b"}, "c", false},
+		{&model.Message{GroupID: "g1"}, "owner", true},
+		{&model.Message{GroupID: "g1"}, "admin", true},
+		{&model.Message{GroupID: "g1"}, "member", false},
+		{&model.Message{GroupID: "g1"}, "outsider", false},
+	}
+	for _, tt := range tests {
+		got, err := s.CanPin(ctx, tt.msg, tt.userID)
+		if err != nil || got != tt.want {
+			t.Errorf("CanPin(%q) = %v, %v, want %v", tt.userID, got, err, tt.want)
+		}
+	}
+}
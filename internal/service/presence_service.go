@@ -0,0 +1,593 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxPresenceQuery         = 200  // 单次最多查询的用户数
+	maxPresenceSubscriptions = 1000 // 每个用户最多订阅的用户数
+	maxPresenceTextLen       = 256
+	maxPresenceEmojiLen      = 32
+)
+
+// 在线状态设置字段（修改设置时指定要修改的字段）
+const (
+	PresenceFieldStatus             = "status"
+	PresenceFieldLastSeenVisibility = "last_seen_visibility"
+)
+
+// presenceKey 用户各平台的在线状态（hash，field 为平台，value 为上线时间，毫秒）
+// key 设置过期时间并由心跳续期，节点异常退出时在线状态自动失效
+func presenceKey(userID string) string {
+	return fmt.Sprintf("presence:%s", userID)
+}
+
+// PlatformPresence 平台在线状态
+type PlatformPresence struct {
+	Platform    string
+	OnlineSince int64
+}
+
+// CustomStatus 自定义状态（忙碌、离开或自定义文字和表情）
+type CustomStatus struct {
+	Type     int
+	Text     string
+	Emoji    string
+	ExpireAt int64 // 过期时间（毫秒，0 表示不过期）
+}
+
+// Presence 用户在线状态
+type Presence struct {
+	UserID    string
+	Online    bool
+	Platforms []PlatformPresence // 按平台名排序
+	LastSeen  int64              // 最后在线时间（毫秒，在线、未知或不可见时为 0）
+	Status    *CustomStatus      // 自定义状态（未设置或已过期时为 nil）
+
+	lastSeenVisibility int // 最后在线时间可见范围（model.LastSeenEveryone 等）
+}
+
+// signature 用于判断在线状态是否发生了变化（只推送变化）
+func (p *Presence) signature() string {
+	var b strings.Builder
+	b.WriteString(strconv.FormatBool(p.Online))
+	for _, platform := range p.Platforms {
+		b.WriteString("|")
+		b.WriteString(platform.Platform)
+	}
+	if p.Status != nil {
+		fmt.Fprintf(&b, "#%d|%s|%s|%d", p.Status.Type, p.Status.Text, p.Status.Emoji, p.Status.ExpireAt)
+	}
+	return b.String()
+}
+
+// presenceExpiry 自定义状态的过期时间
+type presenceExpiry struct {
+	userID   string
+	expireAt int64 // 毫秒
+}
+
+// presenceExpiryHeap 按过期时间排序的最小堆
+type presenceExpiryHeap []presenceExpiry
+
+func (h presenceExpiryHeap) Len() int            { return len(h) }
+func (h presenceExpiryHeap) Less(i, j int) bool  { return h[i].expireAt < h[j].expireAt }
+func (h presenceExpiryHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *presenceExpiryHeap) Push(x interface{}) { *h = append(*h, x.(presenceExpiry)) }
+func (h *presenceExpiryHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// PresenceNotifyFunc 推送在线状态变化给订阅者（由消息处理器实现）
+type PresenceNotifyFunc func(presence *Presence, subscriberIDs []string)
+
+// PresenceService 在线状态服务：各平台的在线状态保存在 Redis 中，最后在线时间保存在 online_status 表，
+// 自定义状态和最后在线时间的可见范围保存在用户表；
+// 订阅关系保存在内存中并随订阅者的连接断开而清除；在线状态变化经过防抖后推送给订阅者，连接频繁断开重连时不会反复推送
+type PresenceService struct {
+	friendService *FriendService
+	ttl           time.Duration // 在线状态的过期时间（心跳超时）
+	debounce      time.Duration // 状态变化后等待该时间再推送
+
+	mu            sync.Mutex
+	subscribers   map[string]map[string]struct{} // 被订阅者 -> 订阅者
+	subscriptions map[string]map[string]struct{} // 订阅者 -> 被订阅者
+	lastPushed    map[string]string              // 被订阅者 -> 最近一次推送（或订阅时返回）的状态
+	pending       map[string]time.Time           // 被订阅者 -> 到期推送时间
+	expiries      presenceExpiryHeap             // 自定义状态的过期时间（到期时作为一次状态变化推送）
+	expireAt      map[string]int64               // 被订阅者 -> 堆中有效的过期时间（堆中其他记录已失效）
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPresenceService 创建在线状态服务
+func NewPresenceService(friendService *FriendService, ttl, debounce time.Duration) *PresenceService {
+	if ttl <= 0 {
+		ttl = 90 * time.Second
+	}
+	if debounce < 0 {
+		debounce = 0
+	}
+	return &PresenceService{
+		friendService: friendService,
+		ttl:           ttl,
+		debounce:      debounce,
+		subscribers:   make(map[string]map[string]struct{}),
+		subscriptions: make(map[string]map[string]struct{}),
+		lastPushed:    make(map[string]string),
+		pending:       make(map[string]time.Time),
+		expireAt:      make(map[string]int64),
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// SetOnline 用户在某个平台上线
+func (s *PresenceService) SetOnline(userID, platform string) error {
+	ctx := context.Background()
+	now := utils.GetCurrentMillis()
+
+	pipe := repository.RedisClient.TxPipeline()
+	pipe.HSet(ctx, presenceKey(userID), platform, now)
+	pipe.Expire(ctx, presenceKey(userID), s.ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	s.markChanged(userID)
+	return s.saveStatus(userID, platform, 1, time.UnixMilli(now))
+}
+
+// SetOffline 用户在某个平台下线（所有平台都下线时记录最后在线时间）
+func (s *PresenceService) SetOffline(userID, platform string) error {
+	ctx := context.Background()
+	if err := repository.RedisClient.HDel(ctx, presenceKey(userID), platform).Err(); err != nil {
+		return err
+	}
+
+	s.markChanged(userID)
+	remaining, err := repository.RedisClient.HLen(ctx, presenceKey(userID)).Result()
+	if err != nil || remaining > 0 {
+		return err
+	}
+	return s.saveStatus(userID, platform, 0, time.Now())
+}
+
+// Touch 心跳：续期在线状态
+func (s *PresenceService) Touch(userID string) error {
+	return repository.RedisClient.Expire(context.Background(), presenceKey(userID), s.ttl).Err()
+}
+
+// saveStatus 保存在线状态和最后在线时间
+func (s *PresenceService) saveStatus(userID, platform string, status int, lastOnline time.Time) error {
+	return repository.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "platform", "last_online", "updated_at"}),
+	}).Create(&model.OnlineStatus{
+		UserID:     userID,
+		Status:     status,
+		Platform:   platform,
+		LastOnline: lastOnline,
+	}).Error
+}
+
+// GetPresences 查询多个用户的在线状态（按 userIDs 的顺序返回）
+func (s *PresenceService) GetPresences(userIDs []string) ([]*Presence, error) {
+	if len(userIDs) > maxPresenceQuery {
+		return nil, ErrTooManyPresenceUsers
+	}
+	ctx := context.Background()
+	presences := make([]*Presence, 0, len(userIDs))
+	if len(userIDs) == 0 {
+		return presences, nil
+	}
+
+	pipe := repository.RedisClient.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(userIDs))
+	for i, userID := range userIDs {
+		cmds[i] = pipe.HGetAll(ctx, presenceKey(userID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	var offline []string
+	for i, userID := range userIDs {
+		p := &Presence{UserID: userID}
+		fields, err := cmds[i].Result()
+		if err != nil && err != redis.Nil {
+			return nil, err
+		}
+		for platform, since := range fields {
+			onlineSince, _ := strconv.ParseInt(since, 10, 64)
+			p.Platforms = append(p.Platforms, PlatformPresence{Platform: platform, OnlineSince: onlineSince})
+		}
+		sort.Slice(p.Platforms, func(a, b int) bool { return p.Platforms[a].Platform < p.Platforms[b].Platform })
+		p.Online = len(p.Platforms) > 0
+		if !p.Online {
+			offline = append(offline, userID)
+		}
+		presences = append(presences, p)
+	}
+
+	if err := s.loadSettings(userIDs, presences); err != nil {
+		return nil, err
+	}
+
+	if len(offline) > 0 {
+		var rows []model.OnlineStatus
+		if err := repository.DB.Where("user_id IN ?", offline).Find(&rows).Error; err != nil {
+			return nil, err
+		}
+		lastSeen := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			if !row.LastOnline.IsZero() {
+				lastSeen[row.UserID] = row.LastOnline.UnixMilli()
+			}
+		}
+		for _, p := range presences {
+			if !p.Online {
+				p.LastSeen = lastSeen[p.UserID]
+			}
+		}
+	}
+	return presences, nil
+}
+
+// loadSettings 加载用户的自定义状态和最后在线时间可见范围（已过期的自定义状态视为未设置）
+func (s *PresenceService) loadSettings(userIDs []string, presences []*Presence) error {
+	var users []model.User
+	err := repository.DB.Select("id, presence_status, presence_text, presence_emoji, presence_expire_at, last_seen_visibility").
+		Where("id IN ?", userIDs).
+		Find(&users).Error
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*model.User, len(users))
+	for i := range users {
+		byID[users[i].ID] = &users[i]
+	}
+
+	now := utils.GetCurrentMillis()
+	for _, p := range presences {
+		user, ok := byID[p.UserID]
+		if !ok {
+			continue
+		}
+		p.lastSeenVisibility = user.LastSeenVisibility
+		if user.PresenceStatus == model.PresenceStatusNone || (user.PresenceExpireAt > 0 && user.PresenceExpireAt <= now) {
+			continue
+		}
+		p.Status = &CustomStatus{
+			Type:     user.PresenceStatus,
+			Text:     user.PresenceText,
+			Emoji:    user.PresenceEmoji,
+			ExpireAt: user.PresenceExpireAt,
+		}
+	}
+	return nil
+}
+
+// UpdateSettings 修改用户的自定义状态（status 为 nil 或类型为 0 表示清除）和最后在线时间可见范围，并推送给订阅者
+func (s *PresenceService) UpdateSettings(userID string, fields []string, status *CustomStatus, lastSeenVisibility int) error {
+	if len(fields) == 0 {
+		return ErrInvalidPresence
+	}
+	if status == nil {
+		status = &CustomStatus{}
+	}
+
+	updates := make(map[string]interface{}, 5)
+	for _, field := range fields {
+		switch field {
+		case PresenceFieldStatus:
+			if status.Type < model.PresenceStatusNone || status.Type > model.PresenceStatusCustom ||
+				len(status.Text) > maxPresenceTextLen || len(status.Emoji) > maxPresenceEmojiLen ||
+				(status.ExpireAt != 0 && status.ExpireAt <= utils.GetCurrentMillis()) {
+				return ErrInvalidPresence
+			}
+			if status.Type == model.PresenceStatusNone {
+				status = &CustomStatus{}
+			}
+			updates["presence_status"] = status.Type
+			updates["presence_text"] = status.Text
+			updates["presence_emoji"] = status.Emoji
+			updates["presence_expire_at"] = status.ExpireAt
+		case PresenceFieldLastSeenVisibility:
+			if lastSeenVisibility < model.LastSeenEveryone || lastSeenVisibility > model.LastSeenNobody {
+				return ErrInvalidPresence
+			}
+			updates["last_seen_visibility"] = lastSeenVisibility
+		default:
+			return ErrInvalidPresence
+		}
+	}
+
+	if err := repository.DB.Model(&model.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+		return err
+	}
+	if _, ok := updates["presence_expire_at"]; ok {
+		s.mu.Lock()
+		s.scheduleExpiry(userID, status.ExpireAt)
+		s.mu.Unlock()
+	}
+	s.markChanged(userID)
+	return nil
+}
+
+// GetLastSeenVisibility 获取用户设置的最后在线时间可见范围
+func (s *PresenceService) GetLastSeenVisibility(userID string) (int, error) {
+	var user model.User
+	err := repository.DB.Select("last_seen_visibility").Where("id = ?", userID).First(&user).Error
+	return user.LastSeenVisibility, err
+}
+
+// ApplyPrivacy 按被查询者的设置隐藏 viewerID 无权看到的最后在线时间（自己总是可以看到）
+func (s *PresenceService) ApplyPrivacy(viewerID string, presences []*Presence) error {
+	var friendsOnly []string
+	for _, p := range presences {
+		switch {
+		case p.UserID == viewerID || p.lastSeenVisibility == model.LastSeenEveryone:
+		case p.lastSeenVisibility == model.LastSeenFriends:
+			friendsOnly = append(friendsOnly, p.UserID)
+		default:
+			p.LastSeen = 0
+		}
+	}
+	if len(friendsOnly) == 0 {
+		return nil
+	}
+
+	owners, err := s.friendService.GetFriendOwners(viewerID, friendsOnly)
+	if err != nil {
+		return err
+	}
+	for _, p := range presences {
+		if p.UserID != viewerID && p.lastSeenVisibility == model.LastSeenFriends && !owners[p.UserID] {
+			p.LastSeen = 0
+		}
+	}
+	return nil
+}
+
+// LastSeenViewers 返回 viewerIDs 中可以看到 presence 最后在线时间的用户（map[userID]）
+func (s *PresenceService) LastSeenViewers(presence *Presence, viewerIDs []string) (map[string]bool, error) {
+	switch presence.lastSeenVisibility {
+	case model.LastSeenEveryone:
+		viewers := make(map[string]bool, len(viewerIDs))
+		for _, id := range viewerIDs {
+			viewers[id] = true
+		}
+		return viewers, nil
+	case model.LastSeenFriends:
+		return s.friendService.GetFriendsAmong(presence.UserID, viewerIDs)
+	default:
+		return map[string]bool{}, nil
+	}
+}
+
+// Subscribe 订阅用户的在线状态变化，返回这些用户当前的在线状态
+func (s *PresenceService) Subscribe(subscriberID string, userIDs []string) ([]*Presence, error) {
+	presences, err := s.GetPresences(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	targets := s.subscriptions[subscriberID]
+	added := 0
+	for _, p := range presences {
+		if _, ok := targets[p.UserID]; !ok && p.UserID != subscriberID {
+			added++
+		}
+	}
+	if len(targets)+added > maxPresenceSubscriptions {
+		return nil, ErrTooManySubscriptions
+	}
+
+	if targets == nil {
+		targets = make(map[string]struct{})
+		s.subscriptions[subscriberID] = targets
+	}
+	for _, p := range presences {
+		if p.UserID == subscriberID {
+			continue
+		}
+		targets[p.UserID] = struct{}{}
+		if s.subscribers[p.UserID] == nil {
+			s.subscribers[p.UserID] = make(map[string]struct{})
+		}
+		s.subscribers[p.UserID][subscriberID] = struct{}{}
+		if _, ok := s.lastPushed[p.UserID]; !ok {
+			s.lastPushed[p.UserID] = p.signature()
+		}
+		if p.Status != nil {
+			s.scheduleExpiry(p.UserID, p.Status.ExpireAt)
+		}
+	}
+	return presences, nil
+}
+
+// Unsubscribe 取消订阅用户的在线状态变化
+func (s *PresenceService) Unsubscribe(subscriberID string, userIDs []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, userID := range userIDs {
+		s.removeSubscription(subscriberID, userID)
+	}
+}
+
+// UnsubscribeAll 取消用户的全部订阅（订阅者的连接断开时调用）
+func (s *PresenceService) UnsubscribeAll(subscriberID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for userID := range s.subscriptions[subscriberID] {
+		s.removeSubscription(subscriberID, userID)
+	}
+	delete(s.subscriptions, subscriberID)
+}
+
+// removeSubscription 删除一条订阅关系（调用方持有锁）
+func (s *PresenceService) removeSubscription(subscriberID, userID string) {
+	if targets, ok := s.subscriptions[subscriberID]; ok {
+		delete(targets, userID)
+		if len(targets) == 0 {
+			delete(s.subscriptions, subscriberID)
+		}
+	}
+	if subs, ok := s.subscribers[userID]; ok {
+		delete(subs, subscriberID)
+		if len(subs) == 0 {
+			delete(s.subscribers, userID)
+			delete(s.lastPushed, userID)
+			delete(s.pending, userID)
+			delete(s.expireAt, userID)
+		}
+	}
+}
+
+// markChanged 记录用户的在线状态发生了变化（有订阅者时在防抖时间后推送）
+func (s *PresenceService) markChanged(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscribers[userID]; !ok {
+		return
+	}
+	// 防抖：每次变化都重新计时，状态稳定后才推送
+	s.pending[userID] = time.Now().Add(s.debounce)
+}
+
+// scheduleExpiry 记录自定义状态的过期时间（0 表示不过期），到期后由推送任务推送给订阅者（调用方持有锁）
+func (s *PresenceService) scheduleExpiry(userID string, expireAt int64) {
+	if expireAt <= 0 {
+		delete(s.expireAt, userID)
+		return
+	}
+	if s.expireAt[userID] == expireAt {
+		return
+	}
+	s.expireAt[userID] = expireAt
+	heap.Push(&s.expiries, presenceExpiry{userID: userID, expireAt: expireAt})
+}
+
+// markExpired 把自定义状态已过期的用户作为状态变化立即推送（调用方持有锁）
+func (s *PresenceService) markExpired(now time.Time) {
+	nowMillis := now.UnixMilli()
+	for len(s.expiries) > 0 && s.expiries[0].expireAt <= nowMillis {
+		item := heap.Pop(&s.expiries).(presenceExpiry)
+		if s.expireAt[item.userID] != item.expireAt {
+			continue // 状态已被修改或清除
+		}
+		delete(s.expireAt, item.userID)
+		if _, ok := s.subscribers[item.userID]; ok {
+			s.pending[item.userID] = now
+		}
+	}
+}
+
+// Start 启动在线状态变化推送任务
+func (s *PresenceService) Start(interval time.Duration, notify PresenceNotifyFunc) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.flush(notify)
+			}
+		}
+	}()
+}
+
+// Stop 停止在线状态变化推送任务
+func (s *PresenceService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// flush 推送防抖时间已到的在线状态变化（与上次推送的状态相同时不推送）
+func (s *PresenceService) flush(notify PresenceNotifyFunc) {
+	now := time.Now()
+
+	s.mu.Lock()
+	s.markExpired(now)
+	var due []string
+	for userID, at := range s.pending {
+		if !now.Before(at) {
+			due = append(due, userID)
+			delete(s.pending, userID)
+		}
+	}
+	s.mu.Unlock()
+
+	if len(due) == 0 {
+		return
+	}
+
+	for start := 0; start < len(due); start += maxPresenceQuery {
+		end := start + maxPresenceQuery
+		if end > len(due) {
+			end = len(due)
+		}
+		presences, err := s.GetPresences(due[start:end])
+		if err != nil {
+			logger.Error("Failed to get presences", zap.Error(err))
+			continue
+		}
+
+		for _, p := range presences {
+			s.mu.Lock()
+			subs := s.subscribers[p.UserID]
+			signature := p.signature()
+			if len(subs) == 0 || s.lastPushed[p.UserID] == signature {
+				s.mu.Unlock()
+				continue
+			}
+			s.lastPushed[p.UserID] = signature
+			if p.Status != nil {
+				s.scheduleExpiry(p.UserID, p.Status.ExpireAt)
+			}
+			subscriberIDs := make([]string, 0, len(subs))
+			for id := range subs {
+				subscriberIDs = append(subscriberIDs, id)
+			}
+			s.mu.Unlock()
+
+			notify(p, subscriberIDs)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+// presencePush 一次在线状态推送
+type presencePush struct {
+	presence    *Presence
+	subscribers []string
+}
+
+// newTestPresenceService 创建使用 miniredis 和测试数据库的在线状态服务
+func newTestPresenceService(t *testing.T, debounce time.Duration) *PresenceService {
+	t.Helper()
+	setupTestDB(t, &model.User{}, &model.OnlineStatus{}, &model.Friend{})
+	setupTestRedis(t)
+	return NewPresenceService(NewFriendService(), time.Minute, debounce)
+}
+
+// flushPresence 执行一次推送任务并返回推送的内容
+func flushPresence(s *PresenceService) []presencePush {
+	var pushes []presencePush
+	s.flush(func(presence *Presence, subscriberIDs []string) {
+		pushes = append(pushes, presencePush{presence, subscriberIDs})
+	})
+	return pushes
+}
+
+// platformNames 在线状态中的平台名
+func platformNames(p *Presence) []string {
+	var names []string
+	for _, platform := range p.Platforms {
+		names = append(names, platform.Platform)
+	}
+	return names
+}
+
+func TestPresenceServicePresences(t *testing.T) {
+	s := newTestPresenceService(t, 0)
+
+	if err := s.SetOnline("b", "web"); err != nil {
+		t.Fatalf("SetOnline() error = %v", err)
+	}
+	if err := s.SetOnline("b", "ios"); err != nil {
+		t.Fatalf("SetOnline() error = %v", err)
+	}
+	if err := s.SetOnline("c", "ios"); err != nil {
+		t.Fatalf("SetOnline() error = %v", err)
+	}
+	if err := s.SetOffline("c", "ios"); err != nil {
+		t.Fatalf("SetOffline() error = %v", err)
+	}
+
+	presences, err := s.GetPresences([]string{"b", "c", "d"})
+	if err != nil {
+		t.Fatalf("GetPresences() error = %v", err)
+	}
+	if !presences[0].Online || !reflect.DeepEqual(platformNames(presences[0]), []string{"ios", "web"}) || presences[0].LastSeen != 0 {
+		t.Errorf("presence b = %+v, want online on ios and web", presences[0])
+	}
+	if presences[1].Online || presences[1].LastSeen == 0 {
+		t.Errorf("presence c = %+v, want offline with last seen", presences[1])
+	}
+	if presences[2].Online || presences[2].LastSeen != 0 {
+		t.Errorf("presence d = %+v, want offline and never seen", presences[2])
+	}
+
+	if _, err := s.GetPresences(make([]string, maxPresenceQuery+1)); err != ErrTooManyPresenceUsers {
+		t.Errorf("GetPresences() over limit error = %v, want %v", err, ErrTooManyPresenceUsers)
+	}
+}
+
+func TestPresenceServiceSubscribe(t *testing.T) {
+	s := newTestPresenceService(t, 0)
+	s.SetOnline("b", "ios")
+
+	presences, err := s.Subscribe("a", []string{"a", "b"})
+	if err != nil || len(presences) != 2 {
+		t.Fatalf("Subscribe() = %d presences, %v", len(presences), err)
+	}
+	if _, ok := s.subscriptions["a"]["a"]; ok {
+		t.Error("Subscribe() subscribed the subscriber to itself")
+	}
+	s.Subscribe("c", []string{"b"})
+
+	// 订阅时返回的状态不再重复推送
+	if pushes := flushPresence(s); len(pushes) != 0 {
+		t.Errorf("flush() after subscribe pushed %d, want 0", len(pushes))
+	}
+
+	s.SetOnline("b", "web")
+	pushes := flushPresence(s)
+	if len(pushes) != 1 || !reflect.DeepEqual(platformNames(pushes[0].presence), []string{"ios", "web"}) || len(pushes[0].subscribers) != 2 {
+		t.Fatalf("flush() after new platform = %+v, want one push to both subscribers", pushes)
+	}
+
+	// 断线后在防抖时间内重连，状态与上次推送相同时不推送
+	s.SetOffline("b", "web")
+	s.SetOnline("b", "web")
+	if pushes := flushPresence(s); len(pushes) != 0 {
+		t.Errorf("flush() after reconnect pushed %d, want 0", len(pushes))
+	}
+
+	s.Unsubscribe("c", []string{"b"})
+	s.SetOffline("b", "web")
+	s.SetOffline("b", "ios")
+	pushes = flushPresence(s)
+	if len(pushes) != 1 || pushes[0].presence.Online || pushes[0].presence.LastSeen == 0 || !reflect.DeepEqual(pushes[0].subscribers, []string{"a"}) {
+		t.Fatalf("flush() after going offline = %+v, want offline pushed to a only", pushes)
+	}
+
+	// 取消全部订阅后不再推送
+	s.UnsubscribeAll("a")
+	s.SetOnline("b", "ios")
+	if pushes := flushPresence(s); len(pushes) != 0 || len(s.subscribers) != 0 {
+		t.Errorf("flush() after UnsubscribeAll pushed %d, subscribers = %v", len(pushes), s.subscribers)
+	}
+}
+
+func TestPresenceServiceDebounceAndLimit(t *testing.T) {
+	s := newTestPresenceService(t, time.Hour)
+	s.Subscribe("a", []string{"b"})
+	s.SetOnline("b", "ios")
+	if pushes := flushPresence(s); len(pushes) != 0 {
+		t.Errorf("flush() within debounce pushed %d, want 0", len(pushes))
+	}
+
+	// b 已订阅，再订阅到上限
+	for start := 1; start < maxPresenceSubscriptions; start += maxPresenceQuery {
+		var batch []string
+		for i := start; i < start+maxPresenceQuery && i < maxPresenceSubscriptions; i++ {
+			batch = append(batch, fmt.Sprintf("u%d", i))
+		}
+		if _, err := s.Subscribe("a", batch); err != nil {
+			t.Fatalf("Subscribe() from %d error = %v", start, err)
+		}
+	}
+	if _, err := s.Subscribe("a", []string{"b", "extra"}); err != ErrTooManySubscriptions {
+		t.Errorf("Subscribe() over limit error = %v, want %v", err, ErrTooManySubscriptions)
+	}
+	if _, err := s.Subscribe("a", []string{"b"}); err != nil {
+		t.Errorf("Subscribe() an existing subscription at the limit error = %v", err)
+	}
+}
+
+func TestPresenceServiceCustomStatus(t *testing.T) {
+	s := newTestPresenceService(t, 0)
+	for _, id := range []string{"a", "b"} {
+		if err := repository.DB.Create(&model.User{ID: id, Username: id, Password: "x"}).Error; err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+	}
+
+	now := utils.GetCurrentMillis()
+	invalid := []struct {
+		fields     []string
+		status     *CustomStatus
+		visibility int
+	}{
+		{nil, nil, 0},
+		{[]string{PresenceFieldStatus}, &CustomStatus{Type: model.PresenceStatusCustom + 1}, 0},
+		{[]string{PresenceFieldStatus}, &CustomStatus{Type: model.PresenceStatusCustom, Text: strings.Repeat("x", maxPresenceTextLen+1)}, 0},
+		{[]string{PresenceFieldStatus}, &CustomStatus{Type: model.PresenceStatusCustom, ExpireAt: now - 1}, 0},
+		{[]string{PresenceFieldLastSeenVisibility}, nil, model.LastSeenNobody + 1},
+		{[]string{"online"}, nil, 0},
+	}
+	for _, tt := range invalid {
+		if err := s.UpdateSettings("b", tt.fields, tt.status, tt.visibility); err != ErrInvalidPresence {
+			t.Errorf("UpdateSettings(%v, %+v, %d) error = %v, want %v", tt.fields, tt.status, tt.visibility, err, ErrInvalidPresence)
+		}
+	}
+
+	s.Subscribe("a", []string{"b"})
+	status := &CustomStatus{Type: model.PresenceStatusCustom, Text: "meeting", Emoji: "📅", ExpireAt: now + 100}
+	if err := s.UpdateSettings("b", []string{PresenceFieldStatus}, status, 0); err != nil {
+		t.Fatalf("UpdateSettings() error = %v", err)
+	}
+	pushes := flushPresence(s)
+	if len(pushes) != 1 || !reflect.DeepEqual(pushes[0].presence.Status, status) {
+		t.Fatalf("flush() after status set = %+v, want the custom status pushed", pushes)
+	}
+
+	// 自定义状态到期后作为一次状态变化推送
+	if pushes := flushPresence(s); len(pushes) != 0 {
+		t.Errorf("flush() before expiry pushed %d, want 0", len(pushes))
+	}
+	time.Sleep(150 * time.Millisecond)
+	pushes = flushPresence(s)
+	if len(pushes) != 1 || pushes[0].presence.Status != nil {
+		t.Fatalf("flush() after expiry = %+v, want the status cleared", pushes)
+	}
+
+	// 类型为 0 表示清除，过期时间一并清除
+	s.UpdateSettings("b", []string{PresenceFieldStatus}, &CustomStatus{Type: model.PresenceStatusCustom, Text: "lunch"}, 0)
+	s.UpdateSettings("b", []string{PresenceFieldStatus}, &CustomStatus{Type: model.PresenceStatusNone, Text: "ignored", ExpireAt: now + 1000}, 0)
+	presences, err := s.GetPresences([]string{"b"})
+	if err != nil || presences[0].Status != nil {
+		t.Errorf("GetPresences() after clearing = %+v, %v, want no status", presences[0].Status, err)
+	}
+	var user model.User
+	repository.DB.First(&user, "id = ?", "b")
+	if user.PresenceText != "" || user.PresenceExpireAt != 0 {
+		t.Errorf("stored status = %q until %d, want cleared", user.PresenceText, user.PresenceExpireAt)
+	}
+}
+
+func TestPresenceServicePrivacy(t *testing.T) {
+	s := newTestPresenceService(t, 0)
+	visibility := map[string]int{"everyone": model.LastSeenEveryone, "friends": model.LastSeenFriends, "nobody": model.LastSeenNobody}
+	for id, v := range visibility {
+		repository.DB.Create(&model.User{ID: id, Username: id, Password: "x", LastSeenVisibility: v})
+		s.SetOnline(id, "ios")
+		s.SetOffline(id, "ios")
+	}
+	repository.DB.Create(&model.Friend{ID: "f1", UserID: "friends", FriendID: "viewer", Status: model.FriendStatusNormal})
+	repository.DB.Create(&model.Friend{ID: "f2", UserID: "friends", FriendID: "blocked", Status: model.FriendStatusBlocked})
+
+	lastSeen := func(viewerID string) map[string]bool {
+		presences, err := s.GetPresences([]string{"everyone", "friends", "nobody"})
+		if err != nil {
+			t.Fatalf("GetPresences() error = %v", err)
+		}
+		if err := s.ApplyPrivacy(viewerID, presences); err != nil {
+			t.Fatalf("ApplyPrivacy() error = %v", err)
+		}
+		visible := make(map[string]bool)
+		for _, p := range presences {
+			visible[p.UserID] = p.LastSeen > 0
+		}
+		return visible
+	}
+
+	if got := lastSeen("viewer"); !reflect.DeepEqual(got, map[string]bool{"everyone": true, "friends": true, "nobody": false}) {
+		t.Errorf("last seen visible to a friend = %v", got)
+	}
+	if got := lastSeen("blocked"); !reflect.DeepEqual(got, map[string]bool{"everyone": true, "friends": false, "nobody": false}) {
+		t.Errorf("last seen visible to a blocked user = %v", got)
+	}
+	if got := lastSeen("nobody"); !got["nobody"] {
+		t.Error("users should always see their own last seen")
+	}
+
+	presences, _ := s.GetPresences([]string{"friends", "nobody"})
+	viewers, err := s.LastSeenViewers(presences[0], []string{"viewer", "blocked", "stranger"})
+	if err != nil || !reflect.DeepEqual(viewers, map[string]bool{"viewer": true}) {
+		t.Errorf("LastSeenViewers() friends only = %v, %v", viewers, err)
+	}
+	if viewers, err := s.LastSeenViewers(presences[1], []string{"viewer"}); err != nil || len(viewers) != 0 {
+		t.Errorf("LastSeenViewers() nobody = %v, %v", viewers, err)
+	}
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxEmojiLength 表情字符串最大长度（字节）
+const maxEmojiLength = 32
+
+// ReactionSummary 单条消息某个表情的回应汇总
+type ReactionSummary struct {
+	Emoji       string
+	Count       int
+	ReactedByMe bool
+}
+
+// ReactionService 表情回应服务
+type ReactionService struct{}
+
+// NewReactionService 创建表情回应服务
+func NewReactionService() *ReactionService {
+	return &ReactionService{}
+}
+
+// AddReaction 添加表情回应（重复添加视为成功），返回该表情当前的回应人数
+func (s *ReactionService) AddReaction(conversationID string, seq int64, emoji, userID string) (int, error) {
+	if emoji == "" || len(emoji) > maxEmojiLength {
+		return 0, ErrInvalidEmoji
+	}
+
+	reaction := &model.MessageReaction{
+		ID:             utils.GenerateID(),
+		ConversationID: conversationID,
+		Seq:            seq,
+		Emoji:          emoji,
+		UserID:         userID,
+	}
+
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(reaction)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return touchMessage(tx, conversationID, seq)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return s.CountReaction(conversationID, seq, emoji)
+}
+
+// RemoveReaction 取消表情回应，返回该表情剩余的回应人数
+func (s *ReactionService) RemoveReaction(conversationID string, seq int64, emoji, userID string) (int, error) {
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("conversation_id = ? AND seq = ? AND emoji = ? AND user_id = ?", conversationID, seq, emoji, userID).
+			Delete(&model.MessageReaction{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return touchMessage(tx, conversationID, seq)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return s.CountReaction(conversationID, seq, emoji)
+}
+
+// CountReaction 统计某条消息某个表情的回应人数
+func (s *ReactionService) CountReaction(conversationID string, seq int64, emoji string) (int, error) {
+	var count int64
+	err := repository.DB.Model(&model.MessageReaction{}).
+		Where("conversation_id = ? AND seq = ? AND emoji = ?", conversationID, seq, emoji).
+		Count(&count).Error
+	return int(count), err
+}
+
+// GetReactionSummaries 批量获取一个会话中多条消息的回应汇总（map[seq][]ReactionSummary）
+func (s *ReactionService) GetReactionSummaries(conversationID string, seqs []int64, userID string) (map[int64][]*ReactionSummary, error) {
+	result := make(map[int64][]*ReactionSummary)
+	if len(seqs) == 0 {
+		return result, nil
+	}
+
+	// 按 (seq, emoji) 聚合回应人数
+	var counts []struct {
+		Seq   int64
+		Emoji string
+		Count int
+	}
+	err := repository.DB.Model(&model.MessageReaction{}).
+		Select("seq, emoji, COUNT(*) AS count").
+		Where("conversation_id = ? AND seq IN ?", conversationID, seqs).
+		Group("seq, emoji").
+		Order("seq ASC, MIN(created_at) ASC").
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(counts) == 0 {
+		return result, nil
+	}
+
+	// 当前用户自己的回应
+	var mine []model.MessageReaction
+	err = repository.DB.Select("seq, emoji").
+		Where("conversation_id = ? AND seq IN ? AND user_id = ?", conversationID, seqs, userID).
+		Find(&mine).Error
+	if err != nil {
+		return nil, err
+	}
+	reactedByMe := make(map[int64]map[string]bool)
+	for _, r := range mine {
+		if reactedByMe[r.Seq] == nil {
+			reactedByMe[r.Seq] = make(map[string]bool)
+		}
+		reactedByMe[r.Seq][r.Emoji] = true
+	}
+
+	for _, c := range counts {
+		result[c.Seq] = append(result[c.Seq], &ReactionSummary{
+			Emoji:       c.Emoji,
+			Count:       c.Count,
+			ReactedByMe: reactedByMe[c.Seq][c.Emoji],
+		})
+	}
+
+	return result, nil
+}
+
+// touchMessage 刷新消息的 updated_at，使已同步过该消息的客户端在增量同步时拿到最新的回应汇总
+func touchMessage(tx *gorm.DB, conversationID string, seq int64) error {
+	return tx.Model(&model.Message{}).
+		Where("conversation_id = ? AND seq = ?", conversationID, seq).
+		UpdateColumn("updated_at", time.Now()).Error
+}
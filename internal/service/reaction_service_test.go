@@ -0,0 +1,67 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/arwen/im-server/internal/model"
+)
+
+func TestReactionServiceAddRemove(t *testing.T) {
+	msgService := newTestMessageService(t, &model.MessageReaction{})
+	msg := saveTestMessage(t, msgService, "group_g1", "a", "hello")
+	s := NewReactionService()
+
+	if _, err := s.AddReaction(msg.ConversationID, msg.Seq, "", "a"); err != ErrInvalidEmoji {
+		t.Fatalf("AddReaction() empty emoji error = %v, want %v", err, ErrInvalidEmoji)
+	}
+
+	steps := []struct {
+		add    bool
+		emoji  string
+		userID string
+		want   int
+	}{
+		{true, "👍", "a", 1},
+		{true, "👍", "a", 1}, // 重复添加视为成功
+		{true, "👍", "b", 2},
+		{true, "❤️", "b", 1},
+		{false, "👍", "a", 1},
+		{false, "👍", "a", 1}, // 未回应时取消视为成功
+	}
+	for i, step := range steps {
+		var count int
+		var err error
+		if step.add {
+			count, err = s.AddReaction(msg.ConversationID, msg.Seq, step.emoji, step.userID)
+		} else {
+			count, err = s.RemoveReaction(msg.ConversationID, msg.Seq, step.emoji, step.userID)
+		}
+		if err != nil || count != step.want {
+			t.Fatalf("step %d: count = %d, err = %v, want %d", i, count, err, step.want)
+		}
+	}
+
+	summaries, err := s.GetReactionSummaries(msg.ConversationID, []int64{msg.Seq}, "b")
+	if err != nil {
+		t.Fatalf("GetReactionSummaries() error = %v", err)
+	}
+	got := summaries[msg.Seq]
+	if len(got) != 2 {
+		t.Fatalf("GetReactionSummaries() = %+v, want 2 emojis", got)
+	}
+	for _, summary := range got {
+		if summary.Count != 1 || !summary.ReactedByMe {
+			t.Errorf("summary %q = count %d reactedByMe %v, want 1 and true", summary.Emoji, summary.Count, summary.ReactedByMe)
+		}
+	}
+
+	mine, err := s.GetReactionSummaries(msg.ConversationID, []int64{msg.Seq}, "a")
+	if err != nil {
+		t.Fatalf("GetReactionSummaries() error = %v", err)
+	}
+	for _, summary := range mine[msg.Seq] {
+		if summary.ReactedByMe {
+			t.Errorf("summary %q reactedByMe = true for a user who removed the reaction", summary.Emoji)
+		}
+	}
+}
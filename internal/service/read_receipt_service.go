@@ -0,0 +1,407 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	groupReadPushLimit = 100 // 每个会话每次最多推送最近多少条消息的已读人数
+	readCursorRetries  = 3   // 并发推进已读游标时的重试次数
+)
+
+// MessageReadState 消息的已读情况（发送者视角）
+type MessageReadState struct {
+	ServerMsgID string
+	Seq         int64
+	ReadCount   int
+	UnreadCount int
+	ReadBy      []string // 已读成员（群人数超过上限时为空）
+}
+
+// MessageReader 已读成员
+type MessageReader struct {
+	UserID   string
+	ReadTime int64 // 已读游标最近一次前进的时间
+}
+
+// ReadProgress 一次已读回执推进的已读位置
+type ReadProgress struct {
+	FromSeq   int64 // 推进前的已读位置
+	ReadSeq   int64 // 推进后的已读位置（游标没有前进时等于 FromSeq）
+	ReadCount int64 // 本次新读的别人发送的、未撤回的消息数
+}
+
+// GroupReadNotifyFunc 推送聚合后的群聊已读回执给消息发送者（由消息处理器实现）
+type GroupReadNotifyFunc func(senderID, conversationID string, states []*MessageReadState)
+
+// groupReadRange 待推送的群聊已读范围（上一个周期内各成员已读游标扫过的 seq 区间的并集）
+type groupReadRange struct {
+	groupID string
+	fromSeq int64 // 不包含
+	toSeq   int64 // 包含
+}
+
+// ReadReceiptService 已读回执服务：维护每个用户在每个会话中的已读游标，并统计群聊消息的已读人数
+// 群成员的已读回执不会逐条推送给发送者，而是按会话聚合，定期推送最新的已读人数
+type ReadReceiptService struct {
+	groupService *GroupService
+	readByLimit  int // 群人数不超过该值时同步消息附带已读成员列表
+	mu           sync.Mutex
+	pending      map[string]*groupReadRange // 待推送的会话（按 conversationID 聚合）
+	stopCh       chan struct{}
+	stopOnce     sync.Once
+}
+
+// NewReadReceiptService 创建已读回执服务
+func NewReadReceiptService(groupService *GroupService, readByLimit int) *ReadReceiptService {
+	return &ReadReceiptService{
+		groupService: groupService,
+		readByLimit:  readByLimit,
+		pending:      make(map[string]*groupReadRange),
+		stopCh:       make(chan struct{}),
+	}
+}
+
+// MarkRead 推进用户在会话中的已读位置
+// readSeq > 0 时读到该 seq；否则读到 serverMsgIDs 中 seq 最大的一条；两者都为空表示读到会话最新的消息
+func (s *ReadReceiptService) MarkRead(conversationID, userID string, readSeq int64, serverMsgIDs []string, readTime int64) (*ReadProgress, error) {
+	target, err := s.resolveReadSeq(conversationID, readSeq, serverMsgIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	fromSeq, advanced, err := advanceReadCursor(userID, conversationID, target, readTime)
+	if err != nil {
+		return nil, err
+	}
+	progress := &ReadProgress{FromSeq: fromSeq, ReadSeq: fromSeq}
+	if !advanced {
+		return progress, nil
+	}
+	progress.ReadSeq = target
+
+	err = repository.DB.Model(&model.Message{}).
+		Where("conversation_id = ? AND seq > ? AND seq <= ? AND sender_id != ? AND status != 4", conversationID, fromSeq, target, userID).
+		Count(&progress.ReadCount).Error
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// resolveReadSeq 计算已读回执对应的 seq（不超过会话当前的最大 seq）
+func (s *ReadReceiptService) resolveReadSeq(conversationID string, readSeq int64, serverMsgIDs []string) (int64, error) {
+	var maxSeq int64
+	err := repository.DB.Model(&model.MessageSequence{}).
+		Where("conversation_id = ?", conversationID).
+		Select("COALESCE(MAX(max_seq), 0)").
+		Scan(&maxSeq).Error
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case readSeq > 0:
+		if readSeq < maxSeq {
+			return readSeq, nil
+		}
+		return maxSeq, nil
+	case len(serverMsgIDs) > 0:
+		var seq int64
+		err := repository.DB.Model(&model.Message{}).
+			Where("conversation_id = ? AND server_msg_id IN ?", conversationID, serverMsgIDs).
+			Select("COALESCE(MAX(seq), 0)").
+			Scan(&seq).Error
+		return seq, err
+	default:
+		return maxSeq, nil
+	}
+}
+
+// advanceReadCursor 把已读游标推进到 readSeq（只前进不后退），返回推进前的位置和是否前进
+// 以推进前的位置作为条件更新，保证并发回执得到的区间 (fromSeq, readSeq] 互不重叠
+func advanceReadCursor(userID, conversationID string, readSeq, readTime int64) (int64, bool, error) {
+	for i := 0; i < readCursorRetries; i++ {
+		var cursor model.ConversationReadCursor
+		err := repository.DB.Where("user_id = ? AND conversation_id = ?", userID, conversationID).
+			Take(&cursor).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if readSeq <= 0 {
+				return 0, false, nil
+			}
+			result := repository.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&model.ConversationReadCursor{
+				UserID:         userID,
+				ConversationID: conversationID,
+				ReadSeq:        readSeq,
+				ReadTime:       readTime,
+			})
+			if result.Error != nil {
+				return 0, false, result.Error
+			}
+			if result.RowsAffected == 1 {
+				return 0, true, nil
+			}
+			continue // 并发创建，重新读取
+		}
+		if err != nil {
+			return 0, false, err
+		}
+
+		if cursor.ReadSeq >= readSeq {
+			return cursor.ReadSeq, false, nil
+		}
+		result := repository.DB.Model(&model.ConversationReadCursor{}).
+			Where("user_id = ? AND conversation_id = ? AND read_seq = ?", userID, conversationID, cursor.ReadSeq).
+			Updates(map[string]interface{}{
+				"read_seq":  readSeq,
+				"read_time": readTime,
+			})
+		if result.Error != nil {
+			return 0, false, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return cursor.ReadSeq, true, nil
+		}
+	}
+	return 0, false, errors.New("read cursor update conflict")
+}
+
+// GetReadSeq 获取用户在会话中的已读位置
+func (s *ReadReceiptService) GetReadSeq(userID, conversationID string) (int64, error) {
+	var readSeq int64
+	err := repository.DB.Model(&model.ConversationReadCursor{}).
+		Where("user_id = ? AND conversation_id = ?", userID, conversationID).
+		Select("COALESCE(MAX(read_seq), 0)").
+		Scan(&readSeq).Error
+	return readSeq, err
+}
+
+// GetReadSeqs 批量获取用户在多个会话中的已读位置（map[conversationID]，没有游标的会话不返回）
+func (s *ReadReceiptService) GetReadSeqs(userID string, conversationIDs []string) (map[string]int64, error) {
+	result := make(map[string]int64, len(conversationIDs))
+	if len(conversationIDs) == 0 {
+		return result, nil
+	}
+
+	var cursors []model.ConversationReadCursor
+	err := repository.DB.Select("conversation_id, read_seq").
+		Where("user_id = ? AND conversation_id IN ?", userID, conversationIDs).
+		Find(&cursors).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cursors {
+		result[c.ConversationID] = c.ReadSeq
+	}
+	return result, nil
+}
+
+// CountUnread 统计用户在会话中的未读数：已读位置之后别人发送的、未撤回且对当前用户可见的消息
+func (s *ReadReceiptService) CountUnread(userID, conversationID string) (int64, error) {
+	readSeq, err := s.GetReadSeq(userID, conversationID)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = repository.DB.Model(&model.Message{}).
+		Where("conversation_id = ? AND seq > ? AND sender_id != ? AND messages.status != 4", conversationID, readSeq, userID).
+		Scopes(visibleToUser(userID, conversationID)).
+		Count(&count).Error
+	return count, err
+}
+
+// RecordGroupReads 记录群成员已读游标扫过的范围 (fromSeq, toSeq]，等待聚合推送给消息的发送者
+func (s *ReadReceiptService) RecordGroupReads(groupID, conversationID string, fromSeq, toSeq int64) {
+	if toSeq <= fromSeq {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.pending[conversationID]
+	if !ok {
+		s.pending[conversationID] = &groupReadRange{groupID: groupID, fromSeq: fromSeq, toSeq: toSeq}
+		return
+	}
+	if fromSeq < r.fromSeq {
+		r.fromSeq = fromSeq
+	}
+	if toSeq > r.toSeq {
+		r.toSeq = toSeq
+	}
+}
+
+// Start 启动聚合推送任务
+func (s *ReadReceiptService) Start(interval time.Duration, notify GroupReadNotifyFunc) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.flush(notify)
+			}
+		}
+	}()
+}
+
+// Stop 停止聚合推送任务
+func (s *ReadReceiptService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// flush 推送上一个周期内有变化的消息的最新已读人数（每个会话只推送范围内最近的 groupReadPushLimit 条消息）
+func (s *ReadReceiptService) flush(notify GroupReadNotifyFunc) {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[string]*groupReadRange)
+	s.mu.Unlock()
+
+	for conversationID, r := range pending {
+		var messages []*model.Message
+		err := repository.DB.
+			Where("conversation_id = ? AND seq > ? AND seq <= ? AND status != 4", conversationID, r.fromSeq, r.toSeq).
+			Order("seq DESC").
+			Limit(groupReadPushLimit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			if err != nil {
+				logger.Error("Failed to load read messages", zap.String("conversation_id", conversationID), zap.Error(err))
+			}
+			continue
+		}
+		sort.Slice(messages, func(i, j int) bool { return messages[i].Seq < messages[j].Seq })
+
+		states, err := s.GetReadStates(r.groupID, conversationID, messages)
+		if err != nil {
+			logger.Error("Failed to get read states",
+				zap.String("conversation_id", conversationID),
+				zap.Error(err))
+			continue
+		}
+
+		bySender := make(map[string][]*MessageReadState)
+		for _, msg := range messages {
+			bySender[msg.SenderID] = append(bySender[msg.SenderID], states[msg.ServerMsgID])
+		}
+		for senderID, list := range bySender {
+			notify(senderID, conversationID, list)
+		}
+	}
+}
+
+// GetReadStates 获取同一群聊中多条消息的已读人数（map[serverMsgID]），群人数不超过上限时附带已读成员
+// 已读成员为已读位置不小于消息 seq 的现有成员；未读人数只统计消息发送时已经在群里的现有成员
+func (s *ReadReceiptService) GetReadStates(groupID, conversationID string, messages []*model.Message) (map[string]*MessageReadState, error) {
+	members, err := s.groupService.GetActiveMembers(context.Background(), groupID)
+	if err != nil {
+		return nil, err
+	}
+	withReadBy := len(members) <= s.readByLimit
+
+	cursors, err := s.loadCursors(conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make(map[string]*MessageReadState, len(messages))
+	for _, msg := range messages {
+		state := &MessageReadState{ServerMsgID: msg.ServerMsgID, Seq: msg.Seq}
+		// 已读和未读都只统计应该读到消息的成员（消息发送后才入群的成员不计入）
+		for _, userID := range eligibleReaders(msg, members) {
+			if c, ok := cursors[userID]; ok && c.ReadSeq >= msg.Seq {
+				state.ReadCount++
+				if withReadBy {
+					state.ReadBy = append(state.ReadBy, userID)
+				}
+			} else {
+				state.UnreadCount++
+			}
+		}
+		sort.Strings(state.ReadBy)
+		states[msg.ServerMsgID] = state
+	}
+	return states, nil
+}
+
+// GetReadMembers 获取消息的已读成员（按读取时间排序）和未读成员（单聊和群聊通用）
+func (s *ReadReceiptService) GetReadMembers(msg *model.Message) ([]*MessageReader, []string, error) {
+	var members []*model.GroupMember
+	if msg.GroupID != "" {
+		var err error
+		if members, err = s.groupService.GetActiveMembers(context.Background(), msg.GroupID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	cursors, err := s.loadCursors(msg.ConversationID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var readList []*MessageReader
+	var unread []string
+	for _, userID := range eligibleReaders(msg, members) {
+		if c, ok := cursors[userID]; ok && c.ReadSeq >= msg.Seq {
+			readList = append(readList, &MessageReader{UserID: userID, ReadTime: c.ReadTime})
+		} else {
+			unread = append(unread, userID)
+		}
+	}
+	sort.Slice(readList, func(i, j int) bool { return readList[i].ReadTime < readList[j].ReadTime })
+	return readList, unread, nil
+}
+
+// eligibleReaders 应该读到消息的用户（单聊为接收者；群聊为消息发送时已在群里的现有成员，不含发送者）
+func eligibleReaders(msg *model.Message, members []*model.GroupMember) []string {
+	if msg.GroupID == "" {
+		return []string{msg.ReceiverID}
+	}
+
+	sentAt := time.UnixMilli(msg.ServerTime)
+	userIDs := make([]string, 0, len(members))
+	for _, m := range members {
+		if m.UserID == msg.SenderID || m.JoinedAt.After(sentAt) {
+			continue
+		}
+		userIDs = append(userIDs, m.UserID)
+	}
+	return userIDs
+}
+
+// loadCursors 查询会话中所有用户的已读游标（map[userID]）
+func (s *ReadReceiptService) loadCursors(conversationID string) (map[string]*model.ConversationReadCursor, error) {
+	var cursors []*model.ConversationReadCursor
+	err := repository.DB.Where("conversation_id = ?", conversationID).Find(&cursors).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*model.ConversationReadCursor, len(cursors))
+	for _, c := range cursors {
+		result[c.UserID] = c
+	}
+	return result, nil
+}
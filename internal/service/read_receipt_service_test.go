@@ -0,0 +1,151 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+// readReceiptTestModels 已读回执测试需要的表
+var readReceiptTestModels = []interface{}{&model.GroupMember{}, &model.ConversationReadCursor{}}
+
+// markTestRead 用户读到会话中的 seq
+func markTestRead(t *testing.T, msgService *MessageService, s *ReadReceiptService, conversationID, userID string, seq, readTime int64) {
+	t.Helper()
+	if _, err := s.MarkRead(conversationID, userID, seq, nil, readTime); err != nil {
+		t.Fatalf("MarkRead() error = %v", err)
+	}
+}
+
+// saveGroupTestMessage 保存一条群消息（serverTime 为发送时间）
+func saveGroupTestMessage(t *testing.T, s *MessageService, groupID, senderID, content string, serverTime int64) *model.Message {
+	t.Helper()
+	msg := &model.Message{ClientMsgID: content, ConversationID: "group_" + groupID, GroupID: groupID, SenderID: senderID, MessageType: 1, Content: content, ServerTime: serverTime, Status: 1}
+	if err := s.SaveMessage(msg); err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	return msg
+}
+
+func TestReadReceiptServiceGroupReadStates(t *testing.T) {
+	msgService := newTestMessageService(t, readReceiptTestModels...)
+	now := time.Now()
+	addTestGroupMembers(t, "g1", map[string]int{"a": 1, "b": 3, "c": 3})
+	// d 在第三条消息发送前入群
+	late := &model.GroupMember{ID: utils.GenerateID(), GroupID: "g1", UserID: "d", Role: 3, Status: 1, JoinedAt: now.Add(-1500 * time.Millisecond)}
+	if err := repository.DB.Create(late).Error; err != nil {
+		t.Fatalf("create group member: %v", err)
+	}
+	s := NewReadReceiptService(NewGroupService(repository.DB), 10)
+
+	var messages []*model.Message
+	for i, content := range []string{"m1", "m2", "m3"} {
+		messages = append(messages, saveGroupTestMessage(t, msgService, "g1", "a", content, now.Add(time.Duration(i-3)*time.Second).UnixMilli()))
+	}
+	markTestRead(t, msgService, s, "group_g1", "b", messages[1].Seq, 200)
+	markTestRead(t, msgService, s, "group_g1", "c", messages[2].Seq, 100)
+
+	states, err := s.GetReadStates("g1", "group_g1", messages)
+	if err != nil {
+		t.Fatalf("GetReadStates() error = %v", err)
+	}
+	want := []struct {
+		read, unread int
+		readBy       []string
+	}{
+		{2, 0, []string{"b", "c"}},
+		{2, 0, []string{"b", "c"}},
+		{1, 2, []string{"c"}},
+	}
+	for i, msg := range messages {
+		state := states[msg.ServerMsgID]
+		if state.ReadCount != want[i].read || state.UnreadCount != want[i].unread || !reflect.DeepEqual(state.ReadBy, want[i].readBy) {
+			t.Errorf("%s state = read %d unread %d by %v, want %+v", msg.Content, state.ReadCount, state.UnreadCount, state.ReadBy, want[i])
+		}
+	}
+
+	// 群人数超过上限时不附带已读成员
+	states, err = NewReadReceiptService(NewGroupService(repository.DB), 2).GetReadStates("g1", "group_g1", messages)
+	if err != nil || states[messages[0].ServerMsgID].ReadCount != 2 || states[messages[0].ServerMsgID].ReadBy != nil {
+		t.Errorf("GetReadStates() over limit = %+v, %v", states[messages[0].ServerMsgID], err)
+	}
+
+	readers, unread, err := s.GetReadMembers(messages[0])
+	if err != nil || len(readers) != 2 || readers[0].UserID != "c" || readers[1].UserID != "b" || len(unread) != 0 {
+		t.Errorf("GetReadMembers(m1) = %+v, %v, %v, want c then b by read time", readers, unread, err)
+	}
+	readers, unread, err = s.GetReadMembers(messages[2])
+	if err != nil || len(readers) != 1 || readers[0].UserID != "c" || !reflect.DeepEqual(unread, []string{"b", "d"}) && !reflect.DeepEqual(unread, []string{"d", "b"}) {
+		t.Errorf("GetReadMembers(m3) = %+v, %v, %v, want read by c and unread by b and d", readers, unread, err)
+	}
+}
+
+func TestReadReceiptServiceSingleChatReadMembers(t *testing.T) {
+	msgService := newTestMessageService(t, readReceiptTestModels...)
+	s := NewReadReceiptService(NewGroupService(repository.DB), 10)
+	msg := &model.Message{ClientMsgID: "hi", ConversationID: "single_a_b", SenderID: "a", ReceiverID: "b", MessageType: 1, Content: "hi", ServerTime: time.Now().UnixMilli(), Status: 1}
+	if err := msgService.SaveMessage(msg); err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+
+	if readers, unread, err := s.GetReadMembers(msg); err != nil || len(readers) != 0 || !reflect.DeepEqual(unread, []string{"b"}) {
+		t.Errorf("GetReadMembers() before read = %+v, %v, %v", readers, unread, err)
+	}
+	// 发送者自己的已读不计入
+	markTestRead(t, msgService, s, "single_a_b", "a", msg.Seq, 50)
+	markTestRead(t, msgService, s, "single_a_b", "b", msg.Seq, 100)
+	readers, unread, err := s.GetReadMembers(msg)
+	if err != nil || len(readers) != 1 || readers[0].UserID != "b" || readers[0].ReadTime != 100 || len(unread) != 0 {
+		t.Errorf("GetReadMembers() after read = %+v, %v, %v", readers, unread, err)
+	}
+}
+
+func TestReadReceiptServiceMarkRead(t *testing.T) {
+	msgService := newTestMessageService(t, readReceiptTestModels...)
+	s := NewReadReceiptService(NewGroupService(repository.DB), 10)
+	var messages []*model.Message
+	for i, sender := range []string{"a", "b", "a", "a"} {
+		messages = append(messages, saveGroupTestMessage(t, msgService, "g1", sender, string(rune('1'+i)), int64(i)))
+	}
+	repository.DB.Model(messages[2]).Update("status", 4)
+
+	tests := []struct {
+		name         string
+		readSeq      int64
+		serverMsgIDs []string
+		want         ReadProgress
+	}{
+		{"by server msg id", 0, []string{messages[1].ServerMsgID, messages[0].ServerMsgID}, ReadProgress{0, 2, 1}},
+		{"does not move back", 1, nil, ReadProgress{2, 2, 0}},
+		{"capped at max seq, skips revoked", 99, nil, ReadProgress{2, 4, 1}},
+		{"already read", 0, nil, ReadProgress{4, 4, 0}},
+	}
+	for _, tt := range tests {
+		progress, err := s.MarkRead("group_g1", "b", tt.readSeq, tt.serverMsgIDs, 100)
+		if err != nil {
+			t.Fatalf("%s: MarkRead() error = %v", tt.name, err)
+		}
+		if *progress != tt.want {
+			t.Errorf("%s: MarkRead() = %+v, want %+v", tt.name, *progress, tt.want)
+		}
+	}
+
+	if seqs, err := s.GetReadSeqs("b", []string{"group_g1", "group_g2"}); err != nil || !reflect.DeepEqual(seqs, map[string]int64{"group_g1": 4}) {
+		t.Errorf("GetReadSeqs() = %v, %v", seqs, err)
+	}
+
+	// 未读数：已读位置之后别人发送的、未撤回且未被删除的消息
+	if _, err := s.MarkRead("group_g1", "c", 1, nil, 100); err != nil {
+		t.Fatalf("MarkRead() error = %v", err)
+	}
+	if _, err := msgService.DeleteMessagesForUser("c", "group_g1", []int64{messages[3].Seq}); err != nil {
+		t.Fatalf("DeleteMessagesForUser() error = %v", err)
+	}
+	if count, err := s.CountUnread("c", "group_g1"); err != nil || count != 1 {
+		t.Errorf("CountUnread() = %d, %v, want 1", count, err)
+	}
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxRetentionDays       = 3650 // 会话可设置的最长保留天数
+	retentionBatchSize     = 1000 // 每批清理的消息数（每批一个短事务，避免长时间锁表）
+	retentionMaxBatches    = 100  // 每轮最多清理的批数（积压时分多轮处理）
+	retentionBatchInterval = 50 * time.Millisecond
+)
+
+// RetentionService 消息保留策略服务
+// 超过保留期限的消息由后台任务分批删除（或归档到 message_archives 后删除），
+// 每个会话清理到的位置记录在 message_sequences.min_seq 上，同步时告知客户端
+type RetentionService struct {
+	groupService  *GroupService
+	searchService *SearchService
+	defaultDays   int  // 全局默认保留天数（<= 0 表示永久保留）
+	archive       bool // 是否在删除前归档
+	stopCh        chan struct{}
+	stopOnce      sync.Once
+}
+
+// NewRetentionService 创建消息保留策略服务
+func NewRetentionService(groupService *GroupService, searchService *SearchService, defaultDays int, archive bool) *RetentionService {
+	return &RetentionService{
+		groupService:  groupService,
+		searchService: searchService,
+		defaultDays:   defaultDays,
+		archive:       archive,
+		stopCh:        make(chan struct{}),
+	}
+}
+
+// GetRetention 获取会话的保留天数（0 表示永久保留），override 表示是否是会话单独设置的
+func (s *RetentionService) GetRetention(conversationID string) (days int, override bool, err error) {
+	var retention model.ConversationRetention
+	err = repository.DB.Where("conversation_id = ?", conversationID).First(&retention).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.effectiveDefault(), false, nil
+		}
+		return 0, false, err
+	}
+	return retention.RetentionDays, true, nil
+}
+
+// SetRetention 设置会话的保留天数（days 为 0 表示恢复全局默认值）
+// 群聊仅群主可以设置，单聊双方均可；调用方需要先确认用户是会话参与者
+func (s *RetentionService) SetRetention(ctx context.Context, conversationID, userID string, days int) error {
+	if days < 0 || days > maxRetentionDays {
+		return ErrInvalidRetention
+	}
+
+	if strings.HasPrefix(conversationID, "group_") {
+		role, err := s.groupService.GetMemberRole(ctx, strings.TrimPrefix(conversationID, "group_"), userID)
+		if err != nil {
+			return err
+		}
+		if role != 1 {
+			return ErrPermissionDenied
+		}
+	}
+
+	if days == 0 {
+		return repository.DB.Where("conversation_id = ?", conversationID).Delete(&model.ConversationRetention{}).Error
+	}
+
+	return repository.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "conversation_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"retention_days", "updated_by", "updated_at"}),
+	}).Create(&model.ConversationRetention{
+		ConversationID: conversationID,
+		RetentionDays:  days,
+		UpdatedBy:      userID,
+	}).Error
+}
+
+// effectiveDefault 全局默认保留天数（0 表示永久保留）
+func (s *RetentionService) effectiveDefault() int {
+	if s.defaultDays < 0 {
+		return 0
+	}
+	return s.defaultDays
+}
+
+// Start 启动过期消息清理任务
+func (s *RetentionService) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logger.Info("Message retention job started",
+			zap.Int("default_days", s.effectiveDefault()),
+			zap.Bool("archive", s.archive),
+			zap.Duration("interval", interval))
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.purge()
+			}
+		}
+	}()
+}
+
+// Stop 停止过期消息清理任务
+func (s *RetentionService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// purge 清理所有超过保留期限的消息（先处理单独设置的会话，再按全局默认值处理其他会话）
+func (s *RetentionService) purge() {
+	now := time.Now()
+	batches := 0
+
+	var overrides []model.ConversationRetention
+	if err := repository.DB.Find(&overrides).Error; err != nil {
+		logger.Error("Failed to load conversation retentions", zap.Error(err))
+		return
+	}
+	for _, r := range overrides {
+		cutoff := utils.TimeToMillis(now.AddDate(0, 0, -r.RetentionDays))
+		query := func() *gorm.DB {
+			return repository.DB.Where("conversation_id = ? AND server_time < ?", r.ConversationID, cutoff).Order("seq ASC")
+		}
+		if !s.purgeBatches(query, &batches) {
+			return
+		}
+	}
+
+	if days := s.effectiveDefault(); days > 0 {
+		cutoff := utils.TimeToMillis(now.AddDate(0, 0, -days))
+		query := func() *gorm.DB {
+			return repository.DB.
+				Where("server_time < ?", cutoff).
+				Where("NOT EXISTS (SELECT 1 FROM conversation_retentions r WHERE r.conversation_id = messages.conversation_id)").
+				Order("server_time ASC")
+		}
+		s.purgeBatches(query, &batches)
+	}
+}
+
+// purgeBatches 按查询条件分批清理，达到本轮批数上限或停止时返回 false
+func (s *RetentionService) purgeBatches(query func() *gorm.DB, batches *int) bool {
+	for {
+		if *batches >= retentionMaxBatches {
+			return false
+		}
+		select {
+		case <-s.stopCh:
+			return false
+		default:
+		}
+
+		var messages []*model.Message
+		if err := query().Limit(retentionBatchSize).Find(&messages).Error; err != nil {
+			logger.Error("Failed to query expired messages", zap.Error(err))
+			return false
+		}
+		if len(messages) == 0 {
+			return true
+		}
+		*batches++
+
+		// 按会话分组，每个会话一个事务
+		byConversation := make(map[string][]*model.Message)
+		var conversationIDs []string
+		for _, msg := range messages {
+			if _, ok := byConversation[msg.ConversationID]; !ok {
+				conversationIDs = append(conversationIDs, msg.ConversationID)
+			}
+			byConversation[msg.ConversationID] = append(byConversation[msg.ConversationID], msg)
+		}
+		for _, conversationID := range conversationIDs {
+			if err := s.purgeConversation(conversationID, byConversation[conversationID]); err != nil {
+				logger.Error("Failed to purge expired messages", zap.Error(err), zap.String("conversation_id", conversationID))
+				return false
+			}
+			seqs := make([]int64, 0, len(byConversation[conversationID]))
+			for _, msg := range byConversation[conversationID] {
+				seqs = append(seqs, msg.Seq)
+			}
+			if err := s.searchService.RemoveMessages(conversationID, seqs); err != nil {
+				logger.Warn("Failed to remove purged messages from search index", zap.Error(err), zap.String("conversation_id", conversationID))
+			}
+		}
+
+		logger.Info("Expired messages purged",
+			zap.Int("message_count", len(messages)),
+			zap.Int("conversation_count", len(conversationIDs)),
+			zap.Bool("archived", s.archive))
+
+		if len(messages) < retentionBatchSize {
+			return true
+		}
+		time.Sleep(retentionBatchInterval)
+	}
+}
+
+// purgeConversation 删除（或归档）同一会话中的一批消息及其关联数据，并推进会话的 min_seq
+func (s *RetentionService) purgeConversation(conversationID string, messages []*model.Message) error {
+	seqs := make([]int64, 0, len(messages))
+	var maxSeq int64
+	for _, msg := range messages {
+		seqs = append(seqs, msg.Seq)
+		if msg.Seq > maxSeq {
+			maxSeq = msg.Seq
+		}
+	}
+
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		if s.archive {
+			archives := make([]*model.MessageArchive, 0, len(messages))
+			for _, msg := range messages {
+				data, err := json.Marshal(msg)
+				if err != nil {
+					return err
+				}
+				archives = append(archives, &model.MessageArchive{
+					ConversationID: msg.ConversationID,
+					Seq:            msg.Seq,
+					ServerMsgID:    msg.ServerMsgID,
+					SenderID:       msg.SenderID,
+					ServerTime:     msg.ServerTime,
+					Data:           string(data),
+					ArchivedAt:     time.Now(),
+				})
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&archives).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("conversation_id = ? AND seq IN ?", conversationID, seqs).Delete(&model.Message{}).Error; err != nil {
+			return err
+		}
+
+		// 关联数据
+		for _, related := range []interface{}{
+			&model.MessageEdit{},
+			&model.MessageReaction{},
+			&model.UserMention{},
+			&model.MessageDeletion{},
+			&model.PinnedMessage{},
+		} {
+			if err := tx.Where("conversation_id = ? AND seq IN ?", conversationID, seqs).Delete(related).Error; err != nil {
+				return err
+			}
+		}
+
+		// min_seq 只前进不后退
+		return tx.Model(&model.MessageSequence{}).
+			Where("conversation_id = ? AND min_seq < ?", conversationID, maxSeq).
+			Update("min_seq", maxSeq).Error
+	})
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+// newTestRetentionService 创建使用测试数据库的保留策略服务
+func newTestRetentionService(defaultDays int, archive bool) *RetentionService {
+	return NewRetentionService(NewGroupService(repository.DB), NewSearchService(NewMemorySearchIndex(), nil, nil), defaultDays, archive)
+}
+
+// saveAgedMessage 保存一条发送于 age 之前的消息
+func saveAgedMessage(t *testing.T, s *MessageService, conversationID, content string, age time.Duration) *model.Message {
+	t.Helper()
+	msg := saveTestMessage(t, s, conversationID, "a", content)
+	msg.ServerTime = utils.TimeToMillis(time.Now().Add(-age))
+	if err := repository.DB.Model(msg).Update("server_time", msg.ServerTime).Error; err != nil {
+		t.Fatalf("update server time: %v", err)
+	}
+	return msg
+}
+
+func TestRetentionServiceSetRetention(t *testing.T) {
+	setupTestDB(t, &model.ConversationRetention{}, &model.GroupMember{})
+	addTestGroupMembers(t, "g1", map[string]int{"owner": 1, "admin": 2})
+	s := newTestRetentionService(30, false)
+	ctx := context.Background()
+
+	if days, override, err := s.GetRetention("single_a_b"); err != nil || days != 30 || override {
+		t.Fatalf("GetRetention() default = %d, %v, %v", days, override, err)
+	}
+	if err := s.SetRetention(ctx, "single_a_b", "a", 7); err != nil {
+		t.Fatalf("SetRetention() error = %v", err)
+	}
+	if days, override, err := s.GetRetention("single_a_b"); err != nil || days != 7 || !override {
+		t.Errorf("GetRetention() override = %d, %v, %v", days, override, err)
+	}
+	if err := s.SetRetention(ctx, "single_a_b", "a", 0); err != nil {
+		t.Fatalf("SetRetention() reset error = %v", err)
+	}
+	if days, override, _ := s.GetRetention("single_a_b"); days != 30 || override {
+		t.Errorf("GetRetention() after reset = %d, %v, want the default", days, override)
+	}
+
+	if err := s.SetRetention(ctx, "single_a_b", "a", maxRetentionDays+1); err != ErrInvalidRetention {
+		t.Errorf("SetRetention() too long error = %v, want %v", err, ErrInvalidRetention)
+	}
+	if err := s.SetRetention(ctx, "group_g1", "admin", 7); err != ErrPermissionDenied {
+		t.Errorf("SetRetention() by admin error = %v, want %v", err, ErrPermissionDenied)
+	}
+	if err := s.SetRetention(ctx, "group_g1", "owner", 7); err != nil {
+		t.Errorf("SetRetention() by owner error = %v", err)
+	}
+}
+
+func TestRetentionServicePurge(t *testing.T) {
+	msgService := newTestMessageService(t,
+		&model.ConversationRetention{}, &model.MessageArchive{}, &model.MessageReaction{},
+		&model.UserMention{}, &model.PinnedMessage{}, &model.GroupMember{})
+	s := newTestRetentionService(30, true)
+	day := 24 * time.Hour
+
+	// 单独设置为 1 天的会话
+	if err := s.SetRetention(context.Background(), "single_a_b", "a", 1); err != nil {
+		t.Fatalf("SetRetention() error = %v", err)
+	}
+	oldOverride := saveAgedMessage(t, msgService, "single_a_b", "a-b old", 2*day)
+	saveAgedMessage(t, msgService, "single_a_b", "a-b new", time.Hour)
+	// 使用全局默认 30 天的会话
+	oldDefault := saveAgedMessage(t, msgService, "single_a_c", "a-c old", 40*day)
+	saveAgedMessage(t, msgService, "single_a_c", "a-c new", 10*day)
+
+	if err := repository.DB.Create(&model.MessageReaction{ID: utils.GenerateID(), ConversationID: "single_a_b", Seq: oldOverride.Seq, UserID: "b", Emoji: "👍"}).Error; err != nil {
+		t.Fatalf("create reaction: %v", err)
+	}
+
+	s.purge()
+
+	var remaining []string
+	repository.DB.Model(&model.Message{}).Order("content ASC").Pluck("content", &remaining)
+	if len(remaining) != 2 || remaining[0] != "a-b new" || remaining[1] != "a-c new" {
+		t.Errorf("remaining messages = %v, want only the messages within retention", remaining)
+	}
+
+	var archived int64
+	repository.DB.Model(&model.MessageArchive{}).Count(&archived)
+	if archived != 2 {
+		t.Errorf("archived messages = %d, want 2", archived)
+	}
+	var reactions int64
+	repository.DB.Model(&model.MessageReaction{}).Count(&reactions)
+	if reactions != 0 {
+		t.Errorf("reactions = %d, want the purged message's reactions removed", reactions)
+	}
+
+	for conversationID, seq := range map[string]int64{"single_a_b": oldOverride.Seq, "single_a_c": oldDefault.Seq} {
+		var sequence model.MessageSequence
+		if err := repository.DB.Where("conversation_id = ?", conversationID).First(&sequence).Error; err != nil {
+			t.Fatalf("load sequence: %v", err)
+		}
+		if sequence.MinSeq != seq {
+			t.Errorf("%s min seq = %d, want %d", conversationID, sequence.MinSeq, seq)
+		}
+	}
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	maxScheduleAhead        = 365 * 24 * time.Hour // 最多提前一年定时
+	maxPendingScheduledMsgs = 100                  // 每个用户最多的待发送定时消息数
+	scheduleClaimBatchSize  = 100                  // 每次轮询最多领取的消息数
+	scheduleLeaseDuration   = time.Minute          // 领取租约时长
+	scheduleMaxAttempts     = 3                    // 最多投递次数
+)
+
+// ScheduledReleaseFunc 投递到期的定时消息（由消息处理器实现：保存消息并推送）
+type ScheduledReleaseFunc func(sm *model.ScheduledMessage) (*model.Message, error)
+
+// ScheduledMessageService 定时消息服务
+// 定时消息持久化在数据库中，调度器定期轮询到期的消息；多个节点通过条件更新领取消息，
+// 同一条消息只会被一个节点投递，节点崩溃后租约到期的消息会被重新领取
+type ScheduledMessageService struct {
+	groupService   *GroupService
+	mentionService *MentionService
+	contentService *ContentService
+	nodeID         string
+	stopCh         chan struct{}
+	stopOnce       sync.Once
+}
+
+// NewScheduledMessageService 创建定时消息服务
+func NewScheduledMessageService(groupService *GroupService, mentionService *MentionService, contentService *ContentService) *ScheduledMessageService {
+	hostname, _ := os.Hostname()
+	return &ScheduledMessageService{
+		groupService:   groupService,
+		mentionService: mentionService,
+		contentService: contentService,
+		nodeID:         fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), utils.GenerateID()),
+		stopCh:         make(chan struct{}),
+	}
+}
+
+// CreateScheduledMessage 创建定时消息
+// 会话ID由接收者/群组生成；群聊要求发送者是群成员，@ 信息在创建时校验（投递时会按当时的群成员再次校验发送者和 @ 信息）
+func (s *ScheduledMessageService) CreateScheduledMessage(ctx context.Context, sm *model.ScheduledMessage) error {
+	if err := validateScheduleTime(sm.ScheduledAt); err != nil {
+		return err
+	}
+	if err := s.contentService.Validate(sm.MessageType, sm.Content); err != nil {
+		return err
+	}
+
+	switch {
+	case sm.GroupID != "":
+		isMember, err := s.groupService.IsGroupMember(ctx, sm.GroupID, sm.SenderID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return ErrNotGroupMember
+		}
+		sm.ReceiverID = ""
+		sm.ConversationID = utils.GetConversationID(2, sm.SenderID, sm.GroupID)
+	case sm.ReceiverID != "":
+		sm.ConversationID = utils.GetConversationID(1, sm.SenderID, sm.ReceiverID)
+	default:
+		return ErrNoScheduleTarget
+	}
+
+	// 校验 @ 信息（规范化后保存）
+	probe := &model.Message{SenderID: sm.SenderID, GroupID: sm.GroupID}
+	if _, err := s.mentionService.ResolveMentions(ctx, probe, ParseMentionUserIDs(sm.MentionUserIDs), sm.MentionAll); err != nil {
+		return err
+	}
+	sm.MentionUserIDs = probe.MentionUserIDs
+	sm.MentionAll = probe.MentionAll
+
+	var pending int64
+	if err := repository.DB.Model(&model.ScheduledMessage{}).
+		Where("sender_id = ? AND status IN ?", sm.SenderID, []int{model.ScheduledStatusPending, model.ScheduledStatusProcessing}).
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending >= maxPendingScheduledMsgs {
+		return ErrTooManyScheduled
+	}
+
+	sm.ID = utils.GenerateID()
+	if sm.ClientMsgID == "" {
+		sm.ClientMsgID = utils.GenerateID()
+	}
+	sm.Status = model.ScheduledStatusPending
+	return repository.DB.Create(sm).Error
+}
+
+// GetScheduledMessage 获取用户的定时消息
+func (s *ScheduledMessageService) GetScheduledMessage(id, senderID string) (*model.ScheduledMessage, error) {
+	var sm model.ScheduledMessage
+	err := repository.DB.Where("id = ? AND sender_id = ?", id, senderID).First(&sm).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return &sm, nil
+}
+
+// ListPendingScheduledMessages 获取用户待发送的定时消息（conversationID 为空表示所有会话），按计划时间排序
+func (s *ScheduledMessageService) ListPendingScheduledMessages(senderID, conversationID string) ([]*model.ScheduledMessage, error) {
+	query := repository.DB.Where("sender_id = ? AND status IN ?", senderID,
+		[]int{model.ScheduledStatusPending, model.ScheduledStatusProcessing})
+	if conversationID != "" {
+		query = query.Where("conversation_id = ?", conversationID)
+	}
+
+	var list []*model.ScheduledMessage
+	err := query.Order("scheduled_at ASC").Find(&list).Error
+	return list, err
+}
+
+// EditScheduledMessage 修改待发送的定时消息（content 为空表示不修改内容，scheduledAt <= 0 表示不修改时间）
+func (s *ScheduledMessageService) EditScheduledMessage(id, senderID, content string, scheduledAt int64) (*model.ScheduledMessage, error) {
+	updates := map[string]interface{}{}
+	if content != "" {
+		sm, err := s.GetScheduledMessage(id, senderID)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.contentService.Validate(sm.MessageType, content); err != nil {
+			return nil, err
+		}
+		updates["content"] = content
+	}
+	if scheduledAt > 0 {
+		if err := validateScheduleTime(scheduledAt); err != nil {
+			return nil, err
+		}
+		updates["scheduled_at"] = scheduledAt
+	}
+	if len(updates) == 0 {
+		return s.GetScheduledMessage(id, senderID)
+	}
+
+	if err := s.updatePending(id, senderID, updates); err != nil {
+		return nil, err
+	}
+	return s.GetScheduledMessage(id, senderID)
+}
+
+// CancelScheduledMessage 取消待发送的定时消息
+func (s *ScheduledMessageService) CancelScheduledMessage(id, senderID string) error {
+	return s.updatePending(id, senderID, map[string]interface{}{
+		"status": model.ScheduledStatusCancelled,
+	})
+}
+
+// updatePending 仅在消息仍处于待发送状态时更新（已被调度器领取的消息不能再修改）
+func (s *ScheduledMessageService) updatePending(id, senderID string, updates map[string]interface{}) error {
+	result := repository.DB.Model(&model.ScheduledMessage{}).
+		Where("id = ? AND sender_id = ? AND status = ?", id, senderID, model.ScheduledStatusPending).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := s.GetScheduledMessage(id, senderID); err != nil {
+			return err
+		}
+		return ErrScheduleNotPending
+	}
+	return nil
+}
+
+// Start 启动调度器（定期投递到期的定时消息）
+func (s *ScheduledMessageService) Start(interval time.Duration, release ScheduledReleaseFunc) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		logger.Info("Scheduled message dispatcher started",
+			zap.String("node_id", s.nodeID),
+			zap.Duration("interval", interval))
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.dispatchDue(release)
+			}
+		}
+	}()
+}
+
+// Stop 停止调度器
+func (s *ScheduledMessageService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// dispatchDue 领取并投递所有到期的定时消息
+func (s *ScheduledMessageService) dispatchDue(release ScheduledReleaseFunc) {
+	for {
+		claimed, err := s.claimDue(scheduleClaimBatchSize)
+		if err != nil {
+			logger.Error("Failed to claim scheduled messages", zap.Error(err))
+			return
+		}
+
+		failed := 0
+		for _, sm := range claimed {
+			msg, err := release(sm)
+			if err != nil {
+				s.markFailed(sm, err)
+				failed++
+				continue
+			}
+			s.markSent(sm, msg)
+		}
+
+		// 有失败时留到下一轮重试，避免同一轮内反复领取失败的消息
+		if len(claimed) < scheduleClaimBatchSize || failed > 0 {
+			return
+		}
+	}
+}
+
+// claimDue 领取到期的定时消息（包括租约已过期的发送中消息）
+func (s *ScheduledMessageService) claimDue(limit int) ([]*model.ScheduledMessage, error) {
+	now := utils.GetCurrentMillis()
+
+	var candidates []*model.ScheduledMessage
+	err := repository.DB.
+		Where("(status = ? AND scheduled_at <= ?) OR (status = ? AND locked_until < ?)",
+			model.ScheduledStatusPending, now, model.ScheduledStatusProcessing, now).
+		Order("scheduled_at ASC").
+		Limit(limit).
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// 逐条条件更新，RowsAffected == 1 表示本节点领取成功（其他节点已领取的会失败）
+	lockedUntil := now + scheduleLeaseDuration.Milliseconds()
+	claimed := make([]*model.ScheduledMessage, 0, len(candidates))
+	for _, sm := range candidates {
+		result := repository.DB.Model(&model.ScheduledMessage{}).
+			Where("id = ? AND status = ? AND locked_until = ?", sm.ID, sm.Status, sm.LockedUntil).
+			Updates(map[string]interface{}{
+				"status":       model.ScheduledStatusProcessing,
+				"locked_by":    s.nodeID,
+				"locked_until": lockedUntil,
+				"attempts":     gorm.Expr("attempts + ?", 1),
+			})
+		if result.Error != nil {
+			logger.Error("Failed to claim scheduled message", zap.Error(result.Error), zap.String("id", sm.ID))
+			continue
+		}
+		if result.RowsAffected == 1 {
+			sm.Status = model.ScheduledStatusProcessing
+			sm.LockedBy = s.nodeID
+			sm.LockedUntil = lockedUntil
+			sm.Attempts++
+			claimed = append(claimed, sm)
+		}
+	}
+
+	return claimed, nil
+}
+
+// markSent 标记定时消息发送成功
+func (s *ScheduledMessageService) markSent(sm *model.ScheduledMessage, msg *model.Message) {
+	err := repository.DB.Model(&model.ScheduledMessage{}).
+		Where("id = ? AND locked_by = ?", sm.ID, s.nodeID).
+		Updates(map[string]interface{}{
+			"status":        model.ScheduledStatusSent,
+			"server_msg_id": msg.ServerMsgID,
+			"seq":           msg.Seq,
+			"last_error":    "",
+		}).Error
+	if err != nil {
+		logger.Error("Failed to mark scheduled message sent", zap.Error(err), zap.String("id", sm.ID))
+		return
+	}
+
+	logger.Info("Scheduled message sent",
+		zap.String("id", sm.ID),
+		zap.String("server_msg_id", msg.ServerMsgID),
+		zap.String("conversation_id", msg.ConversationID),
+		zap.Int64("seq", msg.Seq))
+}
+
+// markFailed 投递失败：未超过最大次数时放回待发送队列，否则标记为失败
+func (s *ScheduledMessageService) markFailed(sm *model.ScheduledMessage, cause error) {
+	status := model.ScheduledStatusPending
+	// 内容未通过审核、发送者已不是群成员或 client_msg_id 已被占用时重试也不会成功
+	if sm.Attempts >= scheduleMaxAttempts || cause == ErrContentRejected || cause == ErrNotGroupMember || cause == ErrScheduleClientMsgID {
+		status = model.ScheduledStatusFailed
+	}
+
+	lastError := cause.Error()
+	if len(lastError) > 512 {
+		lastError = lastError[:512]
+	}
+
+	err := repository.DB.Model(&model.ScheduledMessage{}).
+		Where("id = ? AND locked_by = ?", sm.ID, s.nodeID).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"locked_until": 0,
+			"last_error":   lastError,
+		}).Error
+	if err != nil {
+		logger.Error("Failed to mark scheduled message failed", zap.Error(err), zap.String("id", sm.ID))
+	}
+
+	logger.Warn("Failed to release scheduled message",
+		zap.String("id", sm.ID),
+		zap.Int("attempts", sm.Attempts),
+		zap.Int("status", status),
+		zap.Error(cause))
+}
+
+// validateScheduleTime 校验计划发送时间（必须在将来，且不超过最大提前时长）
+func validateScheduleTime(scheduledAt int64) error {
+	now := time.Now()
+	t := utils.MillisToTime(scheduledAt)
+	if !t.After(now) || t.After(now.Add(maxScheduleAhead)) {
+		return ErrInvalidScheduleTime
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+// createDueScheduledMessage 直接写入一条已到期的定时消息
+func createDueScheduledMessage(t *testing.T, id string) *model.ScheduledMessage {
+	t.Helper()
+	sm := &model.ScheduledMessage{
+		ID:             id,
+		SenderID:       "a",
+		ConversationID: "single_a_b",
+		ReceiverID:     "b",
+		ClientMsgID:    "client-" + id,
+		MessageType:    1,
+		Content:        "scheduled " + id,
+		ScheduledAt:    utils.GetCurrentMillis() - 1000,
+		Status:         model.ScheduledStatusPending,
+	}
+	if err := repository.DB.Create(sm).Error; err != nil {
+		t.Fatalf("create scheduled message: %v", err)
+	}
+	return sm
+}
+
+// getScheduledMessage 读取定时消息的当前状态
+func getScheduledMessage(t *testing.T, id string) *model.ScheduledMessage {
+	t.Helper()
+	var sm model.ScheduledMessage
+	if err := repository.DB.Where("id = ?", id).First(&sm).Error; err != nil {
+		t.Fatalf("load scheduled message: %v", err)
+	}
+	return &sm
+}
+
+func TestScheduledMessageServiceClaimDue(t *testing.T) {
+	setupTestDB(t, &model.ScheduledMessage{})
+	node1 := NewScheduledMessageService(nil, nil, nil)
+	node2 := NewScheduledMessageService(nil, nil, nil)
+	createDueScheduledMessage(t, "s1")
+	future := createDueScheduledMessage(t, "s2")
+	repository.DB.Model(future).Update("scheduled_at", utils.GetCurrentMillis()+time.Hour.Milliseconds())
+
+	claimed, err := node1.claimDue(10)
+	if err != nil || len(claimed) != 1 || claimed[0].ID != "s1" {
+		t.Fatalf("claimDue() node1 = %d claimed, %v, want only the due message", len(claimed), err)
+	}
+	// 租约期内其他节点不能重复领取
+	if claimed, err := node2.claimDue(10); err != nil || len(claimed) != 0 {
+		t.Fatalf("claimDue() node2 = %d claimed, %v, want none", len(claimed), err)
+	}
+
+	// 租约过期后（节点崩溃）其他节点重新领取
+	repository.DB.Model(&model.ScheduledMessage{}).Where("id = ?", "s1").Update("locked_until", utils.GetCurrentMillis()-1)
+	claimed, err = node2.claimDue(10)
+	if err != nil || len(claimed) != 1 || claimed[0].LockedBy != node2.nodeID || claimed[0].Attempts != 2 {
+		t.Fatalf("claimDue() after lease expiry = %+v, %v", claimed, err)
+	}
+
+	// 原节点的租约已失效，不能再修改状态
+	node1.markSent(claimed[0], &model.Message{ServerMsgID: "m1", Seq: 1})
+	if sm := getScheduledMessage(t, "s1"); sm.Status != model.ScheduledStatusProcessing {
+		t.Errorf("status after stale markSent = %d, want %d", sm.Status, model.ScheduledStatusProcessing)
+	}
+	node2.markSent(claimed[0], &model.Message{ServerMsgID: "m1", Seq: 1})
+	if sm := getScheduledMessage(t, "s1"); sm.Status != model.ScheduledStatusSent || sm.ServerMsgID != "m1" {
+		t.Errorf("scheduled message after markSent = status %d msg %q", sm.Status, sm.ServerMsgID)
+	}
+}
+
+func TestScheduledMessageServiceDispatchFailures(t *testing.T) {
+	setupTestDB(t, &model.ScheduledMessage{})
+	s := NewScheduledMessageService(nil, nil, nil)
+	createDueScheduledMessage(t, "retry")
+	createDueScheduledMessage(t, "rejected")
+	createDueScheduledMessage(t, "conflict")
+
+	causes := map[string]error{
+		"retry":    errors.New("database unavailable"),
+		"rejected": ErrContentRejected,
+		"conflict": ErrScheduleClientMsgID,
+	}
+	release := func(sm *model.ScheduledMessage) (*model.Message, error) {
+		return nil, causes[sm.ID]
+	}
+
+	s.dispatchDue(release)
+	if sm := getScheduledMessage(t, "retry"); sm.Status != model.ScheduledStatusPending || sm.LastError == "" {
+		t.Errorf("retry = status %d error %q, want pending with the last error", sm.Status, sm.LastError)
+	}
+	for _, id := range []string{"rejected", "conflict"} {
+		if sm := getScheduledMessage(t, id); sm.Status != model.ScheduledStatusFailed {
+			t.Errorf("%s status = %d, want %d", id, sm.Status, model.ScheduledStatusFailed)
+		}
+	}
+
+	// 超过最大投递次数后标记为失败
+	for i := 1; i < scheduleMaxAttempts; i++ {
+		s.dispatchDue(release)
+	}
+	if sm := getScheduledMessage(t, "retry"); sm.Status != model.ScheduledStatusFailed || sm.Attempts != scheduleMaxAttempts {
+		t.Errorf("retry = status %d attempts %d, want failed after %d attempts", sm.Status, sm.Attempts, scheduleMaxAttempts)
+	}
+}
+
+func TestMessageServiceGetScheduledDelivery(t *testing.T) {
+	s := newTestMessageService(t)
+
+	if _, err := s.GetScheduledDelivery("single_a_b", "client-s1", "s1"); err != ErrMessageNotFound {
+		t.Fatalf("GetScheduledDelivery() before delivery error = %v, want %v", err, ErrMessageNotFound)
+	}
+
+	delivered := &model.Message{ClientMsgID: "client-s1", ConversationID: "single_a_b", SenderID: "a", MessageType: 1, Content: "hi", Status: 1, ScheduleID: "s1"}
+	if err := s.SaveMessage(delivered); err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	msg, err := s.GetScheduledDelivery("single_a_b", "client-s1", "s1")
+	if err != nil || msg.ServerMsgID != delivered.ServerMsgID {
+		t.Errorf("GetScheduledDelivery() = %v, %v, want the delivered message", msg, err)
+	}
+
+	// client_msg_id 被普通消息占用
+	saveTestMessage(t, s, "single_a_b", "a", "client-s2")
+	if _, err := s.GetScheduledDelivery("single_a_b", "client-s2", "s2"); err != ErrScheduleClientMsgID {
+		t.Errorf("GetScheduledDelivery() taken client_msg_id error = %v, want %v", err, ErrScheduleClientMsgID)
+	}
+}
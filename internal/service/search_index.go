@@ -0,0 +1,280 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/arwen/im-server/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSearchTermRunes 单个词项的最大长度（更长的词项不索引）
+const maxSearchTermRunes = 64
+
+// tokenizeSearchText 分词：字母数字按单词切分并转小写，中日韩文字按相邻双字切分
+// forIndex 为 true 时额外写入单字词项，使单字关键词也能命中；查询时单字只在关键词本身只有一个字时使用
+func tokenizeSearchText(text string, forIndex bool) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	add := func(term string) {
+		if term == "" || seen[term] || len([]rune(term)) > maxSearchTermRunes {
+			return
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+
+	var word []rune
+	var cjk []rune
+	flushWord := func() {
+		add(string(word))
+		word = word[:0]
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 || forIndex {
+			for _, r := range cjk {
+				add(string(r))
+			}
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			add(string(cjk[i : i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+
+	return terms
+}
+
+// isCJK 是否为中日韩文字（这些文字不以空格分词）
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// searchDocKey 索引文档键
+type searchDocKey struct {
+	conversationID string
+	seq            int64
+}
+
+// memorySearchIndex 内嵌的内存倒排索引
+// 索引只包含本节点写入的消息，启动时需要通过 SearchService.Rebuild 加载，仅适合单节点部署
+type memorySearchIndex struct {
+	mu       sync.RWMutex
+	docs     map[searchDocKey]*model.MessageSearchDoc
+	postings map[string]map[searchDocKey]struct{}
+}
+
+// NewMemorySearchIndex 创建内存搜索索引
+func NewMemorySearchIndex() SearchIndex {
+	return &memorySearchIndex{
+		docs:     make(map[searchDocKey]*model.MessageSearchDoc),
+		postings: make(map[string]map[searchDocKey]struct{}),
+	}
+}
+
+// Index 写入或更新搜索文档
+func (idx *memorySearchIndex) Index(doc *model.MessageSearchDoc) error {
+	key := searchDocKey{doc.ConversationID, doc.Seq}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.removeLocked(key)
+	d := *doc
+	idx.docs[key] = &d
+	for _, term := range strings.Fields(doc.Terms) {
+		posting, ok := idx.postings[term]
+		if !ok {
+			posting = make(map[searchDocKey]struct{})
+			idx.postings[term] = posting
+		}
+		posting[key] = struct{}{}
+	}
+	return nil
+}
+
+// Remove 删除搜索文档
+func (idx *memorySearchIndex) Remove(conversationID string, seqs []int64) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, seq := range seqs {
+		idx.removeLocked(searchDocKey{conversationID, seq})
+	}
+	return nil
+}
+
+// removeLocked 删除文档及其倒排项（调用方持有写锁）
+func (idx *memorySearchIndex) removeLocked(key searchDocKey) {
+	doc, ok := idx.docs[key]
+	if !ok {
+		return
+	}
+	for _, term := range strings.Fields(doc.Terms) {
+		if posting, ok := idx.postings[term]; ok {
+			delete(posting, key)
+			if len(posting) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+	delete(idx.docs, key)
+}
+
+// Search 从最短的倒排列表开始求交集，再按过滤条件筛选
+func (idx *memorySearchIndex) Search(q *SearchQuery) ([]*SearchHit, error) {
+	if len(q.Terms) == 0 || len(q.ConversationIDs) == 0 {
+		return nil, nil
+	}
+
+	conversations := make(map[string]bool, len(q.ConversationIDs))
+	for _, id := range q.ConversationIDs {
+		conversations[id] = true
+	}
+	messageTypes := make(map[int]bool, len(q.MessageTypes))
+	for _, t := range q.MessageTypes {
+		messageTypes[t] = true
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	postings := make([]map[searchDocKey]struct{}, 0, len(q.Terms))
+	for _, term := range q.Terms {
+		posting, ok := idx.postings[term]
+		if !ok {
+			return nil, nil
+		}
+		postings = append(postings, posting)
+	}
+	sort.Slice(postings, func(i, j int) bool { return len(postings[i]) < len(postings[j]) })
+
+	var hits []*SearchHit
+	for key := range postings[0] {
+		matched := true
+		for _, posting := range postings[1:] {
+			if _, ok := posting[key]; !ok {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+
+		doc := idx.docs[key]
+		if !conversations[doc.ConversationID] ||
+			(q.SenderID != "" && doc.SenderID != q.SenderID) ||
+			(q.StartTime > 0 && doc.ServerTime < q.StartTime) ||
+			(q.EndTime > 0 && doc.ServerTime > q.EndTime) ||
+			(len(messageTypes) > 0 && !messageTypes[doc.MessageType]) ||
+			(q.BeforeTime > 0 && !searchHitBefore(doc.ServerTime, doc.ServerMsgID, q.BeforeTime, q.BeforeMsgID)) {
+			continue
+		}
+		hits = append(hits, &SearchHit{
+			ConversationID: doc.ConversationID,
+			Seq:            doc.Seq,
+			ServerMsgID:    doc.ServerMsgID,
+			ServerTime:     doc.ServerTime,
+		})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		return searchHitBefore(hits[j].ServerTime, hits[j].ServerMsgID, hits[i].ServerTime, hits[i].ServerMsgID)
+	})
+	if len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits, nil
+}
+
+// searchHitBefore (serverTime, serverMsgID) 是否排在游标之后（按倒序）
+func searchHitBefore(serverTime int64, serverMsgID string, beforeTime int64, beforeMsgID string) bool {
+	return serverTime < beforeTime || (serverTime == beforeTime && serverMsgID < beforeMsgID)
+}
+
+// postgresSearchIndex 基于 PostgreSQL tsvector 的搜索索引（message_search_docs 表，多节点共享）
+type postgresSearchIndex struct {
+	db *gorm.DB
+}
+
+// NewPostgresSearchIndex 创建 PostgreSQL 搜索索引（并创建 GIN 表达式索引）
+func NewPostgresSearchIndex(db *gorm.DB) (SearchIndex, error) {
+	if db.Dialector.Name() != "postgres" {
+		return nil, fmt.Errorf("postgres search index requires a postgres database, got %s", db.Dialector.Name())
+	}
+	if err := db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_message_search_docs_terms
+		ON message_search_docs USING GIN (to_tsvector('simple', terms))
+	`).Error; err != nil {
+		return nil, fmt.Errorf("failed to create search index: %w", err)
+	}
+	return &postgresSearchIndex{db: db}, nil
+}
+
+// Index 写入或更新搜索文档
+func (idx *postgresSearchIndex) Index(doc *model.MessageSearchDoc) error {
+	return idx.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "conversation_id"}, {Name: "seq"}},
+		DoUpdates: clause.AssignmentColumns([]string{"sender_id", "message_type", "server_time", "terms"}),
+	}).Create(doc).Error
+}
+
+// Remove 删除搜索文档
+func (idx *postgresSearchIndex) Remove(conversationID string, seqs []int64) error {
+	return idx.db.Where("conversation_id = ? AND seq IN ?", conversationID, seqs).
+		Delete(&model.MessageSearchDoc{}).Error
+}
+
+// Search 全文检索（词项已分好，plainto_tsquery 要求全部命中）
+func (idx *postgresSearchIndex) Search(q *SearchQuery) ([]*SearchHit, error) {
+	if len(q.Terms) == 0 || len(q.ConversationIDs) == 0 {
+		return nil, nil
+	}
+
+	db := idx.db.Model(&model.MessageSearchDoc{}).
+		Where("to_tsvector('simple', terms) @@ plainto_tsquery('simple', ?)", strings.Join(q.Terms, " ")).
+		Where("conversation_id IN ?", q.ConversationIDs)
+	if q.SenderID != "" {
+		db = db.Where("sender_id = ?", q.SenderID)
+	}
+	if q.StartTime > 0 {
+		db = db.Where("server_time >= ?", q.StartTime)
+	}
+	if q.EndTime > 0 {
+		db = db.Where("server_time <= ?", q.EndTime)
+	}
+	if len(q.MessageTypes) > 0 {
+		db = db.Where("message_type IN ?", q.MessageTypes)
+	}
+	if q.BeforeTime > 0 {
+		db = db.Where("(server_time < ? OR (server_time = ? AND server_msg_id < ?))", q.BeforeTime, q.BeforeTime, q.BeforeMsgID)
+	}
+
+	var hits []*SearchHit
+	err := db.Select("conversation_id, seq, server_msg_id, server_time").
+		Order("server_time DESC, server_msg_id DESC").
+		Limit(q.Limit).
+		Scan(&hits).Error
+	return hits, err
+}
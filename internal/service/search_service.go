@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSearchLimit    = 20
+	maxSearchLimit        = 50
+	maxSearchKeywordRunes = 100  // 搜索关键词最大长度（字符）
+	searchMaxRounds       = 5    // 命中结果被可见性过滤后不足一页时，最多向索引取的轮数
+	searchRebuildBatch    = 1000 // 重建索引时每批读取的消息数
+)
+
+// SearchQuery 索引查询条件（结果按 server_time、server_msg_id 倒序）
+type SearchQuery struct {
+	Terms           []string // 关键词分词后的词项（全部命中才算匹配）
+	ConversationIDs []string // 限定的会话范围（由 SearchService 按用户所在会话填充，不能为空）
+	SenderID        string   // 发送者过滤（空表示不限）
+	StartTime       int64    // server_time 下限（毫秒，0 表示不限）
+	EndTime         int64    // server_time 上限（毫秒，0 表示不限）
+	MessageTypes    []int    // 消息类型过滤（空表示不限）
+	BeforeTime      int64    // 分页游标：上一页最后一条的 server_time（0 表示从最新开始）
+	BeforeMsgID     string   // 分页游标：上一页最后一条的 server_msg_id
+	Limit           int
+}
+
+// SearchHit 索引命中的消息
+type SearchHit struct {
+	ConversationID string
+	Seq            int64
+	ServerMsgID    string
+	ServerTime     int64
+}
+
+// SearchIndex 消息全文索引（内嵌的内存倒排索引或 PostgreSQL tsvector）
+type SearchIndex interface {
+	// Index 写入或更新一条消息的搜索文档
+	Index(doc *model.MessageSearchDoc) error
+	// Remove 删除会话中指定消息的搜索文档
+	Remove(conversationID string, seqs []int64) error
+	// Search 按条件查询，返回最多 q.Limit 条命中
+	Search(q *SearchQuery) ([]*SearchHit, error)
+}
+
+// SearchResult 搜索结果（NextBeforeTime/NextBeforeMsgID 为下一页的游标）
+type SearchResult struct {
+	Messages        []*model.Message
+	HasMore         bool
+	NextBeforeTime  int64
+	NextBeforeMsgID string
+}
+
+// SearchService 消息搜索服务
+// 发送、编辑时写入索引，撤回、保留期限清理时从索引删除；阅后即焚消息不进入索引。
+// 单个用户的删除和清空不修改索引，查询时按用户可见性过滤
+type SearchService struct {
+	index        SearchIndex
+	msgService   *MessageService
+	groupService *GroupService
+}
+
+// NewSearchService 创建消息搜索服务
+func NewSearchService(index SearchIndex, msgService *MessageService, groupService *GroupService) *SearchService {
+	return &SearchService{
+		index:        index,
+		msgService:   msgService,
+		groupService: groupService,
+	}
+}
+
+// IndexMessage 将新消息或编辑后的消息写入索引（撤回、过期和阅后即焚消息不索引）
+func (s *SearchService) IndexMessage(msg *model.Message) error {
+	if msg.Status == 4 || msg.Status == model.MessageStatusExpired || msg.ExpireTTL > 0 {
+		return nil
+	}
+
+	terms := tokenizeSearchText(searchableText(msg), true)
+	if len(terms) == 0 {
+		// 编辑后可能不再包含可搜索的文本
+		return s.index.Remove(msg.ConversationID, []int64{msg.Seq})
+	}
+
+	return s.index.Index(&model.MessageSearchDoc{
+		ConversationID: msg.ConversationID,
+		Seq:            msg.Seq,
+		ServerMsgID:    msg.ServerMsgID,
+		SenderID:       msg.SenderID,
+		MessageType:    msg.MessageType,
+		ServerTime:     msg.ServerTime,
+		Terms:          strings.Join(terms, " "),
+	})
+}
+
+// RemoveMessages 从索引中删除消息
+func (s *SearchService) RemoveMessages(conversationID string, seqs []int64) error {
+	if len(seqs) == 0 {
+		return nil
+	}
+	return s.index.Remove(conversationID, seqs)
+}
+
+// SearchMessages 搜索用户的消息
+// q.ConversationIDs 为空时搜索用户所在的全部会话；指定会话时调用方需要先确认用户是会话参与者
+func (s *SearchService) SearchMessages(ctx context.Context, userID, keyword string, q *SearchQuery) (*SearchResult, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" || utf8.RuneCountInString(keyword) > maxSearchKeywordRunes {
+		return nil, ErrInvalidKeyword
+	}
+	terms := tokenizeSearchText(keyword, false)
+	if len(terms) == 0 {
+		return nil, ErrInvalidKeyword
+	}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	conversationIDs := q.ConversationIDs
+	if len(conversationIDs) == 0 {
+		var err error
+		conversationIDs, err = s.userConversationIDs(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := &SearchResult{NextBeforeTime: q.BeforeTime, NextBeforeMsgID: q.BeforeMsgID}
+	if len(conversationIDs) == 0 {
+		return result, nil
+	}
+
+	for round := 0; round < searchMaxRounds && len(result.Messages) < limit; round++ {
+		page := *q
+		page.Terms = terms
+		page.ConversationIDs = conversationIDs
+		page.BeforeTime = result.NextBeforeTime
+		page.BeforeMsgID = result.NextBeforeMsgID
+		page.Limit = limit
+
+		hits, err := s.index.Search(&page)
+		if err != nil {
+			return nil, err
+		}
+		visible, err := s.loadVisibleMessages(userID, hits)
+		if err != nil {
+			return nil, err
+		}
+
+		scanned := 0
+		for _, hit := range hits {
+			scanned++
+			result.NextBeforeTime = hit.ServerTime
+			result.NextBeforeMsgID = hit.ServerMsgID
+			if msg, ok := visible[hit.ServerMsgID]; ok {
+				result.Messages = append(result.Messages, msg)
+				if len(result.Messages) >= limit {
+					break
+				}
+			}
+		}
+		result.HasMore = scanned < len(hits) || len(hits) >= limit
+		if !result.HasMore {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// Rebuild 从消息表重建索引（内存索引在启动时调用）
+func (s *SearchService) Rebuild() error {
+	var (
+		lastConversationID string
+		lastSeq            int64
+		total              int
+	)
+	for {
+		var messages []*model.Message
+		err := repository.DB.
+			Where("(conversation_id > ? OR (conversation_id = ? AND seq > ?))", lastConversationID, lastConversationID, lastSeq).
+			Where("status NOT IN ? AND expire_ttl = 0", []int{4, model.MessageStatusExpired}).
+			Order("conversation_id ASC, seq ASC").
+			Limit(searchRebuildBatch).
+			Find(&messages).Error
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range messages {
+			if err := s.IndexMessage(msg); err != nil {
+				return err
+			}
+		}
+		total += len(messages)
+
+		if len(messages) < searchRebuildBatch {
+			break
+		}
+		last := messages[len(messages)-1]
+		lastConversationID, lastSeq = last.ConversationID, last.Seq
+	}
+
+	logger.Info("Search index rebuilt", zap.Int("message_count", total))
+	return nil
+}
+
+// userConversationIDs 用户可搜索的会话：参与过的单聊和当前所在的群
+func (s *SearchService) userConversationIDs(ctx context.Context, userID string) ([]string, error) {
+	allIDs, err := s.msgService.GetUserConversationIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	conversationIDs := make([]string, 0, len(allIDs))
+	for _, id := range allIDs {
+		// 群会话以当前成员身份为准（已退出的群不再可搜索）
+		if !strings.HasPrefix(id, "group_") {
+			conversationIDs = append(conversationIDs, id)
+		}
+	}
+
+	groups, err := s.groupService.GetMyGroups(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		conversationIDs = append(conversationIDs, "group_"+group.ID)
+	}
+	return conversationIDs, nil
+}
+
+// loadVisibleMessages 加载命中的消息，过滤已撤回、已过期以及用户已删除或已清空的消息（按 server_msg_id 索引）
+func (s *SearchService) loadVisibleMessages(userID string, hits []*SearchHit) (map[string]*model.Message, error) {
+	seqsByConversation := make(map[string][]int64)
+	for _, hit := range hits {
+		seqsByConversation[hit.ConversationID] = append(seqsByConversation[hit.ConversationID], hit.Seq)
+	}
+
+	visible := make(map[string]*model.Message, len(hits))
+	for conversationID, seqs := range seqsByConversation {
+		var messages []*model.Message
+		err := repository.DB.
+			Where("conversation_id = ? AND seq IN ? AND status != 4", conversationID, seqs).
+			Scopes(visibleToUser(userID, conversationID)).
+			Find(&messages).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, msg := range messages {
+			visible[msg.ServerMsgID] = msg
+		}
+	}
+	return visible, nil
+}
+
+// searchableText 提取消息中可搜索的文本：文本消息为内容本身，合并转发为标题，
+// 其他类型取内容 JSON 中的文件名、标题等字段
+func searchableText(msg *model.Message) string {
+	switch msg.MessageType {
+	case 1:
+		return msg.Content
+	case model.MessageTypeMergedForward:
+		var content model.MergedForwardContent
+		if err := json.Unmarshal([]byte(msg.Content), &content); err != nil {
+			return ""
+		}
+		return content.Title
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(msg.Content), &fields); err != nil {
+		return ""
+	}
+	var parts []string
+	for _, key := range []string{"name", "file_name", "fileName", "title", "text"} {
+		if v, ok := fields[key].(string); ok && v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, " ")
+}
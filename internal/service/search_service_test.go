@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+)
+
+// newTestSearchService 创建使用内存索引和测试数据库的搜索服务
+func newTestSearchService(t *testing.T) (*SearchService, *MessageService) {
+	t.Helper()
+	msgService := newTestMessageService(t, &model.Group{}, &model.GroupMember{})
+	if err := repository.DB.Create(&model.Group{ID: "g1", Name: "g1", OwnerID: "a"}).Error; err != nil {
+		t.Fatalf("create group: %v", err)
+	}
+	addTestGroupMembers(t, "g1", map[string]int{"a": 1, "b": 3})
+	return NewSearchService(NewMemorySearchIndex(), msgService, NewGroupService(repository.DB)), msgService
+}
+
+// saveSearchMessage 保存一条消息（serverTime 决定搜索结果顺序）
+func saveSearchMessage(t *testing.T, s *MessageService, conversationID, senderID, receiverID, content string, serverTime int64) *model.Message {
+	t.Helper()
+	msg := &model.Message{
+		ClientMsgID:    content,
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		ReceiverID:     receiverID,
+		MessageType:    1,
+		Content:        content,
+		ServerTime:     serverTime,
+		Status:         1,
+	}
+	if err := s.SaveMessage(msg); err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	return msg
+}
+
+func TestSearchServiceSearchMessages(t *testing.T) {
+	s, msgService := newTestSearchService(t)
+	ctx := context.Background()
+
+	messages := []*model.Message{
+		saveSearchMessage(t, msgService, "single_a_b", "a", "b", "今天天气不错", 100),
+		saveSearchMessage(t, msgService, "single_a_b", "b", "a", "Weather report: 天气晴", 200),
+		saveSearchMessage(t, msgService, "group_g1", "a", "", "明天天气怎么样", 300),
+		saveSearchMessage(t, msgService, "group_g2", "c", "", "天气", 400),
+		saveSearchMessage(t, msgService, "single_a_b", "a", "b", "天空", 500),
+	}
+	ephemeral := &model.Message{ClientMsgID: "burn", ConversationID: "single_a_b", SenderID: "a", ReceiverID: "b", MessageType: 1, Content: "天气 burn", ServerTime: 600, Status: 1, ExpireTTL: 10}
+	if err := msgService.SaveMessage(ephemeral); err != nil {
+		t.Fatalf("SaveMessage() error = %v", err)
+	}
+	for _, msg := range append(messages, ephemeral) {
+		if err := s.IndexMessage(msg); err != nil {
+			t.Fatalf("IndexMessage() error = %v", err)
+		}
+	}
+
+	// 只搜索用户所在的会话，阅后即焚消息不进入索引
+	result, err := s.SearchMessages(ctx, "b", "天气", &SearchQuery{})
+	if err != nil {
+		t.Fatalf("SearchMessages() error = %v", err)
+	}
+	assertSearchContents(t, result, "明天天气怎么样", "Weather report: 天气晴", "今天天气不错")
+
+	result, err = s.SearchMessages(ctx, "b", "weather 天气", &SearchQuery{})
+	if err != nil {
+		t.Fatalf("SearchMessages() error = %v", err)
+	}
+	assertSearchContents(t, result, "Weather report: 天气晴")
+
+	result, err = s.SearchMessages(ctx, "b", "天气", &SearchQuery{SenderID: "a", ConversationIDs: []string{"single_a_b"}})
+	if err != nil {
+		t.Fatalf("SearchMessages() error = %v", err)
+	}
+	assertSearchContents(t, result, "今天天气不错")
+
+	// 用户删除的消息不出现在该用户的搜索结果中
+	if _, err := msgService.DeleteMessagesForUser("b", "group_g1", []int64{messages[2].Seq}); err != nil {
+		t.Fatalf("DeleteMessagesForUser() error = %v", err)
+	}
+	result, err = s.SearchMessages(ctx, "b", "天气", &SearchQuery{})
+	if err != nil {
+		t.Fatalf("SearchMessages() error = %v", err)
+	}
+	assertSearchContents(t, result, "Weather report: 天气晴", "今天天气不错")
+
+	if err := s.RemoveMessages("single_a_b", []int64{messages[1].Seq}); err != nil {
+		t.Fatalf("RemoveMessages() error = %v", err)
+	}
+	result, err = s.SearchMessages(ctx, "a", "天气", &SearchQuery{})
+	if err != nil {
+		t.Fatalf("SearchMessages() error = %v", err)
+	}
+	assertSearchContents(t, result, "明天天气怎么样", "今天天气不错")
+
+	if _, err := s.SearchMessages(ctx, "a", " ,. ", &SearchQuery{}); err != ErrInvalidKeyword {
+		t.Errorf("SearchMessages() punctuation error = %v, want %v", err, ErrInvalidKeyword)
+	}
+}
+
+func TestSearchServicePaging(t *testing.T) {
+	s, msgService := newTestSearchService(t)
+	ctx := context.Background()
+
+	for i, content := range []string{"hello 1", "hello 2", "hello 3"} {
+		msg := saveSearchMessage(t, msgService, "single_a_b", "a", "b", content, int64(100+i))
+		if err := s.IndexMessage(msg); err != nil {
+			t.Fatalf("IndexMessage() error = %v", err)
+		}
+	}
+	// 用户删除的命中在分页时被跳过，不会出现空页
+	if _, err := msgService.DeleteMessagesForUser("a", "single_a_b", []int64{2}); err != nil {
+		t.Fatalf("DeleteMessagesForUser() error = %v", err)
+	}
+
+	var got []string
+	q := &SearchQuery{Limit: 1}
+	for page := 0; page < 5; page++ {
+		result, err := s.SearchMessages(ctx, "a", "HELLO", q)
+		if err != nil {
+			t.Fatalf("SearchMessages() error = %v", err)
+		}
+		for _, msg := range result.Messages {
+			got = append(got, msg.Content)
+		}
+		if !result.HasMore {
+			break
+		}
+		q.BeforeTime, q.BeforeMsgID = result.NextBeforeTime, result.NextBeforeMsgID
+	}
+	if len(got) != 2 || got[0] != "hello 3" || got[1] != "hello 1" {
+		t.Errorf("paged results = %v, want [hello 3 hello 1]", got)
+	}
+}
+
+func TestSearchServiceRebuild(t *testing.T) {
+	s, msgService := newTestSearchService(t)
+	saveSearchMessage(t, msgService, "single_a_b", "a", "b", "rebuild me", 100)
+	revoked := saveSearchMessage(t, msgService, "single_a_b", "a", "b", "rebuild revoked", 200)
+	repository.DB.Model(revoked).Update("status", 4)
+
+	if err := s.Rebuild(); err != nil {
+		t.Fatalf("Rebuild() error = %v", err)
+	}
+	result, err := s.SearchMessages(context.Background(), "b", "rebuild", &SearchQuery{})
+	if err != nil {
+		t.Fatalf("SearchMessages() error = %v", err)
+	}
+	assertSearchContents(t, result, "rebuild me")
+}
+
+// assertSearchContents 检查搜索结果的消息内容和顺序
+func assertSearchContents(t *testing.T, result *SearchResult, want ...string) {
+	t.Helper()
+	got := make([]string, 0, len(result.Messages))
+	for _, msg := range result.Messages {
+		got = append(got, msg.Content)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("search results = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("search results = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// setupTestDB 使用临时 SQLite 数据库替换 repository.DB，并为 models 建表
+func setupTestDB(t *testing.T, models ...interface{}) {
+	t.Helper()
+	setupTestLogger()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	previous := repository.DB
+	repository.DB = db
+	t.Cleanup(func() {
+		repository.DB = previous
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+}
+
+// setupTestRedis 使用 miniredis 替换 repository.RedisClient
+func setupTestRedis(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	setupTestLogger()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	previous := repository.RedisClient
+	repository.RedisClient = client
+	t.Cleanup(func() {
+		repository.RedisClient = previous
+		client.Close()
+	})
+	return mr
+}
+
+// setupTestLogger 服务中的日志输出在测试中丢弃
+func setupTestLogger() {
+	if logger.Log == nil {
+		logger.Log = zap.NewNop()
+	}
+}
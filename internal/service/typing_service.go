@@ -0,0 +1,164 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	typingTTL                = 6 * time.Second  // 客户端没有发送停止时，超过该时间服务端自动结束输入状态
+	typingThrottle           = 2 * time.Second  // 同一用户在同一会话中"正在输入"的最短转发间隔
+	typingLargeGroupThrottle = 10 * time.Second // 大群中的最短转发间隔
+	typingLargeGroupSize     = 100              // 在线成员超过该数量视为大群
+)
+
+// TypingExpireFunc 输入状态超时后推送停止输入（由消息处理器实现）
+type TypingExpireFunc func(conversationID, userID string)
+
+// typingState 用户在会话中的输入状态
+type typingState struct {
+	conversationID string
+	userID         string
+	expireAt       time.Time     // 超过该时间自动结束
+	lastRelayed    time.Time     // 最近一次转发"正在输入"的时间
+	throttle       time.Duration // 转发间隔（按最近一次转发的人数决定）
+	rejected       bool          // 用户不是会话参与者（节流期间不再查询，超时后不推送停止）
+}
+
+// TypingService 输入状态服务：节流"正在输入"的转发，并在客户端没有发送停止时自动结束
+// 输入状态是短暂的，只保存在内存中
+type TypingService struct {
+	mu       sync.Mutex
+	states   map[string]*typingState // key: conversationID + "|" + userID
+	stopCh   chan struct{}
+	stopOnce sync.Once
+}
+
+// NewTypingService 创建输入状态服务
+func NewTypingService() *TypingService {
+	return &TypingService{
+		states: make(map[string]*typingState),
+		stopCh: make(chan struct{}),
+	}
+}
+
+// typingKey 输入状态的 key
+func typingKey(conversationID, userID string) string {
+	return conversationID + "|" + userID
+}
+
+// Typing 用户开始（或继续）输入：刷新超时时间，返回是否需要转发给其他参与者
+// 节流在查询接收者之前进行，返回 false 时调用方不需要查询会话参与者
+func (s *TypingService) Typing(conversationID, userID string) bool {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := typingKey(conversationID, userID)
+	state, ok := s.states[key]
+	if !ok {
+		s.states[key] = &typingState{
+			conversationID: conversationID,
+			userID:         userID,
+			expireAt:       now.Add(typingTTL),
+			lastRelayed:    now,
+			throttle:       typingThrottle,
+		}
+		return true
+	}
+
+	state.expireAt = now.Add(typingTTL)
+	if now.Sub(state.lastRelayed) < state.throttle {
+		return false
+	}
+	state.lastRelayed = now
+	state.rejected = false
+	return true
+}
+
+// SetRecipients 记录本次转发的人数，人数较多时之后使用更长的转发间隔
+func (s *TypingService) SetRecipients(conversationID, userID string, recipients int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.states[typingKey(conversationID, userID)]; ok {
+		state.throttle = typingThrottle
+		if recipients > typingLargeGroupSize {
+			state.throttle = typingLargeGroupThrottle
+		}
+	}
+}
+
+// Reject 用户不是会话参与者：保留状态用于节流（节流期间的输入状态不再查询参与者），超时后不推送停止
+func (s *TypingService) Reject(conversationID, userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if state, ok := s.states[typingKey(conversationID, userID)]; ok {
+		state.rejected = true
+	}
+}
+
+// Clear 用户停止输入（或发送了消息），返回之前是否处于输入状态（是则需要转发停止）
+func (s *TypingService) Clear(conversationID, userID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := typingKey(conversationID, userID)
+	state, ok := s.states[key]
+	if !ok {
+		return false
+	}
+	delete(s.states, key)
+	return !state.rejected
+}
+
+// Start 启动超时检查任务（输入状态超时后调用 expire 推送停止输入）
+func (s *TypingService) Start(interval time.Duration, expire TypingExpireFunc) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopCh:
+				return
+			case <-ticker.C:
+				s.expire(expire)
+			}
+		}
+	}()
+}
+
+// Stop 停止超时检查任务
+func (s *TypingService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// expire 结束超时的输入状态
+func (s *TypingService) expire(expire TypingExpireFunc) {
+	now := time.Now()
+	var expired []*typingState
+
+	s.mu.Lock()
+	for key, state := range s.states {
+		if now.Before(state.expireAt) {
+			continue
+		}
+		delete(s.states, key)
+		if !state.rejected {
+			expired = append(expired, state)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, state := range expired {
+		expire(state.conversationID, state.userID)
+	}
+}
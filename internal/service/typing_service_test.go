@@ -0,0 +1,91 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// backdateTyping 将输入状态的时间提前 d（模拟时间流逝）
+func backdateTyping(s *TypingService, conversationID, userID string, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.states[typingKey(conversationID, userID)]
+	state.expireAt = state.expireAt.Add(-d)
+	state.lastRelayed = state.lastRelayed.Add(-d)
+}
+
+func TestTypingServiceThrottle(t *testing.T) {
+	s := NewTypingService()
+
+	if !s.Typing("c1", "a") {
+		t.Fatal("Typing() first = false, want relayed")
+	}
+	if s.Typing("c1", "a") {
+		t.Error("Typing() within throttle = true, want suppressed")
+	}
+	if !s.Typing("c1", "b") || !s.Typing("c2", "a") {
+		t.Error("Typing() by another user or in another conversation = false, want relayed")
+	}
+
+	backdateTyping(s, "c1", "a", typingThrottle)
+	if !s.Typing("c1", "a") {
+		t.Error("Typing() after throttle = false, want relayed")
+	}
+
+	// 大群使用更长的转发间隔
+	s.SetRecipients("c1", "a", typingLargeGroupSize+1)
+	backdateTyping(s, "c1", "a", typingThrottle)
+	if s.Typing("c1", "a") {
+		t.Error("Typing() in a large group after the normal throttle = true, want suppressed")
+	}
+	backdateTyping(s, "c1", "a", typingLargeGroupThrottle)
+	if !s.Typing("c1", "a") {
+		t.Error("Typing() in a large group after its throttle = false, want relayed")
+	}
+
+	if !s.Clear("c1", "a") {
+		t.Error("Clear() while typing = false, want stop relayed")
+	}
+	if s.Clear("c1", "a") {
+		t.Error("Clear() again = true, want nothing to relay")
+	}
+	if !s.Typing("c1", "a") {
+		t.Error("Typing() after Clear = false, want relayed")
+	}
+}
+
+func TestTypingServiceExpireAndReject(t *testing.T) {
+	s := NewTypingService()
+	s.Typing("c1", "a")
+	s.Typing("c1", "b")
+	s.Typing("c2", "x")
+	s.Reject("c2", "x")
+
+	// 非参与者在节流期间不再转发，停止时也不推送
+	if s.Typing("c2", "x") {
+		t.Error("Typing() by a rejected user within throttle = true, want suppressed")
+	}
+
+	backdateTyping(s, "c1", "a", typingTTL)
+	backdateTyping(s, "c2", "x", typingTTL)
+	var expired []string
+	s.expire(func(conversationID, userID string) {
+		expired = append(expired, conversationID+"|"+userID)
+	})
+	if !reflect.DeepEqual(expired, []string{"c1|a"}) {
+		t.Errorf("expire() pushed %v, want only c1|a", expired)
+	}
+	if s.Clear("c1", "a") || s.Clear("c2", "x") {
+		t.Error("Clear() after expiry = true, want states removed")
+	}
+	if !s.Clear("c1", "b") {
+		t.Error("Clear() for an unexpired state = false, want stop relayed")
+	}
+
+	s.Typing("c3", "y")
+	s.Reject("c3", "y")
+	if s.Clear("c3", "y") {
+		t.Error("Clear() by a rejected user = true, want nothing to relay")
+	}
+}
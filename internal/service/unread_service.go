@@ -0,0 +1,563 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/logger"
+	"github.com/arwen/im-server/pkg/utils"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	unreadDirtyKey       = "unread_dirty" // 待备份到数据库的计数（成员为 "userID|conversationID"）
+	unreadTotalField     = "_total"       // 计数 hash 中的未读总数字段（同时表示已从数据库加载）
+	unreadMutedField     = "_muted"       // 计数 hash 中免打扰会话的未读数之和
+	unreadFlushBatchSize = 500
+)
+
+// unreadKey 用户各会话未读数的 hash（field 为 conversationID）
+// 与 unreadMutedKey 使用相同的 hash tag，保证 Lua 脚本在集群模式下访问同一个 slot
+func unreadKey(userID string) string {
+	return fmt.Sprintf("unread:{%s}", userID)
+}
+
+// unreadMutedKey 用户免打扰的会话（zset，score 为免打扰截止时间，毫秒），不计入角标
+func unreadMutedKey(userID string) string {
+	return fmt.Sprintf("unread_muted:{%s}", userID)
+}
+
+// unreadTotalLua 维护未读总数的 Lua 函数
+// 计数 hash 中除各会话的未读数外，还保存全部会话的未读数之和（_total）和免打扰会话的未读数之和（_muted），
+// 会话未读数变化时用 HINCRBY 同步调整，角标为两者之差；只有免打扰状态变化（设置、到期）时才需要按会话调整 _muted
+const unreadTotalLua = `
+local function expireMutes(key, mutedKey, now)
+	local expired = redis.call('ZRANGEBYSCORE', mutedKey, '-inf', now)
+	if #expired == 0 then
+		return
+	end
+	local sum = 0
+	for i = 1, #expired do
+		sum = sum + tonumber(redis.call('HGET', key, expired[i]) or '0')
+	end
+	if sum ~= 0 then
+		redis.call('HINCRBY', key, '_muted', -sum)
+	end
+	redis.call('ZREMRANGEBYSCORE', mutedKey, '-inf', now)
+end
+
+local function adjust(key, mutedKey, field, delta)
+	if delta == 0 then
+		return
+	end
+	redis.call('HINCRBY', key, '_total', delta)
+	if redis.call('ZSCORE', mutedKey, field) then
+		redis.call('HINCRBY', key, '_muted', delta)
+	end
+end
+
+local function badge(key)
+	local values = redis.call('HMGET', key, '_total', '_muted')
+	return tonumber(values[1] or '0') - tonumber(values[2] or '0')
+end
+`
+
+// unreadIncrScript 会话未读数加一并返回角标（计数尚未加载时返回 nil）
+var unreadIncrScript = redis.NewScript(unreadTotalLua + `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+expireMutes(KEYS[1], KEYS[2], tonumber(ARGV[2]))
+local count = redis.call('HINCRBY', KEYS[1], ARGV[1], 1)
+adjust(KEYS[1], KEYS[2], ARGV[1], 1)
+return {count, badge(KEYS[1])}
+`)
+
+// unreadSetScript 设置会话未读数并返回角标（计数尚未加载时返回 nil）
+var unreadSetScript = redis.NewScript(unreadTotalLua + `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+expireMutes(KEYS[1], KEYS[2], tonumber(ARGV[3]))
+local old = tonumber(redis.call('HGET', KEYS[1], ARGV[1]) or '0')
+local count = tonumber(ARGV[2])
+if count > 0 then
+	redis.call('HSET', KEYS[1], ARGV[1], count)
+else
+	count = 0
+	redis.call('HDEL', KEYS[1], ARGV[1])
+end
+adjust(KEYS[1], KEYS[2], ARGV[1], count - old)
+return {count, badge(KEYS[1])}
+`)
+
+// unreadGetScript 获取会话未读数和角标（计数尚未加载时返回 nil）
+var unreadGetScript = redis.NewScript(unreadTotalLua + `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+expireMutes(KEYS[1], KEYS[2], tonumber(ARGV[2]))
+local count = tonumber(redis.call('HGET', KEYS[1], ARGV[1]) or '0')
+return {count, badge(KEYS[1])}
+`)
+
+// unreadMuteScript 设置会话免打扰（score 为 0 表示关闭），按会话的未读数调整免打扰总数，返回未读数和角标（计数尚未加载时返回 nil）
+var unreadMuteScript = redis.NewScript(unreadTotalLua + `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+local now = tonumber(ARGV[3])
+expireMutes(KEYS[1], KEYS[2], now)
+local count = tonumber(redis.call('HGET', KEYS[1], ARGV[1]) or '0')
+local wasMuted = redis.call('ZSCORE', KEYS[2], ARGV[1]) ~= false
+local muted = tonumber(ARGV[2]) > now
+if muted then
+	redis.call('ZADD', KEYS[2], ARGV[2], ARGV[1])
+else
+	redis.call('ZREM', KEYS[2], ARGV[1])
+end
+if muted and not wasMuted then
+	redis.call('HINCRBY', KEYS[1], '_muted', count)
+elseif wasMuted and not muted then
+	redis.call('HINCRBY', KEYS[1], '_muted', -count)
+end
+return {count, badge(KEYS[1])}
+`)
+
+// unreadLoadScript 从数据库备份加载用户的全部未读数并计算两个总数（已加载时不覆盖；免打扰需要先加载）
+var unreadLoadScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+local now = tonumber(ARGV[1])
+local total, muted = 0, 0
+for i = 2, #ARGV, 2 do
+	local count = tonumber(ARGV[i + 1])
+	redis.call('HSET', KEYS[1], ARGV[i], count)
+	total = total + count
+	local deadline = redis.call('ZSCORE', KEYS[2], ARGV[i])
+	if deadline and tonumber(deadline) > now then
+		muted = muted + count
+	end
+end
+redis.call('HSET', KEYS[1], '_total', total, '_muted', muted)
+return 1
+`)
+
+// UnreadState 会话未读数和用户的未读总数
+type UnreadState struct {
+	UserID         string
+	ConversationID string
+	UnreadCount    int64
+	TotalUnread    int64
+}
+
+// UnreadService 未读数服务：每个用户在每个会话中的未读数和未读总数（角标）
+// 计数保存在 Redis 中（Lua 脚本原子地更新会话未读数并计算角标，免打扰的会话不计入角标），变化的计数定期备份到数据库；
+// 已读和删除后按已读游标重新计算，修正计数的偏差
+type UnreadService struct {
+	readReceiptService *ReadReceiptService
+	stopCh             chan struct{}
+	stopOnce           sync.Once
+}
+
+// NewUnreadService 创建未读数服务
+func NewUnreadService(readReceiptService *ReadReceiptService) *UnreadService {
+	return &UnreadService{
+		readReceiptService: readReceiptService,
+		stopCh:             make(chan struct{}),
+	}
+}
+
+// unreadKeys Lua 脚本使用的 key（未读数 hash 和免打扰 zset）
+func unreadKeys(userID string) []string {
+	return []string{unreadKey(userID), unreadMutedKey(userID)}
+}
+
+// Increment 新消息到达：多个用户在会话中的未读数各加一，返回更新后的计数
+func (s *UnreadService) Increment(conversationID string, userIDs []string) ([]*UnreadState, error) {
+	ctx := context.Background()
+	states := make([]*UnreadState, 0, len(userIDs))
+	if len(userIDs) == 0 {
+		return states, nil
+	}
+
+	now := utils.GetCurrentMillis()
+	pipe := repository.RedisClient.Pipeline()
+	cmds := make([]*redis.Cmd, len(userIDs))
+	for i, userID := range userIDs {
+		cmds[i] = unreadIncrScript.Eval(ctx, pipe, unreadKeys(userID), conversationID, now)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	for i, userID := range userIDs {
+		result, err := cmds[i].Int64Slice()
+		if err == redis.Nil {
+			// 计数尚未加载（新用户或 Redis 数据丢失）：从数据库备份加载后重试
+			result, err = s.runLoaded(ctx, unreadIncrScript, userID, conversationID, now)
+		}
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, &UnreadState{UserID: userID, ConversationID: conversationID, UnreadCount: result[0], TotalUnread: result[1]})
+	}
+
+	s.markDirty(ctx, conversationID, userIDs)
+	return states, nil
+}
+
+// Recompute 按已读游标重新计算用户在会话中的未读数（已读回执、删除消息、清空历史、撤回后调用）
+func (s *UnreadService) Recompute(userID, conversationID string) (*UnreadState, error) {
+	count, err := s.readReceiptService.CountUnread(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	return s.set(userID, conversationID, count)
+}
+
+// RecomputeAfterRemoval 会话中 seq 不小于 minSeq 的消息被撤回或过期后，重新计算还没读到这些消息的用户的未读数
+// 返回未读数发生变化的用户的最新计数
+func (s *UnreadService) RecomputeAfterRemoval(conversationID string, userIDs []string, minSeq int64) ([]*UnreadState, error) {
+	cursors, err := s.readReceiptService.loadCursors(conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []*UnreadState
+	for _, userID := range userIDs {
+		if c, ok := cursors[userID]; ok && c.ReadSeq >= minSeq {
+			continue
+		}
+		state, err := s.Recompute(userID, conversationID)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// SetMute 设置会话免打扰（muteUntil 为 0 表示关闭，model.MuteForever 表示永久），返回变化后的未读数和角标
+func (s *UnreadService) SetMute(userID, conversationID string, muteUntil int64) (*UnreadState, error) {
+	score := 0.0
+	if muteUntil != 0 {
+		score = muteScore(muteUntil)
+	}
+	result, err := s.runLoaded(context.Background(), unreadMuteScript, userID, conversationID, score, utils.GetCurrentMillis())
+	if err != nil {
+		return nil, err
+	}
+	return &UnreadState{UserID: userID, ConversationID: conversationID, UnreadCount: result[0], TotalUnread: result[1]}, nil
+}
+
+// muteForeverScore 永久免打扰在 zset 中的 score（float64 可精确表示的最大整数，Lua 中可直接比较）
+const muteForeverScore = 1 << 53
+
+// muteScore 免打扰截止时间在 zset 中的 score
+func muteScore(muteUntil int64) float64 {
+	if muteUntil == model.MuteForever {
+		return muteForeverScore
+	}
+	return float64(muteUntil)
+}
+
+// IsMuted 用户当前是否对会话设置了免打扰
+func (s *UnreadService) IsMuted(userID, conversationID string) (bool, error) {
+	ctx := context.Background()
+	if err := s.ensureLoaded(ctx, userID); err != nil {
+		return false, err
+	}
+
+	score, err := repository.RedisClient.ZScore(ctx, unreadMutedKey(userID), conversationID).Result()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return score > float64(utils.GetCurrentMillis()), nil
+}
+
+// GetMutedUsers 返回 userIDs 中当前对会话设置了免打扰的用户（map[userID]，用于输入状态等实时推送的过滤）
+// 只查询 Redis 中已加载的免打扰状态，不从数据库加载
+func (s *UnreadService) GetMutedUsers(conversationID string, userIDs []string) (map[string]bool, error) {
+	ctx := context.Background()
+	muted := make(map[string]bool)
+	if len(userIDs) == 0 {
+		return muted, nil
+	}
+
+	pipe := repository.RedisClient.Pipeline()
+	cmds := make([]*redis.FloatCmd, len(userIDs))
+	for i, userID := range userIDs {
+		cmds[i] = pipe.ZScore(ctx, unreadMutedKey(userID), conversationID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	now := float64(utils.GetCurrentMillis())
+	for i, userID := range userIDs {
+		if score, err := cmds[i].Result(); err == nil && score > now {
+			muted[userID] = true
+		}
+	}
+	return muted, nil
+}
+
+// set 设置用户在会话中的未读数
+func (s *UnreadService) set(userID, conversationID string, count int64) (*UnreadState, error) {
+	ctx := context.Background()
+	result, err := s.runLoaded(ctx, unreadSetScript, userID, conversationID, count, utils.GetCurrentMillis())
+	if err != nil {
+		return nil, err
+	}
+
+	s.markDirty(ctx, conversationID, []string{userID})
+	return &UnreadState{UserID: userID, ConversationID: conversationID, UnreadCount: result[0], TotalUnread: result[1]}, nil
+}
+
+// runLoaded 执行计数脚本，计数尚未加载时从数据库备份加载后重试
+func (s *UnreadService) runLoaded(ctx context.Context, script *redis.Script, userID string, args ...interface{}) ([]int64, error) {
+	result, err := script.Run(ctx, repository.RedisClient, unreadKeys(userID), args...).Int64Slice()
+	if err != redis.Nil {
+		return result, err
+	}
+	if err := s.load(ctx, userID); err != nil {
+		return nil, err
+	}
+	return script.Run(ctx, repository.RedisClient, unreadKeys(userID), args...).Int64Slice()
+}
+
+// GetUnreadCounts 获取用户在多个会话中的未读数（map[conversationID]，未读数为 0 的会话不返回）和未读总数
+func (s *UnreadService) GetUnreadCounts(userID string, conversationIDs []string) (map[string]int64, int64, error) {
+	ctx := context.Background()
+	if err := s.ensureLoaded(ctx, userID); err != nil {
+		return nil, 0, err
+	}
+
+	counts := make(map[string]int64, len(conversationIDs))
+	if len(conversationIDs) > 0 {
+		values, err := repository.RedisClient.HMGet(ctx, unreadKey(userID), conversationIDs...).Result()
+		if err != nil {
+			return nil, 0, err
+		}
+		for i, v := range values {
+			str, ok := v.(string)
+			if !ok {
+				continue
+			}
+			if n, err := strconv.ParseInt(str, 10, 64); err == nil && n > 0 {
+				counts[conversationIDs[i]] = n
+			}
+		}
+	}
+
+	total, err := s.GetTotalUnread(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return counts, total, nil
+}
+
+// GetTotalUnread 获取用户的未读总数（角标，不包括免打扰的会话）
+func (s *UnreadService) GetTotalUnread(userID string) (int64, error) {
+	result, err := s.runLoaded(context.Background(), unreadGetScript, userID, unreadTotalField, utils.GetCurrentMillis())
+	if err != nil {
+		return 0, err
+	}
+	return result[1], nil
+}
+
+// ensureLoaded 计数不在 Redis 中时从数据库备份加载
+func (s *UnreadService) ensureLoaded(ctx context.Context, userID string) error {
+	exists, err := repository.RedisClient.Exists(ctx, unreadKey(userID)).Result()
+	if err != nil || exists == 1 {
+		return err
+	}
+	return s.load(ctx, userID)
+}
+
+// load 从数据库加载用户的全部未读数和免打扰的会话
+func (s *UnreadService) load(ctx context.Context, userID string) error {
+	var rows []model.ConversationUnread
+	err := repository.DB.Select("conversation_id, unread_count").
+		Where("user_id = ? AND unread_count > 0", userID).
+		Find(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	// 免打扰先于计数加载，保证加载完成后计算的角标不包括免打扰的会话
+	var muted []model.Conversation
+	err = repository.DB.Select("conversation_id, mute_until").
+		Where("user_id = ? AND (mute_until = ? OR mute_until > ?)", userID, model.MuteForever, utils.GetCurrentMillis()).
+		Find(&muted).Error
+	if err != nil {
+		return err
+	}
+	if len(muted) > 0 {
+		members := make([]redis.Z, 0, len(muted))
+		for _, conv := range muted {
+			members = append(members, redis.Z{Score: muteScore(conv.MuteUntil), Member: conv.ConversationID})
+		}
+		if err := repository.RedisClient.ZAdd(ctx, unreadMutedKey(userID), members...).Err(); err != nil {
+			return err
+		}
+	}
+
+	args := make([]interface{}, 0, len(rows)*2+1)
+	args = append(args, utils.GetCurrentMillis())
+	for _, row := range rows {
+		args = append(args, row.ConversationID, row.UnreadCount)
+	}
+	return unreadLoadScript.Run(ctx, repository.RedisClient, unreadKeys(userID), args...).Err()
+}
+
+// markDirty 记录需要备份到数据库的计数
+func (s *UnreadService) markDirty(ctx context.Context, conversationID string, userIDs []string) {
+	members := make([]interface{}, 0, len(userIDs))
+	for _, userID := range userIDs {
+		members = append(members, userID+"|"+conversationID)
+	}
+	if err := repository.RedisClient.SAdd(ctx, unreadDirtyKey, members...).Err(); err != nil {
+		logger.Warn("Failed to mark unread counts dirty", zap.String("conversation_id", conversationID), zap.Error(err))
+	}
+}
+
+// Start 启动备份任务（定期把变化的计数写入数据库）
+func (s *UnreadService) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stopCh:
+				s.flush()
+				return
+			case <-ticker.C:
+				s.flush()
+			}
+		}
+	}()
+}
+
+// Stop 停止备份任务（停止前再备份一次）
+func (s *UnreadService) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopCh)
+	})
+}
+
+// flush 分批把变化的计数写入数据库：写入成功后才删除待备份标记，写入失败的计数在下次备份时重试
+func (s *UnreadService) flush() {
+	ctx := context.Background()
+	members, err := repository.RedisClient.SMembers(ctx, unreadDirtyKey).Result()
+	if err != nil {
+		logger.Error("Failed to get dirty unread counts", zap.Error(err))
+		return
+	}
+
+	for start := 0; start < len(members); start += unreadFlushBatchSize {
+		end := start + unreadFlushBatchSize
+		if end > len(members) {
+			end = len(members)
+		}
+		if err := s.flushBatch(ctx, members[start:end]); err != nil {
+			logger.Error("Failed to back up unread counts", zap.Error(err))
+			return
+		}
+	}
+}
+
+// flushBatch 把一批计数写入数据库并删除对应的待备份标记
+func (s *UnreadService) flushBatch(ctx context.Context, members []string) error {
+	counts, err := s.readDirtyCounts(ctx, members)
+	if err != nil {
+		return err
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	rows := make([]*model.ConversationUnread, 0, len(counts))
+	flushed := make([]interface{}, 0, len(counts))
+	for member, count := range counts {
+		userID, conversationID, _ := strings.Cut(member, "|")
+		rows = append(rows, &model.ConversationUnread{
+			UserID:         userID,
+			ConversationID: conversationID,
+			UnreadCount:    count,
+		})
+		flushed = append(flushed, member)
+	}
+
+	err = repository.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "conversation_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"unread_count", "updated_at"}),
+	}).CreateInBatches(rows, unreadFlushBatchSize).Error
+	if err != nil {
+		return err
+	}
+
+	// 先删除标记再重新读取计数：读取之后的变化会重新标记，读取时已经变化的计数重新加入待备份
+	if err := repository.RedisClient.SRem(ctx, unreadDirtyKey, flushed...).Err(); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(counts))
+	for member := range counts {
+		keys = append(keys, member)
+	}
+	latest, err := s.readDirtyCounts(ctx, keys)
+	if err != nil {
+		return err
+	}
+	var changed []interface{}
+	for _, member := range keys {
+		if count, ok := latest[member]; !ok || count != counts[member] {
+			changed = append(changed, member)
+		}
+	}
+	if len(changed) > 0 {
+		return repository.RedisClient.SAdd(ctx, unreadDirtyKey, changed...).Err()
+	}
+	return nil
+}
+
+// readDirtyCounts 读取待备份的计数（map[member]count，读取失败的成员不在结果中）
+func (s *UnreadService) readDirtyCounts(ctx context.Context, members []string) (map[string]int64, error) {
+	pipe := repository.RedisClient.Pipeline()
+	cmds := make([]*redis.StringCmd, len(members))
+	for i, member := range members {
+		userID, conversationID, _ := strings.Cut(member, "|")
+		cmds[i] = pipe.HGet(ctx, unreadKey(userID), conversationID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(members))
+	for i, member := range members {
+		count, err := cmds[i].Int64()
+		if err != nil && err != redis.Nil {
+			continue
+		}
+		counts[member] = count
+	}
+	return counts, nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arwen/im-server/internal/model"
+	"github.com/arwen/im-server/internal/repository"
+	"github.com/arwen/im-server/pkg/utils"
+)
+
+// newTestUnreadService 创建使用 miniredis 和测试数据库的未读数服务
+func newTestUnreadService(t *testing.T) (*UnreadService, *MessageService) {
+	t.Helper()
+	msgService := newTestMessageService(t, &model.GroupMember{}, &model.ConversationReadCursor{}, &model.ConversationUnread{}, &model.Conversation{})
+	setupTestRedis(t)
+	return NewUnreadService(NewReadReceiptService(NewGroupService(repository.DB), 10)), msgService
+}
+
+// assertUnread 检查未读数服务返回的会话未读数和角标
+func assertUnread(t *testing.T, step string, state *UnreadState, err error, count, total int64) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: error = %v", step, err)
+	}
+	if state.UnreadCount != count || state.TotalUnread != total {
+		t.Errorf("%s: unread %d total %d, want %d and %d", step, state.UnreadCount, state.TotalUnread, count, total)
+	}
+}
+
+func TestUnreadServiceCountersAndMutes(t *testing.T) {
+	s, _ := newTestUnreadService(t)
+
+	// 数据库中的备份：c1 有 3 条未读，c2 有 2 条未读且永久免打扰
+	repository.DB.Create(&model.ConversationUnread{UserID: "u", ConversationID: "c1", UnreadCount: 3})
+	repository.DB.Create(&model.ConversationUnread{UserID: "u", ConversationID: "c2", UnreadCount: 2})
+	repository.DB.Create(&model.Conversation{ID: utils.GenerateID(), UserID: "u", ConversationID: "c2", MuteUntil: model.MuteForever})
+
+	if total, err := s.GetTotalUnread("u"); err != nil || total != 3 {
+		t.Fatalf("GetTotalUnread() after load = %d, %v, want 3", total, err)
+	}
+
+	states, err := s.Increment("c1", []string{"u"})
+	assertUnread(t, "increment c1", states[0], err, 4, 4)
+	states, err = s.Increment("c2", []string{"u"})
+	assertUnread(t, "increment muted c2", states[0], err, 3, 4)
+
+	state, err := s.SetMute("u", "c2", 0)
+	assertUnread(t, "unmute c2", state, err, 3, 7)
+	state, err = s.SetMute("u", "c2", 0)
+	assertUnread(t, "unmute c2 again", state, err, 3, 7)
+
+	// 免打扰到期后重新计入角标
+	state, err = s.SetMute("u", "c1", utils.GetCurrentMillis()+100)
+	assertUnread(t, "mute c1", state, err, 4, 3)
+	if muted, err := s.IsMuted("u", "c1"); err != nil || !muted {
+		t.Errorf("IsMuted() = %v, %v, want true", muted, err)
+	}
+	states, err = s.Increment("c1", []string{"u"})
+	assertUnread(t, "increment muted c1", states[0], err, 5, 3)
+	time.Sleep(150 * time.Millisecond)
+	if total, err := s.GetTotalUnread("u"); err != nil || total != 8 {
+		t.Errorf("GetTotalUnread() after mute expired = %d, %v, want 8", total, err)
+	}
+
+	counts, total, err := s.GetUnreadCounts("u", []string{"c1", "c2", "c3"})
+	if err != nil || total != 8 || len(counts) != 2 || counts["c1"] != 5 || counts["c2"] != 3 {
+		t.Errorf("GetUnreadCounts() = %v, %d, %v", counts, total, err)
+	}
+}
+
+func TestUnreadServiceRecomputeAndFlush(t *testing.T) {
+	s, msgService := newTestUnreadService(t)
+	for _, content := range []string{"m1", "m2", "m3"} {
+		saveGroupTestMessage(t, msgService, "g1", "a", content, 1)
+	}
+	if _, err := s.Increment("group_g1", []string{"b", "b", "b", "b"}); err != nil {
+		t.Fatalf("Increment() error = %v", err)
+	}
+
+	// 按已读游标修正计数
+	if _, err := s.readReceiptService.MarkRead("group_g1", "b", 1, nil, 100); err != nil {
+		t.Fatalf("MarkRead() error = %v", err)
+	}
+	state, err := s.Recompute("b", "group_g1")
+	assertUnread(t, "recompute", state, err, 2, 2)
+
+	s.flush()
+	var backup model.ConversationUnread
+	if err := repository.DB.Where("user_id = ? AND conversation_id = ?", "b", "group_g1").First(&backup).Error; err != nil || backup.UnreadCount != 2 {
+		t.Fatalf("backup = %+v, %v, want 2", backup, err)
+	}
+
+	// Redis 数据丢失后从备份恢复
+	repository.RedisClient.FlushAll(context.Background())
+	counts, total, err := s.GetUnreadCounts("b", []string{"group_g1"})
+	if err != nil || total != 2 || counts["group_g1"] != 2 {
+		t.Errorf("GetUnreadCounts() after reload = %v, %d, %v", counts, total, err)
+	}
+}
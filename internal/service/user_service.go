@@ -98,6 +98,15 @@ func (s *UserService) GetUserByID(userID string) (*model.User, error) {
 	return &user, nil
 }
 
+// UserExists 检查用户是否存在
+func (s *UserService) UserExists(userID string) (bool, error) {
+	var count int64
+	if err := repository.DB.Model(&model.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // UpdateUserStatus 更新用户状态
 func (s *UserService) UpdateUserStatus(userID string, status int) error {
 	return repository.DB.Model(&model.User{}).Where("id = ?", userID).Update("status", status).Error
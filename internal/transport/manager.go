@@ -7,10 +7,15 @@ import (
 	"go.uber.org/zap"
 )
 
+// PresenceListener 用户上线/下线回调（online 为 false 表示该平台的连接已断开）
+type PresenceListener func(userID, platform string, online bool)
+
 // ConnectionManager 连接管理器
 type ConnectionManager struct {
 	connections map[string]Connection // connID -> Connection
 	userConns   map[string]string     // userID -> connID
+	platforms   map[string]string     // connID -> platform
+	listener    PresenceListener
 	mu          sync.RWMutex
 }
 
@@ -19,9 +24,17 @@ func NewConnectionManager() *ConnectionManager {
 	return &ConnectionManager{
 		connections: make(map[string]Connection),
 		userConns:   make(map[string]string),
+		platforms:   make(map[string]string),
 	}
 }
 
+// SetPresenceListener 设置用户上线/下线回调（在启动服务器之前调用）
+func (m *ConnectionManager) SetPresenceListener(listener PresenceListener) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listener = listener
+}
+
 // AddConnection 添加连接
 func (m *ConnectionManager) AddConnection(conn Connection) {
 	m.mu.Lock()
@@ -31,24 +44,33 @@ func (m *ConnectionManager) AddConnection(conn Connection) {
 	logger.Info("Connection added", zap.String("conn_id", conn.GetID()))
 }
 
-// RemoveConnection 移除连接
+// RemoveConnection 移除连接（用户当前的连接断开时触发下线回调）
 func (m *ConnectionManager) RemoveConnection(connID string) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 	
 	conn, exists := m.connections[connID]
 	if !exists {
+		m.mu.Unlock()
 		return
 	}
 	
 	// 移除用户映射
 	userID := conn.GetUserID()
-	if userID != "" {
+	offline := userID != "" && m.userConns[userID] == connID
+	if offline {
 		delete(m.userConns, userID)
 	}
+	platform := m.platforms[connID]
+	listener := m.listener
 	
 	delete(m.connections, connID)
+	delete(m.platforms, connID)
+	m.mu.Unlock()
 	logger.Info("Connection removed", zap.String("conn_id", connID), zap.String("user_id", userID))
+	
+	if offline && listener != nil {
+		listener(userID, platform, false)
+	}
 }
 
 // GetConnection 获取连接
@@ -60,17 +82,18 @@ func (m *ConnectionManager) GetConnection(connID string) (Connection, bool) {
 	return conn, exists
 }
 
-// BindUser 绑定用户
-func (m *ConnectionManager) BindUser(connID, userID string) error {
+// BindUser 绑定用户（platform 为登录时上报的平台），触发上线回调
+func (m *ConnectionManager) BindUser(connID, userID, platform string) error {
 	m.mu.Lock()
-	defer m.mu.Unlock()
 	
 	conn, exists := m.connections[connID]
 	if !exists {
+		m.mu.Unlock()
 		return ErrConnectionNotFound
 	}
 	
 	// 检查用户是否已有连接
+	oldPlatform := ""
 	if oldConnID, exists := m.userConns[userID]; exists && oldConnID != connID {
 		// 踢掉旧连接
 		if oldConn, exists := m.connections[oldConnID]; exists {
@@ -78,12 +101,25 @@ func (m *ConnectionManager) BindUser(connID, userID string) error {
 			oldConn.Close()
 			delete(m.connections, oldConnID)
 		}
+		oldPlatform = m.platforms[oldConnID]
+		delete(m.platforms, oldConnID)
 	}
 	
 	conn.SetUserID(userID)
 	m.userConns[userID] = connID
+	m.platforms[connID] = platform
+	listener := m.listener
+	m.mu.Unlock()
 	
 	logger.Info("User bound to connection", zap.String("user_id", userID), zap.String("conn_id", connID))
+	
+	if listener != nil {
+		// 被新连接踢下线的旧平台先下线
+		if oldPlatform != "" && oldPlatform != platform {
+			listener(userID, oldPlatform, false)
+		}
+		listener(userID, platform, true)
+	}
 	return nil
 }
 
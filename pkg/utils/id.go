@@ -7,6 +7,7 @@ import (
 	"fmt"
 	mathrand "math/rand"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -90,3 +91,20 @@ func GetConversationID(sessionType int, userID1, userID2 string) string {
 	}
 }
 
+// SingleConversationPeer 解析单聊会话ID中 userID 的对方ID
+// 用户ID可能包含 "_"，以调用方自己的ID为锚点拆分，并要求与 userID 组合后能得到同一个会话ID；
+// 两种拆分都成立时优先取 userID 在前的一种，保证结果确定
+func SingleConversationPeer(conversationID, userID string) (string, bool) {
+	ids, ok := strings.CutPrefix(conversationID, "single_")
+	if !ok || userID == "" {
+		return "", false
+	}
+
+	if peer, ok := strings.CutPrefix(ids, userID+"_"); ok && GetConversationID(1, userID, peer) == conversationID {
+		return peer, true
+	}
+	if peer, ok := strings.CutSuffix(ids, "_"+userID); ok && GetConversationID(1, userID, peer) == conversationID {
+		return peer, true
+	}
+	return "", false
+}
@@ -9,7 +9,7 @@ BEGIN;
 -- 1. 清空消息相关表
 TRUNCATE TABLE messages CASCADE;
 TRUNCATE TABLE message_sequences CASCADE;
-TRUNCATE TABLE message_read_receipts CASCADE;
+TRUNCATE TABLE conversation_read_cursors CASCADE;
 
 -- 2. 清空会话表
 TRUNCATE TABLE conversations CASCADE;
@@ -27,6 +27,26 @@ TRUNCATE TABLE friend_requests CASCADE;
 -- TRUNCATE TABLE groups CASCADE;
 -- TRUNCATE TABLE group_members CASCADE;
 
+-- 6. 清空消息功能相关表
+TRUNCATE TABLE message_edits CASCADE;
+TRUNCATE TABLE message_reactions CASCADE;
+TRUNCATE TABLE user_mentions CASCADE;
+TRUNCATE TABLE message_deletions CASCADE;
+TRUNCATE TABLE conversation_clears CASCADE;
+TRUNCATE TABLE pinned_messages CASCADE;
+TRUNCATE TABLE scheduled_messages CASCADE;
+TRUNCATE TABLE conversation_ephemeral_settings CASCADE;
+TRUNCATE TABLE conversation_retentions CASCADE;
+TRUNCATE TABLE message_archives CASCADE;
+TRUNCATE TABLE message_search_docs CASCADE;
+TRUNCATE TABLE media_files CASCADE;
+TRUNCATE TABLE media_uploads CASCADE;
+TRUNCATE TABLE media_upload_chunks CASCADE;
+TRUNCATE TABLE group_moderation_settings CASCADE;
+TRUNCATE TABLE moderation_logs CASCADE;
+TRUNCATE TABLE conversation_unreads CASCADE;
+TRUNCATE TABLE conversation_versions CASCADE;
+
 COMMIT;
 
 SELECT '✅ 数据库清空完成！' AS status;
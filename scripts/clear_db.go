@@ -24,12 +24,30 @@ func main() {
 	tables := []string{
 		"messages",
 		"message_sequences",
-		"message_read_receipts",
+		"conversation_read_cursors",
 		"conversations",
 		"user_sessions",
 		"online_status",
 		"friends",
 		"friend_requests",
+		"message_edits",
+		"message_reactions",
+		"user_mentions",
+		"message_deletions",
+		"conversation_clears",
+		"pinned_messages",
+		"scheduled_messages",
+		"conversation_ephemeral_settings",
+		"conversation_retentions",
+		"message_archives",
+		"message_search_docs",
+		"media_files",
+		"media_uploads",
+		"media_upload_chunks",
+		"group_moderation_settings",
+		"moderation_logs",
+		"conversation_unreads",
+		"conversation_versions",
 	}
 
 	fmt.Println("\n🗑️  开始清空数据...")